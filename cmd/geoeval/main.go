@@ -0,0 +1,173 @@
+// Package main GEO 流程的离线评测
+//
+// 在 golden 数据集录制的 SERP 数据上运行流程（LLM 默认实时调用），对输出做确定性检查和 LLM 评审，
+// 并与基线结果比较，出现回归时以非 0 状态码退出，可用于上线前的 CI 检查：
+//
+//	ARK_API_KEY=... GEO_PROMPT_DIRS=./prompts-candidate \
+//	go run ./cmd/geoeval -dataset eval/dataset.json -baseline eval/baseline.json -out result.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo"
+	"github.com/solariswu/peanut/internal/agent/geo/eval"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+	"github.com/solariswu/peanut/internal/pkg/logging"
+)
+
+var (
+	datasetPath  string
+	baselinePath string
+	outPath      string
+	reportPath   string
+	label        string
+	llmMode      string
+	promptDirs   string
+	record       bool
+	judge        bool
+	tolerance    float64
+	logLevel     string
+)
+
+func init() {
+	flag.StringVar(&datasetPath, "dataset", "", "数据集文件路径（JSON）")
+	flag.StringVar(&baselinePath, "baseline", "", "基线评测结果路径，为空时不比较")
+	flag.StringVar(&outPath, "out", "geoeval-result.json", "评测结果保存路径，可作为下次评测的基线")
+	flag.StringVar(&reportPath, "report", "", "回归报告（Markdown）保存路径，默认输出到标准输出")
+	flag.StringVar(&label, "label", "", "本次评测的说明，如 prompt 或模型版本")
+	flag.StringVar(&llmMode, "llm", "live", "LLM 调用方式：live（实时调用）、replay（从 fixture 回放，跳过 LLM 评审）")
+	flag.StringVar(&promptDirs, "prompts", "", "待评测的 prompt 覆盖目录（逗号分隔），默认读取 GEO_PROMPT_DIRS")
+	flag.BoolVar(&record, "record", false, "重新录制数据集的 SERP 和网页数据（需要真实凭据）")
+	flag.BoolVar(&judge, "judge", true, "使用 LLM 按评分标准评审输出")
+	flag.Float64Var(&tolerance, "tolerance", eval.DefaultTolerance, "LLM 评审平均分下降超过该值视为回归")
+	flag.StringVar(&logLevel, "log-level", "warn", "日志级别：debug、info、warn、error")
+}
+
+func main() {
+	flag.Parse()
+
+	config := zap.NewDevelopmentConfig()
+	if err := config.Level.UnmarshalText([]byte(logLevel)); err != nil {
+		fmt.Printf("无效的日志级别 %q\n", logLevel)
+		os.Exit(2)
+	}
+	logger, err := config.Build()
+	if err != nil {
+		fmt.Printf("初始化日志失败: %v\n", err)
+		os.Exit(2)
+	}
+	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+
+	if datasetPath == "" {
+		logger.Fatal("请通过 -dataset 指定数据集")
+	}
+	if llmMode != "live" && llmMode != "replay" {
+		logger.Fatal("无效的 -llm，可选 live、replay", zap.String("llm", llmMode))
+	}
+
+	ds, err := eval.LoadDataset(datasetPath)
+	if err != nil {
+		logger.Fatal("加载数据集失败", zap.Error(err))
+	}
+
+	var baseline *eval.Run
+	if baselinePath != "" {
+		if baseline, err = eval.LoadRun(baselinePath); err != nil {
+			logger.Fatal("加载基线失败", zap.Error(err))
+		}
+	}
+
+	// 待评测的 prompt：覆盖目录中的文件优先于内嵌模板
+	dirs := prompts.DirsFromEnv()
+	if promptDirs != "" {
+		dirs = strings.Split(promptDirs, ",")
+	}
+	registry, err := prompts.NewRegistry(dirs...)
+	if err != nil {
+		logger.Fatal("加载 prompt 失败", zap.Error(err))
+	}
+	prompts.SetDefault(registry)
+
+	// SERP 和网页数据从数据集回放（-record 时录制），LLM 默认实时调用
+	mode := transport.ModeReplay
+	if record {
+		mode = transport.ModeRecord
+	}
+	recorder := transport.NewRecorder(mode, ds.Fixtures, nil)
+	if llmMode == "live" {
+		u, err := url.Parse(llm.BaseURL())
+		if err != nil {
+			logger.Fatal("无效的 ARK_BASE_URL", zap.Error(err))
+		}
+		recorder.Passthrough(u.Hostname())
+	} else if judge {
+		logger.Info("LLM 回放模式下跳过 LLM 评审")
+		judge = false
+	}
+	transport.SetDefault(recorder)
+
+	// 回放的请求不需要真实凭据，未设置时使用占位值以便创建客户端
+	if !record {
+		setDefaultEnv("BRIGHT_DATA_API_KEY", "replay")
+		if llmMode == "replay" {
+			setDefaultEnv("ARK_API_KEY", "replay")
+		}
+	}
+
+	ctx := logging.WithLogger(context.Background(), logger)
+	svc, err := geo.NewService("google")
+	if err != nil {
+		logger.Fatal("创建 GEO 服务失败", zap.Error(err))
+	}
+
+	var judgeModel *eval.Judge
+	if judge {
+		if judgeModel, err = eval.NewJudge(ctx); err != nil {
+			logger.Fatal("创建 LLM 评审失败", zap.Error(err))
+		}
+	}
+
+	logger.Info("开始评测", zap.String("dataset", ds.Name), zap.Int("cases", len(ds.Cases)), zap.String("model", llm.ModelName()))
+	run := eval.NewRunner(svc, judgeModel).Run(ctx, ds, label)
+	if misses := recorder.Misses(); len(misses) > 0 {
+		logger.Warn("部分请求没有录制数据，可使用 -record 重新录制", zap.Strings("requests", misses))
+	}
+
+	if outPath != "" {
+		if err := run.Save(outPath); err != nil {
+			logger.Fatal("保存评测结果失败", zap.Error(err))
+		}
+	}
+
+	cmp := eval.Compare(baseline, run, tolerance)
+	report := cmp.Markdown()
+	if reportPath != "" {
+		if err := os.WriteFile(reportPath, []byte(report), 0o644); err != nil {
+			logger.Fatal("保存回归报告失败", zap.Error(err))
+		}
+	} else {
+		fmt.Print(report)
+	}
+
+	if len(cmp.Regressions) > 0 {
+		os.Exit(1)
+	}
+}
+
+// setDefaultEnv 环境变量未设置时设为默认值
+func setDefaultEnv(key, value string) {
+	if os.Getenv(key) == "" {
+		os.Setenv(key, value)
+	}
+}
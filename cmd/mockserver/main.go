@@ -0,0 +1,88 @@
+// Package main 本地开发用的 LLM 与 Bright Data 模拟服务
+//
+// 启动后将 GEO 服务指向模拟服务即可在无网络环境下端到端运行：
+//
+//	ARK_API_KEY=mock ARK_BASE_URL=http://localhost:9090/api/v3 \
+//	BRIGHT_DATA_API_KEY=mock BRIGHT_DATA_ENDPOINT=http://localhost:9090/request \
+//	go run ./cmd/server
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/mock"
+)
+
+var (
+	addr       string
+	scriptPath string
+)
+
+func init() {
+	flag.StringVar(&addr, "addr", ":9090", "监听地址")
+	flag.StringVar(&scriptPath, "script", "", "脚本文件路径（JSON，按 Agent 覆盖回答和网页内容）")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		fmt.Printf("初始化日志失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	script := mock.DefaultScript()
+	if scriptPath != "" {
+		script, err = mock.LoadScript(scriptPath)
+		if err != nil {
+			logger.Fatal("加载脚本失败", zap.Error(err))
+		}
+		logger.Info("已加载脚本", zap.String("path", scriptPath))
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mock.NewServer(script).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	baseURL := "http://" + addr
+	if strings.HasPrefix(addr, ":") {
+		baseURL = "http://localhost" + addr
+	}
+
+	go func() {
+		logger.Info("启动模拟服务", zap.String("addr", addr))
+		logger.Info("GEO 服务环境变量",
+			zap.String("ARK_BASE_URL", baseURL+"/api/v3"),
+			zap.String("BRIGHT_DATA_ENDPOINT", baseURL+"/request"),
+		)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("启动模拟服务失败", zap.Error(err))
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("模拟服务强制关闭", zap.Error(err))
+	}
+}
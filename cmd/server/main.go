@@ -26,21 +26,34 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	_ "github.com/solariswu/peanut/api/v1/docs"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 
 	"github.com/solariswu/peanut/internal/agent/geo"
+	"github.com/solariswu/peanut/internal/agent/geo/export"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/agents"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/agent/geo/trace"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
 	"github.com/solariswu/peanut/internal/config"
 	"github.com/solariswu/peanut/internal/handler"
 	"github.com/solariswu/peanut/internal/middleware"
-	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/cache"
 	"github.com/solariswu/peanut/internal/pkg/database"
+	"github.com/solariswu/peanut/internal/pkg/migrate"
 	"github.com/solariswu/peanut/internal/pkg/progress"
+	"github.com/solariswu/peanut/internal/pkg/telemetry"
 	"github.com/solariswu/peanut/internal/repository"
 	"github.com/solariswu/peanut/internal/service"
 
@@ -48,10 +61,14 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-var configPath string
+var (
+	configPath  string
+	autoMigrate bool
+)
 
 func init() {
 	flag.StringVar(&configPath, "config", "configs/config.yaml", "配置文件路径")
+	flag.BoolVar(&autoMigrate, "auto-migrate", false, "启动时执行未执行的数据库迁移")
 }
 
 func main() {
@@ -76,28 +93,116 @@ func main() {
 		os.Exit(1)
 	}
 	defer logger.Sync()
+	// 未携带上下文日志器的代码（logging.FromContext）使用全局日志器
+	zap.ReplaceGlobals(logger)
+
+	// OpenTelemetry 链路追踪（配置 OTEL_EXPORTER_OTLP_ENDPOINT 时导出 span）
+	shutdownTracing, err := telemetry.Setup(context.Background())
+	if err != nil {
+		logger.Warn("初始化链路追踪失败", zap.Error(err))
+		shutdownTracing = func(context.Context) error { return nil }
+	} else if telemetry.Enabled() {
+		logger.Info("OpenTelemetry 链路追踪已启用")
+	}
 
-	// 连接 SQLite 数据库
-	db, err := database.NewSQLite("peanut.db")
+	// 所有 Graph 的节点执行都导出为 span，并统计 Agent 耗时
+	callbacks.AppendGlobalHandlers(trace.NewOTelHandler())
+
+	// 连接数据库（database.driver：sqlite 或 postgres）
+	db, err := database.New(&cfg.Database)
 	if err != nil {
 		logger.Fatal("连接数据库失败", zap.Error(err))
 	}
 	defer db.Close()
-	logger.Info("SQLite 数据库连接成功")
+	logger.Info("数据库连接成功", zap.String("driver", db.DB().Dialector.Name()))
 
-	// 执行数据库迁移
-	if err := db.DB().AutoMigrate(&model.User{}, &model.GEOAnalysis{}); err != nil {
-		logger.Warn("数据库迁移失败", zap.Error(err))
-	} else {
-		logger.Info("数据库迁移成功")
+	// 数据库结构由内嵌的版本化迁移管理（peanut migrate up|down|status）
+	migrator, err := migrate.New(db.DB())
+	if err != nil {
+		logger.Fatal("加载数据库迁移失败", zap.Error(err))
+	}
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			logger.Fatal("未知的子命令", zap.String("command", args[0]))
+		}
+		if err := runMigrate(context.Background(), migrator, args[1:], logger); err != nil {
+			logger.Fatal("数据库迁移失败", zap.Error(err))
+		}
+		return
+	}
+	if autoMigrate {
+		if _, err := migrateUp(context.Background(), migrator, logger); err != nil {
+			logger.Fatal("数据库迁移失败", zap.Error(err))
+		}
+	}
+	// 数据库结构与程序版本不一致时拒绝启动
+	if err := migrator.Check(context.Background()); err != nil {
+		logger.Fatal("数据库结构与程序版本不一致，请先执行 peanut migrate up", zap.Error(err))
+	}
+	logger.Info("数据库结构版本检查通过", zap.Int("version", migrator.Latest()))
+
+	// 旧分析记录的 JSON 字段拆分写入结构化结果子表
+	resultRepo := repository.NewAnalysisResultRepository(db.DB())
+	resultSvc := service.NewAnalysisResultService(resultRepo)
+	if n, err := resultSvc.Backfill(context.Background()); err != nil {
+		logger.Warn("回填结构化分析结果失败", zap.Error(err))
+	} else if n > 0 {
+		logger.Info("回填结构化分析结果完成", zap.Int("analyses", n))
 	}
 
+	// 后台任务（定时采集、prompt 目录监听）随服务退出停止
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
 	// 初始化服务
 	userRepo := repository.NewUserRepository(db.DB())
 	userSvc := service.NewUserService(userRepo)
 
+	// 初始化 GEO 响应缓存（搜索、AI 回答、网页爬取和 LLM 调用）
+	geoCache := initGEOCache(cfg, logger)
+	cacheTTLs, err := tools.LoadCacheTTLs()
+	if err != nil {
+		logger.Warn("加载缓存有效期失败，使用默认值", zap.Error(err))
+	}
+
+	// LLM 价格表（用于估算每次分析的费用）
+	prices, err := llm.LoadPriceTable()
+	if err != nil {
+		logger.Warn("加载 LLM 价格表失败，使用默认价格", zap.Error(err))
+	}
+	llm.SetPriceTable(prices)
+
+	// 录制/回放外部 HTTP 调用（GEO_HTTP_MODE=record|replay），需在创建 GEO 服务之前设置
+	if recorder := transport.RecorderFromEnv(); recorder != nil {
+		transport.SetDefault(recorder)
+		logger.Info("GEO HTTP 录制/回放已启用", zap.String("mode", os.Getenv("GEO_HTTP_MODE")))
+	}
+
+	// prompt 模板：内嵌默认模板，GEO_PROMPT_DIRS 中的文件覆盖默认模板，数据库中激活的版本优先
+	promptRegistry, err := prompts.NewRegistry(prompts.DirsFromEnv()...)
+	if err != nil {
+		logger.Warn("加载 prompt 覆盖目录失败，使用内嵌模板", zap.Error(err))
+		promptRegistry, _ = prompts.NewRegistry()
+	}
+	prompts.SetDefault(promptRegistry)
+	promptRepo := repository.NewPromptRepository(db.DB())
+	promptSvc := service.NewPromptService(promptRepo, promptRegistry)
+	if err := promptSvc.Load(context.Background()); err != nil {
+		logger.Warn("加载数据库中的 prompt 失败", zap.Error(err))
+	}
+	if err := promptRegistry.Watch(jobCtx); err != nil {
+		logger.Warn("监听 prompt 目录失败，修改文件后需重启生效", zap.Error(err))
+	}
+	promptHandler := handler.NewPromptHandler(promptSvc)
+	experimentSvc := service.NewPromptExperimentService(repository.NewPromptExperimentRepository(db.DB()), promptRepo, promptRegistry)
+	experimentHandler := handler.NewPromptExperimentHandler(experimentSvc)
+	adminToken := os.Getenv("GEO_ADMIN_TOKEN")
+	if adminToken == "" {
+		logger.Warn("未设置 GEO_ADMIN_TOKEN，管理接口已禁用")
+	}
+
 	// 初始化 GEO 服务（使用 Google AI Overview）
-	geoService, err := geo.NewDefaultService()
+	geoService, err := geo.NewServiceWithCache("google", geoCache, cacheTTLs)
 	if err != nil {
 		logger.Warn("创建 GEO 服务失败", zap.Error(err))
 		// 不退出，GEO 服务可选
@@ -111,16 +216,63 @@ func main() {
 
 	// 初始化 GEO 分析服务（数据库版本）
 	var geoAnalysisHandler *handler.GEOAnalysisHandler
+	var brandHandler *handler.BrandHandler
+	var reportTemplateHandler *handler.ReportTemplateHandler
+	var geoAnalysisSvc *service.GEOAnalysisService
+	geoAnalysisRepo := repository.NewGEOAnalysisRepository(db.DB())
 	if geoService != nil {
-		geoAnalysisRepo := repository.NewGEOAnalysisRepository(db.DB())
-		geoAnalysisSvc := service.NewGEOAnalysisService(geoAnalysisRepo, geoService, progressMgr)
-		geoAnalysisHandler = handler.NewGEOAnalysisHandler(geoAnalysisSvc, progressMgr)
+
+		// 品牌提及分析（可选）
+		var brandSvc *service.BrandService
+		if brandAnalyzer, err := agents.NewBrandAnalyzer(context.Background()); err != nil {
+			logger.Warn("创建品牌分析器失败，品牌提及分析不可用", zap.Error(err))
+		} else {
+			brandSvc = service.NewBrandService(repository.NewBrandRepository(db.DB()), geoAnalysisRepo, brandAnalyzer)
+			brandHandler = handler.NewBrandHandler(brandSvc)
+		}
+
+		geoAnalysisSvc = service.NewGEOAnalysisService(geoAnalysisRepo, geoService, progressMgr, brandSvc, repository.NewLLMUsageRepository(db.DB()), repository.NewTraceRepository(db.DB()), repository.NewAnalysisLogRepository(db.DB()), experimentSvc, resultSvc)
+
+		// 报告导出（品牌信息和模板目录可配置）
+		brand, err := export.BrandingFromEnv()
+		if err != nil {
+			logger.Warn("品牌配置无效，使用默认品牌", zap.Error(err))
+		}
+		if brand.LogoURL != "" {
+			// PDF 和 DOCX 嵌入启动时下载的 Logo
+			if brand.Logo, err = export.LoadLogo(context.Background(), brand.LogoURL); err != nil {
+				logger.Warn("下载品牌 Logo 失败，PDF 和 DOCX 不显示 Logo", zap.Error(err))
+			}
+		}
+		exporter, err := export.NewExporter(brand, os.Getenv("GEO_REPORT_TEMPLATES"))
+		if err != nil {
+			logger.Fatal("初始化报告导出失败", zap.Error(err))
+		}
+		reportSvc := service.NewReportService(repository.NewReportTemplateRepository(db.DB()), geoAnalysisSvc, exporter)
+		geoAnalysisHandler = handler.NewGEOAnalysisHandler(geoAnalysisSvc, progressMgr, reportSvc)
+		reportTemplateHandler = handler.NewReportTemplateHandler(reportSvc)
 		logger.Info("GEO 分析服务初始化成功")
 	}
 
+	// 初始化引用份额追踪服务（需要 AI 回答提供者）
+	var querySetHandler *handler.QuerySetHandler
+	if answers, err := tools.NewAnswerProviderFromEnv(); err != nil {
+		logger.Warn("创建 AI 回答提供者失败，引用份额追踪不可用", zap.Error(err))
+	} else {
+		querySetRepo := repository.NewQuerySetRepository(db.DB())
+		shareOfVoiceSvc := service.NewShareOfVoiceService(querySetRepo, answers)
+		querySetHandler = handler.NewQuerySetHandler(shareOfVoiceSvc)
+		go shareOfVoiceSvc.Start(jobCtx)
+		logger.Info("引用份额追踪服务初始化成功")
+	}
+
 	// 初始化处理器
 	userHandler := handler.NewUserHandler(userSvc)
-	healthHandler := handler.NewHealthHandler()
+	// 优化建议任务（GEO 服务不可用时不能在完成后重新分析）
+	suggestionSvc := service.NewSuggestionService(repository.NewSuggestionRepository(db.DB()), geoAnalysisRepo, geoAnalysisSvc)
+	suggestionHandler := handler.NewSuggestionHandler(suggestionSvc)
+	statisticsHandler := handler.NewStatisticsHandler(service.NewStatisticsService(geoAnalysisRepo, resultRepo))
+	healthHandler := handler.NewHealthHandler(db)
 
 	// 设置 Gin 模式
 	gin.SetMode(cfg.Server.Mode)
@@ -128,7 +280,9 @@ func main() {
 	// 创建路由
 	router := gin.New()
 	router.Use(
+		otelgin.Middleware(telemetry.ServiceName),
 		middleware.Recovery(logger),
+		middleware.RequestID(logger),
 		middleware.Logger(logger),
 		middleware.CORS(),
 	)
@@ -136,6 +290,9 @@ func main() {
 	// 注册健康检查路由
 	healthHandler.RegisterRoutes(router)
 
+	// Prometheus 指标
+	router.GET("/metrics", gin.WrapH(telemetry.MetricsHandler()))
+
 	// Swagger 文档路由
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -143,12 +300,40 @@ func main() {
 	api := router.Group("/api/v1")
 	userHandler.RegisterRoutes(api)
 
+	// 注册 prompt 管理路由
+	promptHandler.RegisterRoutes(api, middleware.AdminToken(adminToken))
+	experimentHandler.RegisterRoutes(api, middleware.AdminToken(adminToken))
+
 	// 注册 GEO 分析路由
 	if geoAnalysisHandler != nil {
 		geoAnalysisHandler.RegisterRoutes(api)
 		logger.Info("GEO 分析路由已注册")
 	}
 
+	// 注册优化建议路由
+	suggestionHandler.RegisterRoutes(api)
+
+	// 注册域名统计路由
+	statisticsHandler.RegisterRoutes(api)
+
+	// 注册报告模板路由
+	if reportTemplateHandler != nil {
+		reportTemplateHandler.RegisterRoutes(api)
+		logger.Info("报告模板路由已注册")
+	}
+
+	// 注册品牌提及路由
+	if brandHandler != nil {
+		brandHandler.RegisterRoutes(api)
+		logger.Info("品牌提及路由已注册")
+	}
+
+	// 注册引用份额追踪路由
+	if querySetHandler != nil {
+		querySetHandler.RegisterRoutes(api)
+		logger.Info("引用份额追踪路由已注册")
+	}
+
 	// 启动服务器
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
@@ -172,6 +357,7 @@ func main() {
 	<-quit
 
 	logger.Info("正在关闭服务器...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -180,30 +366,51 @@ func main() {
 		logger.Error("服务器强制关闭", zap.Error(err))
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("关闭链路追踪失败", zap.Error(err))
+	}
+
 	logger.Info("服务器已退出")
 }
 
-// initLogger 初始化日志
-func initLogger(cfg *config.Config) (*zap.Logger, error) {
-	var logger *zap.Logger
-	var err error
+// initGEOCache 根据 GEO_CACHE（memory、redis、off，默认 memory）创建 GEO 响应缓存
+// Redis 使用配置文件中的 redis 连接，连接失败时退回内存缓存
+func initGEOCache(cfg *config.Config, logger *zap.Logger) cache.Store {
+	size, _ := strconv.Atoi(os.Getenv("GEO_CACHE_SIZE"))
+
+	switch strings.ToLower(os.Getenv("GEO_CACHE")) {
+	case "off", "none":
+		logger.Info("GEO 响应缓存已关闭")
+		return nil
+	case "redis":
+		rdb, err := cache.NewRedis(&cfg.Redis)
+		if err == nil {
+			logger.Info("GEO 响应缓存使用 Redis", zap.String("addr", cfg.Redis.Addr()))
+			return rdb
+		}
+		logger.Warn("连接 Redis 失败，GEO 响应缓存使用内存", zap.Error(err))
+	}
 
+	return cache.NewMemoryStore(size)
+}
+
+// initLogger 初始化日志，json 和 console 格式均按配置的级别输出
+func initLogger(cfg *config.Config) (*zap.Logger, error) {
+	config := zap.NewDevelopmentConfig()
 	if cfg.Log.Format == "json" {
-		logger, err = zap.NewProduction()
-	} else {
-		config := zap.NewDevelopmentConfig()
-		switch cfg.Log.Level {
-		case "debug":
-			config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-		case "info":
-			config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-		case "warn":
-			config.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
-		case "error":
-			config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
-		}
-		logger, err = config.Build()
+		config = zap.NewProductionConfig()
+	}
+
+	switch cfg.Log.Level {
+	case "debug":
+		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	case "info":
+		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	case "warn":
+		config.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+	case "error":
+		config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
 	}
 
-	return logger, err
+	return config.Build()
 }
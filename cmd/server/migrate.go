@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/pkg/migrate"
+)
+
+// migrateUsage migrate 子命令用法
+const migrateUsage = `用法: peanut [-config 配置文件] migrate <命令>
+
+命令:
+  up        执行所有未执行的迁移
+  down [n]  回滚最近执行的 n 个迁移（默认 1）
+  status    查看迁移执行状态`
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(ctx context.Context, m *migrate.Migrator, args []string, logger *zap.Logger) error {
+	if len(args) == 0 {
+		return fmt.Errorf("缺少迁移命令\n%s", migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		executed, err := migrateUp(ctx, m, logger)
+		if err != nil {
+			return err
+		}
+		if len(executed) == 0 {
+			logger.Info("没有待执行的迁移", zap.Int("version", m.Latest()))
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("回滚数量必须为正整数: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, migration := range reverted {
+			logger.Info("已回滚迁移", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "未执行"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("未知的迁移命令 %q\n%s", args[0], migrateUsage)
+	}
+}
+
+// migrateUp 执行未执行的迁移，此前由 AutoMigrate 创建的数据库先按初始迁移补齐列再由初始迁移接管
+func migrateUp(ctx context.Context, m *migrate.Migrator, logger *zap.Logger) ([]migrate.Migration, error) {
+	if m.Legacy(ctx) {
+		logger.Info("升级 AutoMigrate 创建的数据库")
+		if err := m.UpgradeLegacy(ctx); err != nil {
+			return nil, fmt.Errorf("升级 AutoMigrate 创建的数据库失败: %w", err)
+		}
+	}
+	executed, err := m.Up(ctx)
+	for _, migration := range executed {
+		logger.Info("已执行迁移", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+	return executed, err
+}
@@ -0,0 +1,120 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/parser"
+)
+
+// 确定性检查项
+const (
+	CheckJSONValid        = "json_valid"        // 各 Agent 的输出均可按 JSON 解析
+	CheckReportSections   = "report_sections"   // 优化报告包含必需章节
+	CheckComparisonTable  = "comparison_table"  // 优化报告包含对比表格
+	CheckOptimizedArticle = "optimized_article" // 生成了优化后的文章
+	CheckTitle            = "title"             // 标题与预期一致
+	CheckMainQuery        = "main_query"        // 主查询与预期一致
+)
+
+// sectionKeys 优化报告必须包含的章节（i18n 消息键），报告由 content_optimizer 的结构化输出渲染，缺少的内容不输出对应章节
+var sectionKeys = []string{"exec_summary", "comparison", "key_findings", "suggestions", "conclusion"}
+
+// DefaultSections 返回输出语言下优化报告必须包含的章节标题
+func DefaultSections(lang string) []string {
+	sections := make([]string, len(sectionKeys))
+	for i, key := range sectionKeys {
+		sections[i] = i18n.T(lang, key)
+	}
+	return sections
+}
+
+// CheckResult 一项检查的结果
+type CheckResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Check 对分析结果做确定性检查，parseFailures 为各 prompt 输出解析失败的次数
+func Check(c Case, report *models.OptimizationReport, parseFailures map[string]int) []CheckResult {
+	results := []CheckResult{
+		checkJSONValid(parseFailures),
+		checkSections(report.OptimizationReport, c.Expect.Sections, report.OutputLanguage),
+		checkComparisonTable(report.OptimizationReport, c.Expect.MinTableRows),
+		{
+			Name:   CheckOptimizedArticle,
+			Passed: strings.TrimSpace(report.OptimizedArticle) != "",
+		},
+	}
+	if c.Expect.Title != "" {
+		results = append(results, checkEqual(CheckTitle, c.Expect.Title, report.Title))
+	}
+	if c.Expect.MainQuery != "" {
+		results = append(results, checkEqual(CheckMainQuery, c.Expect.MainQuery, report.MainQuery))
+	}
+	return results
+}
+
+// checkJSONValid 检查是否有 Agent 的输出无法按 JSON 解析
+func checkJSONValid(parseFailures map[string]int) CheckResult {
+	var failed []string
+	for name, n := range parseFailures {
+		if n > 0 {
+			failed = append(failed, fmt.Sprintf("%s(%d)", name, n))
+		}
+	}
+	sort.Strings(failed)
+
+	result := CheckResult{Name: CheckJSONValid, Passed: len(failed) == 0}
+	if len(failed) > 0 {
+		result.Detail = "解析失败: " + strings.Join(failed, ", ")
+	}
+	return result
+}
+
+// checkSections 检查优化报告是否包含必需章节（不区分大小写），未指定章节时使用报告输出语言的默认章节
+func checkSections(report string, sections []string, lang string) CheckResult {
+	if len(sections) == 0 {
+		sections = DefaultSections(lang)
+	}
+
+	content := strings.ToLower(report)
+	var missing []string
+	for _, section := range sections {
+		if !strings.Contains(content, strings.ToLower(section)) {
+			missing = append(missing, section)
+		}
+	}
+
+	result := CheckResult{Name: CheckReportSections, Passed: len(missing) == 0}
+	if len(missing) > 0 {
+		result.Detail = "缺少: " + strings.Join(missing, ", ")
+	}
+	return result
+}
+
+// checkComparisonTable 检查优化报告中的对比表格行数
+func checkComparisonTable(report string, minRows int) CheckResult {
+	if minRows <= 0 {
+		minRows = 1
+	}
+	rows := len(parser.ExtractComparisonTable(report))
+	return CheckResult{
+		Name:   CheckComparisonTable,
+		Passed: rows >= minRows,
+		Detail: fmt.Sprintf("%d 行（至少 %d 行）", rows, minRows),
+	}
+}
+
+// checkEqual 检查输出与预期一致（忽略首尾空白）
+func checkEqual(name, want, got string) CheckResult {
+	result := CheckResult{Name: name, Passed: strings.TrimSpace(got) == strings.TrimSpace(want)}
+	if !result.Passed {
+		result.Detail = fmt.Sprintf("得到 %q，预期 %q", got, want)
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTolerance LLM 评审平均分下降超过该值视为回归
+const DefaultTolerance = 0.5
+
+// Comparison 评测结果与基线的比较
+type Comparison struct {
+	Baseline    *Run             `json:"baseline,omitempty"`
+	Current     *Run             `json:"current"`
+	Cases       []CaseComparison `json:"cases"`
+	Regressions []string         `json:"regressions"`
+}
+
+// CaseComparison 单个页面与基线的比较
+type CaseComparison struct {
+	ID          string      `json:"id"`
+	Current     *CaseResult `json:"current"`
+	Baseline    *CaseResult `json:"baseline,omitempty"` // 基线中没有该页面时为空
+	Regressions []string    `json:"regressions,omitempty"`
+	Fixed       []string    `json:"fixed,omitempty"` // 基线中失败、本次通过的检查
+}
+
+// Compare 比较评测结果与基线，baseline 为 nil 时只汇总本次结果
+// 回归包括：流程由成功变为失败、检查由通过变为不通过、LLM 评审分下降超过 tolerance
+func Compare(baseline, current *Run, tolerance float64) *Comparison {
+	cmp := &Comparison{Baseline: baseline, Current: current}
+
+	baseCases := map[string]*CaseResult{}
+	if baseline != nil {
+		for i := range baseline.Cases {
+			baseCases[baseline.Cases[i].ID] = &baseline.Cases[i]
+		}
+	}
+
+	for i := range current.Cases {
+		cur := &current.Cases[i]
+		cc := CaseComparison{ID: cur.ID, Current: cur, Baseline: baseCases[cur.ID]}
+		if cc.Baseline != nil {
+			cc.Regressions, cc.Fixed = compareCase(cc.Baseline, cur, tolerance)
+		}
+		for _, r := range cc.Regressions {
+			cmp.Regressions = append(cmp.Regressions, cur.ID+": "+r)
+		}
+		cmp.Cases = append(cmp.Cases, cc)
+	}
+	return cmp
+}
+
+// compareCase 比较单个页面的检查结果和评审分
+func compareCase(base, cur *CaseResult, tolerance float64) (regressions, fixed []string) {
+	if base.Error == "" && cur.Error != "" {
+		return []string{"流程执行失败: " + cur.Error}, nil
+	}
+	if cur.Error != "" {
+		return nil, nil
+	}
+
+	for _, check := range cur.Checks {
+		prev, ok := base.check(check.Name)
+		switch {
+		case !ok || prev.Passed == check.Passed:
+		case !check.Passed:
+			msg := "检查 " + check.Name + " 不再通过"
+			if check.Detail != "" {
+				msg += "（" + check.Detail + "）"
+			}
+			regressions = append(regressions, msg)
+		default:
+			fixed = append(fixed, check.Name)
+		}
+	}
+
+	if base.Judge != nil && cur.Judge != nil && base.Judge.Overall-cur.Judge.Overall > tolerance {
+		regressions = append(regressions, fmt.Sprintf("LLM 评审分下降 %.2f → %.2f", base.Judge.Overall, cur.Judge.Overall))
+	}
+	return regressions, fixed
+}
+
+// Markdown 生成回归报告
+func (c *Comparison) Markdown() string {
+	var sb strings.Builder
+	cur := c.Current
+
+	sb.WriteString("# GEO 离线评测报告\n\n")
+	fmt.Fprintf(&sb, "- 数据集: %s\n", cur.Dataset)
+	fmt.Fprintf(&sb, "- 模型: %s\n", cur.Model)
+	if cur.Label != "" {
+		fmt.Fprintf(&sb, "- 说明: %s\n", cur.Label)
+	}
+	fmt.Fprintf(&sb, "- 时间: %s\n", cur.StartedAt.Format("2006-01-02 15:04:05"))
+	if c.Baseline != nil {
+		fmt.Fprintf(&sb, "- 基线: %s（%s，%s）\n", labelOr(c.Baseline.Label, "未命名"), c.Baseline.Model, c.Baseline.StartedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	sb.WriteString("\n## 汇总\n\n")
+	sb.WriteString("| 指标 | 本次 |")
+	if c.Baseline != nil {
+		sb.WriteString(" 基线 |")
+	}
+	sb.WriteString("\n|---|---|")
+	if c.Baseline != nil {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+	rows := []struct {
+		name string
+		fn   func(s Summary) string
+	}{
+		{"通过页面", func(s Summary) string { return fmt.Sprintf("%d/%d", s.Passed, s.Cases) }},
+		{"执行失败", func(s Summary) string { return fmt.Sprintf("%d", s.Errors) }},
+		{"检查通过率", func(s Summary) string { return fmt.Sprintf("%.1f%%", s.CheckPassRate*100) }},
+		{"LLM 评审平均分", func(s Summary) string { return fmt.Sprintf("%.2f", s.AvgJudgeScore) }},
+		{"Token", func(s Summary) string { return fmt.Sprintf("%d", s.Tokens) }},
+		{"费用", func(s Summary) string { return fmt.Sprintf("%.4f", s.Cost) }},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&sb, "| %s | %s |", row.name, row.fn(cur.Summary))
+		if c.Baseline != nil {
+			fmt.Fprintf(&sb, " %s |", row.fn(c.Baseline.Summary))
+		}
+		sb.WriteString("\n")
+	}
+
+	if c.Baseline != nil {
+		sb.WriteString("\n## 回归\n\n")
+		if len(c.Regressions) == 0 {
+			sb.WriteString("无\n")
+		}
+		for _, r := range c.Regressions {
+			sb.WriteString("- " + r + "\n")
+		}
+	}
+
+	sb.WriteString("\n## 页面\n\n")
+	sb.WriteString("| 页面 | 结果 | 未通过的检查 | 评审分 | Token |\n|---|---|---|---|---|\n")
+	for _, cc := range c.Cases {
+		status := "通过"
+		switch {
+		case cc.Current.Error != "":
+			status = "执行失败"
+		case !cc.Current.Passed():
+			status = "未通过"
+		}
+		if len(cc.Regressions) > 0 {
+			status += "（回归）"
+		}
+
+		var failed []string
+		for _, check := range cc.Current.Checks {
+			if !check.Passed {
+				failed = append(failed, check.Name)
+			}
+		}
+
+		judge := "-"
+		if cc.Current.Judge != nil {
+			judge = fmt.Sprintf("%.2f", cc.Current.Judge.Overall)
+			if cc.Baseline != nil && cc.Baseline.Judge != nil {
+				judge += fmt.Sprintf("（基线 %.2f）", cc.Baseline.Judge.Overall)
+			}
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %d |\n", cc.ID, status, labelOr(strings.Join(failed, ", "), "-"), judge, cc.Current.Tokens)
+	}
+	return sb.String()
+}
+
+// labelOr 返回 s，为空时返回默认值
+func labelOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+// Package eval GEO 流程的离线评测
+//
+// 在录制的 SERP 数据（golden 数据集）上运行流程，对输出做确定性检查和 LLM 评分，
+// 并与基线结果比较，用于在上线前验证 prompt 和模型的修改
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// Dataset 评测数据集
+type Dataset struct {
+	Name string `json:"name"`
+	// Fixtures 录制的外部 HTTP 交互目录，相对路径相对于数据集文件，默认 fixtures
+	Fixtures string `json:"fixtures,omitempty"`
+	Cases    []Case `json:"cases"`
+}
+
+// Case 一个评测页面
+type Case struct {
+	ID             string `json:"id"`
+	URL            string `json:"url"`
+	Platform       string `json:"platform,omitempty"` // 默认 google
+	Country        string `json:"country,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Device         string `json:"device,omitempty"`
+	OutputLanguage string `json:"output_language,omitempty"` // 报告输出语言，默认使用网页语言
+	Expect         Expect `json:"expect"`
+}
+
+// Expect 页面的预期输出，未设置的项使用默认检查或跳过
+type Expect struct {
+	Title        string   `json:"title,omitempty"`
+	MainQuery    string   `json:"main_query,omitempty"`
+	Sections     []string `json:"sections,omitempty"`       // 优化报告必须包含的章节，默认为输出语言的 DefaultSections
+	MinTableRows int      `json:"min_table_rows,omitempty"` // 对比表格最少行数，默认 1
+}
+
+// SearchOptions 返回页面的搜索参数
+func (c Case) SearchOptions() models.SearchOptions {
+	return models.SearchOptions{
+		Country:  c.Country,
+		Language: c.Language,
+		Device:   c.Device,
+	}
+}
+
+// LoadDataset 读取数据集文件
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取数据集失败: %w", err)
+	}
+
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("解析数据集失败: %w", err)
+	}
+	if len(ds.Cases) == 0 {
+		return nil, fmt.Errorf("数据集 %s 没有评测页面", path)
+	}
+
+	seen := make(map[string]bool, len(ds.Cases))
+	for i, c := range ds.Cases {
+		if c.ID == "" || c.URL == "" {
+			return nil, fmt.Errorf("数据集第 %d 个页面缺少 id 或 url", i+1)
+		}
+		if seen[c.ID] {
+			return nil, fmt.Errorf("数据集页面 id 重复: %s", c.ID)
+		}
+		seen[c.ID] = true
+		if c.Platform == "" {
+			ds.Cases[i].Platform = "google"
+		}
+	}
+
+	if ds.Fixtures == "" {
+		ds.Fixtures = "fixtures"
+	}
+	if !filepath.IsAbs(ds.Fixtures) {
+		ds.Fixtures = filepath.Join(filepath.Dir(path), ds.Fixtures)
+	}
+	if ds.Name == "" {
+		ds.Name = filepath.Base(path)
+	}
+	return &ds, nil
+}
@@ -0,0 +1,103 @@
+package eval
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+)
+
+func TestRunner_Replay(t *testing.T) {
+	ds, err := LoadDataset("testdata/dataset.json")
+	if err != nil {
+		t.Fatalf("LoadDataset() error = %v", err)
+	}
+
+	// 数据集 fixture 同时包含 LLM 响应，完整回放不访问网络
+	t.Setenv("ARK_API_KEY", "replay")
+	t.Setenv("BRIGHT_DATA_API_KEY", "replay")
+	for _, key := range []string{
+		"ARK_BASE_URL", "ARK_MODEL",
+		"BRIGHT_DATA_ZONE", "BRIGHT_DATA_ENDPOINT", "BRIGHT_DATA_WEB_UNLOCKER_ZONE",
+		"GEO_SEARCH_PROVIDERS", "GEO_ANSWER_PROVIDERS",
+	} {
+		t.Setenv(key, "")
+	}
+	recorder := transport.NewRecorder(transport.ModeReplay, ds.Fixtures, nil)
+	t.Cleanup(transport.SetDefault(recorder))
+
+	svc, err := geo.NewService("google")
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	run := NewRunner(svc, nil).Run(context.Background(), ds, "replay")
+	if misses := recorder.Misses(); len(misses) > 0 {
+		t.Fatalf("没有 fixture 的请求:\n%s", strings.Join(misses, "\n"))
+	}
+
+	if len(run.Cases) != 1 || run.Cases[0].Error != "" {
+		t.Fatalf("Cases = %+v", run.Cases)
+	}
+	result := &run.Cases[0]
+
+	// 结构化报告包含必需章节和对比表格，全部检查通过
+	want := map[string]bool{
+		CheckJSONValid:        true,
+		CheckReportSections:   true,
+		CheckComparisonTable:  true,
+		CheckOptimizedArticle: true,
+		CheckTitle:            true,
+		CheckMainQuery:        true,
+	}
+	for name, passed := range want {
+		if got, ok := result.check(name); !ok || got.Passed != passed {
+			t.Errorf("check %s = %+v, want passed=%v", name, got, passed)
+		}
+	}
+	if run.Summary.Cases != 1 || run.Summary.Passed != 1 || run.Summary.CheckPassRate != 1 {
+		t.Errorf("Summary = %+v", run.Summary)
+	}
+
+	// 基线中通过的检查在本次失败时报告为回归
+	baseline := *run
+	baseline.Cases = []CaseResult{*result}
+	baseline.Cases[0].Checks = append([]CheckResult(nil), result.Checks...)
+	for i := range baseline.Cases[0].Checks {
+		baseline.Cases[0].Checks[i].Passed = true
+	}
+	baseline.Cases[0].Judge = &JudgeResult{Overall: 4.5}
+	result.Judge = &JudgeResult{Overall: 3.5}
+	for i := range result.Checks {
+		if result.Checks[i].Name == CheckComparisonTable || result.Checks[i].Name == CheckReportSections {
+			result.Checks[i].Passed = false
+		}
+	}
+
+	cmp := Compare(&baseline, run, DefaultTolerance)
+	if len(cmp.Regressions) != 3 {
+		t.Errorf("Regressions = %v, want 2 checks and judge score", cmp.Regressions)
+	}
+	if report := cmp.Markdown(); !strings.Contains(report, "## 回归") || !strings.Contains(report, "home-espresso") {
+		t.Errorf("Markdown() = %s", report)
+	}
+
+	if cmp := Compare(run, run, DefaultTolerance); len(cmp.Regressions) != 0 {
+		t.Errorf("self comparison Regressions = %v", cmp.Regressions)
+	}
+}
+
+func TestParseJudgeResult(t *testing.T) {
+	got, err := parseJudgeResult("```json\n{\"scores\":{\"relevance\":4,\"faithfulness\":3,\"actionability\":5,\"completeness\":3},\"comment\":\"ok\"}\n```")
+	if err != nil {
+		t.Fatalf("parseJudgeResult() error = %v", err)
+	}
+	if got.Overall != 3.75 || got.Comment != "ok" {
+		t.Errorf("parseJudgeResult() = %+v", got)
+	}
+
+	if _, err := parseJudgeResult(`{"scores":{"relevance":6}}`); err == nil {
+		t.Error("parseJudgeResult(invalid) error = nil")
+	}
+}
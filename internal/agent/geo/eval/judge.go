@@ -0,0 +1,90 @@
+package eval
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// judgeAgent 评审调用在 LLM 用量中的 Agent 名称
+const judgeAgent = "eval_judge"
+
+//go:embed judge.md
+var judgePrompt string
+
+// Rubric LLM 评审的评分维度，每项 1-5 分
+var Rubric = []string{"relevance", "faithfulness", "actionability", "completeness"}
+
+// JudgeResult LLM 评审结果
+type JudgeResult struct {
+	Scores  map[string]int `json:"scores"`
+	Overall float64        `json:"overall"` // 各维度平均分
+	Comment string         `json:"comment,omitempty"`
+}
+
+// Judge 按评分标准用 LLM 评估分析输出
+type Judge struct {
+	model model.BaseChatModel
+}
+
+// NewJudge 创建 LLM 评审
+func NewJudge(ctx context.Context) (*Judge, error) {
+	cm, err := llm.NewChatModel(llm.WithAgentName(ctx, judgeAgent))
+	if err != nil {
+		return nil, err
+	}
+	return &Judge{model: cm}, nil
+}
+
+// Score 评估分析输出
+func (j *Judge) Score(ctx context.Context, report *models.OptimizationReport) (*JudgeResult, error) {
+	messages, err := prompt.FromMessages(schema.Jinja2, schema.SystemMessage(judgePrompt)).Format(ctx, map[string]any{
+		"main_query":          report.MainQuery,
+		"ai_overview":         report.AIOverview,
+		"query_summary":       report.QueryFanoutSummary,
+		"optimization_report": report.OptimizationReport,
+		"optimized_article":   report.OptimizedArticle,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("格式化评审 prompt 失败: %w", err)
+	}
+
+	output, err := j.model.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("调用评审模型失败: %w", err)
+	}
+	return parseJudgeResult(output.Content)
+}
+
+// parseJudgeResult 解析评审输出，兼容 JSON 外包裹代码块的情况
+func parseJudgeResult(content string) (*JudgeResult, error) {
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		content = content[start : end+1]
+	}
+
+	var result JudgeResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("解析评审结果失败: %w", err)
+	}
+
+	sum := 0
+	for _, dim := range Rubric {
+		score, ok := result.Scores[dim]
+		if !ok || score < 1 || score > 5 {
+			return nil, fmt.Errorf("评审结果缺少有效的 %s 评分", dim)
+		}
+		sum += score
+	}
+	result.Overall = math.Round(float64(sum)/float64(len(Rubric))*100) / 100
+	return &result, nil
+}
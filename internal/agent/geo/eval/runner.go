@@ -0,0 +1,206 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/flow"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// Run 一次评测的结果，保存为 JSON 后可作为下次评测的基线
+type Run struct {
+	Label     string       `json:"label,omitempty"` // 本次评测的说明，如 prompt 或模型版本
+	Dataset   string       `json:"dataset"`
+	Model     string       `json:"model"`
+	StartedAt time.Time    `json:"started_at"`
+	Cases     []CaseResult `json:"cases"`
+	Summary   Summary      `json:"summary"`
+}
+
+// CaseResult 一个页面的评测结果
+type CaseResult struct {
+	ID             string            `json:"id"`
+	URL            string            `json:"url"`
+	Error          string            `json:"error,omitempty"` // 流程执行失败
+	Checks         []CheckResult     `json:"checks,omitempty"`
+	Judge          *JudgeResult      `json:"judge,omitempty"`
+	JudgeError     string            `json:"judge_error,omitempty"`
+	OverallScore   int               `json:"overall_score"`
+	PromptVersions map[string]string `json:"prompt_versions,omitempty"`
+	Tokens         int               `json:"tokens"`
+	Cost           float64           `json:"cost"`
+	DurationMs     int64             `json:"duration_ms"`
+}
+
+// Passed 流程执行成功且所有检查通过
+func (r *CaseResult) Passed() bool {
+	if r.Error != "" {
+		return false
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// check 返回指定检查的结果
+func (r *CaseResult) check(name string) (CheckResult, bool) {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+// Summary 评测汇总
+type Summary struct {
+	Cases         int     `json:"cases"`
+	Passed        int     `json:"passed"`          // 所有检查通过的页面数
+	Errors        int     `json:"errors"`          // 流程执行失败的页面数
+	CheckPassRate float64 `json:"check_pass_rate"` // 检查项通过率
+	AvgJudgeScore float64 `json:"avg_judge_score"` // LLM 评审平均分，没有评审结果时为 0
+	Tokens        int     `json:"tokens"`
+	Cost          float64 `json:"cost"`
+}
+
+// summarize 汇总各页面结果
+func summarize(cases []CaseResult) Summary {
+	s := Summary{Cases: len(cases)}
+	var checks, passedChecks, judged int
+	var judgeSum float64
+	for i := range cases {
+		c := &cases[i]
+		if c.Error != "" {
+			s.Errors++
+		}
+		if c.Passed() {
+			s.Passed++
+		}
+		for _, check := range c.Checks {
+			checks++
+			if check.Passed {
+				passedChecks++
+			}
+		}
+		if c.Judge != nil {
+			judged++
+			judgeSum += c.Judge.Overall
+		}
+		s.Tokens += c.Tokens
+		s.Cost += c.Cost
+	}
+	if checks > 0 {
+		s.CheckPassRate = math.Round(float64(passedChecks)/float64(checks)*10000) / 10000
+	}
+	if judged > 0 {
+		s.AvgJudgeScore = math.Round(judgeSum/float64(judged)*100) / 100
+	}
+	s.Cost = math.Round(s.Cost*1e6) / 1e6
+	return s
+}
+
+// Runner 在数据集上运行 GEO 流程并评估输出
+type Runner struct {
+	agent flow.AgentService
+	judge *Judge
+}
+
+// NewRunner 创建评测执行器，judge 为 nil 时跳过 LLM 评审
+func NewRunner(agent flow.AgentService, judge *Judge) *Runner {
+	return &Runner{agent: agent, judge: judge}
+}
+
+// Run 依次评测数据集中的页面
+func (r *Runner) Run(ctx context.Context, ds *Dataset, label string) *Run {
+	run := &Run{
+		Label:     label,
+		Dataset:   ds.Name,
+		Model:     llm.ModelName(),
+		StartedAt: time.Now(),
+		Cases:     make([]CaseResult, 0, len(ds.Cases)),
+	}
+	for _, c := range ds.Cases {
+		run.Cases = append(run.Cases, r.runCase(ctx, c))
+	}
+	run.Summary = summarize(run.Cases)
+	return run
+}
+
+// runCase 评测单个页面
+func (r *Runner) runCase(ctx context.Context, c Case) CaseResult {
+	logger := logging.FromContext(ctx).With(zap.String("case", c.ID))
+	ctx = logging.WithLogger(ctx, logger)
+	ctx = tools.WithSearchOptions(ctx, c.SearchOptions())
+	if c.OutputLanguage != "" {
+		ctx = i18n.WithLanguage(ctx, i18n.Normalize(c.OutputLanguage))
+	}
+	rec := prompts.NewRecorder()
+	ctx = prompts.WithRecorder(ctx, rec)
+
+	result := CaseResult{ID: c.ID, URL: c.URL}
+	start := time.Now()
+	report, err := r.agent.Analyze(ctx, c.URL, c.Platform)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		logger.Warn("评测页面执行失败", zap.Error(err))
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Checks = Check(c, report, rec.ParseFailures())
+	result.OverallScore = report.OverallScore
+	result.PromptVersions = report.PromptVersions
+	if report.LLMUsage != nil {
+		result.Tokens = report.LLMUsage.TotalTokens
+		result.Cost = report.LLMUsage.Cost
+	}
+
+	if r.judge != nil {
+		judged, err := r.judge.Score(ctx, report)
+		if err != nil {
+			logger.Warn("LLM 评审失败", zap.Error(err))
+			result.JudgeError = err.Error()
+		} else {
+			result.Judge = judged
+		}
+	}
+	return result
+}
+
+// LoadRun 读取保存的评测结果
+func LoadRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取评测结果失败: %w", err)
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("解析评测结果失败: %w", err)
+	}
+	return &run, nil
+}
+
+// Save 保存评测结果
+func (r *Run) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化评测结果失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("保存评测结果失败: %w", err)
+	}
+	return nil
+}
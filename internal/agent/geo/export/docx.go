@@ -0,0 +1,333 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// docx 页面尺寸（A4，单位 twip）
+const (
+	docxPageWidth  = 11906
+	docxPageHeight = 16838
+	docxMargin     = 1134
+)
+
+const (
+	nsW   = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsR   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	nsWP  = "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+	nsA   = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	nsPic = "http://schemas.openxmlformats.org/drawingml/2006/picture"
+)
+
+// docxWriter 生成 word/document.xml 的正文，并收集超链接关系
+type docxWriter struct {
+	body  strings.Builder
+	brand Branding
+	links []string
+}
+
+// renderDOCX 将块渲染为 DOCX 文档
+func renderDOCX(blocks []block, title string, t Template) ([]byte, error) {
+	brand := t.Brand
+	_, logoW, logoH, err := decodeLogo(brand.Logo)
+	if err != nil {
+		return nil, err
+	}
+	w := &docxWriter{brand: brand}
+	for _, b := range blocks {
+		w.block(b)
+	}
+
+	var rels strings.Builder
+	rels.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	rels.WriteString(`<Relationship Id="rIdHeader" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>`)
+	rels.WriteString(`<Relationship Id="rIdFooter" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>`)
+	for i, link := range w.links {
+		fmt.Fprintf(&rels, `<Relationship Id="rIdLink%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`, i+1, xmlEscape(link))
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="` + nsW + `" xmlns:r="` + nsR + `"><w:body>` + w.body.String() +
+		`<w:sectPr><w:headerReference w:type="default" r:id="rIdHeader"/><w:footerReference w:type="default" r:id="rIdFooter"/>` +
+		fmt.Sprintf(`<w:pgSz w:w="%d" w:h="%d"/><w:pgMar w:top="1440" w:right="%d" w:bottom="1440" w:left="%d" w:header="720" w:footer="720" w:gutter="0"/>`, docxPageWidth, docxPageHeight, docxMargin, docxMargin) +
+		`</w:sectPr></w:body></w:document>`
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", docxCore(title, brand.Name)},
+		{"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`},
+		{"word/document.xml", document},
+		{"word/styles.xml", docxStyles(brand)},
+		{"word/header1.xml", docxHeader(brand, logoW, logoH)},
+		{"word/footer1.xml", docxFooter(brand, t.labels())},
+	}
+	if logoW > 0 {
+		files = append(files,
+			struct{ name, content string }{"word/_rels/header1.xml.rels", docxHeaderRels},
+			struct{ name, content string }{"word/media/logo.png", string(brand.Logo)},
+		)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("生成 DOCX 失败: %w", err)
+		}
+		if _, err := fw.Write([]byte(f.content)); err != nil {
+			return nil, fmt.Errorf("生成 DOCX 失败: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("生成 DOCX 失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// block 渲染一个块
+func (w *docxWriter) block(b block) {
+	switch b.kind {
+	case blockHeading:
+		w.paragraph(fmt.Sprintf("Heading%d", min(b.level, 4)), "", b.text)
+	case blockParagraph:
+		w.paragraph("", "", b.text)
+	case blockList:
+		for i, item := range b.items {
+			marker := "•\t"
+			if b.ordered {
+				marker = fmt.Sprintf("%d.\t", i+1)
+			}
+			w.paragraph("ListItem", marker, item)
+		}
+	case blockTable:
+		w.table(b)
+	case blockCode:
+		for _, line := range strings.Split(b.text, "\n") {
+			w.body.WriteString(`<w:p><w:pPr><w:pStyle w:val="Code"/></w:pPr>` + docxRun(line, "") + `</w:p>`)
+		}
+	case blockQuote:
+		w.paragraph("Quote", "", b.text)
+	case blockRule:
+		w.body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="E5E7EB"/></w:pBdr></w:pPr></w:p>`)
+	}
+}
+
+// paragraph 写入一个段落，marker 为列表项前缀
+func (w *docxWriter) paragraph(style, marker, text string) {
+	w.body.WriteString("<w:p>")
+	if style != "" {
+		w.body.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	if marker != "" {
+		w.body.WriteString(docxRun(marker, ""))
+	}
+	w.inline(text)
+	w.body.WriteString("</w:p>")
+}
+
+// inline 写入行内文本
+func (w *docxWriter) inline(text string) {
+	for _, sp := range parseInline(text) {
+		switch {
+		case sp.bold:
+			w.body.WriteString(docxRun(sp.text, "<w:b/>"))
+		case sp.code:
+			w.body.WriteString(docxRun(sp.text, `<w:rFonts w:ascii="Consolas" w:hAnsi="Consolas"/><w:shd w:val="clear" w:color="auto" w:fill="F3F4F6"/>`))
+		case strings.HasPrefix(sp.href, "http://") || strings.HasPrefix(sp.href, "https://"):
+			w.links = append(w.links, sp.href)
+			fmt.Fprintf(&w.body, `<w:hyperlink r:id="rIdLink%d">%s</w:hyperlink>`, len(w.links),
+				docxRun(sp.text, `<w:color w:val="`+hexColor(w.brand.Color)+`"/><w:u w:val="single"/>`))
+		default:
+			w.body.WriteString(docxRun(sp.text, ""))
+		}
+	}
+}
+
+// table 写入表格，各行单元格数与表头对齐
+func (w *docxWriter) table(b block) {
+	cols := len(b.header)
+	colWidth := (docxPageWidth - 2*docxMargin) / cols
+
+	w.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="ReportTable"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr><w:tblGrid>`)
+	for i := 0; i < cols; i++ {
+		fmt.Fprintf(&w.body, `<w:gridCol w:w="%d"/>`, colWidth)
+	}
+	w.body.WriteString("</w:tblGrid>")
+
+	w.row(b.header, true)
+	for _, row := range b.rows {
+		w.row(normalizeRow(row, cols), false)
+	}
+	w.body.WriteString("</w:tbl>")
+	// 表格后需要段落，避免相邻表格合并
+	w.body.WriteString("<w:p/>")
+}
+
+// row 写入表格行
+func (w *docxWriter) row(cells []string, header bool) {
+	w.body.WriteString("<w:tr>")
+	if header {
+		w.body.WriteString("<w:trPr><w:tblHeader/></w:trPr>")
+	}
+	for _, cell := range cells {
+		w.body.WriteString("<w:tc>")
+		if header {
+			w.body.WriteString(`<w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="` + tintColor(w.brand, 0.12) + `"/></w:tcPr><w:p>` + docxRun(plainText(cell), "<w:b/>") + "</w:p>")
+		} else {
+			w.body.WriteString("<w:p>")
+			w.inline(cell)
+			w.body.WriteString("</w:p>")
+		}
+		w.body.WriteString("</w:tc>")
+	}
+	w.body.WriteString("</w:tr>")
+}
+
+// normalizeRow 补齐或截断单元格
+func normalizeRow(row []string, cols int) []string {
+	if len(row) >= cols {
+		return row[:cols]
+	}
+	return append(row, make([]string, cols-len(row))...)
+}
+
+// docxRun 生成文本 run，制表符转为 <w:tab/>
+func docxRun(text, rPr string) string {
+	var sb strings.Builder
+	sb.WriteString("<w:r>")
+	if rPr != "" {
+		sb.WriteString("<w:rPr>" + rPr + "</w:rPr>")
+	}
+	for i, part := range strings.Split(text, "\t") {
+		if i > 0 {
+			sb.WriteString("<w:tab/>")
+		}
+		if part != "" {
+			sb.WriteString(`<w:t xml:space="preserve">` + xmlEscape(part) + "</w:t>")
+		}
+	}
+	sb.WriteString("</w:r>")
+	return sb.String()
+}
+
+// xmlEscape 转义 XML 文本
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// hexColor 返回不带 # 的颜色值
+func hexColor(color string) string {
+	return strings.ToUpper(strings.TrimPrefix(color, "#"))
+}
+
+// tintColor 将主色与白色混合，ratio 为主色占比
+func tintColor(brand Branding, ratio float64) string {
+	r, g, b := brand.rgb()
+	mix := func(c float64) int { return int((c*ratio + (1 - ratio)) * 255) }
+	return fmt.Sprintf("%02X%02X%02X", mix(r), mix(g), mix(b))
+}
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Default Extension="png" ContentType="image/png"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>` +
+	`<Override PartName="/word/footer1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`</Types>`
+
+const docxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+// docxHeaderRels 页眉引用的 Logo 图片
+const docxHeaderRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rIdLogo" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/logo.png"/>` +
+	`</Relationships>`
+
+// docxCore 文档属性
+func docxCore(title, creator string) string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<dc:title>` + xmlEscape(title) + `</dc:title><dc:creator>` + xmlEscape(creator) + `</dc:creator>` +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + now + `</dcterms:created>` +
+		`</cp:coreProperties>`
+}
+
+// docxStyles 正文、标题、列表、代码、引用和表格样式，标题使用品牌主色
+func docxStyles(brand Branding) string {
+	color := hexColor(brand.Color)
+	heading := func(level, size, before int) string {
+		return fmt.Sprintf(`<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>`+
+			`<w:pPr><w:keepNext/><w:spacing w:before="%d" w:after="120"/><w:outlineLvl w:val="%d"/></w:pPr>`+
+			`<w:rPr><w:b/><w:color w:val="%s"/><w:sz w:val="%d"/></w:rPr></w:style>`, level, level, before, level-1, color, size)
+	}
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:styles xmlns:w="` + nsW + `">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Microsoft YaHei" w:cs="Calibri"/><w:sz w:val="21"/><w:color w:val="1F2937"/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="312" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+		heading(1, 36, 0) + heading(2, 30, 360) + heading(3, 26, 240) + heading(4, 23, 200) +
+		`<w:style w:type="paragraph" w:styleId="ListItem"><w:name w:val="List Item"/><w:basedOn w:val="Normal"/><w:pPr><w:tabs><w:tab w:val="left" w:pos="420"/></w:tabs><w:spacing w:after="60"/><w:ind w:left="420" w:hanging="300"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/><w:pPr><w:shd w:val="clear" w:color="auto" w:fill="F3F4F6"/><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas"/><w:sz w:val="19"/></w:rPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:pPr><w:pBdr><w:left w:val="single" w:sz="24" w:space="8" w:color="` + color + `"/></w:pBdr><w:ind w:left="240"/></w:pPr><w:rPr><w:color w:val="4B5563"/></w:rPr></w:style>` +
+		`<w:style w:type="table" w:styleId="ReportTable"><w:name w:val="Report Table"/><w:pPr><w:spacing w:after="0"/></w:pPr><w:rPr><w:sz w:val="19"/></w:rPr><w:tblPr><w:tblBorders>` +
+		`<w:top w:val="single" w:sz="4" w:color="D1D5DB"/><w:left w:val="single" w:sz="4" w:color="D1D5DB"/><w:bottom w:val="single" w:sz="4" w:color="D1D5DB"/><w:right w:val="single" w:sz="4" w:color="D1D5DB"/>` +
+		`<w:insideH w:val="single" w:sz="4" w:color="D1D5DB"/><w:insideV w:val="single" w:sz="4" w:color="D1D5DB"/></w:tblBorders>` +
+		`<w:tblCellMar><w:top w:w="60" w:type="dxa"/><w:left w:w="100" w:type="dxa"/><w:bottom w:w="60" w:type="dxa"/><w:right w:w="100" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+		`</w:styles>`
+}
+
+// docxHeader 页眉：Logo（宽高单位 pt，为 0 时不显示）和页眉文字
+func docxHeader(brand Branding, logoW, logoH float64) string {
+	logo := ""
+	if logoW > 0 {
+		// 1pt = 12700 EMU
+		cx, cy := int(logoW*12700), int(logoH*12700)
+		logo = `<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">` +
+			fmt.Sprintf(`<wp:extent cx="%d" cy="%d"/><wp:docPr id="1" name="Logo"/>`, cx, cy) +
+			`<a:graphic><a:graphicData uri="` + nsPic + `"><pic:pic>` +
+			`<pic:nvPicPr><pic:cNvPr id="1" name="logo.png"/><pic:cNvPicPr/></pic:nvPicPr>` +
+			`<pic:blipFill><a:blip r:embed="rIdLogo"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>` +
+			fmt.Sprintf(`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`, cx, cy) +
+			`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>` + docxRun(" ", "")
+	}
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:hdr xmlns:w="` + nsW + `" xmlns:r="` + nsR + `" xmlns:wp="` + nsWP + `" xmlns:a="` + nsA + `" xmlns:pic="` + nsPic + `">` +
+		`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="8" w:space="4" w:color="` + hexColor(brand.Color) + `"/></w:pBdr></w:pPr>` +
+		logo + docxRun(brand.header(), `<w:b/><w:color w:val="`+hexColor(brand.Color)+`"/>`) + `</w:p></w:hdr>`
+}
+
+// docxFooter 页脚：品牌页脚文字和页码
+func docxFooter(brand Branding, labels map[string]string) string {
+	prefix := ""
+	if brand.Footer != "" {
+		prefix = brand.Footer + " · "
+	}
+	rPr := `<w:color w:val="6B7280"/><w:sz w:val="18"/>`
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:ftr xmlns:w="` + nsW + `"><w:p><w:pPr><w:jc w:val="center"/></w:pPr>` +
+		docxRun(prefix+labels["page_prefix"], rPr) +
+		`<w:r><w:rPr>` + rPr + `</w:rPr><w:fldChar w:fldCharType="begin"/></w:r>` +
+		`<w:r><w:rPr>` + rPr + `</w:rPr><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r>` +
+		`<w:r><w:rPr>` + rPr + `</w:rPr><w:fldChar w:fldCharType="separate"/></w:r>` +
+		docxRun("1", rPr) +
+		`<w:r><w:rPr>` + rPr + `</w:rPr><w:fldChar w:fldCharType="end"/></w:r>` +
+		docxRun(labels["page_suffix"], rPr) + `</w:p></w:ftr>`
+}
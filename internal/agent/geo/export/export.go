@@ -0,0 +1,284 @@
+// Package export 将 GEO 分析报告导出为 Markdown、HTML、PDF、DOCX 和 JSON
+//
+// 报告正文按模板（品牌、页眉页脚、章节顺序、语言）生成 Markdown，Markdown 和 HTML 外层通过
+// 文本模板渲染（可用 GEO_REPORT_TEMPLATES 目录中的同名文件覆盖），PDF 和 DOCX 由渲染后的
+// Markdown 生成，全部为纯 Go 实现，无需网络和外部程序（PDF 和 DOCX 的 Logo 由 LoadLogo 预先下载）
+package export
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// 导出格式
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatPDF      = "pdf"
+	FormatDOCX     = "docx"
+	FormatJSON     = "json"
+)
+
+// ErrUnsupportedFormat 不支持的导出格式
+var ErrUnsupportedFormat = errors.New("不支持的导出格式，可选 md、html、pdf、docx、json")
+
+// contentTypes 各格式的 Content-Type
+var contentTypes = map[string]string{
+	FormatMarkdown: "text/markdown; charset=utf-8",
+	FormatHTML:     "text/html; charset=utf-8",
+	FormatPDF:      "application/pdf",
+	FormatDOCX:     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	FormatJSON:     "application/json; charset=utf-8",
+}
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Branding 报告的品牌信息
+type Branding struct {
+	Name    string `json:"name"`
+	Color   string `json:"color"`              // 主色，#RRGGBB
+	LogoURL string `json:"logo_url,omitempty"` // HTML 直接引用
+	Logo    []byte `json:"-"`                  // LoadLogo 下载的 PNG，嵌入 PDF 和 DOCX 页眉
+	Header  string `json:"header,omitempty"`   // 页眉文字，为空时使用品牌名称
+	Footer  string `json:"footer,omitempty"`
+}
+
+// colorRe 品牌主色格式
+var colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// DefaultBranding 默认品牌信息
+func DefaultBranding() Branding {
+	return Branding{Name: "Peanut GEO", Color: "#2563eb"}
+}
+
+// ValidColor 是否为 #RRGGBB 格式的颜色
+func ValidColor(color string) bool {
+	return colorRe.MatchString(color)
+}
+
+// BrandingFromEnv 读取 GEO_REPORT_BRAND、GEO_REPORT_COLOR、GEO_REPORT_LOGO_URL、GEO_REPORT_HEADER、GEO_REPORT_FOOTER
+func BrandingFromEnv() (Branding, error) {
+	b := DefaultBranding()
+	if v := os.Getenv("GEO_REPORT_BRAND"); v != "" {
+		b.Name = v
+	}
+	if v := os.Getenv("GEO_REPORT_COLOR"); v != "" {
+		if !colorRe.MatchString(v) {
+			return DefaultBranding(), fmt.Errorf("GEO_REPORT_COLOR 应为 #RRGGBB 格式: %s", v)
+		}
+		b.Color = v
+	}
+	b.LogoURL = os.Getenv("GEO_REPORT_LOGO_URL")
+	b.Header = os.Getenv("GEO_REPORT_HEADER")
+	b.Footer = os.Getenv("GEO_REPORT_FOOTER")
+	return b, nil
+}
+
+// header 页眉文字
+func (b Branding) header() string {
+	if b.Header != "" {
+		return b.Header
+	}
+	return b.Name
+}
+
+// rgb 返回主色的 RGB 分量（0-1）
+func (b Branding) rgb() (r, g, bl float64) {
+	var ri, gi, bi int
+	if _, err := fmt.Sscanf(b.Color, "#%02x%02x%02x", &ri, &gi, &bi); err != nil {
+		ri, gi, bi = 0x25, 0x63, 0xeb
+	}
+	return float64(ri) / 255, float64(gi) / 255, float64(bi) / 255
+}
+
+// Template 报告模板，所有导出格式共用
+type Template struct {
+	Brand    Branding `json:"brand"`
+	Sections []string `json:"sections"` // 章节顺序，为空时使用 DefaultSections
+	Language string   `json:"language"` // 报告文字的语言，为空时使用报告的输出语言
+}
+
+// normalize 补全默认值
+func (t Template) normalize() Template {
+	if len(t.Sections) == 0 {
+		t.Sections = DefaultSections
+	}
+	return t
+}
+
+// withLanguage 确定报告文字的语言：模板未指定时使用报告的输出语言，都不支持时使用中文
+func (t Template) withLanguage(report *models.OptimizationReport) Template {
+	if t.Language == "" {
+		t.Language = report.OutputLanguage
+	}
+	if !IsLanguage(t.Language) {
+		t.Language = LanguageZh
+	}
+	return t
+}
+
+// labels 模板语言的报告文字
+func (t Template) labels() map[string]string {
+	return labelsFor(t.Language)
+}
+
+// Document 待导出的分析报告
+type Document struct {
+	ID       int64
+	Report   *models.OptimizationReport
+	Template *Template // 为空时使用导出器的默认模板
+}
+
+// File 导出结果
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Exporter 报告导出器
+type Exporter struct {
+	defaults Template
+	markdown *template.Template
+	html     *htmltemplate.Template
+	now      func() time.Time
+}
+
+// NewExporter 创建导出器，templateDir 中的 report.md.tmpl、report.html.tmpl 覆盖内置模板，为空或文件不存在时使用内置模板
+func NewExporter(brand Branding, templateDir string) (*Exporter, error) {
+	mdSrc, err := loadTemplate(templateDir, "report.md.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	md, err := template.New("report.md").Parse(mdSrc)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Markdown 模板失败: %w", err)
+	}
+
+	htmlSrc, err := loadTemplate(templateDir, "report.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("report.html").Parse(htmlSrc)
+	if err != nil {
+		return nil, fmt.Errorf("解析 HTML 模板失败: %w", err)
+	}
+
+	return &Exporter{defaults: Template{Brand: brand}.normalize(), markdown: md, html: html, now: time.Now}, nil
+}
+
+// loadTemplate 读取模板，优先使用覆盖目录中的文件
+func loadTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("读取模板 %s 失败: %w", name, err)
+		}
+	}
+	data, err := templatesFS.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("读取内置模板 %s 失败: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// ContentType 返回格式的 Content-Type，不支持的格式返回空
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// templateData 模板变量
+type templateData struct {
+	Title       string
+	Brand       Branding
+	Header      string // 页眉文字
+	Language    string
+	Labels      map[string]string // 当前语言的报告文字
+	GeneratedAt time.Time
+	Report      *models.OptimizationReport
+	Markdown    string            // 按模板章节生成的报告正文
+	Body        htmltemplate.HTML // 报告正文的 HTML（仅 HTML 模板）
+}
+
+// Export 按格式导出报告
+func (e *Exporter) Export(doc *Document, format string) (*File, error) {
+	if _, ok := contentTypes[format]; !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	file := &File{
+		Name:        fmt.Sprintf("geo-report-%d.%s", doc.ID, format),
+		ContentType: contentTypes[format],
+	}
+	if format == FormatJSON {
+		data, err := json.MarshalIndent(doc.Report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化报告失败: %w", err)
+		}
+		file.Data = data
+		return file, nil
+	}
+
+	tmpl := e.defaults
+	if doc.Template != nil {
+		tmpl = doc.Template.normalize()
+	}
+	tmpl = tmpl.withLanguage(doc.Report)
+	data := templateData{
+		Title:       doc.Report.Title,
+		Brand:       tmpl.Brand,
+		Header:      tmpl.Brand.header(),
+		Language:    tmpl.Language,
+		Labels:      tmpl.labels(),
+		GeneratedAt: e.now(),
+		Report:      doc.Report,
+		Markdown:    strings.TrimSpace(renderReport(doc.Report, tmpl.Sections, tmpl.Language)),
+	}
+	if data.Title == "" {
+		data.Title = doc.Report.URL
+	}
+
+	var buf bytes.Buffer
+	if format == FormatHTML {
+		data.Body = htmltemplate.HTML(renderHTML(parseMarkdown(data.Markdown)))
+		if err := e.html.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("渲染 HTML 模板失败: %w", err)
+		}
+		file.Data = buf.Bytes()
+		return file, nil
+	}
+
+	if err := e.markdown.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("渲染 Markdown 模板失败: %w", err)
+	}
+
+	var err error
+	switch format {
+	case FormatMarkdown:
+		file.Data = buf.Bytes()
+	case FormatPDF:
+		file.Data, err = renderPDF(parseMarkdown(buf.String()), data.Title, tmpl)
+	case FormatDOCX:
+		file.Data, err = renderDOCX(parseMarkdown(buf.String()), data.Title, tmpl)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
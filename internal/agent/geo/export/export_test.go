@@ -0,0 +1,142 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+func testDocument() *Document {
+	return &Document{
+		ID: 7,
+		Report: &models.OptimizationReport{
+			URL:       "https://example.com/post",
+			Title:     "示例文章 <script>",
+			MainQuery: "geo 优化",
+			ComparisonTable: []models.ComparisonItem{
+				{Dimension: "结构", YourContent: "无标题", AIOverview: "分级标题", Similarity: "低", Difference: "缺少层级"},
+			},
+			OptimizationSuggestions: []models.OptimizationSuggestion{
+				{Category: "内容", Priority: "high", Issue: "补充数据", Suggestion: "加入 **统计** 数据 🚀"},
+			},
+			OptimizedArticle: "# 新文章\n\n正文内容",
+			OverallScore:     72,
+		},
+	}
+}
+
+func TestExporter_Export(t *testing.T) {
+	brand := Branding{Name: "Acme GEO", Color: "#ff6600", Footer: "内部资料"}
+	e, err := NewExporter(brand, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	html, err := e.Export(testDocument(), FormatHTML)
+	if err != nil {
+		t.Fatalf("Export(html) error = %v", err)
+	}
+	body := string(html.Data)
+	for _, want := range []string{"Acme GEO", "#ff6600", "<table>", "<strong>统计</strong>", "&lt;script&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("html contains unescaped title")
+	}
+
+	md, err := e.Export(testDocument(), FormatMarkdown)
+	if err != nil || !strings.Contains(string(md.Data), "内部资料") || md.Name != "geo-report-7.md" {
+		t.Errorf("Export(md) = %q, %v", md.Name, err)
+	}
+
+	pdf, err := e.Export(testDocument(), FormatPDF)
+	if err != nil {
+		t.Fatalf("Export(pdf) error = %v", err)
+	}
+	if !bytes.HasPrefix(pdf.Data, []byte("%PDF-")) || !bytes.HasSuffix(pdf.Data, []byte("%%EOF\n")) {
+		t.Error("pdf has invalid header or trailer")
+	}
+
+	docx, err := e.Export(testDocument(), FormatDOCX)
+	if err != nil {
+		t.Fatalf("Export(docx) error = %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(docx.Data), int64(len(docx.Data)))
+	if err != nil {
+		t.Fatalf("docx is not a zip: %v", err)
+	}
+	var document string
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			document = string(data)
+		}
+	}
+	if !strings.Contains(document, "<w:tbl>") || !strings.Contains(document, "统计") {
+		t.Error("docx document.xml missing table or suggestions")
+	}
+
+	if _, err := e.Export(testDocument(), "txt"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Export(txt) error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestWrapSegs(t *testing.T) {
+	lines := wrapSegs([]seg{{text: "hello world 你好世界"}}, 40, 10)
+	var got []string
+	for _, l := range lines {
+		var sb strings.Builder
+		for _, s := range l {
+			sb.WriteString(s.text)
+		}
+		got = append(got, sb.String())
+	}
+	// 英文按单词换行，中文按字换行，行首空格被去掉
+	want := []string{"hello ", "world 你", "好世界"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapSegs() = %q, want %q", got, want)
+	}
+}
+
+func TestExporter_Template(t *testing.T) {
+	e, err := NewExporter(DefaultBranding(), "")
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	doc := testDocument()
+	doc.Template = &Template{
+		Brand:    Branding{Name: "Client Co", Color: "#111111", Header: "Prepared for Client Co"},
+		Sections: []string{SectionArticle, SectionSummary},
+		Language: LanguageEn,
+	}
+	md, err := e.Export(doc, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Export(md) error = %v", err)
+	}
+	body := string(md.Data)
+	article, summary := strings.Index(body, "## Optimized Article"), strings.Index(body, "## Overview")
+	if article < 0 || summary < 0 || article > summary {
+		t.Errorf("sections not in template order:\n%s", body)
+	}
+	if strings.Contains(body, "Comparison") || !strings.Contains(body, "Client Co") || strings.Contains(body, "Peanut GEO") {
+		t.Errorf("markdown does not follow template:\n%s", body)
+	}
+
+	html, err := e.Export(doc, FormatHTML)
+	if err != nil {
+		t.Fatalf("Export(html) error = %v", err)
+	}
+	if !strings.Contains(string(html.Data), `lang="en"`) || !strings.Contains(string(html.Data), "Prepared for Client Co") {
+		t.Error("html does not use template language or header")
+	}
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// renderHTML 将块渲染为 HTML 片段，所有文本均转义
+func renderHTML(blocks []block) string {
+	var sb strings.Builder
+	for _, b := range blocks {
+		switch b.kind {
+		case blockHeading:
+			fmt.Fprintf(&sb, "<h%d>%s</h%d>\n", b.level, inlineHTML(b.text), b.level)
+		case blockParagraph:
+			fmt.Fprintf(&sb, "<p>%s</p>\n", inlineHTML(b.text))
+		case blockList:
+			tag := "ul"
+			if b.ordered {
+				tag = "ol"
+			}
+			sb.WriteString("<" + tag + ">\n")
+			for _, item := range b.items {
+				fmt.Fprintf(&sb, "<li>%s</li>\n", inlineHTML(item))
+			}
+			sb.WriteString("</" + tag + ">\n")
+		case blockTable:
+			sb.WriteString("<table>\n<thead><tr>")
+			for _, h := range b.header {
+				fmt.Fprintf(&sb, "<th>%s</th>", inlineHTML(h))
+			}
+			sb.WriteString("</tr></thead>\n<tbody>\n")
+			for _, row := range b.rows {
+				sb.WriteString("<tr>")
+				for _, cell := range row {
+					fmt.Fprintf(&sb, "<td>%s</td>", inlineHTML(cell))
+				}
+				sb.WriteString("</tr>\n")
+			}
+			sb.WriteString("</tbody>\n</table>\n")
+		case blockCode:
+			fmt.Fprintf(&sb, "<pre><code>%s</code></pre>\n", html.EscapeString(b.text))
+		case blockQuote:
+			fmt.Fprintf(&sb, "<blockquote>%s</blockquote>\n", inlineHTML(b.text))
+		case blockRule:
+			sb.WriteString("<hr>\n")
+		}
+	}
+	return sb.String()
+}
+
+// inlineHTML 渲染行内标记，只保留 http(s) 链接
+func inlineHTML(s string) string {
+	var sb strings.Builder
+	for _, sp := range parseInline(s) {
+		text := html.EscapeString(sp.text)
+		switch {
+		case sp.bold:
+			sb.WriteString("<strong>" + text + "</strong>")
+		case sp.code:
+			sb.WriteString("<code>" + text + "</code>")
+		case strings.HasPrefix(sp.href, "http://") || strings.HasPrefix(sp.href, "https://"):
+			fmt.Fprintf(&sb, `<a href="%s">%s</a>`, html.EscapeString(sp.href), text)
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
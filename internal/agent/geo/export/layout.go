@@ -0,0 +1,155 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/parser"
+)
+
+// 报告章节
+const (
+	SectionSummary      = "summary"       // 基本信息
+	SectionComparison   = "comparison"    // 对比分析表格
+	SectionContentGaps  = "content_gaps"  // 内容差距
+	SectionCompetitors  = "competitors"   // 竞品引用分析
+	SectionSERPFeatures = "serp_features" // 搜索结果页模块
+	SectionSuggestions  = "suggestions"   // 优化建议（未能提取时为报告原文）
+	SectionArticle      = "article"       // 优化后的文章
+)
+
+// DefaultSections 默认章节顺序
+var DefaultSections = []string{
+	SectionSummary, SectionComparison, SectionContentGaps, SectionCompetitors,
+	SectionSERPFeatures, SectionSuggestions, SectionArticle,
+}
+
+// 报告语言
+const (
+	LanguageZh = i18n.Zh
+	LanguageEn = i18n.En
+)
+
+// IsSection 是否为支持的章节
+func IsSection(s string) bool {
+	for _, section := range DefaultSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLanguage 是否为支持的报告语言
+func IsLanguage(lang string) bool {
+	return i18n.Supported(lang)
+}
+
+// labelsFor 返回语言的报告文字，来自 i18n 消息目录
+func labelsFor(lang string) map[string]string {
+	return i18n.Catalog(lang)
+}
+
+// renderReport 按章节顺序将报告渲染为 Markdown，没有内容的章节被跳过
+func renderReport(report *models.OptimizationReport, sections []string, lang string) string {
+	l := labelsFor(lang)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", l["title"])
+
+	for _, section := range sections {
+		switch section {
+		case SectionSummary:
+			fmt.Fprintf(&sb, "## %s\n\n", l["summary"])
+			fmt.Fprintf(&sb, "- **%s**: %s\n", l["url"], report.URL)
+			fmt.Fprintf(&sb, "- **%s**: %s\n", l["page_title"], report.Title)
+			fmt.Fprintf(&sb, "- **%s**: %s\n", l["main_query"], report.MainQuery)
+			fmt.Fprintf(&sb, "- **%s**: %d/100\n", l["overall_score"], report.OverallScore)
+			fmt.Fprintf(&sb, "- **%s**: %s\n\n", l["generated_at"], report.Timestamp.Format("2006-01-02 15:04:05"))
+
+		case SectionComparison:
+			if len(report.ComparisonTable) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "## %s\n\n", l["comparison"])
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", l["dimension"], l["your_content"], l["ai_overview"], l["similarity"], l["difference"])
+			sb.WriteString("|------|------|------|------|------|\n")
+			for _, item := range report.ComparisonTable {
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", item.Dimension, item.YourContent, item.AIOverview, item.Similarity, item.Difference)
+			}
+			sb.WriteString("\n")
+
+		case SectionContentGaps:
+			writeList(&sb, "## "+l["content_gaps"], report.ContentGaps, true)
+
+		case SectionCompetitors:
+			ca := report.CompetitorAnalysis
+			if ca == nil || len(ca.Sources)+len(ca.MissingFacts)+len(ca.MissingFormats)+len(ca.MissingEntities) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "## %s\n\n", l["competitors"])
+			writeList(&sb, "### "+l["missing_facts"], ca.MissingFacts, false)
+			writeList(&sb, "### "+l["missing_formats"], ca.MissingFormats, false)
+			writeList(&sb, "### "+l["missing_entities"], ca.MissingEntities, false)
+			var sources []string
+			for _, s := range ca.Sources {
+				title := s.Title
+				if title == "" {
+					title = s.URL
+				}
+				sources = append(sources, fmt.Sprintf("[%s](%s)", title, s.URL))
+			}
+			writeList(&sb, "### "+l["sources"], sources, false)
+
+		case SectionSERPFeatures:
+			f := report.SERPFeatures
+			if f == nil || len(f.PeopleAlsoAsk)+len(f.RelatedSearches) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "## %s\n\n", l["serp_features"])
+			writeList(&sb, "### "+l["people_also_ask"], f.PeopleAlsoAsk, false)
+			writeList(&sb, "### "+l["related_searches"], f.RelatedSearches, false)
+
+		case SectionSuggestions:
+			if len(report.OptimizationSuggestions) > 0 {
+				fmt.Fprintf(&sb, "## %s\n\n", l["suggestions"])
+				for _, priority := range []string{"high", "medium", "low"} {
+					var items []string
+					for _, s := range report.OptimizationSuggestions {
+						if s.Priority == priority {
+							items = append(items, s.Suggestion)
+						}
+					}
+					writeList(&sb, "### "+l["priority_"+priority], items, true)
+				}
+			} else if len(report.ComparisonTable) == 0 && strings.TrimSpace(report.OptimizationReport) != "" {
+				// 未能提取出对比表格和优化建议时保留完整的报告原文
+				fmt.Fprintf(&sb, "## %s\n\n%s\n\n", l["report"], strings.TrimSpace(parser.DemoteHeadings(report.OptimizationReport, 2)))
+			}
+
+		case SectionArticle:
+			if strings.TrimSpace(report.OptimizedArticle) == "" {
+				continue
+			}
+			fmt.Fprintf(&sb, "## %s\n\n%s\n\n", l["article"], strings.TrimSpace(parser.DemoteHeadings(report.OptimizedArticle, 2)))
+		}
+	}
+	return sb.String()
+}
+
+// writeList 写入带标题的列表，列表为空时不输出
+func writeList(sb *strings.Builder, heading string, items []string, ordered bool) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString(heading + "\n\n")
+	for i, item := range items {
+		if ordered {
+			fmt.Fprintf(sb, "%d. %s\n", i+1, item)
+		} else {
+			fmt.Fprintf(sb, "- %s\n", item)
+		}
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // 注册 GIF 解码
+	_ "image/jpeg" // 注册 JPEG 解码
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+)
+
+// 页眉 Logo 的显示尺寸（pt），宽度按比例缩放且不超过上限
+const (
+	logoHeight   = 16.0
+	logoMaxWidth = 120.0
+)
+
+// maxLogoBytes Logo 图片的大小上限
+const maxLogoBytes = 1 << 20
+
+// ErrInvalidLogo Logo 无法获取或不是支持的图片
+var ErrInvalidLogo = errors.New("无效的 Logo，应为 1MB 以内的 PNG、JPEG 或 GIF 图片")
+
+// LoadLogo 下载 Logo 图片并转换为 PNG
+//
+// 在保存模板或启动时调用一次，结果保存在 Branding.Logo 中，导出 PDF 和 DOCX 时直接嵌入，无需网络
+func LoadLogo(ctx context.Context, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("%w: 仅支持 http 和 https 地址", ErrInvalidLogo)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	resp, err := transport.NewClient(10 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %d", ErrInvalidLogo, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	if len(data) > maxLogoBytes {
+		return nil, ErrInvalidLogo
+	}
+	return normalizeLogo(data)
+}
+
+// normalizeLogo 解码图片并重新编码为 PNG
+func normalizeLogo(data []byte) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLogo, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeLogo 解码品牌 Logo 并计算页眉中的显示尺寸（pt），没有 Logo 时返回 nil
+func decodeLogo(data []byte) (img image.Image, width, height float64, err error) {
+	if len(data) == 0 {
+		return nil, 0, 0, nil
+	}
+	img, err = png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("解析 Logo 失败: %w", err)
+	}
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return nil, 0, 0, nil
+	}
+	height = logoHeight
+	width = height * float64(size.X) / float64(size.Y)
+	if width > logoMaxWidth {
+		width, height = logoMaxWidth, logoMaxWidth*float64(size.Y)/float64(size.X)
+	}
+	return img, width, height, nil
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testLogo 生成 w×h 的图片，transparent 为 true 时左半部分透明
+func testLogo(w, h int, transparent bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 0xff, G: 0x66, A: 0xff}
+			if transparent && x < w/2 {
+				c.A = 0
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestLoadLogo 测试下载 Logo 并转换为 PNG，非图片、非 200 和非 http 地址返回 ErrInvalidLogo
+func TestLoadLogo(t *testing.T) {
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, testLogo(40, 20, false), nil); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.jpg":
+			_, _ = w.Write(jpg.Bytes())
+		case "/logo.txt":
+			_, _ = io.WriteString(w, "not an image")
+		case "/large.png":
+			_, _ = w.Write(make([]byte, maxLogoBytes+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	data, err := LoadLogo(context.Background(), srv.URL+"/logo.jpg")
+	if err != nil {
+		t.Fatalf("LoadLogo() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadLogo() is not png: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 40 || size.Y != 20 {
+		t.Errorf("logo size = %v, want 40x20", size)
+	}
+
+	for _, url := range []string{srv.URL + "/logo.txt", srv.URL + "/large.png", srv.URL + "/missing.png", "file:///etc/hostname"} {
+		if _, err := LoadLogo(context.Background(), url); !errors.Is(err, ErrInvalidLogo) {
+			t.Errorf("LoadLogo(%s) error = %v, want ErrInvalidLogo", url, err)
+		}
+	}
+}
+
+// TestDecodeLogo 测试 Logo 按高度缩放，过宽时按最大宽度缩放
+func TestDecodeLogo(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		wantW, wantH float64
+	}{
+		{name: "按高度缩放", w: 64, h: 32, wantW: 32, wantH: logoHeight},
+		{name: "过宽", w: 600, h: 40, wantW: logoMaxWidth, wantH: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, testLogo(tt.w, tt.h, false)); err != nil {
+				t.Fatal(err)
+			}
+			_, w, h, err := decodeLogo(buf.Bytes())
+			if err != nil || w != tt.wantW || h != tt.wantH {
+				t.Errorf("decodeLogo() = %v, %v, %v, want %v, %v", w, h, err, tt.wantW, tt.wantH)
+			}
+		})
+	}
+
+	if img, _, _, err := decodeLogo(nil); img != nil || err != nil {
+		t.Errorf("decodeLogo(nil) = %v, %v, want nil", img, err)
+	}
+}
+
+// TestExporter_Logo 测试 PDF 和 DOCX 页眉嵌入 Logo
+func TestExporter_Logo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testLogo(40, 20, true)); err != nil {
+		t.Fatal(err)
+	}
+	brand := DefaultBranding()
+	brand.Logo = buf.Bytes()
+	e, err := NewExporter(brand, "")
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	pdf, err := e.Export(testDocument(), FormatPDF)
+	if err != nil {
+		t.Fatalf("Export(pdf) error = %v", err)
+	}
+	for _, want := range []string{"/XObject << /Im1", "/Subtype /Image /Width 40 /Height 20", "/SMask", "/DeviceGray"} {
+		if !bytes.Contains(pdf.Data, []byte(want)) {
+			t.Errorf("pdf missing %q", want)
+		}
+	}
+
+	docx, err := e.Export(testDocument(), FormatDOCX)
+	if err != nil {
+		t.Fatalf("Export(docx) error = %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(docx.Data), int64(len(docx.Data)))
+	if err != nil {
+		t.Fatalf("docx is not a zip: %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	if files["word/media/logo.png"] != string(brand.Logo) {
+		t.Error("docx missing word/media/logo.png")
+	}
+	if !strings.Contains(files["word/_rels/header1.xml.rels"], `Target="media/logo.png"`) ||
+		!strings.Contains(files["word/header1.xml"], `r:embed="rIdLogo"`) {
+		t.Error("docx header does not reference logo")
+	}
+	if !strings.Contains(files["[Content_Types].xml"], `Extension="png"`) {
+		t.Error("docx content types missing png")
+	}
+
+	// 没有 Logo 时不嵌入图片
+	e, err = NewExporter(DefaultBranding(), "")
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+	if pdf, err = e.Export(testDocument(), FormatPDF); err != nil || bytes.Contains(pdf.Data, []byte("/XObject")) {
+		t.Errorf("Export(pdf) without logo = %v, want no image", err)
+	}
+}
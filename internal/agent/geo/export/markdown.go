@@ -0,0 +1,176 @@
+package export
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blockKind Markdown 块类型
+type blockKind int
+
+const (
+	blockHeading blockKind = iota
+	blockParagraph
+	blockList
+	blockTable
+	blockCode
+	blockQuote
+	blockRule
+)
+
+// block 报告中的一个 Markdown 块，HTML、DOCX、PDF 都从块渲染
+type block struct {
+	kind    blockKind
+	level   int        // 标题级别
+	text    string     // 标题、段落、代码、引用的内容
+	ordered bool       // 有序列表
+	items   []string   // 列表项
+	header  []string   // 表头
+	rows    [][]string // 表格数据行
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletRe  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	orderedRe = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	ruleRe    = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	tableSep  = regexp.MustCompile(`^\|?\s*:?-{2,}:?\s*(\|\s*:?-{2,}:?\s*)*\|?$`)
+)
+
+// parseMarkdown 将 Markdown 解析为块，只支持报告中用到的语法
+func parseMarkdown(src string) []block {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var blocks []block
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, block{kind: blockParagraph, text: strings.Join(para, " ")})
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, block{kind: blockCode, text: strings.Join(code, "\n")})
+
+		case headingRe.MatchString(line):
+			flush()
+			m := headingRe.FindStringSubmatch(line)
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), text: strings.TrimRight(m[2], " #")})
+
+		case ruleRe.MatchString(line):
+			flush()
+			blocks = append(blocks, block{kind: blockRule})
+
+		case strings.HasPrefix(line, "|") && i+1 < len(lines) && tableSep.MatchString(strings.TrimSpace(lines[i+1])):
+			flush()
+			b := block{kind: blockTable, header: splitRow(line)}
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				b.rows = append(b.rows, splitRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			blocks = append(blocks, b)
+
+		case bulletRe.MatchString(line) || orderedRe.MatchString(line):
+			flush()
+			b := block{kind: blockList, ordered: orderedRe.MatchString(line)}
+			re := bulletRe
+			if b.ordered {
+				re = orderedRe
+			}
+			for ; i < len(lines); i++ {
+				item := strings.TrimSpace(lines[i])
+				m := re.FindStringSubmatch(item)
+				if m == nil {
+					// 缩进的续行并入上一项
+					if item != "" && len(b.items) > 0 && strings.HasPrefix(lines[i], " ") {
+						b.items[len(b.items)-1] += " " + item
+						continue
+					}
+					break
+				}
+				b.items = append(b.items, m[1])
+			}
+			i--
+			blocks = append(blocks, b)
+
+		case strings.HasPrefix(line, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+			i--
+			blocks = append(blocks, block{kind: blockQuote, text: strings.Join(quote, " ")})
+
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// splitRow 拆分表格行的单元格
+func splitRow(line string) []string {
+	cells := strings.Split(strings.Trim(line, "|"), "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+// span 行内文本片段
+type span struct {
+	text string
+	bold bool
+	code bool
+	href string
+}
+
+var inlineRe = regexp.MustCompile("\\*\\*(.+?)\\*\\*|`([^`]+)`|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)")
+
+// parseInline 解析行内的加粗、代码和链接
+func parseInline(s string) []span {
+	var spans []span
+	last := 0
+	for _, m := range inlineRe.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > last {
+			spans = append(spans, span{text: s[last:m[0]]})
+		}
+		switch {
+		case m[2] >= 0:
+			spans = append(spans, span{text: s[m[2]:m[3]], bold: true})
+		case m[4] >= 0:
+			spans = append(spans, span{text: s[m[4]:m[5]], code: true})
+		default:
+			spans = append(spans, span{text: s[m[6]:m[7]], href: s[m[8]:m[9]]})
+		}
+		last = m[1]
+	}
+	if last < len(s) {
+		spans = append(spans, span{text: s[last:]})
+	}
+	return spans
+}
+
+// plainText 去掉行内标记后的文本
+func plainText(s string) string {
+	var sb strings.Builder
+	for _, sp := range parseInline(s) {
+		sb.WriteString(sp.text)
+	}
+	return sb.String()
+}
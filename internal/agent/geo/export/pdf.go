@@ -0,0 +1,544 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// PDF 页面尺寸（A4，单位 pt）
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 56.0
+	pdfContentW   = pdfPageWidth - 2*pdfMargin
+	pdfBodySize   = 10.5
+	pdfLineHeight = 1.6
+	pdfListIndent = 14.0
+	pdfCellPad    = 4.0
+	pdfTableSize  = 9.0
+	pdfCodeSize   = 9.0
+	pdfFooterSize = 8.0
+)
+
+// pdfHeadingSizes 各级标题字号，四级及以下与三级相同
+var pdfHeadingSizes = map[int]float64{1: 20, 2: 15, 3: 12.5}
+
+// rgbColor PDF 颜色
+type rgbColor struct{ r, g, b float64 }
+
+var (
+	pdfTextColor  = rgbColor{0.12, 0.16, 0.22}
+	pdfMutedColor = rgbColor{0.42, 0.45, 0.50}
+	pdfBorder     = rgbColor{0.82, 0.84, 0.86}
+	pdfCodeFill   = rgbColor{0.95, 0.96, 0.96}
+)
+
+// seg 一行中样式相同的文本片段
+type seg struct {
+	text string
+	bold bool
+	code bool
+	link bool
+}
+
+// pdfWriter 排版报告并生成 PDF
+//
+// 使用 Adobe 标准 CJK 字体 STSong-Light（UniGB-UTF16-H 编码），阅读器自带字体，
+// 无需嵌入字体文件；不在 BMP 内的字符（如 emoji）会被去掉
+type pdfWriter struct {
+	brand  Branding
+	labels map[string]string
+	title  string
+	logo   image.Image // 页眉 Logo，为空时只显示页眉文字
+	logoW  float64
+	logoH  float64
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	y      float64 // 当前位置到页面顶部的距离
+}
+
+// renderPDF 将块渲染为 PDF 文档
+func renderPDF(blocks []block, title string, t Template) ([]byte, error) {
+	w := &pdfWriter{brand: t.Brand, labels: t.labels(), title: title}
+	var err error
+	if w.logo, w.logoW, w.logoH, err = decodeLogo(t.Brand.Logo); err != nil {
+		return nil, err
+	}
+	w.newPage()
+	for _, b := range blocks {
+		w.block(b)
+	}
+	w.footers()
+	return w.bytes()
+}
+
+// brandColor 品牌主色
+func (w *pdfWriter) brandColor() rgbColor {
+	r, g, b := w.brand.rgb()
+	return rgbColor{r, g, b}
+}
+
+// tint 主色与白色混合，ratio 为主色占比
+func (w *pdfWriter) tint(ratio float64) rgbColor {
+	c := w.brandColor()
+	mix := func(v float64) float64 { return v*ratio + (1 - ratio) }
+	return rgbColor{mix(c.r), mix(c.g), mix(c.b)}
+}
+
+// newPage 新建页面并绘制页眉，有 Logo 时页眉文字排在 Logo 右侧
+func (w *pdfWriter) newPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+
+	top := pdfMargin - 20
+	x := pdfMargin
+	if w.logo != nil {
+		// Logo 底部略低于页眉文字基线
+		fmt.Fprintf(w.page, "q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n", w.logoW, w.logoH, x, pdfPageHeight-top-2)
+		x += w.logoW + 6
+	}
+	w.text(x, top, 9, []seg{{text: w.brand.header(), bold: true}}, w.brandColor())
+	w.line(pdfMargin, top+6, pdfPageWidth-pdfMargin, top+6, w.brandColor(), 0.8)
+	w.y = pdfMargin + 8
+}
+
+// ensure 剩余空间不足 h 时换页，返回是否换页
+func (w *pdfWriter) ensure(h float64) bool {
+	if w.y+h > pdfPageHeight-pdfMargin {
+		w.newPage()
+		return true
+	}
+	return false
+}
+
+// block 渲染一个块
+func (w *pdfWriter) block(b block) {
+	switch b.kind {
+	case blockHeading:
+		size, ok := pdfHeadingSizes[b.level]
+		if !ok {
+			size = pdfHeadingSizes[3]
+		}
+		lh := size * 1.3
+		lines := wrapSegs([]seg{{text: plainText(b.text), bold: true}}, pdfContentW, size)
+		if w.y > pdfMargin+8 {
+			w.y += size * 0.6
+		}
+		// 标题至少与下一行正文在同一页
+		w.ensure(float64(len(lines))*lh + pdfBodySize*pdfLineHeight)
+		for _, l := range lines {
+			w.text(pdfMargin, w.y+size, size, l, w.brandColor())
+			w.y += lh
+		}
+		if b.level == 1 {
+			w.line(pdfMargin, w.y+2, pdfPageWidth-pdfMargin, w.y+2, w.tint(0.3), 0.6)
+			w.y += 6
+		}
+		w.y += 4
+
+	case blockParagraph:
+		w.paragraph(pdfMargin, pdfContentW, inlineSegs(b.text), pdfTextColor)
+		w.y += 6
+
+	case blockList:
+		for i, item := range b.items {
+			marker := "·"
+			if b.ordered {
+				marker = fmt.Sprintf("%d.", i+1)
+			}
+			lh := pdfBodySize * pdfLineHeight
+			w.ensure(lh)
+			w.text(pdfMargin+2, w.y+pdfBodySize, pdfBodySize, []seg{{text: marker}}, pdfTextColor)
+			w.paragraph(pdfMargin+pdfListIndent, pdfContentW-pdfListIndent, inlineSegs(item), pdfTextColor)
+			w.y += 2
+		}
+		w.y += 4
+
+	case blockTable:
+		w.table(b)
+		w.y += 8
+
+	case blockCode:
+		lh := pdfCodeSize * 1.45
+		w.y += 2
+		for _, raw := range strings.Split(b.text, "\n") {
+			for _, l := range wrapSegs([]seg{{text: raw, code: true}}, pdfContentW-12, pdfCodeSize) {
+				w.ensure(lh)
+				w.rect(pdfMargin, w.y, pdfContentW, lh, &pdfCodeFill, nil)
+				w.text(pdfMargin+6, w.y+pdfCodeSize+1.5, pdfCodeSize, l, pdfTextColor)
+				w.y += lh
+			}
+		}
+		w.y += 8
+
+	case blockQuote:
+		lh := pdfBodySize * pdfLineHeight
+		brand := w.brandColor()
+		for _, l := range wrapSegs(inlineSegs(b.text), pdfContentW-12, pdfBodySize) {
+			w.ensure(lh)
+			w.rect(pdfMargin, w.y, 2.5, lh, &brand, nil)
+			w.text(pdfMargin+12, w.y+pdfBodySize+2, pdfBodySize, l, pdfMutedColor)
+			w.y += lh
+		}
+		w.y += 6
+
+	case blockRule:
+		w.ensure(12)
+		w.line(pdfMargin, w.y+6, pdfPageWidth-pdfMargin, w.y+6, pdfBorder, 0.6)
+		w.y += 12
+	}
+}
+
+// paragraph 自动换行绘制文本
+func (w *pdfWriter) paragraph(x, width float64, segs []seg, color rgbColor) {
+	lh := pdfBodySize * pdfLineHeight
+	for _, l := range wrapSegs(segs, width, pdfBodySize) {
+		w.ensure(lh)
+		w.text(x, w.y+pdfBodySize+2, pdfBodySize, l, color)
+		w.y += lh
+	}
+}
+
+// table 绘制等宽列表格，换页时重复表头
+func (w *pdfWriter) table(b block) {
+	cols := len(b.header)
+	colW := pdfContentW / float64(cols)
+
+	header := make([]string, cols)
+	for i, h := range b.header {
+		header[i] = "**" + plainText(h) + "**"
+	}
+	w.ensure(w.rowHeight(header, colW) + pdfTableSize*1.45 + 2*pdfCellPad)
+	w.tableRow(header, colW, true)
+	for _, row := range b.rows {
+		row = normalizeRow(row, cols)
+		if w.ensure(w.rowHeight(row, colW)) {
+			w.tableRow(header, colW, true)
+		}
+		w.tableRow(row, colW, false)
+	}
+}
+
+// rowHeight 计算行高，单行最高不超过一页
+func (w *pdfWriter) rowHeight(cells []string, colW float64) float64 {
+	lines := 1
+	for _, c := range cells {
+		lines = max(lines, len(wrapSegs(inlineSegs(c), colW-2*pdfCellPad, pdfTableSize)))
+	}
+	return min(float64(lines)*pdfTableSize*1.45+2*pdfCellPad, pdfPageHeight-2*pdfMargin-40)
+}
+
+// tableRow 绘制表格行，超出行高的内容被截断
+func (w *pdfWriter) tableRow(cells []string, colW float64, header bool) {
+	h := w.rowHeight(cells, colW)
+	lh := pdfTableSize * 1.45
+	for i, c := range cells {
+		x := pdfMargin + float64(i)*colW
+		if header {
+			fill := w.tint(0.12)
+			w.rect(x, w.y, colW, h, &fill, &pdfBorder)
+		} else {
+			w.rect(x, w.y, colW, h, nil, &pdfBorder)
+		}
+		y := w.y + pdfCellPad
+		for _, l := range wrapSegs(inlineSegs(c), colW-2*pdfCellPad, pdfTableSize) {
+			if y+lh > w.y+h {
+				break
+			}
+			w.text(x+pdfCellPad, y+pdfTableSize+1, pdfTableSize, l, pdfTextColor)
+			y += lh
+		}
+	}
+	w.y += h
+}
+
+// footers 在每页底部绘制页脚和页码
+func (w *pdfWriter) footers() {
+	for i, p := range w.pages {
+		w.page = p
+		label := fmt.Sprintf(w.labels["page"], i+1, len(w.pages))
+		if w.brand.Footer != "" {
+			label = w.brand.Footer + " · " + label
+		}
+		x := (pdfPageWidth - textWidth(label, pdfFooterSize)) / 2
+		w.text(x, pdfPageHeight-pdfMargin+28, pdfFooterSize, []seg{{text: label}}, pdfMutedColor)
+	}
+}
+
+// text 在 (x, baseline) 处绘制一行文本，y 为到页面顶部的距离
+func (w *pdfWriter) text(x, baseline, size float64, segs []seg, color rgbColor) {
+	brand := w.brandColor()
+	for _, s := range segs {
+		if s.text == "" {
+			continue
+		}
+		c := color
+		if s.link {
+			c = brand
+		}
+		fmt.Fprintf(w.page, "q BT /F1 %.2f Tf %.3f %.3f %.3f rg ", size, c.r, c.g, c.b)
+		if s.bold {
+			fmt.Fprintf(w.page, "2 Tr %.2f w %.3f %.3f %.3f RG ", size*0.035, c.r, c.g, c.b)
+		}
+		fmt.Fprintf(w.page, "%.2f %.2f Td <%s> Tj ET Q\n", x, pdfPageHeight-baseline, hexUTF16(s.text))
+		x += textWidth(s.text, size)
+	}
+}
+
+// line 绘制直线
+func (w *pdfWriter) line(x1, y1, x2, y2 float64, c rgbColor, width float64) {
+	fmt.Fprintf(w.page, "q %.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S Q\n",
+		c.r, c.g, c.b, width, x1, pdfPageHeight-y1, x2, pdfPageHeight-y2)
+}
+
+// rect 绘制矩形，fill、stroke 为空时不填充或不描边
+func (w *pdfWriter) rect(x, y, width, height float64, fill, stroke *rgbColor) {
+	op := "S"
+	w.page.WriteString("q ")
+	if fill != nil {
+		fmt.Fprintf(w.page, "%.3f %.3f %.3f rg ", fill.r, fill.g, fill.b)
+		op = "f"
+	}
+	if stroke != nil {
+		fmt.Fprintf(w.page, "%.3f %.3f %.3f RG 0.5 w ", stroke.r, stroke.g, stroke.b)
+		if fill != nil {
+			op = "B"
+		}
+	}
+	fmt.Fprintf(w.page, "%.2f %.2f %.2f %.2f re %s Q\n", x, pdfPageHeight-y-height, width, height, op)
+}
+
+// bytes 组装 PDF 对象、交叉引用表和文件尾
+func (w *pdfWriter) bytes() ([]byte, error) {
+	// 对象编号：1 Catalog，2 Pages，3 Type0 字体，4 CIDFont，5 FontDescriptor，6 Info，之后每页两个对象，
+	// 最后是 Logo 图片及其透明度蒙版
+	const firstPage = 7
+	logoObj := firstPage + 2*len(w.pages)
+	resources := "<< /Font << /F1 3 0 R >> >>"
+	if w.logo != nil {
+		resources = fmt.Sprintf("<< /Font << /F1 3 0 R >> /XObject << /Im1 %d 0 R >> >>", logoObj)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // Pages 在下方生成
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light-UniGB-UTF16-H /Encoding /UniGB-UTF16-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> /FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+		fmt.Sprintf("<< /Title <%s> /Author <%s> /Producer (peanut) >>", "FEFF"+hexUTF16(w.title), "FEFF"+hexUTF16(w.brand.Name)),
+	}
+
+	kids := make([]string, len(w.pages))
+	for i, p := range w.pages {
+		pageObj := firstPage + 2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj)
+
+		z, err := deflate(p.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, resources, pageObj+1),
+			fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(z), z),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages))
+	if w.logo != nil {
+		logo, err := pdfImageObjects(w.logo, logoObj)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, logo...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// pdfImageObjects 将图片转换为 RGB 图片对象（编号 obj），非不透明图片另加灰度蒙版对象（编号 obj+1）
+func pdfImageObjects(img image.Image, obj int) ([]string, error) {
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	alpha := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// 预乘透明度的颜色还原为原色
+			r, g, b, a := img.At(x, y).RGBA()
+			if a > 0 && a < 0xffff {
+				r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+			}
+			rgb = append(rgb, byte(r>>8), byte(g>>8), byte(b>>8))
+			alpha = append(alpha, byte(a>>8))
+		}
+	}
+
+	z, err := deflate(rgb)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /BitsPerComponent 8 /Filter /FlateDecode", bounds.Dx(), bounds.Dy())
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return []string{fmt.Sprintf("<< %s /ColorSpace /DeviceRGB /Length %d >>\nstream\n%s\nendstream", header, len(z), z)}, nil
+	}
+
+	mask, err := deflate(alpha)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("<< %s /ColorSpace /DeviceRGB /SMask %d 0 R /Length %d >>\nstream\n%s\nendstream", header, obj+1, len(z), z),
+		fmt.Sprintf("<< %s /ColorSpace /DeviceGray /Length %d >>\nstream\n%s\nendstream", header, len(mask), mask),
+	}, nil
+}
+
+// deflate 使用 zlib 压缩流内容
+func deflate(data []byte) ([]byte, error) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return z.Bytes(), nil
+}
+
+// inlineSegs 将行内标记转换为文本片段
+func inlineSegs(s string) []seg {
+	var segs []seg
+	for _, sp := range parseInline(s) {
+		segs = append(segs, seg{text: sp.text, bold: sp.bold, code: sp.code, link: sp.href != ""})
+	}
+	return segs
+}
+
+// sanitizePDF 去掉控制字符和 BMP 以外的字符，制表符转为空格
+func sanitizePDF(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r > 0xFFFF, unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// runeWidth 字符宽度（em），ASCII 为半角，其余按全角计算
+func runeWidth(r rune) float64 {
+	if r < 0x80 {
+		return 0.5
+	}
+	return 1
+}
+
+// textWidth 文本宽度（pt）
+func textWidth(s string, size float64) float64 {
+	var w float64
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w * size
+}
+
+// hexUTF16 将文本编码为 UTF-16BE 十六进制串
+func hexUTF16(s string) string {
+	var sb strings.Builder
+	for _, u := range utf16.Encode([]rune(sanitizePDF(s))) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	return sb.String()
+}
+
+// wrapSegs 按宽度换行，ASCII 单词不拆开（超过一行时按字符拆分），中文按字换行
+func wrapSegs(segs []seg, width, size float64) [][]seg {
+	var lines [][]seg
+	var cur []seg
+	var curW float64
+
+	push := func(s seg, text string, w float64) {
+		if n := len(cur); n > 0 && cur[n-1].bold == s.bold && cur[n-1].code == s.code && cur[n-1].link == s.link {
+			cur[n-1].text += text
+		} else {
+			s.text = text
+			cur = append(cur, s)
+		}
+		curW += w
+	}
+	breakLine := func() {
+		lines = append(lines, cur)
+		cur, curW = nil, 0
+	}
+
+	for _, s := range segs {
+		for _, word := range splitWords(sanitizePDF(s.text)) {
+			ww := textWidth(word, size)
+			if curW+ww > width && curW > 0 {
+				breakLine()
+				if word == " " {
+					continue
+				}
+			}
+			if ww <= width {
+				push(s, word, ww)
+				continue
+			}
+			for _, r := range word {
+				rw := runeWidth(r) * size
+				if curW+rw > width && curW > 0 {
+					breakLine()
+				}
+				push(s, string(r), rw)
+			}
+		}
+	}
+	if len(cur) > 0 || len(lines) == 0 {
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// splitWords 拆分为 ASCII 单词、单个空格和单个非 ASCII 字符
+func splitWords(s string) []string {
+	var words []string
+	start := -1
+	for i, r := range s {
+		if r < 0x80 && r != ' ' {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, s[start:i])
+			start = -1
+		}
+		words = append(words, string(r))
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
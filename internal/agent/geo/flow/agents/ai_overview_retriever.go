@@ -20,6 +20,7 @@ import (
 
 	"github.com/solariswu/peanut/internal/agent/geo/llm"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
 )
 
 // AIOverviewResult AI 摘要结果
@@ -33,9 +34,9 @@ type AIOverviewResult struct {
 
 // loadAIOOverviewRetrieverPrompt 加载 prompt
 func loadAIOOverviewRetrieverPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("ai_overview_retriever")
+	sysPrompt, err := GetPromptTemplate(ctx, "ai_overview_retriever")
 	if err != nil {
-		sysPrompt = defaultAIOOverviewRetrieverPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
@@ -51,28 +52,13 @@ func loadAIOOverviewRetrieverPrompt(ctx context.Context, state *models.FlowState
 	return promptTemp.Format(ctx, variables)
 }
 
-const defaultAIOOverviewRetrieverPrompt = `你是 AI 搜索引擎摘要专家。
-
-## 任务
-1. 基于主查询和关键词，使用 get_ai_overview 工具获取 AI 摘要
-2. 分析摘要的内容结构和特点
-3. 提取关键信息点
-
-## 输出格式
-{
-  "query": "查询词",
-  "summary": "AI摘要内容",
-  "sources": ["来源1", "来源2"],
-  "key_points": ["要点1", "要点2"],
-  "content_structure": "摘要结构特点"
-}`
-
 // routerAIOverviewRetriever 路由函数
 func routerAIOverviewRetriever(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
-	result := parseAIOverviewResult(input.Content)
+	result := parseAIOverviewResult(ctx, input.Content)
 
 	state.AIOverview = result.Summary
 	state.Sources = result.Sources
+	state.ApplySERPFeatures(tools.SERPFeaturesFromContext(ctx))
 	state.Step = 4
 
 	// 发送进度回调
@@ -80,15 +66,17 @@ func routerAIOverviewRetriever(ctx context.Context, input *schema.Message, state
 		state.OnProgress(4, state.TotalSteps, "AI摘要获取", "处理完成")
 	}
 
-	state.Goto = AgentQuerySummarizer
+	state.Goto = AgentCitationAnalyzer
 	return state.Goto, nil
 }
 
-func parseAIOverviewResult(content string) *AIOverviewResult {
+func parseAIOverviewResult(ctx context.Context, content string) *AIOverviewResult {
 	result := &AIOverviewResult{}
-	if err := json.Unmarshal([]byte(content), &result); err == nil {
+	err := json.Unmarshal([]byte(content), &result)
+	if err == nil {
 		return result
 	}
+	recordParseFailure(ctx, AgentAIOverviewRetriever, err)
 	result.Summary = content
 	return result
 }
@@ -97,7 +85,7 @@ func parseAIOverviewResult(content string) *AIOverviewResult {
 func NewAIOverviewRetrieverAgent[I, O any](ctx context.Context, overviewTool tool.InvokableTool) *compose.Graph[I, O] {
 	cag := compose.NewGraph[I, O]()
 
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentAIOverviewRetriever))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -123,7 +111,7 @@ func NewAIOverviewRetrieverAgent[I, O any](ctx context.Context, overviewTool too
 		panic(fmt.Sprintf("创建 ReAct Agent 失败: %v", err))
 	}
 
-	agentLambda, err := compose.AnyLambda(agent.Generate, agent.Stream, nil, nil)
+	agentLambda, err := searchAgentLambda(agent)
 	if err != nil {
 		panic(fmt.Sprintf("包装 Agent 失败: %v", err))
 	}
@@ -137,9 +125,9 @@ func NewAIOverviewRetrieverAgent[I, O any](ctx context.Context, overviewTool too
 			return nil, err
 		}
 		return loadAIOOverviewRetrieverPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
-	_ = cag.AddLambdaNode("agent", agentLambda)
+	_ = cag.AddLambdaNode("agent", agentLambda, compose.WithNodeName("agent"))
 
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
 		var next string
@@ -149,7 +137,7 @@ func NewAIOverviewRetrieverAgent[I, O any](ctx context.Context, overviewTool too
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	_ = cag.AddEdge(compose.START, "load")
 	_ = cag.AddEdge("load", "agent")
@@ -0,0 +1,311 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * Brand Analyzer - AI 回答中的品牌提及与情感分析
+ */
+
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// mentionSnippetRadius 提及上下文片段在命中词前后各保留的字符数
+const mentionSnippetRadius = 40
+
+// brandTerm 品牌词典中的一个待匹配词
+type brandTerm struct {
+	text string
+	kind string
+}
+
+// FindBrandMentions 在文本中查找品牌名、别名和产品名的提及（不区分大小写）
+// 英文词要求完整单词匹配；较长的词优先，已命中的区间不会重复匹配
+func FindBrandMentions(text string, brand models.BrandDictionary) []models.BrandMention {
+	terms := make([]brandTerm, 0, 1+len(brand.Aliases)+len(brand.Products))
+	terms = append(terms, brandTerm{text: brand.Name, kind: models.MentionKindName})
+	for _, a := range brand.Aliases {
+		terms = append(terms, brandTerm{text: a, kind: models.MentionKindAlias})
+	}
+	for _, p := range brand.Products {
+		terms = append(terms, brandTerm{text: p, kind: models.MentionKindProduct})
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return utf8.RuneCountInString(terms[i].text) > utf8.RuneCountInString(terms[j].text)
+	})
+
+	// 大小写转换后字节长度不变时才使用小写匹配，保证偏移量可映射回原文
+	haystack := text
+	lower := strings.ToLower(text)
+	foldCase := len(lower) == len(text)
+	if foldCase {
+		haystack = lower
+	}
+
+	type span struct{ start, end int }
+	covered := make([]span, 0)
+	overlaps := func(start, end int) bool {
+		for _, s := range covered {
+			if start < s.end && end > s.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	mentions := make([]models.BrandMention, 0)
+	positions := make([]int, 0)
+	for _, term := range terms {
+		needle := strings.TrimSpace(term.text)
+		if needle == "" {
+			continue
+		}
+		if foldCase {
+			needle = strings.ToLower(needle)
+		}
+
+		for from := 0; from < len(haystack); {
+			idx := strings.Index(haystack[from:], needle)
+			if idx < 0 {
+				break
+			}
+			start := from + idx
+			end := start + len(needle)
+			from = end
+
+			if !isWordBoundary(haystack, start, end) || overlaps(start, end) {
+				continue
+			}
+			covered = append(covered, span{start, end})
+			positions = append(positions, start)
+			mentions = append(mentions, models.BrandMention{
+				Term:    text[start:end],
+				Kind:    term.kind,
+				Offset:  utf8.RuneCountInString(text[:start]),
+				Snippet: mentionSnippet(text, start, end),
+			})
+		}
+	}
+
+	// 按出现位置排序
+	order := make([]int, len(mentions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return positions[order[i]] < positions[order[j]] })
+	sorted := make([]models.BrandMention, len(mentions))
+	for i, k := range order {
+		sorted[i] = mentions[k]
+	}
+	return sorted
+}
+
+// isWordBoundary 英文/数字开头或结尾的词要求两侧不是英文字母或数字
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 && isASCIIWordByte(s[start]) && isASCIIWordByte(s[start-1]) {
+		return false
+	}
+	if end < len(s) && isASCIIWordByte(s[end-1]) && isASCIIWordByte(s[end]) {
+		return false
+	}
+	return true
+}
+
+func isASCIIWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// mentionSnippet 截取命中词前后的上下文
+func mentionSnippet(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+	if len(before) > mentionSnippetRadius {
+		before = before[len(before)-mentionSnippetRadius:]
+	}
+	if len(after) > mentionSnippetRadius {
+		after = after[:mentionSnippetRadius]
+	}
+	snippet := string(before) + text[start:end] + string(after)
+	return strings.TrimSpace(strings.ReplaceAll(snippet, "\n", " "))
+}
+
+// BrandAnalyzer 品牌提及分析器：词典匹配定位提及，LLM 判断情感和错误说法
+type BrandAnalyzer struct {
+	model model.BaseChatModel
+}
+
+// NewBrandAnalyzer 创建品牌提及分析器
+func NewBrandAnalyzer(ctx context.Context) (*BrandAnalyzer, error) {
+	cm, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentBrandAnalyzer))
+	if err != nil {
+		return nil, err
+	}
+	return &BrandAnalyzer{model: cm}, nil
+}
+
+// Analyze 分析 AI 回答中各品牌的提及情况
+// 仅对被提及的品牌调用 LLM；LLM 调用失败时仍返回匹配结果（情感为 neutral）和错误
+func (a *BrandAnalyzer) Analyze(ctx context.Context, answer string, brands []models.BrandDictionary) ([]models.BrandAnalysis, error) {
+	results := make([]models.BrandAnalysis, len(brands))
+	mentioned := make([]int, 0, len(brands))
+
+	for i, brand := range brands {
+		mentions := FindBrandMentions(answer, brand)
+		results[i] = models.BrandAnalysis{
+			BrandID:     brand.ID,
+			Brand:       brand.Name,
+			Mentioned:   len(mentions) > 0,
+			Mentions:    mentions,
+			WrongClaims: make([]models.WrongClaim, 0),
+		}
+		if len(mentions) > 0 {
+			results[i].Sentiment = models.SentimentNeutral
+			for j := range results[i].Mentions {
+				results[i].Mentions[j].Sentiment = models.SentimentNeutral
+			}
+			mentioned = append(mentioned, i)
+		}
+	}
+
+	if len(mentioned) == 0 || a.model == nil {
+		return results, nil
+	}
+
+	messages, err := loadBrandAnalyzerPrompt(ctx, answer, brands, results, mentioned)
+	if err != nil {
+		return results, fmt.Errorf("构建品牌分析 prompt 失败: %w", err)
+	}
+
+	resp, err := a.model.Generate(ctx, messages)
+	if err != nil {
+		return results, fmt.Errorf("品牌情感分析失败: %w", err)
+	}
+
+	applyBrandAnalysisResult(ctx, resp.Content, brands, results)
+	return results, nil
+}
+
+// loadBrandAnalyzerPrompt 加载 prompt
+func loadBrandAnalyzerPrompt(ctx context.Context, answer string, brands []models.BrandDictionary, results []models.BrandAnalysis, mentioned []int) ([]*schema.Message, error) {
+	sysPrompt, err := GetPromptTemplate(ctx, "brand_analyzer")
+	if err != nil {
+		return nil, err
+	}
+
+	promptTemp := prompt.FromMessages(schema.Jinja2,
+		schema.SystemMessage(sysPrompt),
+		schema.UserMessage("开始分析"),
+	)
+
+	var sb strings.Builder
+	for _, i := range mentioned {
+		brand := brands[i]
+		sb.WriteString(fmt.Sprintf("### %s\n", brand.Name))
+		if len(brand.Aliases) > 0 {
+			sb.WriteString(fmt.Sprintf("- 别名: %s\n", strings.Join(brand.Aliases, ", ")))
+		}
+		if len(brand.Products) > 0 {
+			sb.WriteString(fmt.Sprintf("- 产品: %s\n", strings.Join(brand.Products, ", ")))
+		}
+		if len(brand.Facts) > 0 {
+			sb.WriteString("- 已知事实:\n")
+			for _, f := range brand.Facts {
+				sb.WriteString(fmt.Sprintf("  - %s\n", f))
+			}
+		} else {
+			sb.WriteString("- 已知事实: 无\n")
+		}
+		sb.WriteString("- 提及:\n")
+		for j, m := range results[i].Mentions {
+			sb.WriteString(fmt.Sprintf("  %d. 「%s」%s\n", j+1, m.Term, m.Snippet))
+		}
+		sb.WriteString("\n")
+	}
+
+	return promptTemp.Format(ctx, map[string]any{
+		"answer": answer,
+		"brands": sb.String(),
+	})
+}
+
+// brandAnalysisOutput LLM 输出结构
+type brandAnalysisOutput struct {
+	Brands []struct {
+		Brand          string  `json:"brand"`
+		Sentiment      string  `json:"sentiment"`
+		SentimentScore float64 `json:"sentiment_score"`
+		Summary        string  `json:"summary"`
+		Mentions       []struct {
+			Index     int    `json:"index"`
+			Sentiment string `json:"sentiment"`
+		} `json:"mentions"`
+		WrongClaims []models.WrongClaim `json:"wrong_claims"`
+	} `json:"brands"`
+}
+
+// applyBrandAnalysisResult 将 LLM 输出合并到匹配结果中
+func applyBrandAnalysisResult(ctx context.Context, content string, brands []models.BrandDictionary, results []models.BrandAnalysis) {
+	var output brandAnalysisOutput
+	if err := parseJSONObject(content, &output); err != nil {
+		recordParseFailure(ctx, AgentBrandAnalyzer, err)
+		return
+	}
+
+	for _, item := range output.Brands {
+		for i := range results {
+			if !results[i].Mentioned || !strings.EqualFold(strings.TrimSpace(item.Brand), results[i].Brand) {
+				continue
+			}
+
+			r := &results[i]
+			r.Sentiment = normalizeSentiment(item.Sentiment)
+			r.SentimentScore = clampScore(item.SentimentScore)
+			r.Summary = item.Summary
+			for _, m := range item.Mentions {
+				if m.Index >= 1 && m.Index <= len(r.Mentions) {
+					r.Mentions[m.Index-1].Sentiment = normalizeSentiment(m.Sentiment)
+				}
+			}
+			// 没有已知事实的品牌由调用方丢弃错误说法
+			for _, c := range item.WrongClaims {
+				if strings.TrimSpace(c.Claim) != "" {
+					r.WrongClaims = append(r.WrongClaims, c)
+				}
+			}
+		}
+	}
+}
+
+// normalizeSentiment 规范化情感倾向
+func normalizeSentiment(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case models.SentimentPositive:
+		return models.SentimentPositive
+	case models.SentimentNegative:
+		return models.SentimentNegative
+	default:
+		return models.SentimentNeutral
+	}
+}
+
+// clampScore 将情感分数限制在 [-1, 1]
+func clampScore(score float64) float64 {
+	if score > 1 {
+		return 1
+	}
+	if score < -1 {
+		return -1
+	}
+	return score
+}
@@ -0,0 +1,36 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// TestFindBrandMentions 测试品牌词典匹配
+func TestFindBrandMentions(t *testing.T) {
+	brand := models.BrandDictionary{
+		Name:     "Peanut",
+		Aliases:  []string{"花生"},
+		Products: []string{"Peanut Pro"},
+	}
+	text := "推荐 peanut pro 和花生咖啡机，PeanutButter 不是品牌，Peanut 口碑不错。"
+
+	mentions := FindBrandMentions(text, brand)
+
+	want := []struct{ term, kind string }{
+		{"peanut pro", models.MentionKindProduct},
+		{"花生", models.MentionKindAlias},
+		{"Peanut", models.MentionKindName},
+	}
+	if len(mentions) != len(want) {
+		t.Fatalf("len(mentions) = %d, want %d: %+v", len(mentions), len(want), mentions)
+	}
+	for i, w := range want {
+		if mentions[i].Term != w.term || mentions[i].Kind != w.kind {
+			t.Errorf("mentions[%d] = %s/%s, want %s/%s", i, mentions[i].Term, mentions[i].Kind, w.term, w.kind)
+		}
+	}
+	if mentions[0].Offset != 3 {
+		t.Errorf("mentions[0].Offset = %d, want 3", mentions[0].Offset)
+	}
+}
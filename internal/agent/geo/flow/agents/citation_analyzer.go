@@ -0,0 +1,250 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * Citation Analyzer Agent - 竞品引用分析
+ */
+
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+)
+
+const (
+	// maxCitedPages 最多爬取的引用来源数量
+	maxCitedPages = 3
+	// citedExcerptLen 每个来源送入 LLM 的正文节选长度（字符）
+	citedExcerptLen = 1500
+	// ownExcerptLen 我方页面送入 LLM 的正文节选长度（字符）
+	ownExcerptLen = 3000
+)
+
+// selectCitedURLs 从 AI 摘要来源中挑选需要爬取的竞品 URL（去重、排除我方域名）
+func selectCitedURLs(sources []string, ownURL string, limit int) []string {
+	ownDomain := tools.Domain(ownURL)
+	seen := make(map[string]bool)
+	urls := make([]string, 0, limit)
+
+	for _, src := range sources {
+		src = strings.TrimSpace(src)
+		if !tools.IsHTTPURL(src) || seen[src] {
+			continue
+		}
+		if ownDomain != "" && tools.Domain(src) == ownDomain {
+			continue
+		}
+		seen[src] = true
+		urls = append(urls, src)
+		if len(urls) >= limit {
+			break
+		}
+	}
+
+	return urls
+}
+
+// scrapeCitedPages 并发爬取引用来源并提取结构，保持来源原有顺序
+func scrapeCitedPages(ctx context.Context, scraper tools.WebScraper, urls []string) []models.CitedPage {
+	pages := make([]models.CitedPage, len(urls))
+
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+
+			scraped, err := scraper.Scrape(ctx, u)
+			if err != nil {
+				pages[i] = models.CitedPage{URL: u, Error: err.Error()}
+				return
+			}
+
+			page := tools.ExtractPageStructure(u, scraped.Content, citedExcerptLen)
+			if page.Title == "" {
+				page.Title = scraped.Title
+			}
+			pages[i] = *page
+		}(i, u)
+	}
+	wg.Wait()
+
+	return pages
+}
+
+// formatCitedPages 将竞品页面结构格式化为 prompt 文本
+func formatCitedPages(pages []models.CitedPage) string {
+	var sb strings.Builder
+	for i, p := range pages {
+		if p.Error != "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("### 来源 %d: %s\n", i+1, p.Title))
+		sb.WriteString(fmt.Sprintf("- URL: %s\n", p.URL))
+		sb.WriteString(fmt.Sprintf("- 字数: %d\n", p.WordCount))
+		if len(p.Formats) > 0 {
+			sb.WriteString(fmt.Sprintf("- 内容形式: %s\n", strings.Join(p.Formats, ", ")))
+		}
+		if len(p.Headings) > 0 {
+			sb.WriteString(fmt.Sprintf("- 小标题: %s\n", strings.Join(p.Headings, " / ")))
+		}
+		sb.WriteString("- 正文节选:\n")
+		sb.WriteString(p.Excerpt)
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// hasScrapedPages 是否至少有一个来源爬取成功
+func hasScrapedPages(pages []models.CitedPage) bool {
+	for _, p := range pages {
+		if p.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCitationAnalyzerPrompt 加载 prompt
+func loadCitationAnalyzerPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
+	sysPrompt, err := GetPromptTemplate(ctx, "citation_analyzer")
+	if err != nil {
+		return nil, err
+	}
+
+	promptTemp := prompt.FromMessages(schema.Jinja2,
+		schema.SystemMessage(localize(sysPrompt, state.OutputLanguage)),
+		schema.UserMessage(i18n.T(state.OutputLanguage, "start_compare")),
+	)
+
+	own := tools.ExtractPageStructure(state.URL, state.Content, ownExcerptLen)
+
+	variables := map[string]any{
+		"main_query":  state.MainQuery,
+		"title":       state.Title,
+		"formats":     strings.Join(own.Formats, ", "),
+		"headings":    strings.Join(own.Headings, " / "),
+		"content":     own.Excerpt,
+		"competitors": formatCitedPages(state.CitedPages),
+	}
+
+	return promptTemp.Format(ctx, variables)
+}
+
+// routerCitationAnalyzer 路由函数
+func routerCitationAnalyzer(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
+	analysis := parseCitationAnalysisResult(ctx, input.Content)
+
+	state.CompetitorAnalysis = analysis
+	state.ContentGaps = mergeContentGaps(analysis)
+	state.Step = 5
+
+	if state.OnProgress != nil {
+		state.OnProgress(5, state.TotalSteps, "竞品引用分析", fmt.Sprintf("发现 %d 个内容差距", len(state.ContentGaps)))
+	}
+
+	state.Goto = AgentQuerySummarizer
+	return state.Goto, nil
+}
+
+// parseCitationAnalysisResult 解析竞品分析结果
+func parseCitationAnalysisResult(ctx context.Context, content string) *models.CompetitorAnalysis {
+	result := &models.CompetitorAnalysis{}
+	if err := parseJSONObject(content, result); err != nil {
+		recordParseFailure(ctx, AgentCitationAnalyzer, err)
+		return &models.CompetitorAnalysis{}
+	}
+	return result
+}
+
+// mergeContentGaps 汇总内容差距：优先使用模型给出的 content_gaps，否则由缺失项生成
+func mergeContentGaps(analysis *models.CompetitorAnalysis) []string {
+	if analysis == nil {
+		return nil
+	}
+	if len(analysis.ContentGaps) > 0 {
+		return analysis.ContentGaps
+	}
+
+	gaps := make([]string, 0, len(analysis.MissingFacts)+len(analysis.MissingFormats)+len(analysis.MissingEntities))
+	for _, f := range analysis.MissingFacts {
+		gaps = append(gaps, "缺少事实: "+f)
+	}
+	for _, f := range analysis.MissingFormats {
+		gaps = append(gaps, "缺少内容形式: "+f)
+	}
+	for _, e := range analysis.MissingEntities {
+		gaps = append(gaps, "缺少实体: "+e)
+	}
+	return gaps
+}
+
+// NewCitationAnalyzerAgent 创建 Citation Analyzer Agent
+func NewCitationAnalyzerAgent[I, O any](ctx context.Context, scraper tools.WebScraper) *compose.Graph[I, O] {
+	cag := compose.NewGraph[I, O]()
+
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentCitationAnalyzer))
+	if err != nil {
+		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
+	}
+
+	// load 节点：爬取引用来源并准备 prompt
+	_ = cag.AddLambdaNode("load", compose.InvokableLambdaWithOption(func(ctx context.Context, input string, opts ...any) ([]*schema.Message, error) {
+		var state *models.FlowState
+		if err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, s *models.FlowState) error {
+			state = s
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		urls := selectCitedURLs(state.Sources, state.URL, maxCitedPages)
+		state.CitedPages = scrapeCitedPages(ctx, scraper, urls)
+
+		return loadCitationAnalyzerPrompt(ctx, state)
+	}), compose.WithNodeName("load"))
+
+	// agent 节点：没有可对比的竞品页面时跳过 LLM 调用
+	_ = cag.AddLambdaNode("agent", compose.InvokableLambdaWithOption(func(ctx context.Context, input []*schema.Message, opts ...any) (*schema.Message, error) {
+		var pages []models.CitedPage
+		if err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, s *models.FlowState) error {
+			pages = s.CitedPages
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if !hasScrapedPages(pages) {
+			return schema.AssistantMessage("{}", nil), nil
+		}
+		return llmModel.Generate(ctx, input)
+	}), compose.WithNodeName("agent"))
+
+	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
+		var next string
+		err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, state *models.FlowState) error {
+			var err error
+			next, err = routerCitationAnalyzer(ctx, input, state)
+			return err
+		})
+		return next, err
+	}), compose.WithNodeName("router"))
+
+	_ = cag.AddEdge(compose.START, "load")
+	_ = cag.AddEdge("load", "agent")
+	_ = cag.AddEdge("agent", "router")
+	_ = cag.AddEdge("router", compose.END)
+
+	return cag
+}
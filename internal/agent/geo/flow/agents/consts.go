@@ -12,10 +12,12 @@ const (
 	AgentQueryResearcher     = "query_researcher"
 	AgentMainQueryExtractor  = "main_query_extractor"
 	AgentAIOverviewRetriever = "ai_overview_retriever"
+	AgentCitationAnalyzer    = "citation_analyzer"
 	AgentQuerySummarizer     = "query_summarizer"
 	AgentContentOptimizer    = "content_optimizer"
 	AgentContentRewriter     = "content_rewriter"
 	AgentValidator           = "content_validator"
+	AgentBrandAnalyzer       = "brand_analyzer"
 
 	StepStart = "start"
 	StepEnd   = "end"
@@ -9,24 +9,30 @@ package agents
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
 	"github.com/solariswu/peanut/internal/agent/geo/llm"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/parser"
 )
 
 // loadContentOptimizerPrompt 加载 prompt
 func loadContentOptimizerPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("content_optimizer")
+	sysPrompt, err := GetPromptTemplate(ctx, "content_optimizer")
 	if err != nil {
-		sysPrompt = defaultContentOptimizerPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
-		schema.SystemMessage(sysPrompt),
+		schema.SystemMessage(localize(sysPrompt, state.OutputLanguage)),
 	)
 
 	variables := map[string]any{
@@ -34,56 +40,134 @@ func loadContentOptimizerPrompt(ctx context.Context, state *models.FlowState) ([
 		"main_query":    state.MainQuery,
 		"ai_overview":   state.AIOverview,
 		"query_summary": state.QuerySummary,
+		"content_gaps":  formatContentGaps(state.ContentGaps, state.OutputLanguage),
 	}
 
 	return promptTemp.Format(ctx, variables)
 }
 
-const defaultContentOptimizerPrompt = `你是 GEO 内容优化专家。
-
-## 任务
-1. 对比分析 Query Summary 与 Google AI Overview 的差距
-2. 识别两者的共性和差异
-3. 生成可操作的优化建议（action items）
-4. 输出 Markdown 格式的对比报告
-
-## 输出格式要求
-请生成一份 Markdown 格式的优化报告，必须包含以下内容：
-
-1. 执行摘要 - 总体对比结论
-2. 对比表格（必须包含以下列）：
-   | Aspect | Query Summary | Google AI Overview | Similarities/Patterns | Differences |
-3. 关键发现 - 主要共性和差异点
-4. Action Items - 基于对比的具体优化建议（按优先级排序）
-5. 结论
+// formatContentGaps 将竞品内容差距格式化为列表
+func formatContentGaps(gaps []string, lang string) string {
+	if len(gaps) == 0 {
+		return i18n.T(lang, "none")
+	}
+	var sb strings.Builder
+	for _, gap := range gaps {
+		sb.WriteString("- " + gap + "\n")
+	}
+	return sb.String()
+}
 
-注意：表格必须完整呈现，包含所有对比维度。`
+// ContentOptimizerResult 优化报告的结构化输出
+type ContentOptimizerResult struct {
+	OverallScore     int                             `json:"overall_score"`
+	ExecutiveSummary string                          `json:"executive_summary"`
+	ComparisonTable  []models.ComparisonItem         `json:"comparison_table"`
+	KeyFindings      []string                        `json:"key_findings"`
+	ActionItems      []models.OptimizationSuggestion `json:"action_items"`
+	Conclusion       string                          `json:"conclusion"`
+}
 
 // routerContentOptimizer 路由函数
 func routerContentOptimizer(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
-	// 直接保存 Markdown 报告
-	state.Report = &models.OptimizationReport{
-		URL:                state.URL,
-		Title:              state.Title,
-		OverallScore:       0, // Markdown 报告不需要数字评分
-		OptimizationReport: input.Content,
-	}
-	state.Step = 6
+	state.Report = buildOptimizationReport(ctx, input.Content, state)
+	state.Step = 7
 
 	// 发送进度回调
 	if state.OnProgress != nil {
-		state.OnProgress(6, state.TotalSteps, "内容优化", "处理完成")
+		state.OnProgress(7, state.TotalSteps, "内容优化", fmt.Sprintf("总体评分 %d，%d 条优化建议", state.Report.OverallScore, len(state.Report.OptimizationSuggestions)))
 	}
 
 	state.Goto = AgentContentRewriter
 	return state.Goto, nil
 }
 
+// buildOptimizationReport 由结构化输出生成优化报告，报告正文由结构渲染
+// 输出无法按 JSON 解析时降级为从 Markdown 中提取，并保留原文
+func buildOptimizationReport(ctx context.Context, content string, state *models.FlowState) *models.OptimizationReport {
+	var result ContentOptimizerResult
+	if err := parseJSONObject(content, &result); err != nil {
+		recordParseFailure(ctx, AgentContentOptimizer, err)
+		report := parser.ParseOptimizationReport(content, state.URL)
+		report.Title = state.Title
+		report.OutputLanguage = state.OutputLanguage
+		report.OptimizationReport = content
+		if len(report.ContentGaps) == 0 {
+			report.ContentGaps = state.ContentGaps
+		}
+		return report
+	}
+
+	report := &models.OptimizationReport{
+		URL:                     state.URL,
+		Title:                   state.Title,
+		MainQuery:               state.MainQuery,
+		ExecutiveSummary:        strings.TrimSpace(result.ExecutiveSummary),
+		ComparisonTable:         normalizeComparisonTable(result.ComparisonTable),
+		KeyFindings:             nonEmpty(result.KeyFindings),
+		ContentGaps:             state.ContentGaps,
+		OptimizationSuggestions: normalizeSuggestions(result.ActionItems),
+		Conclusion:              strings.TrimSpace(result.Conclusion),
+		OverallScore:            min(max(result.OverallScore, 0), 100),
+		OutputLanguage:          state.OutputLanguage,
+		Timestamp:               time.Now(),
+	}
+	report.OptimizationReport = parser.FormatOptimizationReport(report)
+	return report
+}
+
+// normalizeComparisonTable 去掉没有维度的对比项
+func normalizeComparisonTable(items []models.ComparisonItem) []models.ComparisonItem {
+	table := make([]models.ComparisonItem, 0, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item.Dimension) != "" {
+			table = append(table, item)
+		}
+	}
+	return table
+}
+
+// normalizeSuggestions 规范化优先级和类别（不在 models.SuggestionCategories 中的归为 general），去掉空建议，并按优先级稳定排序
+func normalizeSuggestions(items []models.OptimizationSuggestion) []models.OptimizationSuggestion {
+	rank := map[string]int{"high": 0, "medium": 1, "low": 2}
+	suggestions := make([]models.OptimizationSuggestion, 0, len(items))
+	for _, item := range items {
+		item.Suggestion = strings.TrimSpace(item.Suggestion)
+		if item.Suggestion == "" {
+			continue
+		}
+		item.Priority = strings.ToLower(strings.TrimSpace(item.Priority))
+		if _, ok := rank[item.Priority]; !ok {
+			item.Priority = "medium"
+		}
+		if item.Category = strings.ToLower(strings.TrimSpace(item.Category)); !slices.Contains(models.SuggestionCategories, item.Category) {
+			item.Category = "general"
+		}
+		item.Issue = strings.TrimSpace(item.Issue)
+		suggestions = append(suggestions, item)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return rank[suggestions[i].Priority] < rank[suggestions[j].Priority]
+	})
+	return suggestions
+}
+
+// nonEmpty 去掉空字符串
+func nonEmpty(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // NewContentOptimizerAgent 创建 Content Optimizer Agent
 func NewContentOptimizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O] {
 	cag := compose.NewGraph[I, O]()
 
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentContentOptimizer))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -97,9 +181,9 @@ func NewContentOptimizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O
 			return nil, err
 		}
 		return loadContentOptimizerPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
-	_ = cag.AddChatModelNode("agent", llmModel)
+	_ = cag.AddChatModelNode("agent", llmModel, compose.WithNodeName("agent"))
 
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
 		var next string
@@ -109,7 +193,7 @@ func NewContentOptimizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	_ = cag.AddEdge(compose.START, "load")
 	_ = cag.AddEdge("load", "agent")
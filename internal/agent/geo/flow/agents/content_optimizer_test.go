@@ -0,0 +1,73 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// TestBuildOptimizationReport 测试结构化输出的规范化和非 JSON 输出的降级解析
+func TestBuildOptimizationReport(t *testing.T) {
+	state := &models.FlowState{
+		URL:         "https://example.com/espresso",
+		Title:       "咖啡机选购指南",
+		MainQuery:   "咖啡机怎么选",
+		ContentGaps: []string{"缺少型号对比"},
+	}
+
+	content := "```json\n" + `{
+  "overall_score": 120,
+  "executive_summary": " 页面缺少型号对比。 ",
+  "comparison_table": [
+    {"dimension": "型号对比", "your_content": "无", "ai_overview": "列出型号", "similarity": "无", "difference": "缺少表格"},
+    {"dimension": "", "your_content": "无"}
+  ],
+  "key_findings": ["竞品都有对比表格", " "],
+  "action_items": [
+    {"priority": "Low", "category": "", "issue": "", "suggestion": "增加 FAQ"},
+    {"priority": "urgent", "category": "content", "issue": "", "suggestion": "补充磨豆机建议"},
+    {"priority": "HIGH", "category": "structure", "issue": "缺少型号对比", "suggestion": "增加对比表格"},
+    {"priority": "high", "suggestion": " "}
+  ],
+  "conclusion": "补充对比表格。"
+}` + "\n```"
+
+	report := buildOptimizationReport(context.Background(), content, state)
+	if report.OverallScore != 100 {
+		t.Errorf("OverallScore = %d, want 100", report.OverallScore)
+	}
+	if report.ExecutiveSummary != "页面缺少型号对比。" || len(report.KeyFindings) != 1 {
+		t.Errorf("ExecutiveSummary = %q, KeyFindings = %v", report.ExecutiveSummary, report.KeyFindings)
+	}
+	if len(report.ComparisonTable) != 1 {
+		t.Errorf("ComparisonTable = %+v, want 1 row", report.ComparisonTable)
+	}
+	want := []struct{ priority, category string }{
+		{"high", "structure"},
+		{"medium", "content"},
+		{"low", "general"},
+	}
+	if len(report.OptimizationSuggestions) != len(want) {
+		t.Fatalf("OptimizationSuggestions = %+v", report.OptimizationSuggestions)
+	}
+	for i, w := range want {
+		if s := report.OptimizationSuggestions[i]; s.Priority != w.priority || s.Category != w.category {
+			t.Errorf("OptimizationSuggestions[%d] = %s/%s, want %s/%s", i, s.Priority, s.Category, w.priority, w.category)
+		}
+	}
+	if len(report.ContentGaps) != 1 || !strings.Contains(report.OptimizationReport, "## 执行摘要") {
+		t.Errorf("ContentGaps = %v, OptimizationReport = %q", report.ContentGaps, report.OptimizationReport)
+	}
+
+	// 非 JSON 输出按 Markdown 解析，保留原文
+	markdown := "# GEO 优化报告\n\n## 优化建议\n\n### 🔴 高优先级\n\n1. 增加对比表格\n"
+	report = buildOptimizationReport(context.Background(), markdown, state)
+	if report.OptimizationReport != markdown || report.Title != state.Title {
+		t.Errorf("降级报告 = %+v", report)
+	}
+	if len(report.OptimizationSuggestions) != 1 || len(report.ContentGaps) != 1 {
+		t.Errorf("降级报告 suggestions = %+v, gaps = %v", report.OptimizationSuggestions, report.ContentGaps)
+	}
+}
@@ -20,13 +20,13 @@ import (
 
 // loadContentRewriterPrompt 加载 prompt
 func loadContentRewriterPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("content_rewriter")
+	sysPrompt, err := GetPromptTemplate(ctx, "content_rewriter")
 	if err != nil {
-		sysPrompt = defaultContentRewriterPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
-		schema.SystemMessage(sysPrompt),
+		schema.SystemMessage(localize(sysPrompt, state.ContentLanguage)),
 	)
 
 	// 构建优化报告摘要
@@ -62,32 +62,14 @@ func buildReportSummary(report *models.OptimizationReport) string {
 	return report.OptimizationReport
 }
 
-const defaultContentRewriterPrompt = `你是 GEO 内容重写专家。
-
-## 任务
-1. 分析优化报告中的建议
-2. 基于原始网页内容和 AI Overview 洞察
-3. 重写优化后的完整文章
-4. 增强可信度和可读性
-
-## 优化原则
-- 权威性优先: 开篇明确引用权威来源
-- 时效性强化: 标注时间、使用最新数据
-- 结构化呈现: 使用标题、列表、表格
-- 覆盖 AI Overview 中的关键信息点
-- 保持原文核心观点和信息
-
-## 输出格式
-请直接返回优化后的完整文章（Markdown格式）。`
-
 // routerContentRewriter 路由函数
 func routerContentRewriter(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
 	state.OptimizedArticle = input.Content
-	state.Step = 7
+	state.Step = 8
 
 	// 发送进度回调
 	if state.OnProgress != nil {
-		state.OnProgress(7, state.TotalSteps, "文章重写", "处理完成")
+		state.OnProgress(8, state.TotalSteps, "文章重写", "处理完成")
 	}
 
 	// 更新报告
@@ -95,7 +77,7 @@ func routerContentRewriter(ctx context.Context, input *schema.Message, state *mo
 		state.Report.OptimizedArticle = input.Content
 	}
 
-	state.Goto = compose.END
+	state.Goto = AgentValidator
 	return state.Goto, nil
 }
 
@@ -103,7 +85,7 @@ func routerContentRewriter(ctx context.Context, input *schema.Message, state *mo
 func NewContentRewriterAgent[I, O any](ctx context.Context) *compose.Graph[I, O] {
 	cag := compose.NewGraph[I, O]()
 
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentContentRewriter))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -117,9 +99,9 @@ func NewContentRewriterAgent[I, O any](ctx context.Context) *compose.Graph[I, O]
 			return nil, err
 		}
 		return loadContentRewriterPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
-	_ = cag.AddChatModelNode("agent", llmModel)
+	_ = cag.AddChatModelNode("agent", llmModel, compose.WithNodeName("agent"))
 
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
 		var next string
@@ -129,7 +111,7 @@ func NewContentRewriterAgent[I, O any](ctx context.Context) *compose.Graph[I, O]
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	_ = cag.AddEdge(compose.START, "load")
 	_ = cag.AddEdge("load", "agent")
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * Content Validator Agent - 内容验证
+ */
+
+package agents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// 验证维度的对比状态
+const (
+	ValidationImproved  = "improved"
+	ValidationUnchanged = "unchanged"
+	ValidationDeclined  = "declined"
+)
+
+// loadContentValidatorPrompt 加载 prompt
+func loadContentValidatorPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
+	sysPrompt, err := GetPromptTemplate(ctx, AgentValidator)
+	if err != nil {
+		return nil, err
+	}
+
+	promptTemp := prompt.FromMessages(schema.Jinja2,
+		schema.SystemMessage(localize(sysPrompt, state.OutputLanguage)),
+	)
+
+	variables := map[string]any{
+		"platform":          state.PlatformType,
+		"platform_type":     getPlatformTypeName(state.PlatformType),
+		"main_query":        state.MainQuery,
+		"ai_overview":       state.AIOverview,
+		"content":           state.Content,
+		"optimized_article": state.OptimizedArticle,
+	}
+
+	return promptTemp.Format(ctx, variables)
+}
+
+// ContentValidatorResult 内容验证的结构化输出
+type ContentValidatorResult struct {
+	OriginalScore  models.ScoreDetail `json:"original_score"`
+	OptimizedScore models.ScoreDetail `json:"optimized_score"`
+	Comments       map[string]string  `json:"comments"`
+	Suggestions    []string           `json:"suggestions"`
+}
+
+// routerContentValidator 路由函数
+func routerContentValidator(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
+	state.Step = 9
+
+	// 验证结果只用于评估优化效果，无法解析时不影响报告
+	validation := buildValidationResult(ctx, input.Content, state.PlatformType)
+	message := "无法解析验证结果"
+	if validation != nil {
+		message = fmt.Sprintf("优化前 %d 分，优化后 %d 分", validation.OriginalScore.Total, validation.OptimizedScore.Total)
+		if state.Report != nil {
+			state.Report.Validation = validation
+		}
+	}
+
+	// 发送进度回调
+	if state.OnProgress != nil {
+		state.OnProgress(9, state.TotalSteps, "效果验证", message)
+	}
+
+	state.Goto = compose.END
+	return state.Goto, nil
+}
+
+// buildValidationResult 由结构化输出生成验证结果，总分按平台权重计算，输出无法按 JSON 解析时返回 nil
+func buildValidationResult(ctx context.Context, content, platform string) *models.ValidationResult {
+	var result ContentValidatorResult
+	if err := parseJSONObject(content, &result); err != nil {
+		recordParseFailure(ctx, AgentValidator, err)
+		return nil
+	}
+
+	weight := models.GetPlatformWeight(models.PlatformType(platform))
+	original, optimized := clampScoreDetail(result.OriginalScore), clampScoreDetail(result.OptimizedScore)
+	original.Total = original.CalculateTotal(weight)
+	optimized.Total = optimized.CalculateTotal(weight)
+
+	dimensions := []struct {
+		name                string
+		weight              int
+		original, optimized int
+	}{
+		{"authority", weight.Authority, original.Authority, optimized.Authority},
+		{"timeliness", weight.Timeliness, original.Timeliness, optimized.Timeliness},
+		{"structure", weight.Structure, original.Structure, optimized.Structure},
+		{"engagement", weight.Engagement, original.Engagement, optimized.Engagement},
+		{"originality", weight.Originality, original.Originality, optimized.Originality},
+	}
+	table := make([]models.Comparison, len(dimensions))
+	for i, d := range dimensions {
+		status := ValidationUnchanged
+		if d.optimized > d.original {
+			status = ValidationImproved
+		} else if d.optimized < d.original {
+			status = ValidationDeclined
+		}
+		table[i] = models.Comparison{
+			Dimension: d.name,
+			Weight:    d.weight,
+			Original:  d.original,
+			Optimized: d.optimized,
+			Diff:      d.optimized - d.original,
+			Status:    status,
+			Comment:   result.Comments[d.name],
+		}
+	}
+
+	return &models.ValidationResult{
+		OriginalScore:   original,
+		OptimizedScore:  optimized,
+		Improvement:     models.CalculateImprovement(original, optimized, weight),
+		ComparisonTable: table,
+		Suggestions:     nonEmpty(result.Suggestions),
+		Timestamp:       time.Now(),
+	}
+}
+
+// clampScoreDetail 将各维度得分限制在 0-100
+func clampScoreDetail(s models.ScoreDetail) models.ScoreDetail {
+	clamp := func(v int) int { return min(max(v, 0), 100) }
+	return models.ScoreDetail{
+		Authority:   clamp(s.Authority),
+		Timeliness:  clamp(s.Timeliness),
+		Structure:   clamp(s.Structure),
+		Engagement:  clamp(s.Engagement),
+		Originality: clamp(s.Originality),
+	}
+}
+
+// NewContentValidatorAgent 创建 Content Validator Agent
+func NewContentValidatorAgent[I, O any](ctx context.Context) *compose.Graph[I, O] {
+	cag := compose.NewGraph[I, O]()
+
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentValidator))
+	if err != nil {
+		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
+	}
+
+	_ = cag.AddLambdaNode("load", compose.InvokableLambdaWithOption(func(ctx context.Context, input string, opts ...any) ([]*schema.Message, error) {
+		var state *models.FlowState
+		if err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, s *models.FlowState) error {
+			state = s
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return loadContentValidatorPrompt(ctx, state)
+	}), compose.WithNodeName("load"))
+
+	_ = cag.AddChatModelNode("agent", llmModel, compose.WithNodeName("agent"))
+
+	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
+		var next string
+		err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, state *models.FlowState) error {
+			var err error
+			next, err = routerContentValidator(ctx, input, state)
+			return err
+		})
+		return next, err
+	}), compose.WithNodeName("router"))
+
+	_ = cag.AddEdge(compose.START, "load")
+	_ = cag.AddEdge("load", "agent")
+	_ = cag.AddEdge("agent", "router")
+	_ = cag.AddEdge("router", compose.END)
+
+	return cag
+}
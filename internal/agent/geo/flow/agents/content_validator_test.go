@@ -0,0 +1,54 @@
+package agents
+
+import (
+	"context"
+	"testing"
+)
+
+// TestBuildValidationResult 测试得分限制、按平台权重计算总分、各维度对比状态和非 JSON 输出
+func TestBuildValidationResult(t *testing.T) {
+	content := "验证结果如下：\n```json\n" + `{
+  "original_score": {"authority": 40, "timeliness": 50, "structure": 60, "engagement": 20, "originality": 70},
+  "optimized_score": {"authority": 80, "timeliness": 50, "structure": 120, "engagement": 10, "originality": 70},
+  "comments": {"authority": "补充了官方数据", "structure": "增加了对比表格"},
+  "suggestions": ["引用评测机构数据", " "]
+}` + "\n```"
+
+	v := buildValidationResult(context.Background(), content, "google")
+	if v == nil {
+		t.Fatal("buildValidationResult() = nil")
+	}
+	// google 权重：权威 45、时效 30、结构 15、互动 0、原创 10
+	if v.OriginalScore.Total != 18+15+9+7 || v.OptimizedScore.Total != 36+15+15+7 {
+		t.Errorf("Total = %d -> %d, want 49 -> 73", v.OriginalScore.Total, v.OptimizedScore.Total)
+	}
+	if v.OptimizedScore.Structure != 100 || v.Improvement.TotalDiff != 24 {
+		t.Errorf("OptimizedScore = %+v, Improvement = %+v", v.OptimizedScore, v.Improvement)
+	}
+
+	want := map[string]string{
+		"authority":   ValidationImproved,
+		"timeliness":  ValidationUnchanged,
+		"structure":   ValidationImproved,
+		"engagement":  ValidationDeclined,
+		"originality": ValidationUnchanged,
+	}
+	if len(v.ComparisonTable) != len(want) {
+		t.Fatalf("ComparisonTable = %+v", v.ComparisonTable)
+	}
+	for _, c := range v.ComparisonTable {
+		if c.Status != want[c.Dimension] || c.Diff != c.Optimized-c.Original {
+			t.Errorf("%s = %+v, want status %s", c.Dimension, c, want[c.Dimension])
+		}
+	}
+	if v.ComparisonTable[0].Weight != 45 || v.ComparisonTable[0].Comment != "补充了官方数据" {
+		t.Errorf("ComparisonTable[0] = %+v", v.ComparisonTable[0])
+	}
+	if len(v.Suggestions) != 1 {
+		t.Errorf("Suggestions = %q, want 1", v.Suggestions)
+	}
+
+	if v := buildValidationResult(context.Background(), "优化后的文章明显更好", "google"); v != nil {
+		t.Errorf("buildValidationResult(非 JSON) = %+v, want nil", v)
+	}
+}
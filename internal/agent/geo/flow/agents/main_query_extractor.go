@@ -30,13 +30,13 @@ type MainQueryResult struct {
 
 // loadMainQueryExtractorPrompt 加载 prompt
 func loadMainQueryExtractorPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("main_query_extractor")
+	sysPrompt, err := GetPromptTemplate(ctx, "main_query_extractor")
 	if err != nil {
-		sysPrompt = defaultMainQueryExtractorPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
-		schema.SystemMessage(sysPrompt),
+		schema.SystemMessage(localize(sysPrompt, state.ContentLanguage)),
 	)
 
 	variables := map[string]any{
@@ -56,25 +56,9 @@ func formatSearchResults(results []models.SearchResult) string {
 	return sb.String()
 }
 
-const defaultMainQueryExtractorPrompt = `你是查询分析专家。
-
-## 任务
-1. 分析相关查询的共同主题
-2. 提取最核心的主查询词（1-3个）
-3. 识别搜索关键词
-4. 判断搜索意图
-
-## 输出格式
-{
-  "main_query": "核心查询词",
-  "keywords": ["关键词1", "关键词2"],
-  "search_intent": "信息查询/交易/导航",
-  "reasoning": "分析理由"
-}`
-
 // routerMainQueryExtractor 路由函数
 func routerMainQueryExtractor(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
-	result := parseMainQueryResult(input.Content)
+	result := parseMainQueryResult(ctx, input.Content)
 
 	state.MainQuery = result.MainQuery
 	state.Keywords = result.Keywords
@@ -90,11 +74,13 @@ func routerMainQueryExtractor(ctx context.Context, input *schema.Message, state
 	return state.Goto, nil
 }
 
-func parseMainQueryResult(content string) *MainQueryResult {
+func parseMainQueryResult(ctx context.Context, content string) *MainQueryResult {
 	result := &MainQueryResult{}
-	if err := json.Unmarshal([]byte(content), &result); err == nil {
+	err := json.Unmarshal([]byte(content), &result)
+	if err == nil {
 		return result
 	}
+	recordParseFailure(ctx, AgentMainQueryExtractor, err)
 	// 降级处理
 	result.MainQuery = extractJSONField(content, "main_query")
 	result.Keywords = extractStringArray(content, "keywords")
@@ -106,7 +92,7 @@ func parseMainQueryResult(content string) *MainQueryResult {
 func NewMainQueryExtractorAgent[I, O any](ctx context.Context) *compose.Graph[I, O] {
 	cag := compose.NewGraph[I, O]()
 
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentMainQueryExtractor))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -120,9 +106,9 @@ func NewMainQueryExtractorAgent[I, O any](ctx context.Context) *compose.Graph[I,
 			return nil, err
 		}
 		return loadMainQueryExtractorPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
-	_ = cag.AddChatModelNode("agent", llmModel)
+	_ = cag.AddChatModelNode("agent", llmModel, compose.WithNodeName("agent"))
 
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
 		var next string
@@ -132,7 +118,7 @@ func NewMainQueryExtractorAgent[I, O any](ctx context.Context) *compose.Graph[I,
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	_ = cag.AddEdge(compose.START, "load")
 	_ = cag.AddEdge("load", "agent")
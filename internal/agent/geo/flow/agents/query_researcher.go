@@ -18,8 +18,10 @@ import (
 	"github.com/cloudwego/eino/flow/agent/react"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
 	"github.com/solariswu/peanut/internal/agent/geo/llm"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
 )
 
 // QueryResearcherResult 研究结果
@@ -31,14 +33,14 @@ type QueryResearcherResult struct {
 
 // loadQueryResearcherPrompt 加载 prompt
 func loadQueryResearcherPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("query_researcher")
+	sysPrompt, err := GetPromptTemplate(ctx, "query_researcher")
 	if err != nil {
-		sysPrompt = defaultQueryResearcherPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
-		schema.SystemMessage(sysPrompt),
-		schema.UserMessage("开始研究"),
+		schema.SystemMessage(localize(sysPrompt, state.ContentLanguage)),
+		schema.UserMessage(i18n.T(state.ContentLanguage, "start_research")),
 	)
 
 	variables := map[string]any{
@@ -49,29 +51,14 @@ func loadQueryResearcherPrompt(ctx context.Context, state *models.FlowState) ([]
 	return promptTemp.Format(ctx, variables)
 }
 
-// defaultQueryResearcherPrompt 默认 prompt
-const defaultQueryResearcherPrompt = `你是搜索研究专家。
-
-## 任务
-1. 基于网页标题和内容，分析核心主题
-2. 使用 search_queries 工具进行搜索
-3. 收集相关查询列表（至少5个）
-
-## 输出格式
-请以 JSON 格式返回：
-{
-  "original_query": "原始查询词",
-  "related_queries": ["查询1", "查询2", ...],
-  "search_results": [{"title": "...", "url": "...", "snippet": "..."}]
-}`
-
 // routerQueryResearcher 路由函数
 func routerQueryResearcher(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
-	result := parseQueryResearcherResult(input.Content)
+	result := parseQueryResearcherResult(ctx, input.Content)
 
 	// 保存到 State
 	state.QueryFanout = result.RelatedQueries
 	state.SearchResults = result.SearchResults
+	state.ApplySERPFeatures(tools.SERPFeaturesFromContext(ctx))
 	state.Step = 2
 
 	// 发送进度回调
@@ -84,13 +71,15 @@ func routerQueryResearcher(ctx context.Context, input *schema.Message, state *mo
 }
 
 // parseQueryResearcherResult 解析结果
-func parseQueryResearcherResult(content string) *QueryResearcherResult {
+func parseQueryResearcherResult(ctx context.Context, content string) *QueryResearcherResult {
 	result := &QueryResearcherResult{}
 
 	// 尝试解析 JSON
-	if err := json.Unmarshal([]byte(content), &result); err == nil {
+	err := json.Unmarshal([]byte(content), &result)
+	if err == nil {
 		return result
 	}
+	recordParseFailure(ctx, AgentQueryResearcher, err)
 
 	// 降级处理：从文本中提取
 	result.OriginalQuery = extractJSONField(content, "original_query")
@@ -132,7 +121,7 @@ func NewQueryResearcherAgent[I, O any](ctx context.Context, searchTool tool.Invo
 	cag := compose.NewGraph[I, O]()
 
 	// 创建 LLM 模型
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentQueryResearcher))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -162,7 +151,7 @@ func NewQueryResearcherAgent[I, O any](ctx context.Context, searchTool tool.Invo
 	}
 
 	// 包装为 Lambda
-	agentLambda, err := compose.AnyLambda(agent.Generate, agent.Stream, nil, nil)
+	agentLambda, err := searchAgentLambda(agent)
 	if err != nil {
 		panic(fmt.Sprintf("包装 Agent 失败: %v", err))
 	}
@@ -177,10 +166,10 @@ func NewQueryResearcherAgent[I, O any](ctx context.Context, searchTool tool.Invo
 			return nil, err
 		}
 		return loadQueryResearcherPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
 	// 添加 agent 节点
-	_ = cag.AddLambdaNode("agent", agentLambda)
+	_ = cag.AddLambdaNode("agent", agentLambda, compose.WithNodeName("agent"))
 
 	// 添加 router 节点
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
@@ -191,7 +180,7 @@ func NewQueryResearcherAgent[I, O any](ctx context.Context, searchTool tool.Invo
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	// 添加边
 	_ = cag.AddEdge(compose.START, "load")
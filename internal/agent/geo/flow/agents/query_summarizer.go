@@ -31,60 +31,53 @@ type QuerySummarizerResult struct {
 
 // loadQuerySummarizerPrompt 加载 prompt
 func loadQuerySummarizerPrompt(ctx context.Context, state *models.FlowState) ([]*schema.Message, error) {
-	sysPrompt, err := GetPromptTemplate("query_summarizer")
+	sysPrompt, err := GetPromptTemplate(ctx, "query_summarizer")
 	if err != nil {
-		sysPrompt = defaultQuerySummarizerPrompt
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
-		schema.SystemMessage(sysPrompt),
+		schema.SystemMessage(localize(sysPrompt, state.OutputLanguage)),
 	)
 
+	featuredSnippet := ""
+	if state.FeaturedSnippet != nil {
+		featuredSnippet = fmt.Sprintf("%s - %s (%s)", state.FeaturedSnippet.Title, state.FeaturedSnippet.Snippet, state.FeaturedSnippet.URL)
+	}
+
 	variables := map[string]any{
-		"related_queries": strings.Join(state.QueryFanout, ", "),
+		"related_queries":  strings.Join(state.QueryFanout, ", "),
+		"people_also_ask":  strings.Join(state.PeopleAlsoAsk, "; "),
+		"related_searches": strings.Join(state.RelatedSearches, ", "),
+		"featured_snippet": featuredSnippet,
 	}
 
 	return promptTemp.Format(ctx, variables)
 }
 
-const defaultQuerySummarizerPrompt = `你是信息总结专家。
-
-## 任务
-1. 分析所有搜索结果的共性和差异
-2. 识别热门话题和用户关注点
-3. 生成结构化的查询总结
-4. 提取关键洞察
-
-## 输出格式
-{
-  "summary": "查询总结（300-500字）",
-  "key_topics": ["主题1", "主题2", "主题3"],
-  "user_intents": ["用户意图1", "用户意图2"],
-  "hot_keywords": ["热词1", "热词2", "热词3"],
-  "insights": "关键洞察"
-}`
-
 // routerQuerySummarizer 路由函数
 func routerQuerySummarizer(ctx context.Context, input *schema.Message, state *models.FlowState) (string, error) {
-	result := parseQuerySummarizerResult(input.Content)
+	result := parseQuerySummarizerResult(ctx, input.Content)
 
 	state.QuerySummary = result.Summary
-	state.Step = 5
+	state.Step = 6
 
 	// 发送进度回调
 	if state.OnProgress != nil {
-		state.OnProgress(5, state.TotalSteps, "查询总结", "处理完成")
+		state.OnProgress(6, state.TotalSteps, "查询总结", "处理完成")
 	}
 
 	state.Goto = AgentContentOptimizer
 	return state.Goto, nil
 }
 
-func parseQuerySummarizerResult(content string) *QuerySummarizerResult {
+func parseQuerySummarizerResult(ctx context.Context, content string) *QuerySummarizerResult {
 	result := &QuerySummarizerResult{}
-	if err := json.Unmarshal([]byte(content), &result); err == nil {
+	err := json.Unmarshal([]byte(content), &result)
+	if err == nil {
 		return result
 	}
+	recordParseFailure(ctx, AgentQuerySummarizer, err)
 	result.Summary = content
 	return result
 }
@@ -93,7 +86,7 @@ func parseQuerySummarizerResult(content string) *QuerySummarizerResult {
 func NewQuerySummarizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O] {
 	cag := compose.NewGraph[I, O]()
 
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentQuerySummarizer))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -107,9 +100,9 @@ func NewQuerySummarizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O]
 			return nil, err
 		}
 		return loadQuerySummarizerPrompt(ctx, state)
-	}))
+	}), compose.WithNodeName("load"))
 
-	_ = cag.AddChatModelNode("agent", llmModel)
+	_ = cag.AddChatModelNode("agent", llmModel, compose.WithNodeName("agent"))
 
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
 		var next string
@@ -119,7 +112,7 @@ func NewQuerySummarizerAgent[I, O any](ctx context.Context) *compose.Graph[I, O]
 			return err
 		})
 		return next, err
-	}))
+	}), compose.WithNodeName("router"))
 
 	_ = cag.AddEdge(compose.START, "load")
 	_ = cag.AddEdge("load", "agent")
@@ -17,9 +17,12 @@ import (
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent/react"
 	"github.com/cloudwego/eino/schema"
+	"go.uber.org/zap"
 
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
 	"github.com/solariswu/peanut/internal/agent/geo/llm"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
 // TitleScraperResult 爬取结果
@@ -40,15 +43,12 @@ func min(a, b int) int {
 
 // loadTitleScraperPrompt 从 State 加载 prompt
 func loadTitleScraperPrompt(ctx context.Context, state *models.FlowState, scraperTool tool.BaseTool) ([]*schema.Message, error) {
-	fmt.Println("[TitleScraper] loadTitleScraperPrompt 开始")
+	logger := logging.FromContext(ctx)
+
 	// 读取 prompt 模板
-	sysPrompt, err := GetPromptTemplate("title_scraper")
+	sysPrompt, err := GetPromptTemplate(ctx, "title_scraper")
 	if err != nil {
-		// 使用默认 prompt
-		fmt.Println("[TitleScraper] 使用默认 prompt")
-		sysPrompt = defaultTitleScraperPrompt
-	} else {
-		fmt.Println("[TitleScraper] 使用自定义 prompt")
+		return nil, err
 	}
 
 	promptTemp := prompt.FromMessages(schema.Jinja2,
@@ -60,37 +60,24 @@ func loadTitleScraperPrompt(ctx context.Context, state *models.FlowState, scrape
 		"url": state.URL,
 	}
 
-	fmt.Println("[TitleScraper] 调用 promptTemp.Format...")
 	result, err := promptTemp.Format(ctx, variables)
 	if err != nil {
-		fmt.Println("[TitleScraper] promptTemp.Format 失败:", err)
-	} else {
-		fmt.Println("[TitleScraper] promptTemp.Format 成功, 消息数量:", len(result))
+		logger.Error("渲染 prompt 失败", zap.Error(err))
 	}
 	return result, err
 }
 
-// defaultTitleScraperPrompt 默认 prompt
-const defaultTitleScraperPrompt = `你是网页爬虫专家。使用 scrape_webpage 工具爬取网页并提取标题和主要内容。
-
-请以 JSON 格式返回:
-{
-  "url": "网页URL",
-  "title": "网页标题",
-  "h1": "主标题",
-  "content": "正文摘要"
-}`
-
 // routerTitleScraper 路由函数 - 保存结果并决定下一步
 // 修改 state.Goto 来决定下一步，不返回值
 func routerTitleScraper(ctx context.Context, input *schema.Message, state *models.FlowState) error {
 	// 解析结果
-	result := parseTitleScraperResult(input.Content)
+	result := parseTitleScraperResult(ctx, input.Content)
 
 	// 保存到 State
 	state.Title = result.Title
 	state.Content = result.Content
 	state.Step = 1
+	applyContentLanguage(state)
 
 	// 发送进度回调
 	if state.OnProgress != nil {
@@ -109,13 +96,29 @@ func routerTitleScraper(ctx context.Context, input *schema.Message, state *model
 	return nil
 }
 
+// applyContentLanguage 检测网页语言，并为未指定的输出语言和搜索语言选择默认值
+// 搜索语言只在网页不是中文时补全，中文网页保持原有的搜索请求
+func applyContentLanguage(state *models.FlowState) {
+	state.ContentLanguage = i18n.Detect(state.Title + "\n" + state.Content)
+	if state.ContentLanguage == "" {
+		state.ContentLanguage = i18n.Default
+	}
+	if state.OutputLanguage == "" {
+		state.OutputLanguage = i18n.OutputFor(state.ContentLanguage)
+	}
+	if state.SearchOptions.Language == "" && state.ContentLanguage != i18n.Default {
+		state.SearchOptions.Language = state.ContentLanguage
+	}
+}
+
 // parseTitleScraperResult 解析爬取结果
-func parseTitleScraperResult(content string) *TitleScraperResult {
+func parseTitleScraperResult(ctx context.Context, content string) *TitleScraperResult {
 	result := &TitleScraperResult{}
 
 	// 尝试解析 JSON
 	var jsonResult map[string]string
-	if err := json.Unmarshal([]byte(content), &jsonResult); err == nil {
+	err := json.Unmarshal([]byte(content), &jsonResult)
+	if err == nil {
 		result.URL = jsonResult["url"]
 		result.Title = jsonResult["title"]
 		result.H1 = jsonResult["h1"]
@@ -124,6 +127,7 @@ func parseTitleScraperResult(content string) *TitleScraperResult {
 	}
 
 	// 降级：从文本中提取
+	recordParseFailure(ctx, AgentTitleScraper, err)
 	result.Title = extractField(content, "title", "标题")
 	result.H1 = extractField(content, "h1", "主标题")
 	result.Content = extractField(content, "content", "正文")
@@ -203,7 +207,7 @@ func NewTitleScraperAgent[I, O any](ctx context.Context, scraperTool tool.Invoka
 	cag := compose.NewGraph[I, O]()
 
 	// 创建 LLM 模型
-	llmModel, err := llm.NewChatModel(ctx)
+	llmModel, err := llm.NewChatModel(llm.WithAgentName(ctx, AgentTitleScraper))
 	if err != nil {
 		panic(fmt.Sprintf("创建 LLM 模型失败: %v", err))
 	}
@@ -233,51 +237,46 @@ func NewTitleScraperAgent[I, O any](ctx context.Context, scraperTool tool.Invoka
 
 	// 添加 load 节点 - 准备 prompt
 	_ = cag.AddLambdaNode("load", compose.InvokableLambdaWithOption(func(ctx context.Context, input string, opts ...any) ([]*schema.Message, error) {
-		fmt.Println("[TitleScraper] load 节点被调用, input:", input)
 		var state *models.FlowState
 		if err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, s *models.FlowState) error {
 			state = s
 			state.URL = input
 			return nil
 		}); err != nil {
-			fmt.Println("[TitleScraper] ProcessState 失败:", err)
 			return nil, err
 		}
-		fmt.Println("[TitleScraper] state.URL:", state.URL)
+		logging.FromContext(ctx).Debug("开始爬取网页", zap.String("url", state.URL))
 		return loadTitleScraperPrompt(ctx, state, scraperTool)
-	}))
+	}), compose.WithNodeName("load"))
 
 	// 添加 agent 节点，包装以添加日志
 	_ = cag.AddLambdaNode("agent", compose.InvokableLambdaWithOption(func(ctx context.Context, input []*schema.Message, opts ...any) (*schema.Message, error) {
-		fmt.Println("[TitleScraper] agent 节点开始执行, 输入消息数:", len(input))
-		for i, msg := range input {
-			fmt.Printf("[TitleScraper] 消息 %d: role=%s, content=%s\n", i, msg.Role, msg.Content[:min(100, len(msg.Content))])
-		}
+		logger := logging.FromContext(ctx)
+		logger.Debug("调用 ReAct Agent", zap.Int("messages", len(input)))
 		result, err := agent.Generate(ctx, input)
 		if err != nil {
-			fmt.Println("[TitleScraper] agent 执行失败:", err)
+			logger.Error("ReAct Agent 执行失败", zap.Error(err))
 			return nil, err
 		}
-		fmt.Println("[TitleScraper] agent 执行成功, 结果:", result.Content[:min(100, len(result.Content))])
+		logger.Debug("ReAct Agent 执行成功", zap.String("result", result.Content[:min(100, len(result.Content))]))
 		return result, nil
-	}))
+	}), compose.WithNodeName("agent"))
 
 	// 添加 router 节点 - 修改 state.Goto 并返回空字符串
 	// 注意：子图输出是 string 类型，但主图通过 state.Goto 决定跳转，不是通过输出
 	_ = cag.AddLambdaNode("router", compose.InvokableLambdaWithOption(func(ctx context.Context, input *schema.Message, opts ...any) (string, error) {
-		fmt.Println("[TitleScraper] router 节点开始执行")
 		err := compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, state *models.FlowState) error {
 			err := routerTitleScraper(ctx, input, state)
 			if err != nil {
-				fmt.Println("[TitleScraper] routerTitleScraper 失败:", err)
+				logging.FromContext(ctx).Error("解析爬取结果失败", zap.Error(err))
 			} else {
-				fmt.Printf("[TitleScraper] router 执行成功, 下一步: %s\n", state.Goto)
+				logging.FromContext(ctx).Debug("已提取网页标题", zap.String("title", state.Title), zap.String("next", state.Goto))
 			}
 			return err
 		})
 		// 返回空字符串，主图通过 state.Goto 决定下一步
 		return "", err
-	}))
+	}), compose.WithNodeName("router"))
 
 	// 添加边
 	_ = cag.AddEdge(compose.START, "load")
@@ -7,32 +7,83 @@
 package agents
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
-// GetPromptTemplate 加载 prompt 模板文件
-func GetPromptTemplate(name string) (string, error) {
-	dir, err := os.Getwd()
+// GetPromptTemplate 返回当前生效的 prompt 模板（上下文中指定的实验变体优先），并在上下文的记录器中记录所用版本
+func GetPromptTemplate(ctx context.Context, name string) (string, error) {
+	p, err := prompts.Resolve(ctx, name)
 	if err != nil {
-		dir = "."
+		return "", err
+	}
+	if rec := prompts.RecorderFromContext(ctx); rec != nil {
+		rec.Record(p)
+	}
+	return p.Content, nil
+}
+
+// localize 在 system prompt 之后追加输出语言要求
+// 中文是 prompt 的原始语言，不追加任何内容，请求保持不变
+func localize(sysPrompt, lang string) string {
+	instruction := i18n.Instruction(lang)
+	if instruction == "" {
+		return sysPrompt
 	}
+	return sysPrompt + "\n\n" + instruction
+}
+
+// searchAgentLambda 将 ReAct Agent 包装为 Lambda，工具调用使用 State 中的搜索选项（可能已按网页语言补全）
+func searchAgentLambda(a *react.Agent) (*compose.Lambda, error) {
+	withOptions := func(ctx context.Context) context.Context {
+		_ = compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, s *models.FlowState) error {
+			ctx = tools.WithSearchOptions(ctx, s.SearchOptions)
+			return nil
+		})
+		return ctx
+	}
+	return compose.AnyLambda(
+		func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+			return a.Generate(withOptions(ctx), input, opts...)
+		},
+		func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+			return a.Stream(withOptions(ctx), input, opts...)
+		},
+		nil, nil)
+}
 
-	possiblePaths := []string{
-		filepath.Join(dir, "internal", "agent", "geo", "flow", "prompts", fmt.Sprintf("%s.md", name)),
-		filepath.Join(dir, "prompts", fmt.Sprintf("%s.md", name)),
-		filepath.Join(dir, "flow", "prompts", fmt.Sprintf("%s.md", name)),
+// recordParseFailure 记录模型输出无法按 JSON 解析（降级为文本提取），用于比较 prompt 版本的输出稳定性
+func recordParseFailure(ctx context.Context, name string, err error) {
+	logging.FromContext(ctx).Warn("解析模型输出失败，降级处理", zap.String("prompt", name), zap.Error(err))
+	if rec := prompts.RecorderFromContext(ctx); rec != nil {
+		rec.RecordParseFailure(name)
 	}
+}
 
-	for _, path := range possiblePaths {
-		content, err := os.ReadFile(path)
-		if err == nil {
-			return string(content), nil
-		}
+// parseJSONObject 解析模型输出的 JSON 对象，兼容 JSON 外包裹说明文字或代码块的情况
+func parseJSONObject(content string, v any) error {
+	err := json.Unmarshal([]byte(content), v)
+	if err == nil {
+		return nil
 	}
 
-	return "", fmt.Errorf("prompt template not found: %s", name)
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		return json.Unmarshal([]byte(content[start:end+1]), v)
+	}
+	return err
 }
 
 // StringPtr 返回字符串指针
@@ -11,32 +11,32 @@ import (
 	"fmt"
 
 	"github.com/cloudwego/eino/compose"
+	"go.uber.org/zap"
 
 	"github.com/solariswu/peanut/internal/agent/geo/flow/agents"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
 	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
 // agentHandOff 子图流转函数
 // 参考 deer-go: branch 函数的 input 类型是 Graph 的输出类型 (string)，不是 *State
 // 需要通过 compose.ProcessState 来访问 state
 func agentHandOff(ctx context.Context, input string) (next string, err error) {
-	fmt.Printf("[agentHandOff] 被调用, input=%s\n", input)
 	err = compose.ProcessState[*State](ctx, func(_ context.Context, state *State) error {
 		if state.Goto == "" {
 			next = compose.END
-			fmt.Println("[agentHandOff] state.Goto 为空, 跳转到 END")
 		} else {
 			next = state.Goto
-			fmt.Printf("[agentHandOff] 从 state.Goto 获取下一步: %s\n", next)
 		}
 		return nil
 	})
 	if err != nil {
-		fmt.Printf("[agentHandOff] ProcessState 失败: %v\n", err)
+		logging.FromContext(ctx).Error("读取 Flow State 失败", zap.Error(err))
+		return next, err
 	}
-	fmt.Printf("[agentHandOff] 返回 next=%s\n", next)
-	return next, err
+	logging.FromContext(ctx).Debug("Agent 流转", zap.String("input", input), zap.String("next", next))
+	return next, nil
 }
 
 // BuildGraph 构建 GEO Flow Graph（使用默认 CheckPointStore）
@@ -48,21 +48,30 @@ func BuildGraph[I, O, S any](ctx context.Context, genLocalState func(ctx context
 // BuildGraphWithCheckpoint 构建 GEO Flow Graph（使用指定的 CheckPointStore）
 func BuildGraphWithCheckpoint[I, O, S any](ctx context.Context, genLocalState func(ctx context.Context) S, checkPointStore compose.CheckPointStore) (compose.Runnable[I, O], error) {
 	// 初始化工具
+	var scraper tools.WebScraper
 	scraper, err := tools.NewBrightDataWebScraper()
 	if err != nil {
 		return nil, fmt.Errorf("创建 scraper tool 失败: %w", err)
 	}
 
-	searcher, err := tools.NewBrightDataSearcher()
+	// 搜索和 AI 回答提供者由 GEO_SEARCH_PROVIDERS / GEO_ANSWER_PROVIDERS 配置，按顺序故障转移
+	searcher, err := tools.NewSearcherFromEnv()
 	if err != nil {
 		return nil, fmt.Errorf("创建 searcher tool 失败: %w", err)
 	}
 
-	serp, err := tools.NewBrightDataSERPProvider()
+	answers, err := tools.NewAnswerProviderFromEnv()
 	if err != nil {
 		return nil, fmt.Errorf("创建 serp tool 失败: %w", err)
 	}
 
+	// 配置了缓存时，外部调用结果按来源分别缓存（LLM 缓存由 llm.NewChatModel 根据 ctx 启用）
+	if cfg := cacheFromContext(ctx); cfg != nil {
+		scraper = tools.NewCachedScraper(scraper, cfg.store, cfg.ttls.Scrape)
+		searcher = tools.NewCachedSearcher(searcher, cfg.store, cfg.ttls.Search)
+		answers = tools.NewCachedAnswerProvider(answers, cfg.store, cfg.ttls.Answer)
+	}
+
 	// 创建 Graph
 	g := compose.NewGraph[I, O](
 		compose.WithGenLocalState(genLocalState),
@@ -74,38 +83,46 @@ func BuildGraphWithCheckpoint[I, O, S any](ctx context.Context, genLocalState fu
 		AgentQueryResearcher:     true,
 		AgentMainQueryExtractor:  true,
 		AgentAIOverviewRetriever: true,
+		AgentCitationAnalyzer:    true,
 		AgentQuerySummarizer:     true,
 		AgentContentOptimizer:    true,
 		AgentContentRewriter:     true,
+		AgentValidator:           true,
 		compose.END:              true,
 	}
 
 	// 创建各 Agent 子图
-	titleScraperGraph := agents.NewTitleScraperAgent[I, O](ctx, scraper)
-	queryResearcherGraph := agents.NewQueryResearcherAgent[I, O](ctx, searcher)
+	titleScraperGraph := agents.NewTitleScraperAgent[I, O](ctx, tools.NewScrapeTool(scraper))
+	queryResearcherGraph := agents.NewQueryResearcherAgent[I, O](ctx, tools.NewSearchTool(searcher))
 	mainQueryExtractorGraph := agents.NewMainQueryExtractorAgent[I, O](ctx)
-	aiOverviewRetrieverGraph := agents.NewAIOverviewRetrieverAgent[I, O](ctx, serp)
+	aiOverviewRetrieverGraph := agents.NewAIOverviewRetrieverAgent[I, O](ctx, tools.NewAnswerTool(answers))
+	citationAnalyzerGraph := agents.NewCitationAnalyzerAgent[I, O](ctx, scraper)
 	querySummarizerGraph := agents.NewQuerySummarizerAgent[I, O](ctx)
 	contentOptimizerGraph := agents.NewContentOptimizerAgent[I, O](ctx)
 	contentRewriterGraph := agents.NewContentRewriterAgent[I, O](ctx)
+	contentValidatorGraph := agents.NewContentValidatorAgent[I, O](ctx)
 
 	// 添加节点到 Graph
 	_ = g.AddGraphNode(AgentTitleScraper, titleScraperGraph, compose.WithNodeName(AgentTitleScraper))
 	_ = g.AddGraphNode(AgentQueryResearcher, queryResearcherGraph, compose.WithNodeName(AgentQueryResearcher))
 	_ = g.AddGraphNode(AgentMainQueryExtractor, mainQueryExtractorGraph, compose.WithNodeName(AgentMainQueryExtractor))
 	_ = g.AddGraphNode(AgentAIOverviewRetriever, aiOverviewRetrieverGraph, compose.WithNodeName(AgentAIOverviewRetriever))
+	_ = g.AddGraphNode(AgentCitationAnalyzer, citationAnalyzerGraph, compose.WithNodeName(AgentCitationAnalyzer))
 	_ = g.AddGraphNode(AgentQuerySummarizer, querySummarizerGraph, compose.WithNodeName(AgentQuerySummarizer))
 	_ = g.AddGraphNode(AgentContentOptimizer, contentOptimizerGraph, compose.WithNodeName(AgentContentOptimizer))
 	_ = g.AddGraphNode(AgentContentRewriter, contentRewriterGraph, compose.WithNodeName(AgentContentRewriter))
+	_ = g.AddGraphNode(AgentValidator, contentValidatorGraph, compose.WithNodeName(AgentValidator))
 
 	// 添加分支
 	_ = g.AddBranch(AgentTitleScraper, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentQueryResearcher, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentMainQueryExtractor, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentAIOverviewRetriever, compose.NewGraphBranch(agentHandOff, outMap))
+	_ = g.AddBranch(AgentCitationAnalyzer, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentQuerySummarizer, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentContentOptimizer, compose.NewGraphBranch(agentHandOff, outMap))
 	_ = g.AddBranch(AgentContentRewriter, compose.NewGraphBranch(agentHandOff, outMap))
+	_ = g.AddBranch(AgentValidator, compose.NewGraphBranch(agentHandOff, outMap))
 
 	// 设置起始节点
 	_ = g.AddEdge(compose.START, AgentTitleScraper)
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Flow Cache - 构建 Graph 时的响应缓存配置
+ */
+
+package flow
+
+import (
+	"context"
+
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/pkg/cache"
+)
+
+// cacheConfigKey 是构建 Graph 时缓存配置的上下文键
+type cacheConfigKey struct{}
+
+// cacheConfig 响应缓存配置
+type cacheConfig struct {
+	store cache.Store
+	ttls  tools.CacheTTLs
+}
+
+// WithCache 配置构建 Graph 时使用的响应缓存：搜索、AI 回答、网页爬取和 LLM 调用均按各自 TTL 缓存
+func WithCache(ctx context.Context, store cache.Store, ttls tools.CacheTTLs) context.Context {
+	if store == nil {
+		return ctx
+	}
+	ctx = llm.WithResponseCache(ctx, store, ttls.LLM)
+	return context.WithValue(ctx, cacheConfigKey{}, &cacheConfig{store: store, ttls: ttls})
+}
+
+// cacheFromContext 获取缓存配置，未配置时返回 nil
+func cacheFromContext(ctx context.Context) *cacheConfig {
+	cfg, _ := ctx.Value(cacheConfigKey{}).(*cacheConfig)
+	return cfg
+}
@@ -14,6 +14,7 @@ const (
 	AgentQueryResearcher     = "query_researcher"
 	AgentMainQueryExtractor  = "main_query_extractor"
 	AgentAIOverviewRetriever = "ai_overview_retriever"
+	AgentCitationAnalyzer    = "citation_analyzer"
 	AgentQuerySummarizer     = "query_summarizer"
 	AgentContentOptimizer    = "content_optimizer"
 	AgentContentRewriter     = "content_rewriter"
@@ -23,3 +24,6 @@ const (
 	StepStart = "start"
 	StepEnd   = "end"
 )
+
+// TotalSteps GEO Flow 的总步骤数（用于进度计算）
+const TotalSteps = 9
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Flow 日志 - 为各 Agent 子图的上下文日志器追加 agent 字段
+ */
+
+package flow
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/pkg/logging"
+)
+
+// agentNames GEO Flow 中的 Agent 子图节点
+var agentNames = map[string]bool{
+	AgentTitleScraper:        true,
+	AgentQueryResearcher:     true,
+	AgentMainQueryExtractor:  true,
+	AgentAIOverviewRetriever: true,
+	AgentCitationAnalyzer:    true,
+	AgentQuerySummarizer:     true,
+	AgentContentOptimizer:    true,
+	AgentContentRewriter:     true,
+	AgentValidator:           true,
+}
+
+// agentStartKey 是 Agent 开始时间的上下文键
+type agentStartKey struct{}
+
+// NewLogHandler 创建日志回调处理器：Agent 子图开始执行时为上下文日志器追加 agent 字段，
+// 子图内的节点、模型和工具通过 logging.FromContext 获取带字段的日志器
+func NewLogHandler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if !agentNames[info.Name] {
+				return ctx
+			}
+			ctx = logging.With(ctx, zap.String("agent", info.Name))
+			logging.FromContext(ctx).Debug("Agent 开始执行")
+			return context.WithValue(ctx, agentStartKey{}, time.Now())
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if start, ok := ctx.Value(agentStartKey{}).(time.Time); ok && agentNames[info.Name] {
+				logging.FromContext(ctx).Info("Agent 执行完成", zap.Duration("duration", time.Since(start)))
+			}
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if agentNames[info.Name] {
+				logging.FromContext(ctx).Error("Agent 执行失败", zap.Error(err))
+			}
+			return ctx
+		}).
+		Build()
+}
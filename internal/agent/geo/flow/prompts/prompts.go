@@ -0,0 +1,342 @@
+// Package prompts 管理 GEO Agent 的 prompt 模板
+//
+// 查找顺序：数据库中激活的版本 > 覆盖目录（GEO_PROMPT_DIRS）中的文件 > 编译时内嵌的默认模板
+package prompts
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 模板来源
+const (
+	SourceBuiltin = "builtin" // 编译时内嵌
+	SourceFile    = "file"    // 覆盖目录
+	SourceDB      = "db"      // 数据库（管理 API 编辑）
+)
+
+//go:embed *.md
+var builtinFS embed.FS
+
+// Prompt 一个 prompt 模板
+type Prompt struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Source  string `json:"source"`
+	// Version 唯一标识模板内容：数据库版本为 db:v<N>，其余为 <source>:<内容哈希前 8 位>
+	Version string `json:"version"`
+	Path    string `json:"path,omitempty"` // 覆盖目录中的文件路径
+}
+
+// newFilePrompt 创建内嵌或覆盖目录中的模板，版本号取内容哈希
+func newFilePrompt(name, content, source, path string) Prompt {
+	sum := sha256.Sum256([]byte(content))
+	return Prompt{
+		Name:    name,
+		Content: content,
+		Source:  source,
+		Version: source + ":" + hex.EncodeToString(sum[:4]),
+		Path:    path,
+	}
+}
+
+// NewDBPrompt 创建数据库中的模板
+func NewDBPrompt(name, content string, version int) Prompt {
+	return Prompt{
+		Name:    name,
+		Content: content,
+		Source:  SourceDB,
+		Version: fmt.Sprintf("%s:v%d", SourceDB, version),
+	}
+}
+
+// Registry 按来源优先级查找 prompt 模板，并发安全
+type Registry struct {
+	mu        sync.RWMutex
+	dirs      []string
+	builtin   map[string]Prompt
+	files     map[string]Prompt
+	overrides map[string]Prompt
+}
+
+// NewRegistry 创建模板注册表，dirs 为覆盖目录（靠后的目录优先），目录不存在时忽略
+func NewRegistry(dirs ...string) (*Registry, error) {
+	r := &Registry{
+		dirs:      dirs,
+		builtin:   loadBuiltin(),
+		overrides: map[string]Prompt{},
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// loadBuiltin 读取内嵌的模板
+func loadBuiltin() map[string]Prompt {
+	entries, _ := fs.ReadDir(builtinFS, ".")
+	builtin := make(map[string]Prompt, len(entries))
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".md")
+		content, err := builtinFS.ReadFile(e.Name())
+		if err != nil {
+			continue
+		}
+		builtin[name] = newFilePrompt(name, string(content), SourceBuiltin, "")
+	}
+	return builtin
+}
+
+// Reload 重新读取覆盖目录中的模板，任一文件无效时保留原有模板
+func (r *Registry) Reload() error {
+	files := map[string]Prompt{}
+	for _, dir := range r.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("读取 prompt 目录 %s 失败: %w", dir, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("读取 prompt 文件 %s 失败: %w", path, err)
+			}
+			if err := Validate(context.Background(), string(content)); err != nil {
+				return fmt.Errorf("prompt 文件 %s: %w", path, err)
+			}
+			name := strings.TrimSuffix(e.Name(), ".md")
+			files[name] = newFilePrompt(name, string(content), SourceFile, path)
+		}
+	}
+
+	r.mu.Lock()
+	r.files = files
+	r.mu.Unlock()
+	return nil
+}
+
+// Dirs 返回覆盖目录
+func (r *Registry) Dirs() []string {
+	return r.dirs
+}
+
+// Get 返回当前生效的模板
+func (r *Registry) Get(name string) (Prompt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if p, ok := r.overrides[name]; ok {
+		return p, nil
+	}
+	if p, ok := r.files[name]; ok {
+		return p, nil
+	}
+	if p, ok := r.builtin[name]; ok {
+		return p, nil
+	}
+	return Prompt{}, fmt.Errorf("prompt 模板不存在: %s", name)
+}
+
+// Builtin 返回内嵌的默认模板
+func (r *Registry) Builtin(name string) (Prompt, bool) {
+	p, ok := r.builtin[name]
+	return p, ok
+}
+
+// Names 返回所有模板名称（内嵌模板和覆盖目录中的模板），按名称排序
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range []map[string]Prompt{r.builtin, r.files, r.overrides} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetOverride 设置数据库中激活的模板
+func (r *Registry) SetOverride(p Prompt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[p.Name] = p
+}
+
+// RemoveOverride 移除数据库模板，恢复使用覆盖目录或内嵌模板
+func (r *Registry) RemoveOverride(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, name)
+}
+
+// SetOverrides 替换全部数据库模板
+func (r *Registry) SetOverrides(prompts []Prompt) {
+	overrides := make(map[string]Prompt, len(prompts))
+	for _, p := range prompts {
+		overrides[p.Name] = p
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides = overrides
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultRegistry *Registry
+)
+
+// DirsFromEnv 读取 GEO_PROMPT_DIRS（逗号分隔的覆盖目录）
+func DirsFromEnv() []string {
+	var dirs []string
+	for _, dir := range strings.Split(os.Getenv("GEO_PROMPT_DIRS"), ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// SetDefault 设置 Agent 使用的默认注册表，返回恢复原注册表的函数
+func SetDefault(r *Registry) (restore func()) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	prev := defaultRegistry
+	defaultRegistry = r
+	return func() {
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		defaultRegistry = prev
+	}
+}
+
+// Default 返回默认注册表，未设置时只包含内嵌模板
+func Default() *Registry {
+	defaultMu.RLock()
+	r := defaultRegistry
+	defaultMu.RUnlock()
+	if r != nil {
+		return r
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultRegistry == nil {
+		defaultRegistry, _ = NewRegistry()
+	}
+	return defaultRegistry
+}
+
+// overridesKey 是单次分析模板覆盖的上下文键
+type overridesKey struct{}
+
+// WithOverride 为单次分析指定模板（如 A/B 实验的变体），优先于注册表
+func WithOverride(ctx context.Context, p Prompt) context.Context {
+	prev, _ := ctx.Value(overridesKey{}).(map[string]Prompt)
+	overrides := make(map[string]Prompt, len(prev)+1)
+	for name, o := range prev {
+		overrides[name] = o
+	}
+	overrides[p.Name] = p
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+// Resolve 返回上下文中指定的模板，未指定时返回默认注册表中生效的模板
+func Resolve(ctx context.Context, name string) (Prompt, error) {
+	if overrides, ok := ctx.Value(overridesKey{}).(map[string]Prompt); ok {
+		if p, ok := overrides[name]; ok {
+			return p, nil
+		}
+	}
+	return Default().Get(name)
+}
+
+// recorderKey 是模板版本记录器的上下文键
+type recorderKey struct{}
+
+// Recorder 记录一次分析中各 Agent 使用的模板版本和输出解析失败次数，并发安全
+type Recorder struct {
+	mu            sync.Mutex
+	versions      map[string]string
+	parseFailures map[string]int
+}
+
+// NewRecorder 创建模板版本记录器
+func NewRecorder() *Recorder {
+	return &Recorder{
+		versions:      map[string]string{},
+		parseFailures: map[string]int{},
+	}
+}
+
+// WithRecorder 在上下文中设置模板版本记录器
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rec)
+}
+
+// RecorderFromContext 从上下文获取模板版本记录器
+func RecorderFromContext(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// Record 记录模板版本
+func (r *Recorder) Record(p Prompt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions[p.Name] = p.Version
+}
+
+// Versions 返回模板名称到版本的映射
+func (r *Recorder) Versions() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := make(map[string]string, len(r.versions))
+	for name, v := range r.versions {
+		versions[name] = v
+	}
+	return versions
+}
+
+// RecordParseFailure 记录模型输出无法按模板要求的格式解析
+func (r *Recorder) RecordParseFailure(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parseFailures[name]++
+}
+
+// ParseFailures 返回模板名称到解析失败次数的映射
+func (r *Recorder) ParseFailures() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failures := make(map[string]int, len(r.parseFailures))
+	for name, n := range r.parseFailures {
+		failures[name] = n
+	}
+	return failures
+}
@@ -0,0 +1,108 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Priority(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	p, err := r.Get("title_scraper")
+	if err != nil || p.Source != SourceBuiltin || !strings.HasPrefix(p.Version, "builtin:") {
+		t.Fatalf("Get() = %+v, %v, want builtin prompt", p, err)
+	}
+
+	// 覆盖目录优先于内嵌模板
+	path := filepath.Join(dir, "title_scraper.md")
+	if err := os.WriteFile(path, []byte("custom {{url}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if p, _ := r.Get("title_scraper"); p.Source != SourceFile || p.Content != "custom {{url}}" || p.Path != path {
+		t.Errorf("after Reload Get() = %+v, want file prompt", p)
+	}
+
+	// 无效文件不会替换已加载的模板
+	if err := os.WriteFile(path, []byte("broken {{url"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Reload() error = %v, want ErrInvalid", err)
+	}
+	if p, _ := r.Get("title_scraper"); p.Content != "custom {{url}}" {
+		t.Errorf("invalid file replaced prompt: %q", p.Content)
+	}
+
+	// 数据库版本优先，移除后恢复覆盖目录中的模板
+	r.SetOverride(NewDBPrompt("title_scraper", "db", 3))
+	if p, _ := r.Get("title_scraper"); p.Version != "db:v3" {
+		t.Errorf("Get() version = %q, want db:v3", p.Version)
+	}
+	r.RemoveOverride("title_scraper")
+	if p, _ := r.Get("title_scraper"); p.Source != SourceFile {
+		t.Errorf("after RemoveOverride source = %q, want file", p.Source)
+	}
+
+	if _, err := r.Get("unknown"); err == nil {
+		t.Error("Get(unknown) error = nil")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{"plain text", "{{ url }}", "{% if a %}x{% endif %}", "{# note #}"}
+	for _, content := range valid {
+		if err := Validate(context.Background(), content); err != nil {
+			t.Errorf("Validate(%q) error = %v", content, err)
+		}
+	}
+
+	invalid := []string{"{{ url", "x }} {{", "{% if a %}x", "{{ a | nofilter }}"}
+	for _, content := range invalid {
+		if err := Validate(context.Background(), content); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%q) error = %v, want ErrInvalid", content, err)
+		}
+	}
+}
+
+func TestResolve_ContextOverride(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	defer SetDefault(r)()
+
+	ctx := context.Background()
+	if p, err := Resolve(ctx, "title_scraper"); err != nil || p.Source != SourceBuiltin {
+		t.Fatalf("Resolve() = %+v, %v, want builtin prompt", p, err)
+	}
+
+	// 上下文中的实验变体优先于注册表，且只影响本次分析
+	variant := WithOverride(ctx, NewDBPrompt("title_scraper", "variant", 2))
+	if p, _ := Resolve(variant, "title_scraper"); p.Version != "db:v2" {
+		t.Errorf("Resolve(variant) version = %q, want db:v2", p.Version)
+	}
+	if p, _ := Resolve(variant, "main_query_extractor"); p.Source != SourceBuiltin {
+		t.Errorf("Resolve(other) source = %q, want builtin", p.Source)
+	}
+	if p, _ := Resolve(ctx, "title_scraper"); p.Source != SourceBuiltin {
+		t.Errorf("Resolve(ctx) source = %q, want builtin", p.Source)
+	}
+
+	rec := NewRecorder()
+	rec.RecordParseFailure("title_scraper")
+	rec.RecordParseFailure("title_scraper")
+	if n := rec.ParseFailures()["title_scraper"]; n != 2 {
+		t.Errorf("ParseFailures() = %d, want 2", n)
+	}
+}
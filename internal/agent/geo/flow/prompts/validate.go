@@ -0,0 +1,42 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrInvalid prompt 模板无效
+var ErrInvalid = errors.New("prompt 模板无效")
+
+// delimiters Jinja2 的表达式、语句和注释定界符
+var delimiters = [][2]string{{"{{", "}}"}, {"{%", "%}"}, {"{#", "#}"}}
+
+// Validate 检查模板能否按 Jinja2 语法渲染
+// 先检查定界符是否闭合：gonja 的词法分析器遇到未闭合的定界符会陷入死循环
+func Validate(ctx context.Context, content string) error {
+	for _, d := range delimiters {
+		rest := content
+		for {
+			start := strings.Index(rest, d[0])
+			if start < 0 {
+				break
+			}
+			end := strings.Index(rest[start+len(d[0]):], d[1])
+			if end < 0 {
+				return fmt.Errorf("%w: %s 未闭合", ErrInvalid, d[0])
+			}
+			rest = rest[start+len(d[0])+end+len(d[1]):]
+		}
+	}
+
+	tpl := prompt.FromMessages(schema.Jinja2, schema.SystemMessage(content))
+	if _, err := tpl.Format(ctx, map[string]any{}); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// reloadDelay 合并编辑器保存时连续触发的文件事件
+const reloadDelay = 200 * time.Millisecond
+
+// Watch 监听覆盖目录，文件变化时重新加载模板，直到 ctx 取消
+// 监听目录而非文件，以兼容编辑器先写临时文件再重命名的保存方式
+func (r *Registry) Watch(ctx context.Context) error {
+	if len(r.dirs) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("创建 prompt 目录监听失败: %w", err)
+	}
+	for _, dir := range r.dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("监听 prompt 目录 %s 失败: %w", dir, err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var timer *time.Timer
+		reload := make(chan struct{}, 1)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Ext(event.Name) != ".md" {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(reloadDelay, func() {
+					select {
+					case reload <- struct{}{}:
+					default:
+					}
+				})
+			case <-reload:
+				if err := r.Reload(); err != nil {
+					zap.L().Warn("重新加载 prompt 失败", zap.Error(err))
+					continue
+				}
+				zap.L().Info("prompt 已重新加载", zap.Strings("dirs", r.dirs))
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				zap.L().Warn("监听 prompt 目录出错", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
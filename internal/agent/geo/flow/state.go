@@ -8,9 +8,13 @@ package flow
 
 import (
 	"context"
-	"fmt"
 
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
 // State 导出 models.FlowState
@@ -35,17 +39,31 @@ func GetProgressCallback(ctx context.Context) func(step int, total int, agentNam
 	return nil
 }
 
+// stateCallbackKey 是存储状态回调的上下文键
+type stateCallbackKey struct{}
+
+// WithStateCallback 将状态回调添加到上下文，Graph 创建本次运行的 State 时调用，
+// 用于在运行结束后读取最终状态
+func WithStateCallback(ctx context.Context, callback func(state *State)) context.Context {
+	return context.WithValue(ctx, stateCallbackKey{}, callback)
+}
+
 // GenLocalState 生成 Local State 的工厂函数
 func GenLocalState(ctx context.Context) *State {
-	fmt.Println("[GEO] GenLocalState 被调用")
 	state := models.GenFlowState(ctx)
+	state.SearchOptions = tools.SearchOptionsFromContext(ctx)
+	state.OutputLanguage = i18n.FromContext(ctx)
 
 	// 从上下文中获取进度回调并设置到 state
 	if callback := GetProgressCallback(ctx); callback != nil {
 		state.OnProgress = callback
-		state.TotalSteps = 7
-		fmt.Println("[GEO] GenLocalState: 进度回调已设置")
+		state.TotalSteps = TotalSteps
+	}
+
+	if callback, ok := ctx.Value(stateCallbackKey{}).(func(state *State)); ok {
+		callback(state)
 	}
 
+	logging.FromContext(ctx).Debug("创建 Flow State", zap.Bool("progress", state.OnProgress != nil))
 	return state
 }
@@ -0,0 +1,126 @@
+package i18n
+
+import "fmt"
+
+// messages 消息目录：报告标题和文字、Agent 的用户消息和输出语言要求
+// 报告输出语言只有中文和英文；其他网页语言只用到输出语言要求，缺少的翻译使用英文
+var messages = map[string]map[string]string{
+	Zh: {
+		// 报告
+		"title":            "GEO 优化报告",
+		"summary":          "基本信息",
+		"url":              "URL",
+		"page_title":       "标题",
+		"main_query":       "主查询",
+		"overall_score":    "总体评分",
+		"generated_at":     "生成时间",
+		"comparison":       "对比分析",
+		"dimension":        "维度",
+		"your_content":     "查询发散总结",
+		"ai_overview":      "AI Overview",
+		"similarity":       "相似点",
+		"difference":       "差异",
+		"content_gaps":     "内容差距",
+		"competitors":      "竞品引用分析",
+		"missing_facts":    "缺失的事实",
+		"missing_formats":  "缺失的内容形式",
+		"missing_entities": "缺失的实体",
+		"sources":          "被引用的来源",
+		"serp_features":    "搜索结果页模块",
+		"people_also_ask":  "相关问题",
+		"related_searches": "相关搜索",
+		"suggestions":      "优化建议",
+		"priority_high":    "🔴 高优先级",
+		"priority_medium":  "🟡 中优先级",
+		"priority_low":     "🟢 低优先级",
+		"report":           "详细报告",
+		"article":          "优化后的文章",
+		"exec_summary":     "执行摘要",
+		"key_findings":     "关键发现",
+		"conclusion":       "结论",
+		"generated":        "生成于",
+		"page":             "第 %d / %d 页",
+		"page_prefix":      "第 ",
+		"page_suffix":      " 页",
+
+		// Agent 用户消息和 prompt 变量
+		"start_research": "开始研究",
+		"start_compare":  "开始对比",
+		"none":           "无",
+	},
+	En: {
+		"title":            "GEO Optimization Report",
+		"summary":          "Overview",
+		"url":              "URL",
+		"page_title":       "Title",
+		"main_query":       "Main query",
+		"overall_score":    "Overall score",
+		"generated_at":     "Generated at",
+		"comparison":       "Comparison",
+		"dimension":        "Dimension",
+		"your_content":     "Query fan-out summary",
+		"ai_overview":      "AI Overview",
+		"similarity":       "Similarities",
+		"difference":       "Differences",
+		"content_gaps":     "Content Gaps",
+		"competitors":      "Competitor Citations",
+		"missing_facts":    "Missing facts",
+		"missing_formats":  "Missing formats",
+		"missing_entities": "Missing entities",
+		"sources":          "Cited sources",
+		"serp_features":    "SERP Features",
+		"people_also_ask":  "People also ask",
+		"related_searches": "Related searches",
+		"suggestions":      "Recommendations",
+		"priority_high":    "🔴 High priority",
+		"priority_medium":  "🟡 Medium priority",
+		"priority_low":     "🟢 Low priority",
+		"report":           "Full Report",
+		"article":          "Optimized Article",
+		"exec_summary":     "Executive Summary",
+		"key_findings":     "Key Findings",
+		"conclusion":       "Conclusion",
+		"generated":        "Generated",
+		"page":             "Page %d of %d",
+		"page_prefix":      "Page ",
+		"page_suffix":      "",
+
+		"start_research": "Start the research.",
+		"start_compare":  "Start the comparison.",
+		"none":           "None",
+
+		// 输出语言要求，追加在中文 system prompt 之后
+		"respond_in": "Write all natural-language output in %s, regardless of the language of these instructions. " +
+			"Keep JSON keys, field names, enum values and any required output structure exactly as specified.",
+	},
+}
+
+// T 返回语言的消息，未指定语言时使用默认语言，缺少翻译时依次使用英文、中文
+func T(lang, key string) string {
+	if lang == "" {
+		lang = Default
+	}
+	for _, l := range []string{lang, En, Zh} {
+		if msg, ok := messages[l][key]; ok {
+			return msg
+		}
+	}
+	return key
+}
+
+// Catalog 返回语言的全部消息（缺少的翻译已补全），供报告模板使用
+func Catalog(lang string) map[string]string {
+	catalog := make(map[string]string, len(messages[Zh]))
+	for key := range messages[Zh] {
+		catalog[key] = T(lang, key)
+	}
+	return catalog
+}
+
+// Instruction 返回要求模型使用指定语言输出的说明，中文（prompt 的原始语言）返回空字符串
+func Instruction(lang string) string {
+	if lang == "" || lang == Zh {
+		return ""
+	}
+	return fmt.Sprintf(T(lang, "respond_in"), Name(lang))
+}
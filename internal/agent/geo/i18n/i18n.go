@@ -0,0 +1,147 @@
+// Package i18n 分析输出语言：语言代码、网页语言检测和消息目录
+package i18n
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// 可检测的网页语言，其中只有 Languages 可作为报告输出语言
+const (
+	Zh = "zh"
+	En = "en"
+	Ja = "ja"
+	Ko = "ko"
+	Fr = "fr"
+	De = "de"
+	Es = "es"
+	Pt = "pt"
+)
+
+// Default 默认语言，与 prompt 模板的原始语言一致
+const Default = Zh
+
+// names 各语言在 prompt 中的名称
+var names = map[string]string{
+	Zh: "Simplified Chinese (简体中文)",
+	En: "English",
+	Ja: "Japanese (日本語)",
+	Ko: "Korean (한국어)",
+	Fr: "French (Français)",
+	De: "German (Deutsch)",
+	Es: "Spanish (Español)",
+	Pt: "Portuguese (Português)",
+}
+
+// Languages 支持的报告输出语言代码，即有完整消息目录的语言
+var Languages = []string{Zh, En}
+
+// Normalize 将 zh-CN、en_US 等语言标记规范为输出语言代码，不支持的语言返回空字符串
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if Supported(lang) {
+		return lang
+	}
+	return ""
+}
+
+// Supported 是否为支持的输出语言
+func Supported(lang string) bool {
+	return slices.Contains(Languages, lang)
+}
+
+// OutputFor 返回网页语言对应的默认输出语言，没有消息目录的语言使用英文
+func OutputFor(contentLang string) string {
+	if Supported(contentLang) {
+		return contentLang
+	}
+	return En
+}
+
+// Name 返回语言在 prompt 中的名称
+func Name(lang string) string {
+	if name, ok := names[lang]; ok {
+		return name
+	}
+	return names[Default]
+}
+
+// stopwords 拉丁字母语言的常见虚词，用于区分同为拉丁字母的语言
+var stopwords = map[string][]string{
+	En: {"the", "and", "of", "to", "is", "for", "with", "that", "you", "are"},
+	Fr: {"le", "la", "les", "et", "des", "est", "pour", "une", "dans", "vous"},
+	De: {"der", "die", "und", "das", "ist", "mit", "für", "nicht", "ein", "sie"},
+	Es: {"el", "los", "las", "y", "es", "para", "una", "por", "con", "que"},
+	Pt: {"o", "os", "as", "e", "é", "para", "uma", "com", "não", "que"},
+}
+
+// Detect 按文字的书写系统检测网页语言，无法判断时返回空字符串
+// 中日韩按字符计数（一个汉字约等于一个词），拉丁字母按词计数并用常见虚词区分语言
+func Detect(text string) string {
+	var han, kana, hangul int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		}
+	}
+
+	latin := map[string]int{}
+	words := 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.Is(unicode.Latin, r)
+	}) {
+		words++
+		for lang, list := range stopwords {
+			if slices.Contains(list, word) {
+				latin[lang]++
+			}
+		}
+	}
+
+	cjk := han + kana + hangul
+	switch {
+	case cjk == 0 && words == 0:
+		return ""
+	case cjk >= words:
+		switch {
+		case kana*10 >= cjk:
+			return Ja
+		case hangul > han:
+			return Ko
+		default:
+			return Zh
+		}
+	}
+
+	best, score := En, latin[En]
+	for _, lang := range []string{Fr, De, Es, Pt} {
+		if latin[lang] > score {
+			best, score = lang, latin[lang]
+		}
+	}
+	return best
+}
+
+// languageKey 是存储输出语言的上下文键
+type languageKey struct{}
+
+// WithLanguage 将请求指定的输出语言添加到上下文
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// FromContext 从上下文获取请求指定的输出语言，未指定时返回空字符串
+func FromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(languageKey{}).(string)
+	return lang
+}
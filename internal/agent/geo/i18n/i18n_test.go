@@ -0,0 +1,82 @@
+package i18n
+
+import "testing"
+
+// TestDetect 测试网页语言检测
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"中文", "家用意式咖啡机选购指南：如何选择适合自己的 espresso 机器", Zh},
+		{"英文", "How to choose the best espresso machine for your home kitchen", En},
+		{"日文", "家庭用エスプレッソマシンの選び方とおすすめ", Ja},
+		{"韩文", "가정용 에스프레소 머신 고르는 방법", Ko},
+		{"德文", "Die besten Espressomaschinen für die Küche und das Büro", De},
+		{"法文", "Comment choisir une machine à espresso pour la maison et le bureau", Fr},
+		{"空文本", "  123 ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalize 测试语言标记规范化
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"zh-CN": Zh,
+		"EN_us": En,
+		" en ":  En,
+		"ja":    "",
+		"ru":    "",
+		"":      "",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestOutputFor 测试网页语言没有消息目录时默认输出英文
+func TestOutputFor(t *testing.T) {
+	tests := map[string]string{
+		Zh: Zh,
+		En: En,
+		Ja: En,
+		De: En,
+	}
+	for in, want := range tests {
+		if got := OutputFor(in); got != want {
+			t.Errorf("OutputFor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestT 测试消息目录的回退顺序
+func TestT(t *testing.T) {
+	if got := T("", "start_research"); got != "开始研究" {
+		t.Errorf("未指定语言应使用中文, got %q", got)
+	}
+	if got := T(En, "suggestions"); got != "Recommendations" {
+		t.Errorf("T(en) = %q", got)
+	}
+	if got := T(Ja, "suggestions"); got != "Recommendations" {
+		t.Errorf("缺少翻译时应使用英文, got %q", got)
+	}
+	if got := T(Zh, "unknown_key"); got != "unknown_key" {
+		t.Errorf("未知消息应返回键名, got %q", got)
+	}
+	if Instruction(Zh) != "" || Instruction("") != "" {
+		t.Error("中文不应追加输出语言要求")
+	}
+	if got := Catalog(De)["title"]; got != "GEO Optimization Report" {
+		t.Errorf("Catalog(de) 应补全英文, got %q", got)
+	}
+}
@@ -11,13 +11,22 @@ import (
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
+// AgentHeader 标记发起调用的 Agent 的请求头，模拟服务据此返回对应 Agent 的回答
+const AgentHeader = "X-Peanut-Agent"
+
 // ArkClient 火山引擎豆包模型客户端
 type ArkClient struct {
-	apiKey  string
-	baseURL string
-	model   string
+	apiKey     string
+	baseURL    string
+	model      string
+	agent      string // 发起调用的 Agent，非空时通过 AgentHeader 发送
+	httpClient *http.Client
 }
 
 // ChatMessage 聊天消息
@@ -32,6 +41,22 @@ type ChatRequest struct {
 	Messages []ChatMessage `json:"messages"`
 }
 
+// BaseURL 返回 Ark API 地址（ARK_BASE_URL）
+func BaseURL() string {
+	if baseURL := os.Getenv("ARK_BASE_URL"); baseURL != "" {
+		return baseURL
+	}
+	return "https://ark.cn-beijing.volces.com/api/v3"
+}
+
+// ModelName 返回使用的模型（ARK_MODEL）
+func ModelName() string {
+	if modelName := os.Getenv("ARK_MODEL"); modelName != "" {
+		return modelName
+	}
+	return "doubao-seed-2-0-pro-260215"
+}
+
 // NewArkClient 创建火山引擎豆包模型客户端
 func NewArkClient() (*ArkClient, error) {
 	apiKey := os.Getenv("ARK_API_KEY")
@@ -39,20 +64,11 @@ func NewArkClient() (*ArkClient, error) {
 		return nil, fmt.Errorf("未设置 ARK_API_KEY 环境变量")
 	}
 
-	baseURL := os.Getenv("ARK_BASE_URL")
-	if baseURL == "" {
-		baseURL = "https://ark.cn-beijing.volces.com/api/v3"
-	}
-
-	modelName := os.Getenv("ARK_MODEL")
-	if modelName == "" {
-		modelName = "doubao-seed-2-0-pro-260215"
-	}
-
 	return &ArkClient{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		model:   modelName,
+		apiKey:     apiKey,
+		baseURL:    BaseURL(),
+		model:      ModelName(),
+		httpClient: transport.NewClient(0),
 	}, nil
 }
 
@@ -91,26 +107,25 @@ func (c *ArkClient) Generate(ctx context.Context, messages []*schema.Message) (*
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.agent != "" {
+		req.Header.Set(AgentHeader, c.agent)
+	}
 
 	// 设置请求体
 	req.Body = io.NopCloser(strings.NewReader(string(jsonData)))
 
 	// 发送请求（真实实现）
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		// 如果 API 调用失败，返回模拟响应用于测试
-		return &schema.Message{
-			Role:    schema.Assistant,
-			Content: c.getFallbackResponse(messages),
-		}, nil
+		logging.FromContext(ctx).Warn("LLM 调用失败，使用备用响应", zap.String("model", c.model), zap.Error(err))
+		return c.fallbackMessage(messages), nil
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return &schema.Message{
-			Role:    schema.Assistant,
-			Content: c.getFallbackResponse(messages),
-		}, nil
+		logging.FromContext(ctx).Warn("LLM 返回错误状态，使用备用响应", zap.String("model", c.model), zap.Int("status", resp.StatusCode))
+		return c.fallbackMessage(messages), nil
 	}
 
 	// 解析响应
@@ -120,7 +135,9 @@ func (c *ArkClient) Generate(ctx context.Context, messages []*schema.Message) (*
 				Role    string `json:"role"`
 				Content string `json:"content"`
 			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
 		} `json:"choices"`
+		Usage *schema.TokenUsage `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
@@ -134,6 +151,10 @@ func (c *ArkClient) Generate(ctx context.Context, messages []*schema.Message) (*
 	return &schema.Message{
 		Role:    schema.Assistant,
 		Content: chatResp.Choices[0].Message.Content,
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: chatResp.Choices[0].FinishReason,
+			Usage:        chatResp.Usage,
+		},
 	}, nil
 }
 
@@ -160,6 +181,15 @@ func (c *ArkClient) BindTools(tools interface{}) error {
 	return nil
 }
 
+// fallbackMessage 构造备用响应消息，并标记为备用响应（不应被缓存）
+func (c *ArkClient) fallbackMessage(messages []*schema.Message) *schema.Message {
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: c.getFallbackResponse(messages),
+		Extra:   map[string]any{ExtraFallback: true},
+	}
+}
+
 // getFallbackResponse 获取备用响应（用于 API 调用失败时）
 func (c *ArkClient) getFallbackResponse(messages []*schema.Message) string {
 	// 提取用户输入
@@ -218,16 +248,27 @@ type ArkChatModel struct {
 	tools  []*schema.ToolInfo
 }
 
-// NewArkChatModel 创建 Eino 兼容的 ToolCallingChatModel
-func NewArkChatModel() (model.ToolCallingChatModel, error) {
+// NewArkChatModel 创建 Eino 兼容的 ToolCallingChatModel，agent 为发起调用的 Agent 名称（可为空）
+func NewArkChatModel(agent string) (model.ToolCallingChatModel, error) {
 	arkClient, err := NewArkClient()
 	if err != nil {
 		return nil, err
 	}
+	arkClient.agent = agent
 
 	return &ArkChatModel{client: arkClient}, nil
 }
 
+// ModelName 返回模型名称
+func (m *ArkChatModel) ModelName() string {
+	return m.client.model
+}
+
+// GetType 实现 components.Typer 接口，用于回调中的组件类型
+func (m *ArkChatModel) GetType() string {
+	return "Ark"
+}
+
 // Generate 实现 model.ToolCallingChatModel 接口
 func (m *ArkChatModel) Generate(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.Message, error) {
 	return m.client.Generate(ctx, messages)
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/pkg/cache"
+)
+
+// ExtraFallback 标记消息为 API 失败时的备用响应，此类响应不写入缓存
+const ExtraFallback = "fallback"
+
+// cacheSourceLLM LLM 响应缓存的数据来源名称
+const cacheSourceLLM = "llm"
+
+// responseCacheKey 是 LLM 响应缓存配置的上下文键
+type responseCacheKey struct{}
+
+// responseCacheConfig LLM 响应缓存配置
+type responseCacheConfig struct {
+	store cache.Store
+	ttl   time.Duration
+}
+
+// WithResponseCache 在上下文中配置 LLM 响应缓存，NewChatModel 据此返回带缓存的模型
+func WithResponseCache(ctx context.Context, store cache.Store, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, responseCacheKey{}, &responseCacheConfig{store: store, ttl: ttl})
+}
+
+// CachedChatModel 按模型、工具和消息内容缓存 LLM 响应
+type CachedChatModel struct {
+	inner model.ToolCallingChatModel
+	store cache.Store
+	ttl   time.Duration
+	tools []*schema.ToolInfo
+}
+
+// NewCachedChatModel 创建带缓存的 ChatModel
+func NewCachedChatModel(inner model.ToolCallingChatModel, store cache.Store, ttl time.Duration) *CachedChatModel {
+	return &CachedChatModel{inner: inner, store: store, ttl: ttl}
+}
+
+// cacheKey 以模型名、工具名和消息（角色、内容、工具调用）生成缓存键
+func (m *CachedChatModel) cacheKey(messages []*schema.Message) string {
+	modelName := ""
+	if named, ok := m.inner.(interface{ ModelName() string }); ok {
+		modelName = named.ModelName()
+	}
+
+	toolNames := make([]string, 0, len(m.tools))
+	for _, t := range m.tools {
+		toolNames = append(toolNames, t.Name)
+	}
+
+	type cacheMessage struct {
+		Role       schema.RoleType   `json:"role"`
+		Content    string            `json:"content"`
+		ToolCalls  []schema.ToolCall `json:"tool_calls,omitempty"`
+		ToolCallID string            `json:"tool_call_id,omitempty"`
+	}
+	msgs := make([]cacheMessage, 0, len(messages))
+	for _, msg := range messages {
+		msgs = append(msgs, cacheMessage{Role: msg.Role, Content: msg.Content, ToolCalls: msg.ToolCalls, ToolCallID: msg.ToolCallID})
+	}
+
+	return cache.Key("geo:"+cacheSourceLLM, modelName, toolNames, msgs)
+}
+
+// Generate 实现 model.ToolCallingChatModel 接口，命中缓存时不调用模型
+func (m *CachedChatModel) Generate(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	key := m.cacheKey(messages)
+	if !cache.IsForceRefresh(ctx) {
+		if data, ok, err := m.store.Load(ctx, key); err == nil && ok {
+			var msg schema.Message
+			if err := json.Unmarshal(data, &msg); err == nil {
+				cache.ReportHit(ctx, cacheSourceLLM, lastUserContent(messages))
+				return &msg, nil
+			}
+		}
+	}
+
+	msg, err := m.inner.Generate(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if fallback, _ := msg.Extra[ExtraFallback].(bool); !fallback {
+		if data, err := json.Marshal(msg); err == nil {
+			_ = m.store.Save(ctx, key, data, m.ttl)
+		}
+	}
+	return msg, nil
+}
+
+// Stream 实现 model.ToolCallingChatModel 接口，将完整响应作为单个 chunk 发送
+func (m *CachedChatModel) Stream(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		sw.Send(msg, nil)
+		sw.Close()
+	}()
+	return sr, nil
+}
+
+// WithTools 实现 model.ToolCallingChatModel 接口
+func (m *CachedChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedChatModel{inner: inner, store: m.store, ttl: m.ttl, tools: tools}, nil
+}
+
+// lastUserContent 取最后一条用户消息的前 50 个字符，用于命中上报
+func lastUserContent(messages []*schema.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == schema.User {
+			content := []rune(messages[i].Content)
+			if len(content) > 50 {
+				content = content[:50]
+			}
+			return string(content)
+		}
+	}
+	return ""
+}
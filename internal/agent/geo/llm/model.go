@@ -11,9 +11,17 @@ import (
 // 使用火山引擎 Ark 模型（豆包），如果未配置则返回错误
 func NewChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
 	// 尝试创建 Ark 模型
-	cm, err := NewArkChatModel()
+	agent, _ := ctx.Value(agentNameKey{}).(string)
+	cm, err := NewArkChatModel(agent)
 	if err == nil {
-		return cm, nil
+		// 记录实际调用的用量（缓存命中不产生费用，因此在缓存内层）
+		metered := NewMeteredChatModel(cm, agent)
+
+		// 上下文配置了响应缓存时包装为带缓存的模型
+		if cfg, ok := ctx.Value(responseCacheKey{}).(*responseCacheConfig); ok && cfg.store != nil {
+			return NewCachedChatModel(metered, cfg.store, cfg.ttl), nil
+		}
+		return metered, nil
 	}
 
 	return nil, fmt.Errorf("未配置有效的 LLM，请设置 ARK_API_KEY 环境变量: %w", err)
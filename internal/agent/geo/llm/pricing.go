@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ModelPrice 模型单价（每百万 token）
+type ModelPrice struct {
+	Input  float64 `json:"input"`  // 输入（prompt）单价
+	Output float64 `json:"output"` // 输出（completion）单价
+}
+
+// PriceTable 模型价格表，模型名按完全匹配或最长前缀匹配
+type PriceTable struct {
+	Currency string                `json:"currency"`
+	Models   map[string]ModelPrice `json:"models"`
+}
+
+// DefaultPriceTable 返回默认价格表（仅供估算，实际价格请通过 GEO_LLM_PRICES 配置）
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		Currency: "CNY",
+		Models: map[string]ModelPrice{
+			"doubao-seed-2-0-pro": {Input: 3.2, Output: 16},
+			"doubao-seed-1-6":     {Input: 0.8, Output: 8},
+			"doubao-pro-256k":     {Input: 5, Output: 9},
+			"doubao-pro-32k":      {Input: 0.8, Output: 2},
+		},
+	}
+}
+
+// LoadPriceTable 从 GEO_LLM_PRICES 加载价格表
+// 取值为 JSON（{"currency": "CNY", "models": {"模型名或前缀": {"input": 0.8, "output": 8}}}）或 JSON 文件路径，
+// 未设置时使用默认价格表；配置中的模型覆盖默认价格
+func LoadPriceTable() (PriceTable, error) {
+	table := DefaultPriceTable()
+
+	raw := strings.TrimSpace(os.Getenv("GEO_LLM_PRICES"))
+	if raw == "" {
+		return table, nil
+	}
+	if !strings.HasPrefix(raw, "{") {
+		data, err := os.ReadFile(raw)
+		if err != nil {
+			return table, fmt.Errorf("读取 GEO_LLM_PRICES 文件失败: %w", err)
+		}
+		raw = string(data)
+	}
+
+	var custom PriceTable
+	if err := json.Unmarshal([]byte(raw), &custom); err != nil {
+		return table, fmt.Errorf("解析 GEO_LLM_PRICES 失败: %w", err)
+	}
+	if custom.Currency != "" {
+		table.Currency = custom.Currency
+	}
+	for name, price := range custom.Models {
+		table.Models[name] = price
+	}
+	return table, nil
+}
+
+// Price 查找模型单价，优先完全匹配，其次最长前缀匹配
+func (t PriceTable) Price(modelName string) (ModelPrice, bool) {
+	if price, ok := t.Models[modelName]; ok {
+		return price, true
+	}
+
+	var (
+		best    ModelPrice
+		bestLen int
+	)
+	for name, price := range t.Models {
+		if len(name) > bestLen && strings.HasPrefix(modelName, name) {
+			best, bestLen = price, len(name)
+		}
+	}
+	return best, bestLen > 0
+}
+
+// Cost 估算费用，价格表中没有的模型返回 0
+func (t PriceTable) Cost(modelName string, promptTokens, completionTokens int) float64 {
+	price, ok := t.Price(modelName)
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6
+}
+
+var (
+	priceMu    sync.RWMutex
+	priceTable = DefaultPriceTable()
+)
+
+// SetPriceTable 设置用于费用估算的价格表
+func SetPriceTable(t PriceTable) {
+	priceMu.Lock()
+	defer priceMu.Unlock()
+	priceTable = t
+}
+
+// CurrentPriceTable 返回当前价格表
+func CurrentPriceTable() PriceTable {
+	priceMu.RLock()
+	defer priceMu.RUnlock()
+	return priceTable
+}
@@ -0,0 +1,224 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/pkg/telemetry"
+)
+
+// agentNameKey 是 Agent 名称的上下文键
+type agentNameKey struct{}
+
+// WithAgentName 在上下文中设置 Agent 名称，NewChatModel 据此标记用量归属并在请求头中发送
+func WithAgentName(ctx context.Context, agent string) context.Context {
+	return context.WithValue(ctx, agentNameKey{}, agent)
+}
+
+// usageRecorderKey 是用量记录器的上下文键
+type usageRecorderKey struct{}
+
+// WithUsageRecorder 在上下文中设置用量记录器，运行期间的 LLM 调用都记录到该记录器
+func WithUsageRecorder(ctx context.Context, recorder *UsageRecorder) context.Context {
+	return context.WithValue(ctx, usageRecorderKey{}, recorder)
+}
+
+// UsageRecorderFromContext 从上下文获取用量记录器
+func UsageRecorderFromContext(ctx context.Context) *UsageRecorder {
+	recorder, _ := ctx.Value(usageRecorderKey{}).(*UsageRecorder)
+	return recorder
+}
+
+// UsageRecorder 按 Agent 和模型汇总 LLM 调用用量，并发安全
+type UsageRecorder struct {
+	mu       sync.Mutex
+	currency string
+	usages   []*models.AgentUsage
+}
+
+// NewUsageRecorder 创建用量记录器，费用按当前价格表估算
+func NewUsageRecorder() *UsageRecorder {
+	return &UsageRecorder{currency: CurrentPriceTable().Currency}
+}
+
+// Record 记录一次调用
+// usage 为 nil 时按字数估算 token 数
+func (r *UsageRecorder) Record(agent, modelName string, messages []*schema.Message, output *schema.Message, latency time.Duration) {
+	promptTokens, completionTokens, estimated := countTokens(messages, output)
+	cost := CurrentPriceTable().Cost(modelName, promptTokens, completionTokens)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var u *models.AgentUsage
+	for _, existing := range r.usages {
+		if existing.Agent == agent && existing.Model == modelName {
+			u = existing
+			break
+		}
+	}
+	if u == nil {
+		u = &models.AgentUsage{Agent: agent, Model: modelName}
+		r.usages = append(r.usages, u)
+	}
+
+	u.Calls++
+	u.PromptTokens += promptTokens
+	u.CompletionTokens += completionTokens
+	u.TotalTokens += promptTokens + completionTokens
+	u.LatencyMs += latency.Milliseconds()
+	u.Cost += cost
+	if estimated {
+		u.EstimatedCalls++
+	}
+}
+
+// Summary 返回汇总结果，没有调用时返回 nil
+func (r *UsageRecorder) Summary() *models.LLMUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.usages) == 0 {
+		return nil
+	}
+
+	summary := &models.LLMUsage{
+		Currency: r.currency,
+		Agents:   make([]models.AgentUsage, 0, len(r.usages)),
+	}
+	for _, u := range r.usages {
+		summary.Calls += u.Calls
+		summary.PromptTokens += u.PromptTokens
+		summary.CompletionTokens += u.CompletionTokens
+		summary.TotalTokens += u.TotalTokens
+		summary.LatencyMs += u.LatencyMs
+		summary.Cost += u.Cost
+		summary.Agents = append(summary.Agents, *u)
+	}
+	return summary
+}
+
+// countTokens 返回一次调用的 token 数，模型未返回用量时按字数估算
+func countTokens(messages []*schema.Message, output *schema.Message) (promptTokens, completionTokens int, estimated bool) {
+	if output.ResponseMeta != nil && output.ResponseMeta.Usage != nil {
+		return output.ResponseMeta.Usage.PromptTokens, output.ResponseMeta.Usage.CompletionTokens, false
+	}
+	for _, msg := range messages {
+		promptTokens += estimateTokens(msg.Content)
+	}
+	return promptTokens, estimateTokens(output.Content), true
+}
+
+// estimateTokens 模型未返回用量时粗略估算 token 数
+func estimateTokens(s string) int {
+	if s == "" {
+		return 0
+	}
+	return utf8.RuneCountInString(s)/2 + 1
+}
+
+// MeteredChatModel 记录每次模型调用的 token 用量、耗时和估算费用，并上报 Prometheus token 指标
+// 备用响应（API 调用失败）不计入用量
+// 模型调用自行触发 eino 回调，因此在 Lambda 中直接调用时也能被追踪
+type MeteredChatModel struct {
+	inner model.ToolCallingChatModel
+	agent string
+}
+
+// NewMeteredChatModel 创建记录用量的 ChatModel
+func NewMeteredChatModel(inner model.ToolCallingChatModel, agent string) *MeteredChatModel {
+	return &MeteredChatModel{inner: inner, agent: agent}
+}
+
+// ModelName 返回模型名称
+func (m *MeteredChatModel) ModelName() string {
+	if named, ok := m.inner.(interface{ ModelName() string }); ok {
+		return named.ModelName()
+	}
+	return ""
+}
+
+// GetType 实现 components.Typer 接口
+func (m *MeteredChatModel) GetType() string {
+	if typ, ok := components.GetType(m.inner); ok {
+		return typ
+	}
+	return "ChatModel"
+}
+
+// IsCallbacksEnabled 实现 components.Checker 接口，回调由 Generate 触发，Graph 不再重复触发
+func (m *MeteredChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// Generate 实现 model.ToolCallingChatModel 接口
+func (m *MeteredChatModel) Generate(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      "ChatModel",
+		Type:      m.GetType(),
+		Component: components.ComponentOfChatModel,
+	})
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: messages})
+
+	start := time.Now()
+	msg, err := m.inner.Generate(ctx, messages, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+
+	var usage *model.TokenUsage
+	if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		usage = &model.TokenUsage{
+			PromptTokens:     msg.ResponseMeta.Usage.PromptTokens,
+			CompletionTokens: msg.ResponseMeta.Usage.CompletionTokens,
+			TotalTokens:      msg.ResponseMeta.Usage.TotalTokens,
+		}
+	}
+	callbacks.OnEnd(ctx, &model.CallbackOutput{Message: msg, TokenUsage: usage})
+
+	if fallback, _ := msg.Extra[ExtraFallback].(bool); fallback {
+		return msg, nil
+	}
+
+	promptTokens, completionTokens, _ := countTokens(messages, msg)
+	telemetry.LLMTokens.WithLabelValues(m.agent, m.ModelName(), "prompt").Add(float64(promptTokens))
+	telemetry.LLMTokens.WithLabelValues(m.agent, m.ModelName(), "completion").Add(float64(completionTokens))
+
+	if recorder := UsageRecorderFromContext(ctx); recorder != nil {
+		recorder.Record(m.agent, m.ModelName(), messages, msg, time.Since(start))
+	}
+	return msg, nil
+}
+
+// Stream 实现 model.ToolCallingChatModel 接口，将完整响应作为单个 chunk 发送
+func (m *MeteredChatModel) Stream(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		sw.Send(msg, nil)
+		sw.Close()
+	}()
+	return sr, nil
+}
+
+// WithTools 实现 model.ToolCallingChatModel 接口
+func (m *MeteredChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &MeteredChatModel{inner: inner, agent: m.agent}, nil
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestPriceTable_Cost(t *testing.T) {
+	table := PriceTable{
+		Currency: "CNY",
+		Models: map[string]ModelPrice{
+			"doubao-seed":     {Input: 1, Output: 2},
+			"doubao-seed-1-6": {Input: 0.8, Output: 8},
+		},
+	}
+
+	// 最长前缀匹配
+	if got := table.Cost("doubao-seed-1-6-250615", 1_000_000, 500_000); math.Abs(got-4.8) > 1e-9 {
+		t.Errorf("Cost(doubao-seed-1-6-250615) = %v, want 4.8", got)
+	}
+	if got := table.Cost("doubao-seed-2-0", 1_000_000, 0); math.Abs(got-1) > 1e-9 {
+		t.Errorf("Cost(doubao-seed-2-0) = %v, want 1", got)
+	}
+	if got := table.Cost("gpt-4o", 1_000_000, 1_000_000); got != 0 {
+		t.Errorf("Cost(unknown) = %v, want 0", got)
+	}
+}
+
+func TestUsageRecorder(t *testing.T) {
+	SetPriceTable(PriceTable{Currency: "USD", Models: map[string]ModelPrice{"m": {Input: 1, Output: 1}}})
+	defer SetPriceTable(DefaultPriceTable())
+
+	r := NewUsageRecorder()
+	if r.Summary() != nil {
+		t.Fatal("Summary() of empty recorder should be nil")
+	}
+
+	input := []*schema.Message{schema.UserMessage("你好你好")}
+	withUsage := &schema.Message{Content: "ok", ResponseMeta: &schema.ResponseMeta{Usage: &schema.TokenUsage{PromptTokens: 100, CompletionTokens: 50}}}
+	r.Record("a", "m", input, withUsage, 20*time.Millisecond)
+	r.Record("a", "m", input, withUsage, 30*time.Millisecond)
+	r.Record("b", "m", input, &schema.Message{Content: "四个汉字"}, time.Millisecond)
+
+	s := r.Summary()
+	if s.Currency != "USD" || s.Calls != 3 || len(s.Agents) != 2 {
+		t.Fatalf("Summary() = %+v", s)
+	}
+	if a := s.Agents[0]; a.PromptTokens != 200 || a.CompletionTokens != 100 || a.LatencyMs != 50 || a.EstimatedCalls != 0 {
+		t.Errorf("agent a = %+v", a)
+	}
+	if b := s.Agents[1]; b.EstimatedCalls != 1 || b.PromptTokens != 3 || b.CompletionTokens != 3 {
+		t.Errorf("agent b = %+v", b)
+	}
+	if math.Abs(s.Cost-306.0/1e6) > 1e-12 {
+		t.Errorf("Cost = %v", s.Cost)
+	}
+}
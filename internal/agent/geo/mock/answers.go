@@ -0,0 +1,111 @@
+package mock
+
+// defaultAnswers 内置的各 Agent 回答，格式与各 prompt 要求的输出一致
+var defaultAnswers = map[string]string{
+	AgentTitleScraper: `{
+  "url": "{{url}}",
+  "title": "示例页面：家用咖啡机选购指南",
+  "h1": "家用咖啡机选购指南",
+  "content": "本文介绍家用咖啡机的主要类型、选购要点和日常维护方法。意式咖啡机适合喜欢浓缩咖啡和奶咖的用户，滴滤咖啡机操作简单，胶囊咖啡机最方便但单杯成本较高。"
+}`,
+
+	AgentQueryResearcher: `{
+  "original_query": "家用咖啡机选购指南",
+  "related_queries": ["家用咖啡机推荐", "意式咖啡机和胶囊咖啡机哪个好", "咖啡机泵压多少合适", "入门咖啡机预算"],
+  "search_results": [
+    {"title": "家用咖啡机选购指南 完整指南", "url": "https://docs.example.org/guide", "snippet": "涵盖咖啡机类型、选择要点和常见问题。"},
+    {"title": "家用咖啡机选购指南 横向对比评测", "url": "https://reviews.example.net/compare", "snippet": "横向对比十款热门家用咖啡机。"}
+  ]
+}`,
+
+	AgentMainQueryExtractor: `{
+  "main_query": "家用咖啡机怎么选",
+  "keywords": ["家用咖啡机", "意式咖啡机", "胶囊咖啡机", "选购"],
+  "search_intent": "commercial",
+  "reasoning": "页面围绕咖啡机的类型对比和选购要点展开，用户处于购买决策阶段。"
+}`,
+
+	AgentAIOverviewRetriever: `{
+  "query": "家用咖啡机怎么选",
+  "summary": "选择家用咖啡机主要看饮用习惯和预算：喜欢奶咖选意式咖啡机，追求方便选胶囊咖啡机，日常黑咖啡选滴滤咖啡机。意式机建议关注锅炉类型和泵压，同时预留磨豆机预算。",
+  "sources": ["https://docs.example.org/guide", "https://reviews.example.net/compare", "https://blog.example.com/faq"],
+  "key_points": ["按饮用习惯选择类型", "意式机关注锅炉和泵压", "预留磨豆机预算"],
+  "content_structure": "分类说明 + 对比表格 + 常见问题"
+}`,
+
+	AgentCitationAnalyzer: `{
+  "sources": [
+    {"url": "https://docs.example.org/guide", "title": "完整指南", "key_facts": ["不同类型咖啡机的适用人群"], "formats": ["list", "faq"], "entities": ["意式咖啡机", "胶囊咖啡机"], "strengths": "结构清晰，覆盖常见问题"},
+    {"url": "https://reviews.example.net/compare", "title": "横向对比评测", "key_facts": ["主流型号价格和泵压对比"], "formats": ["table"], "entities": ["德龙", "百胜图"], "strengths": "提供具体型号的对比数据"}
+  ],
+  "missing_facts": ["主流型号的价格区间"],
+  "missing_formats": ["table", "faq"],
+  "missing_entities": ["德龙", "百胜图"],
+  "content_gaps": ["缺少主流型号的价格与参数对比表格", "缺少常见问题（FAQ）模块", "缺少磨豆机搭配建议"]
+}`,
+
+	AgentQuerySummarizer: `{
+  "summary": "用户在选购家用咖啡机时最关心类型选择、预算和具体型号推荐，其次是泵压、锅炉等参数含义以及日常维护成本。",
+  "key_topics": ["咖啡机类型", "预算", "型号推荐", "维护"],
+  "user_intents": ["选购对比", "参数理解"],
+  "hot_keywords": ["家用咖啡机推荐", "意式咖啡机", "胶囊咖啡机"],
+  "insights": "包含对比表格和 FAQ 的页面更容易被 AI 摘要引用。"
+}`,
+
+	AgentContentOptimizer: `{
+  "overall_score": 62,
+  "executive_summary": "页面覆盖了咖啡机的主要类型，但缺少被 AI 摘要引用的竞品所具备的结构化数据。",
+  "comparison_table": [
+    {"dimension": "类型覆盖", "your_content": "介绍意式、滴滤、胶囊三种类型", "ai_overview": "按饮用习惯推荐类型", "similarity": "都按类型区分", "difference": "缺少按饮用习惯的推荐"},
+    {"dimension": "参数说明", "your_content": "未说明泵压和锅炉", "ai_overview": "建议关注锅炉类型和泵压", "similarity": "无", "difference": "缺少关键参数"},
+    {"dimension": "预算建议", "your_content": "仅提到胶囊单杯成本", "ai_overview": "建议预留磨豆机预算", "similarity": "都涉及成本", "difference": "缺少预算分配"}
+  ],
+  "key_findings": ["被引用的竞品普遍包含型号对比表格", "FAQ 形式更容易被 AI 摘要引用"],
+  "action_items": [
+    {"priority": "high", "category": "structure", "issue": "增加型号对比表格", "suggestion": "列出主流型号的价格、泵压和锅炉类型"},
+    {"priority": "high", "category": "content", "issue": "补充 FAQ 模块", "suggestion": "回答「新手选哪种」「泵压多少合适」等高频问题"},
+    {"priority": "medium", "category": "content", "issue": "补充磨豆机搭配建议", "suggestion": "说明预算分配"},
+    {"priority": "low", "category": "freshness", "issue": "标注更新时间", "suggestion": "提升时效性信号"}
+  ],
+  "conclusion": "补充结构化的对比数据和 FAQ 后，页面更有机会被 AI 摘要引用。"
+}`,
+
+	AgentContentRewriter: `# 家用咖啡机选购指南
+
+> 更新时间：2025 年
+
+## 按饮用习惯选择类型
+
+- **意式咖啡机**：适合喜欢浓缩咖啡和奶咖的用户
+- **胶囊咖啡机**：最方便，单杯成本较高
+- **滴滤咖啡机**：操作简单，适合日常黑咖啡
+
+## 主流型号对比
+
+| 型号 | 类型 | 价格 | 泵压 |
+|------|------|------|------|
+| 德龙 EC685 | 意式 | 约 1500 元 | 15 bar |
+| 百胜图 二代 | 意式 | 约 2500 元 | 20 bar |
+
+## 常见问题
+
+### 新手选哪种咖啡机？
+
+预算有限且想喝奶咖，可以从入门意式咖啡机开始，并预留约三成预算购买磨豆机。
+`,
+
+	AgentValidator: `{
+  "original_score": {"authority": 40, "timeliness": 30, "structure": 45, "engagement": 35, "originality": 55},
+  "optimized_score": {"authority": 55, "timeliness": 70, "structure": 85, "engagement": 65, "originality": 60},
+  "comments": {
+    "authority": "仍缺少权威来源引用",
+    "timeliness": "标注了更新时间",
+    "structure": "增加了型号对比表格和 FAQ",
+    "engagement": "回答了新手的高频问题",
+    "originality": "预算分配建议有一定参考价值"
+  },
+  "suggestions": ["引用品牌官网或评测机构的参数数据"]
+}`,
+
+	AgentBrandAnalyzer: `{"brands": []}`,
+}
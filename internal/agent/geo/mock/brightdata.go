@@ -0,0 +1,135 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// brightDataRequest Bright Data /request 请求体
+type brightDataRequest struct {
+	Zone       string `json:"zone"`
+	URL        string `json:"url"`
+	Format     string `json:"format"`
+	AIOverview string `json:"brd_ai_overview"`
+}
+
+// mockSources 模拟 SERP 和 AI Overview 中引用的网页
+var mockSources = []struct {
+	url   string
+	title string
+}{
+	{"https://docs.example.org/guide", "完整指南"},
+	{"https://reviews.example.net/compare", "横向对比评测"},
+	{"https://blog.example.com/faq", "常见问题解答"},
+}
+
+// brightDataRequest 处理 SERP API（Google 搜索 URL）和 Web Unlocker（其他 URL）请求
+func (s *Server) brightDataRequest(c *gin.Context) {
+	var req brightDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.String(http.StatusBadRequest, "invalid request: %v", err)
+		return
+	}
+
+	target, err := url.Parse(req.URL)
+	if err != nil || target.Host == "" {
+		c.String(http.StatusBadRequest, "invalid url: %s", req.URL)
+		return
+	}
+
+	if strings.Contains(target.Host, "google.") && target.Path == "/search" {
+		c.JSON(http.StatusOK, serpResponse(target.Query().Get("q"), req.AIOverview != ""))
+		return
+	}
+
+	page, ok := s.script.Pages[req.URL]
+	if !ok {
+		page = generatedPage(target)
+	}
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(page))
+}
+
+// serpResponse 生成 brd_json=1 格式的搜索结果
+func serpResponse(query string, aiOverview bool) gin.H {
+	organic := make([]gin.H, 0, len(mockSources))
+	references := make([]gin.H, 0, len(mockSources))
+	sources := make([]string, 0, len(mockSources))
+	for i, src := range mockSources {
+		title := query + " " + src.title
+		organic = append(organic, gin.H{
+			"title":       title,
+			"link":        src.url,
+			"description": fmt.Sprintf("关于「%s」的%s，涵盖核心概念、选择要点和常见问题。", query, src.title),
+			"rank":        i + 1,
+		})
+		references = append(references, gin.H{"title": title, "href": src.url})
+		sources = append(sources, src.url)
+	}
+
+	resp := gin.H{
+		"organic": organic,
+		"related": []gin.H{
+			{"text": query + " 推荐"},
+			{"text": query + " 对比"},
+			{"text": query + " 入门"},
+		},
+		"people_also_ask": []gin.H{
+			{"question": query + " 有哪些注意事项？"},
+			{"question": query + " 适合新手吗？"},
+		},
+		"featured_snippets": []gin.H{{
+			"title":       query + " 完整指南",
+			"description": fmt.Sprintf("%s 需要综合考虑需求、预算和使用场景。", query),
+			"link":        mockSources[0].url,
+		}},
+	}
+
+	if aiOverview {
+		resp["ai_overview"] = gin.H{
+			"texts": []gin.H{
+				{"type": "paragraph", "snippet": fmt.Sprintf("%s 主要取决于使用场景、预算和长期维护成本。", query)},
+				{"type": "list", "snippet": "先明确核心需求"},
+				{"type": "list", "snippet": "对比主流方案的优缺点"},
+				{"type": "list", "snippet": "参考真实用户评价"},
+			},
+			"references": references,
+			"sources":    sources,
+		}
+	}
+	return resp
+}
+
+// generatedPage 根据 URL 生成示例网页 Markdown
+func generatedPage(target *url.URL) string {
+	name := strings.TrimSuffix(path.Base(target.Path), path.Ext(target.Path))
+	if name == "" || name == "/" || name == "." {
+		name = target.Host
+	}
+	name = strings.ReplaceAll(name, "-", " ")
+
+	return fmt.Sprintf(`# %s
+
+这是模拟服务为 %s 生成的示例页面。
+
+## 概述
+
+%s 介绍了核心概念和适用场景，帮助读者快速了解主题。
+
+## 选择要点
+
+- 明确需求和预算
+- 对比主流方案
+- 关注售后与维护
+
+## 常见问题
+
+### 新手应该从哪里开始？
+
+先阅读概述，再根据选择要点逐项对比。
+`, name, target.String(), name)
+}
@@ -0,0 +1,127 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+)
+
+// chatRequest OpenAI 兼容的聊天补全请求
+type chatRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+	Stream bool `json:"stream"`
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>)]+`)
+
+// answer 返回 Agent 的脚本回答，Agent 由 LLM 客户端在请求头中标记
+func (s *Server) answer(agent string, req *chatRequest) string {
+	if len(req.Messages) == 0 {
+		return ""
+	}
+
+	content, ok := s.script.Answers[agent]
+	if !ok {
+		return fmt.Sprintf("[mock] 未配置 Agent %q 的回答", agent)
+	}
+
+	if strings.Contains(content, "{{url}}") {
+		target := "https://example.com/"
+		for i := len(req.Messages) - 1; i >= 0; i-- {
+			if u := urlPattern.FindString(req.Messages[i].Content); u != "" {
+				target = u
+				break
+			}
+		}
+		content = strings.ReplaceAll(content, "{{url}}", target)
+	}
+	return content
+}
+
+// estimateTokens 粗略估算 token 数
+func estimateTokens(s string) int {
+	return utf8.RuneCountInString(s)/2 + 1
+}
+
+// chatCompletions 处理聊天补全请求
+func (s *Server) chatCompletions(c *gin.Context) {
+	var req chatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": err.Error(), "type": "invalid_request_error"}})
+		return
+	}
+
+	agent := c.GetHeader(llm.AgentHeader)
+	content := s.answer(agent, &req)
+	c.Header("X-Mock-Agent", agent)
+
+	model := req.Model
+	if model == "" {
+		model = "mock"
+	}
+	id := fmt.Sprintf("chatcmpl-mock-%d", time.Now().UnixNano())
+	created := time.Now().Unix()
+
+	if req.Stream {
+		s.streamCompletion(c, id, created, model, content)
+		return
+	}
+
+	promptTokens := 0
+	for _, m := range req.Messages {
+		promptTokens += estimateTokens(m.Content)
+	}
+	completionTokens := estimateTokens(content)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": created,
+		"model":   model,
+		"choices": []gin.H{{
+			"index":         0,
+			"message":       gin.H{"role": "assistant", "content": content},
+			"finish_reason": "stop",
+		}},
+		"usage": gin.H{
+			"prompt_tokens":     promptTokens,
+			"completion_tokens": completionTokens,
+			"total_tokens":      promptTokens + completionTokens,
+		},
+	})
+}
+
+// streamCompletion 以 SSE 返回单个内容块
+func (s *Server) streamCompletion(c *gin.Context, id string, created int64, model, content string) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+
+	chunks := []gin.H{
+		{"index": 0, "delta": gin.H{"role": "assistant", "content": content}},
+		{"index": 0, "delta": gin.H{}, "finish_reason": "stop"},
+	}
+	for _, choice := range chunks {
+		data, _ := json.Marshal(gin.H{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   model,
+			"choices": []gin.H{choice},
+		})
+		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Mock - 本地开发用的 LLM 与 Bright Data 模拟服务
+ */
+
+// Package mock 提供 OpenAI 兼容的聊天补全接口和 Bright Data 形式的 SERP / Web Unlocker 接口，
+// 按 Agent 返回脚本化的回答，便于在无网络、无 API Key 的环境下端到端运行
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Agent 名称（与 flow/agents 中的节点名称一致）
+const (
+	AgentTitleScraper        = "title_scraper"
+	AgentQueryResearcher     = "query_researcher"
+	AgentMainQueryExtractor  = "main_query_extractor"
+	AgentAIOverviewRetriever = "ai_overview_retriever"
+	AgentCitationAnalyzer    = "citation_analyzer"
+	AgentQuerySummarizer     = "query_summarizer"
+	AgentContentOptimizer    = "content_optimizer"
+	AgentContentRewriter     = "content_rewriter"
+	AgentValidator           = "content_validator"
+	AgentBrandAnalyzer       = "brand_analyzer"
+)
+
+// Script 脚本化的回答
+type Script struct {
+	// Answers 各 Agent 的 LLM 回答，{{url}} 会替换为请求中出现的第一个 URL
+	Answers map[string]string `json:"answers"`
+	// Pages Web Unlocker 返回的网页 Markdown，未配置的 URL 返回生成的示例页面
+	Pages map[string]string `json:"pages"`
+}
+
+// DefaultScript 返回内置脚本
+func DefaultScript() *Script {
+	answers := make(map[string]string, len(defaultAnswers))
+	for k, v := range defaultAnswers {
+		answers[k] = v
+	}
+	return &Script{Answers: answers, Pages: map[string]string{}}
+}
+
+// LoadScript 从 JSON 文件加载脚本，未配置的 Agent 使用内置回答
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取脚本失败: %w", err)
+	}
+
+	var custom Script
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("解析脚本失败: %w", err)
+	}
+
+	script := DefaultScript()
+	for k, v := range custom.Answers {
+		script.Answers[k] = v
+	}
+	for k, v := range custom.Pages {
+		script.Pages[k] = v
+	}
+	return script, nil
+}
+
+// Server 模拟服务
+type Server struct {
+	script *Script
+}
+
+// NewServer 创建模拟服务，script 为 nil 时使用内置脚本
+func NewServer(script *Script) *Server {
+	if script == nil {
+		script = DefaultScript()
+	}
+	return &Server{script: script}
+}
+
+// Handler 返回 HTTP 处理器
+// 聊天补全同时挂载在 /chat/completions、/v1/chat/completions 和 /api/v3/chat/completions
+func (s *Server) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	for _, path := range []string{"/chat/completions", "/v1/chat/completions", "/api/v3/chat/completions"} {
+		r.POST(path, s.chatCompletions)
+	}
+	r.POST("/request", s.brightDataRequest)
+
+	return r
+}
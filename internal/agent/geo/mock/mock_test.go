@@ -0,0 +1,129 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+)
+
+// useMockServer 启动模拟服务并将 GEO 环境变量指向它
+func useMockServer(t *testing.T) {
+	t.Helper()
+
+	srv := httptest.NewServer(NewServer(nil).Handler())
+	t.Cleanup(srv.Close)
+
+	t.Setenv("ARK_API_KEY", "mock")
+	t.Setenv("ARK_BASE_URL", srv.URL+"/api/v3")
+	t.Setenv("ARK_MODEL", "")
+	t.Setenv("BRIGHT_DATA_API_KEY", "mock")
+	t.Setenv("BRIGHT_DATA_ENDPOINT", srv.URL+"/request")
+	t.Setenv("GEO_SEARCH_PROVIDERS", "")
+	t.Setenv("GEO_ANSWER_PROVIDERS", "")
+}
+
+// TestChatCompletionsAgentHeader 测试按请求头中的 Agent 返回回答，与 prompt 内容无关
+func TestChatCompletionsAgentHeader(t *testing.T) {
+	srv := httptest.NewServer(NewServer(nil).Handler())
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name  string
+		agent string
+		want  string
+	}{
+		{name: "自定义 prompt", agent: AgentQuerySummarizer, want: defaultAnswers[AgentQuerySummarizer]},
+		{name: "未配置的 Agent", agent: "unknown_agent", want: `[mock] 未配置 Agent "unknown_agent" 的回答`},
+		{name: "没有请求头", want: `[mock] 未配置 Agent "" 的回答`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"model":"mock","messages":[{"role":"system","content":"你是一名资深编辑，请按要求输出 JSON"},{"role":"user","content":"开始"}]}`
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v3/chat/completions", strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tt.agent != "" {
+				req.Header.Set(llm.AgentHeader, tt.agent)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			var out struct {
+				Choices []struct {
+					Message struct {
+						Content string `json:"content"`
+					} `json:"message"`
+				} `json:"choices"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(out.Choices) != 1 || out.Choices[0].Message.Content != tt.want {
+				t.Errorf("content = %+v, want %q", out.Choices, tt.want)
+			}
+			if got := resp.Header.Get("X-Mock-Agent"); got != tt.agent {
+				t.Errorf("X-Mock-Agent = %q, want %q", got, tt.agent)
+			}
+		})
+	}
+}
+
+func TestBrightDataSERP(t *testing.T) {
+	useMockServer(t)
+
+	provider, err := tools.NewBrightDataSERPProvider()
+	if err != nil {
+		t.Fatalf("NewBrightDataSERPProvider() error = %v", err)
+	}
+	overview, err := provider.GetAIOverview(context.Background(), "家用咖啡机")
+	if err != nil {
+		t.Fatalf("GetAIOverview() error = %v", err)
+	}
+	if !overview.Found || len(overview.Sources) != len(mockSources) {
+		t.Errorf("overview = %+v, want %d sources", overview, len(mockSources))
+	}
+	if overview.Features == nil || len(overview.Features.PeopleAlsoAsk) == 0 {
+		t.Errorf("Features = %+v, want people also ask", overview.Features)
+	}
+}
+
+func TestAnalyzeAgainstMock(t *testing.T) {
+	useMockServer(t)
+
+	svc, err := geo.NewService("google")
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	report, err := svc.Analyze(context.Background(), "https://example.com/coffee", "google")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if report.Title != "示例页面：家用咖啡机选购指南" || report.MainQuery != "家用咖啡机怎么选" {
+		t.Errorf("Title = %q, MainQuery = %q", report.Title, report.MainQuery)
+	}
+	if report.CompetitorAnalysis == nil || len(report.CompetitorAnalysis.Sources) != 2 {
+		t.Errorf("CompetitorAnalysis = %+v", report.CompetitorAnalysis)
+	}
+	if !strings.Contains(report.OptimizedArticle, "主流型号对比") {
+		t.Errorf("OptimizedArticle = %q", report.OptimizedArticle)
+	}
+	if v := report.Validation; v == nil || v.OriginalScore.Total != 38 || v.OptimizedScore.Total != 63 {
+		t.Errorf("Validation = %+v, want scores 38 -> 63", v)
+	}
+	if report.LLMUsage == nil || report.LLMUsage.PromptTokens == 0 || report.LLMUsage.Cost <= 0 {
+		t.Errorf("LLMUsage = %+v, want reported tokens and cost", report.LLMUsage)
+	}
+}
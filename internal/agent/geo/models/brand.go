@@ -0,0 +1,52 @@
+package models
+
+// 品牌提及类型
+const (
+	MentionKindName    = "name"    // 品牌名
+	MentionKindAlias   = "alias"   // 别名
+	MentionKindProduct = "product" // 产品名
+)
+
+// 情感倾向
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// BrandDictionary 品牌词典（用于在 AI 回答中识别品牌提及）
+type BrandDictionary struct {
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Products []string `json:"products,omitempty"`
+	Facts    []string `json:"facts,omitempty"` // 已知事实，用于判断 AI 回答中的错误说法
+}
+
+// BrandMention AI 回答中的一次品牌提及
+type BrandMention struct {
+	Term      string `json:"term"`                // 命中的词
+	Kind      string `json:"kind"`                // name, alias, product
+	Offset    int    `json:"offset"`              // 在回答中的字符位置
+	Snippet   string `json:"snippet"`             // 上下文片段
+	Sentiment string `json:"sentiment,omitempty"` // positive, neutral, negative
+}
+
+// WrongClaim AI 回答中关于品牌的错误说法
+type WrongClaim struct {
+	Claim      string `json:"claim"`      // AI 回答中的原始说法
+	Correction string `json:"correction"` // 依据已知事实的更正
+	Severity   string `json:"severity"`   // high, medium, low
+}
+
+// BrandAnalysis 单个品牌在 AI 回答中的分析结果
+type BrandAnalysis struct {
+	BrandID        int64          `json:"brand_id"`
+	Brand          string         `json:"brand"`
+	Mentioned      bool           `json:"mentioned"`
+	Mentions       []BrandMention `json:"mentions"`
+	Sentiment      string         `json:"sentiment"`       // 整体情感倾向
+	SentimentScore float64        `json:"sentiment_score"` // -1 ~ 1
+	Summary        string         `json:"summary"`         // AI 回答如何描述该品牌
+	WrongClaims    []WrongClaim   `json:"wrong_claims"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// CitedPage AI 摘要引用的来源网页（爬取后提取的结构信息）
+type CitedPage struct {
+	URL       string   `json:"url"`
+	Title     string   `json:"title,omitempty"`
+	Headings  []string `json:"headings,omitempty"`   // H2/H3 小标题
+	Formats   []string `json:"formats,omitempty"`    // 内容形式：table, list, faq, steps, code, image
+	WordCount int      `json:"word_count,omitempty"` // 正文字数（中文按字计）
+	Excerpt   string   `json:"excerpt,omitempty"`    // 正文节选（供 LLM 对比）
+	Error     string   `json:"error,omitempty"`      // 爬取失败原因
+}
+
+// CompetitorSource 单个竞品来源的对比结果
+type CompetitorSource struct {
+	URL       string   `json:"url"`
+	Title     string   `json:"title"`
+	KeyFacts  []string `json:"key_facts"` // 该来源覆盖的核心事实/论点
+	Formats   []string `json:"formats"`   // 使用的内容形式
+	Entities  []string `json:"entities"`  // 提及的实体（品牌、产品、人物、机构）
+	Strengths string   `json:"strengths"` // 被 AI 引用的可能原因
+}
+
+// CompetitorAnalysis 竞品引用分析结果
+type CompetitorAnalysis struct {
+	Sources         []CompetitorSource `json:"sources"`
+	MissingFacts    []string           `json:"missing_facts"`    // 竞品覆盖而我方页面缺失的事实
+	MissingFormats  []string           `json:"missing_formats"`  // 竞品使用而我方页面缺失的内容形式
+	MissingEntities []string           `json:"missing_entities"` // 竞品提及而我方页面缺失的实体
+	ContentGaps     []string           `json:"content_gaps"`     // 汇总后的可操作内容差距
+}
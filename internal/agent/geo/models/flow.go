@@ -9,9 +9,11 @@ package models
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/cloudwego/eino/compose"
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
 func init() {
@@ -24,8 +26,13 @@ type ProgressCallback func(step int, total int, agentName string, message string
 // FlowState GEO Flow 状态
 type FlowState struct {
 	// 输入参数
-	URL          string `json:"url,omitempty"`
-	PlatformType string `json:"platform_type,omitempty"`
+	URL           string        `json:"url,omitempty"`
+	PlatformType  string        `json:"platform_type,omitempty"`
+	SearchOptions SearchOptions `json:"search_options,omitempty"` // 目标国家、语言和设备
+
+	// 语言：网页语言由爬取结果检测，输出语言未指定时使用网页语言
+	ContentLanguage string `json:"content_language,omitempty"`
+	OutputLanguage  string `json:"output_language,omitempty"`
 
 	// 步骤 1: 网页爬取结果
 	Title   string `json:"title,omitempty"`
@@ -35,6 +42,12 @@ type FlowState struct {
 	QueryFanout   []string       `json:"query_fanout,omitempty"`
 	SearchResults []SearchResult `json:"search_results,omitempty"`
 
+	// 搜索结果页模块（各次 SERP 请求合并）
+	PeopleAlsoAsk   []string         `json:"people_also_ask,omitempty"`
+	RelatedSearches []string         `json:"related_searches,omitempty"`
+	FeaturedSnippet *FeaturedSnippet `json:"featured_snippet,omitempty"`
+	KnowledgePanel  *KnowledgePanel  `json:"knowledge_panel,omitempty"`
+
 	// 步骤 3: 主查询提取
 	MainQuery    string   `json:"main_query,omitempty"`
 	Keywords     []string `json:"keywords,omitempty"`
@@ -44,13 +57,18 @@ type FlowState struct {
 	AIOverview string   `json:"ai_overview,omitempty"`
 	Sources    []string `json:"sources,omitempty"`
 
-	// 步骤 5: 查询总结
+	// 步骤 5: 竞品引用分析
+	CitedPages         []CitedPage         `json:"cited_pages,omitempty"`
+	CompetitorAnalysis *CompetitorAnalysis `json:"competitor_analysis,omitempty"`
+	ContentGaps        []string            `json:"content_gaps,omitempty"`
+
+	// 步骤 6: 查询总结
 	QuerySummary string `json:"query_summary,omitempty"`
 
-	// 步骤 6: 优化报告
+	// 步骤 7: 优化报告
 	Report *OptimizationReport `json:"report,omitempty"`
 
-	// 步骤 7: 重写后的文章
+	// 步骤 8: 重写后的文章
 	OptimizedArticle string `json:"optimized_article,omitempty"`
 
 	// 流程控制
@@ -66,6 +84,31 @@ type FlowState struct {
 	OnProgress ProgressCallback `json:"-"`
 }
 
+// ApplySERPFeatures 将 SERP 模块写入状态
+func (s *FlowState) ApplySERPFeatures(features *SERPFeatures) {
+	if features == nil {
+		return
+	}
+	s.PeopleAlsoAsk = features.PeopleAlsoAsk
+	s.RelatedSearches = features.RelatedSearches
+	s.FeaturedSnippet = features.FeaturedSnippet
+	s.KnowledgePanel = features.KnowledgePanel
+}
+
+// SERPFeatures 从状态中汇总 SERP 模块，没有任何模块时返回 nil
+func (s *FlowState) SERPFeatures() *SERPFeatures {
+	features := &SERPFeatures{
+		PeopleAlsoAsk:   s.PeopleAlsoAsk,
+		RelatedSearches: s.RelatedSearches,
+		FeaturedSnippet: s.FeaturedSnippet,
+		KnowledgePanel:  s.KnowledgePanel,
+	}
+	if features.IsEmpty() {
+		return nil
+	}
+	return features
+}
+
 // SearchResult 搜索结果条目
 type SearchResult struct {
 	Title   string `json:"title"`
@@ -91,7 +134,6 @@ func (s *FlowState) UnmarshalJSON(b []byte) error {
 
 // GenFlowState 生成 Flow State 的工厂函数
 func GenFlowState(ctx context.Context) *FlowState {
-	fmt.Println("[GEO] GenFlowState 被调用")
 	return &FlowState{
 		Goto: "title_scraper", // 默认从 title_scraper 开始
 	}
@@ -111,7 +153,7 @@ func (gc *GEOCheckPoint) Get(ctx context.Context, checkPointID string) ([]byte,
 
 // Set 设置 checkpoint 数据
 func (gc *GEOCheckPoint) Set(ctx context.Context, checkPointID string, checkPoint []byte) error {
-	fmt.Printf("[GEOCheckPoint] Set called: id=%s, len=%d\n", checkPointID, len(checkPoint))
+	logging.FromContext(ctx).Debug("保存 checkpoint", zap.String("checkpoint_id", checkPointID), zap.Int("size", len(checkPoint)))
 	gc.buf[checkPointID] = checkPoint
 	return nil
 }
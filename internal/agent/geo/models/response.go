@@ -12,9 +12,10 @@ type ScrapedTitle struct {
 
 // QueryFanout 查询发散结果
 type QueryFanout struct {
-	OriginalQuery  string    `json:"original_query"`
-	RelatedQueries []string  `json:"related_queries"`
-	Timestamp      time.Time `json:"timestamp"`
+	OriginalQuery  string        `json:"original_query"`
+	RelatedQueries []string      `json:"related_queries"` // 搜索引擎给出的相关搜索
+	Features       *SERPFeatures `json:"features,omitempty"`
+	Timestamp      time.Time     `json:"timestamp"`
 }
 
 // MainQuery 主查询
@@ -30,6 +31,9 @@ type AIOverview struct {
 	Summary string   `json:"summary"`
 	Sources []string `json:"sources"`
 	Snippet string   `json:"snippet"`
+	Found   bool     `json:"found"` // 搜索结果中是否真实存在 AI 摘要（否则为基于自然结果生成的摘要）
+
+	Features *SERPFeatures `json:"features,omitempty"`
 }
 
 // QueryFanoutSummary 查询发散总结
@@ -47,6 +51,9 @@ type OptimizationSuggestion struct {
 	Suggestion string `json:"suggestion"`
 }
 
+// SuggestionCategories 优化建议的类别，其他类别归为 general
+var SuggestionCategories = []string{"content", "structure", "authority", "freshness", "format"}
+
 // ComparisonItem 对比项
 type ComparisonItem struct {
 	Dimension   string `json:"dimension"`
@@ -62,14 +69,27 @@ type OptimizationReport struct {
 	Title                   string                   `json:"title"`
 	MainQuery               string                   `json:"main_query"`
 	QueryFanout             string                   `json:"query_fanout"`              // 查询发散结果
+	RelatedQueries          []string                 `json:"related_queries,omitempty"` // 查询发散的相关查询
+	SearchResults           []SearchResult           `json:"search_results,omitempty"`  // 查询发散的搜索结果
 	QueryFanoutSummary      string                   `json:"query_fanout_summary"`      // 查询发散总结
 	AIOverview              string                   `json:"ai_overview"`               // AI 摘要内容
+	Sources                 []string                 `json:"sources,omitempty"`         // AI 摘要引用的来源
+	ExecutiveSummary        string                   `json:"executive_summary,omitempty"` // 执行摘要
+	KeyFindings             []string                 `json:"key_findings,omitempty"`      // 关键发现
+	Conclusion              string                   `json:"conclusion,omitempty"`        // 结论
 	ComparisonTable         []ComparisonItem         `json:"comparison_table"`
 	ContentGaps             []string                 `json:"content_gaps"`
+	CompetitorAnalysis      *CompetitorAnalysis      `json:"competitor_analysis,omitempty"` // 竞品引用分析
+	SERPFeatures            *SERPFeatures            `json:"serp_features,omitempty"`       // 搜索结果页模块
+	CacheHits               map[string]int           `json:"cache_hits,omitempty"`          // 各来源缓存命中次数
+	LLMUsage                *LLMUsage                `json:"llm_usage,omitempty"`           // LLM 调用用量和估算费用
+	PromptVersions          map[string]string        `json:"prompt_versions,omitempty"`     // 各 Agent 使用的 prompt 版本
 	OptimizationSuggestions []OptimizationSuggestion `json:"optimization_suggestions"`
 	OptimizationReport      string                   `json:"optimization_report"`       // 优化报告内容
 	OptimizedArticle        string                   `json:"optimized_article"`         // 优化后的完整文章
 	OverallScore            int                      `json:"overall_score"`
+	Validation              *ValidationResult        `json:"validation,omitempty"`      // 原文与优化后文章的验证评分
+	OutputLanguage          string                   `json:"output_language,omitempty"` // 报告语言，为空时为中文
 	Timestamp               time.Time                `json:"timestamp"`
 }
 
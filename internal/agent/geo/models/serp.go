@@ -0,0 +1,76 @@
+package models
+
+// 搜索设备类型
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+)
+
+// SearchOptions 搜索参数（目标国家、语言、设备和页码）
+type SearchOptions struct {
+	Country  string `json:"country,omitempty"`  // 国家代码（gl），如 us、cn
+	Language string `json:"language,omitempty"` // 语言代码（hl），如 en、zh-CN
+	Device   string `json:"device,omitempty"`   // desktop, mobile
+	Page     int    `json:"page,omitempty"`     // 结果页码，从 1 开始
+}
+
+// FeaturedSnippet 精选摘要
+type FeaturedSnippet struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	URL     string `json:"url"`
+}
+
+// KnowledgePanel 知识面板
+type KnowledgePanel struct {
+	Title       string            `json:"title"`
+	Subtitle    string            `json:"subtitle,omitempty"`
+	Description string            `json:"description,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Facts       map[string]string `json:"facts,omitempty"`
+}
+
+// SERPFeatures 搜索结果页中除自然结果外的结构化模块
+type SERPFeatures struct {
+	PeopleAlsoAsk   []string         `json:"people_also_ask,omitempty"`
+	RelatedSearches []string         `json:"related_searches,omitempty"`
+	FeaturedSnippet *FeaturedSnippet `json:"featured_snippet,omitempty"`
+	KnowledgePanel  *KnowledgePanel  `json:"knowledge_panel,omitempty"`
+}
+
+// Merge 合并另一个结果页的模块：问题和相关搜索去重追加，精选摘要和知识面板保留先出现的
+func (f *SERPFeatures) Merge(other *SERPFeatures) {
+	if other == nil {
+		return
+	}
+	f.PeopleAlsoAsk = appendUniqueStrings(f.PeopleAlsoAsk, other.PeopleAlsoAsk...)
+	f.RelatedSearches = appendUniqueStrings(f.RelatedSearches, other.RelatedSearches...)
+	if f.FeaturedSnippet == nil {
+		f.FeaturedSnippet = other.FeaturedSnippet
+	}
+	if f.KnowledgePanel == nil {
+		f.KnowledgePanel = other.KnowledgePanel
+	}
+}
+
+// IsEmpty 是否没有任何模块
+func (f *SERPFeatures) IsEmpty() bool {
+	return f == nil || len(f.PeopleAlsoAsk) == 0 && len(f.RelatedSearches) == 0 &&
+		f.FeaturedSnippet == nil && f.KnowledgePanel == nil
+}
+
+func appendUniqueStrings(list []string, items ...string) []string {
+	for _, item := range items {
+		exists := false
+		for _, v := range list {
+			if v == item {
+				exists = true
+				break
+			}
+		}
+		if !exists && item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
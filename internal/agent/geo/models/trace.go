@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TraceSpan 一次节点执行的追踪记录（Graph、Lambda、ChatModel、Tool 等）
+type TraceSpan struct {
+	ID         int       `json:"id"`
+	ParentID   int       `json:"parent_id"` // 0 表示顶层节点
+	Agent      string    `json:"agent"`     // 所属 Agent 子图
+	Name       string    `json:"name"`      // 节点名称
+	Type       string    `json:"type"`      // 实现类型，如 Ark、Lambda
+	Component  string    `json:"component"` // 组件类型，如 Graph、ChatModel、Tool、Lambda
+	Input      string    `json:"input,omitempty"`
+	Output     string    `json:"output,omitempty"`
+	State      string    `json:"state,omitempty"` // router 节点执行后的 FlowState 快照
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
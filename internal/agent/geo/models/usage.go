@@ -0,0 +1,26 @@
+package models
+
+// AgentUsage 单个 Agent 使用某个模型的 LLM 调用统计
+type AgentUsage struct {
+	Agent            string  `json:"agent"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	LatencyMs        int64   `json:"latency_ms"`      // 调用耗时合计
+	Cost             float64 `json:"cost"`            // 估算费用
+	EstimatedCalls   int     `json:"estimated_calls"` // 模型未返回用量、按字数估算 token 的调用次数
+}
+
+// LLMUsage 一次分析的 LLM 调用统计（不含缓存命中）
+type LLMUsage struct {
+	Currency         string       `json:"currency"`
+	Calls            int          `json:"calls"`
+	PromptTokens     int          `json:"prompt_tokens"`
+	CompletionTokens int          `json:"completion_tokens"`
+	TotalTokens      int          `json:"total_tokens"`
+	LatencyMs        int64        `json:"latency_ms"`
+	Cost             float64      `json:"cost"`
+	Agents           []AgentUsage `json:"agents"`
+}
@@ -40,7 +40,7 @@ type Comparison struct {
 	Original    int    `json:"original"`     // 原始得分
 	Optimized   int    `json:"optimized"`    // 优化后得分
 	Diff        int    `json:"diff"`         // 差异
-	Status      string `json:"status"`       // 状态：提升/持平/下降
+	Status      string `json:"status"`       // 状态：improved（提升）、unchanged（持平）、declined（下降）
 	Comment     string `json:"comment"`      // 评语
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
 )
 
@@ -20,7 +21,7 @@ func ParseOptimizationReport(content, url string) *models.OptimizationReport {
 	}
 
 	// 提取对比表格
-	report.ComparisonTable = extractComparisonTable(content)
+	report.ComparisonTable = ExtractComparisonTable(content)
 
 	// 提取内容差距
 	report.ContentGaps = extractContentGaps(content)
@@ -198,8 +199,8 @@ func ExtractOverallScore(content string) int {
 	return 75
 }
 
-// extractComparisonTable 提取对比表格
-func extractComparisonTable(content string) []models.ComparisonItem {
+// ExtractComparisonTable 提取对比表格（表头含“维度”、Aspect 或 Dimension 的 Markdown 表格）
+func ExtractComparisonTable(content string) []models.ComparisonItem {
 	var items []models.ComparisonItem
 
 	// 查找 Markdown 表格
@@ -211,7 +212,7 @@ func extractComparisonTable(content string) []models.ComparisonItem {
 		line = strings.TrimSpace(line)
 
 		// 检测表格开始
-		if strings.HasPrefix(line, "|") && strings.Contains(line, "维度") || strings.Contains(line, "Aspect") {
+		if strings.HasPrefix(line, "|") && (strings.Contains(line, "维度") || strings.Contains(line, "Aspect") || strings.Contains(line, "Dimension")) {
 			inTable = true
 			headers = strings.Split(strings.Trim(line, "|"), "|")
 			// 清理表头
@@ -278,6 +279,9 @@ func extractContentGaps(content string) []string {
 	if startIdx == -1 {
 		startIdx = strings.Index(content, "差距分析")
 	}
+	if startIdx == -1 {
+		startIdx = strings.Index(content, "## Content Gaps")
+	}
 
 	if startIdx != -1 {
 		// 查找下一个 ## 或文档结尾
@@ -320,6 +324,12 @@ func extractOptimizationSuggestions(content string) []models.OptimizationSuggest
 	if startIdx == -1 {
 		startIdx = strings.Index(content, "## 行动项")
 	}
+	if startIdx == -1 {
+		startIdx = strings.Index(content, "## Action Items")
+	}
+	if startIdx == -1 {
+		startIdx = strings.Index(content, "## Recommendations")
+	}
 
 	if startIdx != -1 {
 		// 查找下一个同级标题或文档结尾
@@ -338,23 +348,24 @@ func extractOptimizationSuggestions(content string) []models.OptimizationSuggest
 
 		section := restContent[:endIdx]
 
-		// 解析优先级（🔴 高优先级, 🟡 中优先级, 🟢 低优先级）
+		// 解析优先级（🔴 高优先级, 🟡 中优先级, 🟢 低优先级，或英文 High priority 等）
 		lines := strings.Split(section, "\n")
 		currentPriority := "medium"
 
 		for _, line := range lines {
 			line = strings.TrimSpace(line)
+			lower := strings.ToLower(line)
 
 			// 检测优先级
-			if strings.Contains(line, "🔴") || strings.Contains(line, "高优先级") {
+			if strings.Contains(line, "🔴") || strings.Contains(line, "高优先级") || strings.Contains(lower, "high priority") {
 				currentPriority = "high"
 				continue
 			}
-			if strings.Contains(line, "🟡") || strings.Contains(line, "中优先级") {
+			if strings.Contains(line, "🟡") || strings.Contains(line, "中优先级") || strings.Contains(lower, "medium priority") {
 				currentPriority = "medium"
 				continue
 			}
-			if strings.Contains(line, "🟢") || strings.Contains(line, "低优先级") {
+			if strings.Contains(line, "🟢") || strings.Contains(line, "低优先级") || strings.Contains(lower, "low priority") {
 				currentPriority = "low"
 				continue
 			}
@@ -466,84 +477,148 @@ func ExtractSources(content string) []string {
 	return sources
 }
 
-// FormatAsMarkdown 将报告格式化为 Markdown
+// FormatAsMarkdown 将报告格式化为 Markdown，标题使用报告的输出语言
 func FormatAsMarkdown(report *models.OptimizationReport) string {
 	var sb strings.Builder
+	t := func(key string) string { return i18n.T(report.OutputLanguage, key) }
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", t("title")))
+	sb.WriteString(fmt.Sprintf("## %s\n\n", t("summary")))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", t("url"), report.URL))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", t("page_title"), report.Title))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", t("main_query"), report.MainQuery))
+	sb.WriteString(fmt.Sprintf("- **%s**: %d/100\n", t("overall_score"), report.OverallScore))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n\n", t("generated_at"), report.Timestamp.Format("2006-01-02 15:04:05")))
+
+	writeComparisonTable(&sb, report.ComparisonTable, t)
+	writeNumbered(&sb, t("content_gaps"), report.ContentGaps)
+	writeSuggestions(&sb, report.OptimizationSuggestions, t)
+
+	// 未能提取出对比表格和优化建议时保留完整的报告原文
+	if len(report.ComparisonTable) == 0 && len(report.OptimizationSuggestions) == 0 && strings.TrimSpace(report.OptimizationReport) != "" {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", t("report")))
+		sb.WriteString(strings.TrimSpace(DemoteHeadings(report.OptimizationReport, 2)))
+		sb.WriteString("\n\n")
+	}
 
-	sb.WriteString("# GEO 优化报告\n\n")
-	sb.WriteString(fmt.Sprintf("## 基本信息\n\n"))
-	sb.WriteString(fmt.Sprintf("- **URL**: %s\n", report.URL))
-	sb.WriteString(fmt.Sprintf("- **标题**: %s\n", report.Title))
-	sb.WriteString(fmt.Sprintf("- **主查询**: %s\n", report.MainQuery))
-	sb.WriteString(fmt.Sprintf("- **总体评分**: %d/100\n", report.OverallScore))
-	sb.WriteString(fmt.Sprintf("- **生成时间**: %s\n\n", report.Timestamp.Format("2006-01-02 15:04:05")))
-
-	if len(report.ComparisonTable) > 0 {
-		sb.WriteString("## 对比分析\n\n")
-		sb.WriteString("| 维度 | 查询发散总结 | AI Overview | 相似点 | 差异 |\n")
-		sb.WriteString("|------|-------------|-------------|--------|------|\n")
-		for _, item := range report.ComparisonTable {
-			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-				item.Dimension,
-				item.YourContent,
-				item.AIOverview,
-				item.Similarity,
-				item.Difference))
-		}
-		sb.WriteString("\n")
+	if strings.TrimSpace(report.OptimizedArticle) != "" {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", t("article")))
+		sb.WriteString(strings.TrimSpace(DemoteHeadings(report.OptimizedArticle, 2)))
+		sb.WriteString("\n\n")
 	}
 
-	if len(report.ContentGaps) > 0 {
-		sb.WriteString("## 内容差距\n\n")
-		for i, gap := range report.ContentGaps {
-			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, gap))
+	return sb.String()
+}
+
+// FormatOptimizationReport 将 content_optimizer 的结构化输出渲染为优化报告正文（OptimizationReport 字段），
+// 标题使用报告的输出语言，没有内容的章节不输出
+func FormatOptimizationReport(report *models.OptimizationReport) string {
+	var sb strings.Builder
+	t := func(key string) string { return i18n.T(report.OutputLanguage, key) }
+
+	if summary := strings.TrimSpace(report.ExecutiveSummary); summary != "" {
+		sb.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", t("exec_summary"), summary))
+	}
+	writeComparisonTable(&sb, report.ComparisonTable, t)
+	if len(report.KeyFindings) > 0 {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", t("key_findings")))
+		for _, finding := range report.KeyFindings {
+			sb.WriteString(fmt.Sprintf("- %s\n", finding))
 		}
 		sb.WriteString("\n")
 	}
+	writeNumbered(&sb, t("content_gaps"), report.ContentGaps)
+	writeSuggestions(&sb, report.OptimizationSuggestions, t)
+	if conclusion := strings.TrimSpace(report.Conclusion); conclusion != "" {
+		sb.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", t("conclusion"), conclusion))
+	}
 
-	if len(report.OptimizationSuggestions) > 0 {
-		sb.WriteString("## 优化建议\n\n")
+	return strings.TrimSpace(sb.String())
+}
 
-		// 按优先级分组
-		highPriority := []models.OptimizationSuggestion{}
-		mediumPriority := []models.OptimizationSuggestion{}
-		lowPriority := []models.OptimizationSuggestion{}
+// writeComparisonTable 写入对比表格章节
+func writeComparisonTable(sb *strings.Builder, items []models.ComparisonItem, t func(string) string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", t("comparison")))
+	sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", t("dimension"), t("your_content"), t("ai_overview"), t("similarity"), t("difference")))
+	sb.WriteString("|------|-------------|-------------|--------|------|\n")
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			tableCell(item.Dimension),
+			tableCell(item.YourContent),
+			tableCell(item.AIOverview),
+			tableCell(item.Similarity),
+			tableCell(item.Difference)))
+	}
+	sb.WriteString("\n")
+}
 
-		for _, s := range report.OptimizationSuggestions {
-			switch s.Priority {
-			case "high":
-				highPriority = append(highPriority, s)
-			case "medium":
-				mediumPriority = append(mediumPriority, s)
-			case "low":
-				lowPriority = append(lowPriority, s)
-			}
-		}
+// tableCell 将单元格内容合并为一行，竖线替换为斜杠以免破坏表格结构
+func tableCell(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "|", "/")), " ")
+}
 
-		if len(highPriority) > 0 {
-			sb.WriteString("### 🔴 高优先级\n\n")
-			for i, s := range highPriority {
-				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, s.Suggestion))
-			}
-			sb.WriteString("\n")
-		}
+// writeNumbered 写入带编号列表的章节
+func writeNumbered(sb *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", heading))
+	for i, item := range items {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, item))
+	}
+	sb.WriteString("\n")
+}
 
-		if len(mediumPriority) > 0 {
-			sb.WriteString("### 🟡 中优先级\n\n")
-			for i, s := range mediumPriority {
-				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, s.Suggestion))
+// writeSuggestions 按优先级分组写入优化建议章节，有问题描述时加粗列在建议之前
+func writeSuggestions(sb *strings.Builder, suggestions []models.OptimizationSuggestion, t func(string) string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", t("suggestions")))
+
+	for _, priority := range []string{"high", "medium", "low"} {
+		var items []models.OptimizationSuggestion
+		for _, s := range suggestions {
+			if s.Priority == priority {
+				items = append(items, s)
 			}
-			sb.WriteString("\n")
 		}
-
-		if len(lowPriority) > 0 {
-			sb.WriteString("### 🟢 低优先级\n\n")
-			for i, s := range lowPriority {
+		if len(items) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n\n", t("priority_"+priority)))
+		for i, s := range items {
+			if s.Issue != "" {
+				sb.WriteString(fmt.Sprintf("%d. **%s** — %s\n", i+1, s.Issue, s.Suggestion))
+			} else {
 				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, s.Suggestion))
 			}
-			sb.WriteString("\n")
 		}
+		sb.WriteString("\n")
 	}
+}
 
-	return sb.String()
+// DemoteHeadings 将 Markdown 标题降低 levels 级（最多到六级），用于嵌入到报告的章节中
+func DemoteHeadings(content string, levels int) string {
+	lines := strings.Split(content, "\n")
+	inCode := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		if level > 6 || (len(trimmed) > level && trimmed[level] != ' ') {
+			continue
+		}
+		lines[i] = strings.Repeat("#", min(level+levels, 6)) + trimmed[level:]
+	}
+	return strings.Join(lines, "\n")
 }
@@ -228,7 +228,7 @@ func TestExtractComparisonTable(t *testing.T) {
 | 权威性 | 中等 | 高 | 都引用来源 | 引用数量不同 |
 `
 
-	items := extractComparisonTable(content)
+	items := ExtractComparisonTable(content)
 
 	if len(items) != 2 {
 		t.Errorf("对比表格行数不匹配: got %d, want 2", len(items))
@@ -365,6 +365,86 @@ func TestFormatAsMarkdown(t *testing.T) {
 	t.Logf("生成的 Markdown:\n%s", markdown)
 }
 
+// TestParseOptimizationReport_English 测试英文报告的解析和格式化
+func TestParseOptimizationReport_English(t *testing.T) {
+	content := `## Executive Summary
+
+The page covers the basics.
+
+## Content Gaps
+
+1. No price comparison
+2. No maintenance tips
+
+## Action Items
+
+### 🔴 High priority
+
+1. Add a buying checklist
+
+### Low priority
+
+1. Add an FAQ section
+`
+
+	report := ParseOptimizationReport(content, "https://example.com/espresso")
+	if len(report.ContentGaps) != 2 {
+		t.Errorf("内容差距数量不匹配: got %d, want 2", len(report.ContentGaps))
+	}
+	if len(report.OptimizationSuggestions) != 2 {
+		t.Fatalf("建议数量不匹配: got %d, want 2", len(report.OptimizationSuggestions))
+	}
+	if got := report.OptimizationSuggestions[1].Priority; got != "low" {
+		t.Errorf("Low priority 应解析为 low, got %s", got)
+	}
+
+	report.OutputLanguage = "en"
+	markdown := FormatAsMarkdown(report)
+	for _, expected := range []string{"# GEO Optimization Report", "## Content Gaps", "### 🔴 High priority", "### 🟢 Low priority"} {
+		if !contains(markdown, expected) {
+			t.Errorf("Markdown 中缺少期望内容: %s", expected)
+		}
+	}
+	if contains(markdown, "优化建议") {
+		t.Error("英文报告不应包含中文标题")
+	}
+}
+
+// TestFormatOptimizationReport 测试结构化报告渲染，渲染结果可以被重新解析
+func TestFormatOptimizationReport(t *testing.T) {
+	report := &models.OptimizationReport{
+		ExecutiveSummary: "页面缺少型号对比。",
+		ComparisonTable: []models.ComparisonItem{
+			{Dimension: "型号对比", YourContent: "无", AIOverview: "价格 | 泵压", Similarity: "无", Difference: "缺少表格"},
+		},
+		KeyFindings: []string{"竞品都有对比表格"},
+		ContentGaps: []string{"缺少型号对比"},
+		OptimizationSuggestions: []models.OptimizationSuggestion{
+			{Priority: "high", Category: "structure", Issue: "缺少型号对比", Suggestion: "增加对比表格"},
+			{Priority: "low", Category: "content", Suggestion: "增加 FAQ"},
+		},
+		Conclusion: "补充对比表格。",
+	}
+
+	markdown := FormatOptimizationReport(report)
+	for _, expected := range []string{
+		"## 执行摘要", "## 对比分析", "价格 / 泵压", "## 关键发现", "- 竞品都有对比表格",
+		"## 内容差距", "### 🔴 高优先级", "**缺少型号对比** — 增加对比表格", "### 🟢 低优先级", "## 结论",
+	} {
+		if !contains(markdown, expected) {
+			t.Errorf("Markdown 中缺少期望内容: %s", expected)
+		}
+	}
+
+	parsed := ParseOptimizationReport(markdown, "https://example.com")
+	if len(parsed.ComparisonTable) != 1 || parsed.ComparisonTable[0].Dimension != "型号对比" {
+		t.Errorf("重新解析的对比表格 = %+v", parsed.ComparisonTable)
+	}
+	if len(parsed.ContentGaps) != 1 || len(parsed.OptimizationSuggestions) != 2 {
+		t.Errorf("重新解析的内容差距 = %v, 建议 = %+v", parsed.ContentGaps, parsed.OptimizationSuggestions)
+	}
+}
+
 // TestExtractSources 测试来源提取
 func TestExtractSources(t *testing.T) {
 	content := `## 来源
@@ -10,12 +10,20 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudwego/eino/compose"
+	"go.uber.org/zap"
 
 	"github.com/solariswu/peanut/internal/agent/geo/flow"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/agent/geo/trace"
+	"github.com/solariswu/peanut/internal/pkg/cache"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 )
 
 // AgentService Agent 服务接口
@@ -28,9 +36,14 @@ type Service struct {
 	runnable compose.Runnable[string, string]
 }
 
-// NewService 创建新的 GEO 服务（使用 flow 模式）
+// NewService 创建新的 GEO 服务（使用 flow 模式，不缓存外部调用）
 func NewService(platform string) (*Service, error) {
-	ctx := context.Background()
+	return NewServiceWithCache(platform, nil, tools.DefaultCacheTTLs())
+}
+
+// NewServiceWithCache 创建使用响应缓存的 GEO 服务，store 为 nil 时不缓存
+func NewServiceWithCache(platform string, store cache.Store, ttls tools.CacheTTLs) (*Service, error) {
+	ctx := flow.WithCache(context.Background(), store, ttls)
 
 	// 创建 GenLocalState 函数
 	genLocalState := func(ctx context.Context) *flow.State {
@@ -59,58 +72,102 @@ func (s *Service) Analyze(ctx context.Context, url, platform string) (*models.Op
 
 // AnalyzeWithProgress 分析 URL（带进度回调）
 func (s *Service) AnalyzeWithProgress(ctx context.Context, url, platform string, progress func(step int, total int, agentName string, message string)) (*models.OptimizationReport, error) {
-	fmt.Printf("[GEO] 开始分析 URL: %s, 平台: %s\n", url, platform)
+	logger := logging.FromContext(ctx)
+	logger.Info("开始 GEO 分析", zap.String("url", url), zap.String("platform", platform))
 
-	// 添加超时控制（10分钟，7个agent需要较长时间）
+	// 添加超时控制（10分钟，8个agent需要较长时间）
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
 	// 发送初始进度
 	if progress != nil {
-		progress(0, 7, "初始化", fmt.Sprintf("开始 %s GEO 分析", platform))
+		progress(0, flow.TotalSteps, "初始化", fmt.Sprintf("开始 %s GEO 分析", platform))
+	}
+
+	// 记录最近一次进度步骤，缓存命中事件沿用该步骤上报
+	var (
+		cacheMu   sync.Mutex
+		cacheHits = make(map[string]int)
+		lastStep  int
+	)
+	if progress != nil {
+		notify := progress
+		progress = func(step int, total int, agentName string, message string) {
+			cacheMu.Lock()
+			lastStep = step
+			cacheMu.Unlock()
+			notify(step, total, agentName, message)
+		}
 	}
 
 	// 将进度回调放入上下文
 	ctx = flow.WithProgressCallback(ctx, progress)
 
-	// 用于存储最终状态
+	// 收集各次 SERP 请求返回的相关问题、精选摘要等模块
+	ctx = tools.WithSERPFeatureCollector(ctx)
+
+	// 缓存命中通过进度事件上报，并在报告中统计各来源命中次数
+	ctx = cache.WithHitCallback(ctx, func(source, detail string) {
+		cacheMu.Lock()
+		cacheHits[source]++
+		step := lastStep
+		cacheMu.Unlock()
+		if progress != nil {
+			progress(step, flow.TotalSteps, "缓存命中", fmt.Sprintf("%s: %s", source, detail))
+		}
+	})
+
+	// 记录 LLM 调用用量，调用方已在上下文中设置记录器时沿用（便于合并后续调用）
+	usage := llm.UsageRecorderFromContext(ctx)
+	if usage == nil {
+		usage = llm.NewUsageRecorder()
+		ctx = llm.WithUsageRecorder(ctx, usage)
+	}
+
+	// 记录各 Agent 使用的 prompt 版本，调用方已设置记录器时沿用
+	promptVersions := prompts.RecorderFromContext(ctx)
+	if promptVersions == nil {
+		promptVersions = prompts.NewRecorder()
+		ctx = prompts.WithRecorder(ctx, promptVersions)
+	}
+
+	// 用于存储最终状态（创建 State 时保存引用，流程结束时包含完整数据）
 	var finalState *flow.State
+	ctx = flow.WithStateCallback(ctx, func(state *flow.State) {
+		finalState = state
+	})
+
+	// Agent 子图内的日志带 agent 字段；调用方在上下文中设置了追踪器时，记录每个节点的执行过程
+	opts := []compose.Option{compose.WithCallbacks(flow.NewLogHandler())}
+	if tracer := trace.FromContext(ctx); tracer != nil {
+		opts = append(opts, compose.WithCallbacks(tracer.Handler()))
+	}
 
 	// 使用 Invoke 模式执行
-	fmt.Println("[GEO] 调用 Invoke...")
-	result, err := s.runnable.Invoke(ctx, url,
-		compose.WithStateModifier(func(ctx context.Context, path compose.NodePath, state any) error {
-			fmt.Println("[GEO] StateModifier 被调用")
-			s := state.(*flow.State)
-			// 保存状态引用（将在流程结束时包含完整数据）
-			finalState = s
-			return nil
-		}),
-	)
+	result, err := s.runnable.Invoke(ctx, url, opts...)
 	if err != nil {
-		fmt.Printf("[GEO] Invoke 失败: %v\n", err)
+		logger.Error("GEO 分析失败", zap.Error(err))
 		return nil, fmt.Errorf("执行 GEO 分析失败: %w", err)
 	}
-	fmt.Printf("[GEO] Invoke 成功, 结果: %s\n", result)
+	logger.Debug("Flow 执行完成", zap.String("result", result))
 
 	// 使用最终状态生成报告
 	if finalState == nil {
-		fmt.Println("[GEO] 警告: finalState 为空，返回默认报告")
+		logger.Warn("未获取到 Flow State，返回默认报告")
 		if progress != nil {
-			progress(7, 7, "完成", "分析完成")
+			progress(flow.TotalSteps, flow.TotalSteps, "完成", "分析完成")
 		}
 		return &models.OptimizationReport{
-			URL:          url,
-			Title:        "分析完成",
-			OverallScore: 75,
+			URL:   url,
+			Title: "分析完成",
 		}, nil
 	}
 
-	// 如果 Report 字段为空，创建一个基于 state 的报告
+	// 如果 Report 字段为空（内容优化未执行），创建一个基于 state 的报告，没有评分
 	if finalState.Report == nil {
 		finalState.Report = &models.OptimizationReport{
-			OverallScore:       75,
 			OptimizationReport: generateReportFromState(finalState),
+			Timestamp:          time.Now(),
 		}
 	}
 
@@ -120,21 +177,38 @@ func (s *Service) AnalyzeWithProgress(ctx context.Context, url, platform string,
 	report.Title = finalState.Title
 	report.MainQuery = finalState.MainQuery
 	report.QueryFanout = strings.Join(finalState.QueryFanout, ", ")
+	report.RelatedQueries = finalState.QueryFanout
+	report.SearchResults = finalState.SearchResults
 	report.AIOverview = finalState.AIOverview
+	report.Sources = finalState.Sources
 	report.QueryFanoutSummary = finalState.QuerySummary
 	report.OptimizedArticle = finalState.OptimizedArticle
+	report.CompetitorAnalysis = finalState.CompetitorAnalysis
+	report.SERPFeatures = finalState.SERPFeatures()
+	report.OutputLanguage = finalState.OutputLanguage
+	report.LLMUsage = usage.Summary()
+	report.PromptVersions = promptVersions.Versions()
+	cacheMu.Lock()
+	if len(cacheHits) > 0 {
+		report.CacheHits = cacheHits
+	}
+	cacheMu.Unlock()
+	if len(report.ContentGaps) == 0 {
+		report.ContentGaps = finalState.ContentGaps
+	}
 
-	fmt.Printf("[GEO] 分析完成, 标题: %s, 主查询: %s\n", report.Title, report.MainQuery)
-	fmt.Printf("[GEO] 相关查询: %s\n", report.QueryFanout)
-	fmt.Printf("[GEO] AI Overview: %d 字符\n", len(report.AIOverview))
-	fmt.Printf("[GEO] 查询总结: %d 字符\n", len(report.QueryFanoutSummary))
-	fmt.Printf("[GEO] 优化文章: %d 字符\n", len(report.OptimizedArticle))
-	fmt.Printf("[GEO] 评分: %d\n", report.OverallScore)
-	fmt.Printf("[GEO] 优化文章长度: %d 字符\n", len(report.OptimizedArticle))
+	logger.Info("GEO 分析完成",
+		zap.String("title", report.Title),
+		zap.String("main_query", report.MainQuery),
+		zap.String("query_fanout", report.QueryFanout),
+		zap.Int("ai_overview_len", len(report.AIOverview)),
+		zap.Int("query_summary_len", len(report.QueryFanoutSummary)),
+		zap.Int("optimized_article_len", len(report.OptimizedArticle)),
+		zap.Int("overall_score", report.OverallScore))
 
 	// 发送完成进度
 	if progress != nil {
-		progress(7, 7, "完成", "分析完成")
+		progress(flow.TotalSteps, flow.TotalSteps, "完成", "分析完成")
 	}
 
 	return report, nil
@@ -163,6 +237,13 @@ func generateReportFromState(state *flow.State) string {
 		parts = append(parts, fmt.Sprintf("\n### AI Overview:\n%s\n", state.AIOverview))
 	}
 
+	if len(state.ContentGaps) > 0 {
+		parts = append(parts, "\n### 竞品内容差距:\n")
+		for _, gap := range state.ContentGaps {
+			parts = append(parts, fmt.Sprintf("- %s\n", gap))
+		}
+	}
+
 	if state.QuerySummary != "" {
 		parts = append(parts, fmt.Sprintf("\n### 查询总结:\n%s\n", state.QuerySummary))
 	}
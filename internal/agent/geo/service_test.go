@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/flow"
+	"github.com/solariswu/peanut/internal/agent/geo/trace"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+	"github.com/solariswu/peanut/internal/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// replayURL 录制 fixture 时分析的页面
+const replayURL = "https://example.com/guides/home-espresso"
+
+// setupReplay 启用录制/回放传输层，默认回放 testdata/fixtures
+// 回放时固定使用默认的模型、端点和提供者，保证请求与录制时一致
+func setupReplay(t *testing.T) *transport.Recorder {
+	t.Helper()
+
+	recorder := transport.RecorderFromEnv()
+	if recorder == nil {
+		recorder = transport.NewRecorder(transport.ModeReplay, "testdata/fixtures", nil)
+	}
+
+	if os.Getenv("GEO_HTTP_MODE") != string(transport.ModeRecord) {
+		t.Setenv("ARK_API_KEY", "replay")
+		t.Setenv("BRIGHT_DATA_API_KEY", "replay")
+		for _, key := range []string{
+			"ARK_BASE_URL", "ARK_MODEL",
+			"BRIGHT_DATA_ZONE", "BRIGHT_DATA_ENDPOINT", "BRIGHT_DATA_WEB_UNLOCKER_ZONE",
+			"GEO_SEARCH_PROVIDERS", "GEO_ANSWER_PROVIDERS",
+		} {
+			t.Setenv(key, "")
+		}
+	}
+
+	t.Cleanup(transport.SetDefault(recorder))
+	return recorder
+}
+
+func TestAnalyzeWithProgress_Replay(t *testing.T) {
+	recorder := setupReplay(t)
+
+	svc, err := NewService("google")
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	var steps []int
+	var agentsSeen []string
+	tracer := trace.NewTracer()
+	ctx := trace.WithTracer(context.Background(), tracer)
+	logs := logging.NewBuffer(0)
+	ctx = logging.WithLogger(ctx, logs.Tee(zap.NewNop()))
+	report, err := svc.AnalyzeWithProgress(ctx, replayURL, "google", func(step, total int, agentName, message string) {
+		if total != flow.TotalSteps {
+			t.Errorf("progress total = %d, want %d", total, flow.TotalSteps)
+		}
+		steps = append(steps, step)
+		agentsSeen = append(agentsSeen, agentName)
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeWithProgress() error = %v", err)
+	}
+
+	if misses := recorder.Misses(); len(misses) > 0 {
+		t.Fatalf("没有 fixture 的请求（可用 GEO_HTTP_MODE=record 重新录制）:\n%s", strings.Join(misses, "\n"))
+	}
+
+	if report.Title != "家用意式咖啡机选购指南" {
+		t.Errorf("Title = %q", report.Title)
+	}
+	if report.MainQuery != "家用意式咖啡机怎么选" {
+		t.Errorf("MainQuery = %q", report.MainQuery)
+	}
+	if got := strings.Split(report.QueryFanout, ", "); len(got) != 3 {
+		t.Errorf("QueryFanout = %q, want 3 related queries", report.QueryFanout)
+	}
+	if report.AIOverview == "" || report.QueryFanoutSummary == "" {
+		t.Errorf("AIOverview = %q, QueryFanoutSummary = %q", report.AIOverview, report.QueryFanoutSummary)
+	}
+	if report.CompetitorAnalysis == nil || len(report.CompetitorAnalysis.Sources) != 2 {
+		t.Errorf("CompetitorAnalysis = %+v, want 2 sources", report.CompetitorAnalysis)
+	}
+	if len(report.ContentGaps) == 0 {
+		t.Error("ContentGaps is empty")
+	}
+	if !strings.Contains(report.OptimizationReport, "优化建议") {
+		t.Errorf("OptimizationReport = %q", report.OptimizationReport)
+	}
+	if !strings.HasPrefix(report.OptimizedArticle, "# 家用意式咖啡机选购指南") {
+		t.Errorf("OptimizedArticle = %q", report.OptimizedArticle)
+	}
+
+	// 验证分按平台权重计算
+	if v := report.Validation; v == nil || v.OriginalScore.Total != 36 || v.OptimizedScore.Total != 66 || len(v.ComparisonTable) != 5 {
+		t.Errorf("Validation = %+v, want scores 36 -> 66 with 5 dimensions", v)
+	}
+
+	// fixture 中的响应没有 usage 字段，用量按字数估算
+	if report.LLMUsage == nil || report.LLMUsage.Calls != flow.TotalSteps || len(report.LLMUsage.Agents) != flow.TotalSteps {
+		t.Errorf("LLMUsage = %+v, want %d calls from %d agents", report.LLMUsage, flow.TotalSteps, flow.TotalSteps)
+	} else if report.LLMUsage.Agents[0].Agent != "title_scraper" || report.LLMUsage.Agents[0].EstimatedCalls != 1 {
+		t.Errorf("LLMUsage.Agents[0] = %+v", report.LLMUsage.Agents[0])
+	}
+
+	// 未配置覆盖时所有 Agent 使用内嵌 prompt
+	if len(report.PromptVersions) != flow.TotalSteps {
+		t.Errorf("PromptVersions = %v, want %d agents", report.PromptVersions, flow.TotalSteps)
+	}
+	for name, version := range report.PromptVersions {
+		if !strings.HasPrefix(version, "builtin:") {
+			t.Errorf("PromptVersions[%s] = %q, want builtin", name, version)
+		}
+	}
+
+	// 每个 Agent 的模型调用都有 span，router 节点记录更新后的 FlowState
+	var modelSpans, routerSpans int
+	for _, span := range tracer.Spans() {
+		switch {
+		case span.Component == "ChatModel":
+			modelSpans++
+			if span.Agent == "" || !strings.Contains(span.Input, "[system]") || span.Output == "" {
+				t.Errorf("ChatModel span = %+v", span)
+			}
+		case span.Name == "router":
+			routerSpans++
+			if !strings.Contains(span.State, `"step"`) {
+				t.Errorf("router span of %s has no state snapshot", span.Agent)
+			}
+		}
+	}
+	if modelSpans != flow.TotalSteps || routerSpans != flow.TotalSteps {
+		t.Errorf("trace has %d ChatModel and %d router spans, want %d each", modelSpans, routerSpans, flow.TotalSteps)
+	}
+
+	// 每个 Agent 的完成日志都带有 agent 字段
+	agentLogs := map[any]bool{}
+	for _, entry := range logs.Entries() {
+		if entry.Message == "Agent 执行完成" {
+			agentLogs[entry.Fields["agent"]] = true
+		}
+	}
+	if len(agentLogs) != flow.TotalSteps {
+		t.Errorf("completion logs from %d agents (%v), want %d", len(agentLogs), agentLogs, flow.TotalSteps)
+	}
+
+	if len(steps) == 0 || steps[len(steps)-1] != flow.TotalSteps {
+		t.Errorf("progress steps = %v (%v), want last step %d", steps, agentsSeen, flow.TotalSteps)
+	}
+	for i := 1; i < len(steps); i++ {
+		if steps[i] < steps[i-1] {
+			t.Errorf("progress steps not monotonic: %v", steps)
+			break
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// BaiduConfig 百度千帆 AI 搜索配置
+type BaiduConfig struct {
+	APIKey   string
+	Endpoint string
+	Model    string
+}
+
+// LoadBaiduConfig 从环境变量加载百度 AI 搜索配置
+func LoadBaiduConfig() (*BaiduConfig, error) {
+	apiKey := os.Getenv("BAIDU_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("未设置 BAIDU_API_KEY 环境变量")
+	}
+
+	endpoint := os.Getenv("BAIDU_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://qianfan.baidubce.com/v2/ai_search/chat/completions"
+	}
+
+	return &BaiduConfig{
+		APIKey:   apiKey,
+		Endpoint: endpoint,
+		Model:    os.Getenv("BAIDU_MODEL"), // 为空时使用服务端默认模型
+	}, nil
+}
+
+// BaiduProvider 使用百度千帆 AI 搜索的实现（AI 回答、引用来源和追问推荐）
+type BaiduProvider struct {
+	config     *BaiduConfig
+	httpClient *http.Client
+}
+
+// NewBaiduProvider 创建百度 AI 搜索提供者
+func NewBaiduProvider() (*BaiduProvider, error) {
+	config, err := LoadBaiduConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BaiduProvider{
+		config:     config,
+		httpClient: newProviderHTTPClient(),
+	}, nil
+}
+
+// baiduResponse 百度 AI 搜索响应结构
+type baiduResponse struct {
+	Code    any    `json:"code"`
+	Message string `json:"message"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	References []struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Content string `json:"content"`
+	} `json:"references"`
+	FollowupQueries []string `json:"followup_queries"`
+}
+
+// Name 返回提供者名称
+func (p *BaiduProvider) Name() string {
+	return ProviderBaidu
+}
+
+// ask 调用百度 AI 搜索
+func (p *BaiduProvider) ask(ctx context.Context, query string) (*baiduResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	payload := map[string]any{
+		"messages":                []map[string]string{{"role": "user", "content": query}},
+		"search_source":           "baidu_search_v2",
+		"resource_type_filter":    []map[string]any{{"type": "web", "top_k": 10}},
+		"enable_followup_queries": true,
+		"stream":                  false,
+	}
+	if p.config.Model != "" {
+		payload["model"] = p.config.Model
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("构建请求体失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp baiduResponse
+	if err := doJSONRequest(p.httpClient, req, "百度 AI 搜索", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != nil && fmt.Sprint(resp.Code) != "0" && fmt.Sprint(resp.Code) != "" {
+		return nil, fmt.Errorf("百度 AI 搜索返回错误: %v %s", resp.Code, resp.Message)
+	}
+	return &resp, nil
+}
+
+// Search 执行搜索，使用追问推荐作为查询发散
+func (p *BaiduProvider) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	resp, err := p.ask(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return buildFanout(query, resp.FollowupQueries), nil
+}
+
+// GetAIOverview 获取百度 AI 搜索的回答及引用来源
+func (p *BaiduProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	resp, err := p.ask(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0, len(resp.References))
+	for _, ref := range resp.References {
+		results = append(results, models.SearchResult{Title: ref.Title, URL: ref.URL, Snippet: ref.Content})
+	}
+
+	content := ""
+	if len(resp.Choices) > 0 {
+		content = strings.TrimSpace(resp.Choices[0].Message.Content)
+	}
+	if content == "" {
+		return fallbackOverview(query, results), nil
+	}
+
+	return &models.AIOverview{
+		Query:   query,
+		Summary: content,
+		Sources: resultURLs(results),
+		Snippet: resultSnippet(query, results),
+		Found:   true,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// BingConfig Bing Web Search 配置
+type BingConfig struct {
+	APIKey   string
+	Endpoint string
+	Market   string
+}
+
+// LoadBingConfig 从环境变量加载 Bing 配置
+func LoadBingConfig() (*BingConfig, error) {
+	apiKey := os.Getenv("BING_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("未设置 BING_API_KEY 环境变量")
+	}
+
+	endpoint := os.Getenv("BING_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://api.bing.microsoft.com/v7.0/search"
+	}
+
+	return &BingConfig{
+		APIKey:   apiKey,
+		Endpoint: endpoint,
+		Market:   os.Getenv("BING_MARKET"), // 例如 zh-CN、en-US，为空时由 Bing 自动判断
+	}, nil
+}
+
+// BingProvider 使用 Bing Web Search API 的实现
+// Bing API 不提供 AI 回答，GetAIOverview 返回基于自然结果生成的摘要（Found 为 false）
+type BingProvider struct {
+	config     *BingConfig
+	httpClient *http.Client
+}
+
+// NewBingProvider 创建 Bing 提供者
+func NewBingProvider() (*BingProvider, error) {
+	config, err := LoadBingConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BingProvider{
+		config:     config,
+		httpClient: newProviderHTTPClient(),
+	}, nil
+}
+
+// bingResponse Bing Web Search 响应结构
+type bingResponse struct {
+	WebPages struct {
+		Value []struct {
+			Name    string `json:"name"`
+			URL     string `json:"url"`
+			Snippet string `json:"snippet"`
+		} `json:"value"`
+	} `json:"webPages"`
+	RelatedSearches struct {
+		Value []struct {
+			Text string `json:"text"`
+		} `json:"value"`
+	} `json:"relatedSearches"`
+}
+
+// Name 返回提供者名称
+func (p *BingProvider) Name() string {
+	return ProviderBing
+}
+
+// search 调用 Bing Web Search
+func (p *BingProvider) search(ctx context.Context, query string) (*bingResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	opts := SearchOptionsFromContext(ctx)
+	switch {
+	case opts.Language != "" && opts.Country != "":
+		params.Set("mkt", opts.Language+"-"+strings.ToUpper(opts.Country))
+	case p.config.Market != "":
+		params.Set("mkt", p.config.Market)
+	}
+	if offset := resultOffset(opts); offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", p.config.APIKey)
+
+	var resp bingResponse
+	if err := doJSONRequest(p.httpClient, req, "Bing", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Search 执行搜索，获取查询发散
+func (p *BingProvider) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	related := make([]string, 0, len(resp.RelatedSearches.Value))
+	for _, r := range resp.RelatedSearches.Value {
+		related = append(related, r.Text)
+	}
+	return buildFanout(query, related), nil
+}
+
+// GetAIOverview 基于搜索结果生成摘要
+func (p *BingProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0, len(resp.WebPages.Value))
+	for _, r := range resp.WebPages.Value {
+		results = append(results, models.SearchResult{Title: r.Name, URL: r.URL, Snippet: r.Snippet})
+	}
+	return fallbackOverview(query, results), nil
+}
@@ -9,12 +9,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
 )
 
 // BrightDataConfig Bright Data 配置
@@ -87,24 +89,67 @@ func NewBrightDataSearcher() (*BrightDataSearcher, error) {
 
 	return &BrightDataSearcher{
 		config: config,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		httpClient: transport.NewClient(60 * time.Second),
 	}, nil
 }
 
-// Search 执行搜索，获取查询发散
+// Name 返回提供者名称
+func (s *BrightDataSearcher) Name() string {
+	return ProviderBrightData
+}
+
+// Search 执行搜索，获取查询发散（相关搜索）及结果页中的结构化模块
 func (s *BrightDataSearcher) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	serpResp, err := requestBrightDataSERP(ctx, s.httpClient, s.config, query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	features := serpResp.features()
+	return &models.QueryFanout{
+		OriginalQuery:  query,
+		RelatedQueries: features.RelatedSearches,
+		Features:       features,
+		Timestamp:      time.Now(),
+	}, nil
+}
+
+// buildGoogleSearchURL 构造带国家、语言、设备和分页参数的 Google 搜索 URL
+// brd_json=1 让 Bright Data 返回解析后的 JSON，brd_mobile=1 模拟移动端
+func buildGoogleSearchURL(query string, opts models.SearchOptions) string {
+	params := url.Values{}
+	params.Set("q", query)
+	if opts.Country != "" {
+		params.Set("gl", opts.Country)
+	}
+	if opts.Language != "" {
+		params.Set("hl", opts.Language)
+	}
+	if offset := resultOffset(opts); offset > 0 {
+		params.Set("start", strconv.Itoa(offset))
+	}
+	if opts.Device == models.DeviceMobile {
+		params.Set("brd_mobile", "1")
+	}
+	params.Set("brd_json", "1")
+	return "https://www.google.com/search?" + params.Encode()
+}
+
+// requestBrightDataSERP 请求 Bright Data SERP API，搜索参数从上下文读取
+func requestBrightDataSERP(ctx context.Context, client *http.Client, config *BrightDataConfig, query string, aiOverview bool) (*BrightDataSERPResponse, error) {
 	if query == "" {
 		return nil, fmt.Errorf("搜索查询不能为空")
 	}
 
 	// 构建请求体（匹配官方 API 格式）
 	requestBody := map[string]any{
-		"zone":   s.config.Zone,
-		"url":    fmt.Sprintf("https://www.google.com/search?q=%s", url.QueryEscape(query)),
+		"zone":   config.Zone,
+		"url":    buildGoogleSearchURL(query, SearchOptionsFromContext(ctx)),
 		"format": "raw",
 	}
+	if aiOverview {
+		requestBody["brd_ai_overview"] = "2" // 启用 AI Overview
+	}
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
@@ -112,17 +157,17 @@ func (s *BrightDataSearcher) Search(ctx context.Context, query string) (*models.
 	}
 
 	// 创建 POST 请求
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.Endpoint, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Endpoint, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	// 设置请求头
-	req.Header.Set("Authorization", "Bearer "+s.config.APIKey)
+	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	// 发送请求
-	resp, err := s.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("Bright Data API 请求失败: %w", err)
 	}
@@ -144,70 +189,154 @@ func (s *BrightDataSearcher) Search(ctx context.Context, query string) (*models.
 	if err := json.Unmarshal(body, &serpResp); err != nil {
 		return nil, fmt.Errorf("解析响应失败: %w", err)
 	}
-
-	// 转换为 QueryFanout
-	relatedQueries := make([]string, 0)
-
-	// 从相关搜索中提取查询
-	for _, related := range serpResp.RelatedSearches {
-		if related.Query != "" {
-			relatedQueries = append(relatedQueries, related.Query)
-		}
-	}
-
-	// 如果相关搜索不够，从搜索结果标题中生成
-	if len(relatedQueries) < 5 {
-		for _, result := range serpResp.OrganicResults {
-			if result.Title != "" {
-				relatedQueries = append(relatedQueries, result.Title)
-			}
-			if len(relatedQueries) >= 10 {
-				break
-			}
-		}
-	}
-
-	return &models.QueryFanout{
-		OriginalQuery:  query,
-		RelatedQueries: relatedQueries,
-		Timestamp:      time.Now(),
-	}, nil
+	return &serpResp, nil
 }
 
-// BrightDataSERPResponse Bright Data SERP API 响应结构
+// BrightDataSERPResponse Bright Data SERP API 响应结构（brd_json=1）
+// 同时兼容 organic_results / related_searches 等旧字段
 type BrightDataSERPResponse struct {
-	SearchMetadata struct {
-		ID          string `json:"id"`
-		Status      string `json:"status"`
-		CreatedAt   string `json:"created_at"`
-		ProcessedAt string `json:"processed_at"`
-	} `json:"search_metadata"`
-
-	SearchParameters struct {
-		Q  string `json:"q"`
-		Gl string `json:"gl"`
-		Hl string `json:"hl"`
-	} `json:"search_parameters"`
+	Organic []struct {
+		Title       string `json:"title"`
+		Link        string `json:"link"`
+		Description string `json:"description"`
+		DisplayLink string `json:"display_link"`
+		Rank        int    `json:"rank"`
+	} `json:"organic"`
 
 	OrganicResults []struct {
-		Title        string `json:"title"`
-		URL          string `json:"link"`
-		Snippet      string `json:"snippet"`
-		DisplayURL   string `json:"displayed_link"`
+		Title      string `json:"title"`
+		URL        string `json:"link"`
+		Snippet    string `json:"snippet"`
+		DisplayURL string `json:"displayed_link"`
 	} `json:"organic_results"`
 
+	Related []struct {
+		Text string `json:"text"`
+		Link string `json:"link"`
+	} `json:"related"`
+
 	RelatedSearches []struct {
 		Query string `json:"query"`
 		Link  string `json:"link"`
 	} `json:"related_searches"`
 
+	PeopleAlsoAsk []struct {
+		Question     string `json:"question"`
+		AnswerSource string `json:"answer_source"`
+		AnswerLink   string `json:"answer_link"`
+	} `json:"people_also_ask"`
+
+	FeaturedSnippets []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Link        string `json:"link"`
+	} `json:"featured_snippets"`
+
+	Knowledge *struct {
+		Name            string `json:"name"`
+		Subtitle        string `json:"subtitle"`
+		Description     string `json:"description"`
+		DescriptionLink string `json:"description_link"`
+		Facts           []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"facts"`
+	} `json:"knowledge"`
+
 	AIOverview *struct {
-		Title    string   `json:"title"`
-		Snippet  string   `json:"snippet"`
-		Sources  []string `json:"sources"`
+		Title   string   `json:"title"`
+		Snippet string   `json:"snippet"`
+		Sources []string `json:"sources"`
+		Texts   []struct {
+			Type    string `json:"type"`
+			Snippet string `json:"snippet"`
+		} `json:"texts"`
+		References []struct {
+			Title string `json:"title"`
+			Href  string `json:"href"`
+		} `json:"references"`
 	} `json:"ai_overview,omitempty"`
 }
 
+// organicResults 转换自然搜索结果
+func (r *BrightDataSERPResponse) organicResults() []models.SearchResult {
+	results := make([]models.SearchResult, 0, len(r.Organic)+len(r.OrganicResults))
+	for _, o := range r.Organic {
+		results = append(results, models.SearchResult{Title: o.Title, URL: o.Link, Snippet: o.Description})
+	}
+	for _, o := range r.OrganicResults {
+		results = append(results, models.SearchResult{Title: o.Title, URL: o.URL, Snippet: o.Snippet})
+	}
+	return results
+}
+
+// features 提取相关搜索、相关问题、精选摘要和知识面板
+func (r *BrightDataSERPResponse) features() *models.SERPFeatures {
+	related := make([]string, 0, len(r.Related)+len(r.RelatedSearches))
+	for _, item := range r.Related {
+		related = append(related, item.Text)
+	}
+	for _, item := range r.RelatedSearches {
+		related = append(related, item.Query)
+	}
+
+	questions := make([]string, 0, len(r.PeopleAlsoAsk))
+	for _, item := range r.PeopleAlsoAsk {
+		questions = append(questions, item.Question)
+	}
+
+	features := &models.SERPFeatures{}
+	features.RelatedSearches = buildFanout("", related).RelatedQueries
+	features.PeopleAlsoAsk = buildFanout("", questions).RelatedQueries
+
+	if len(r.FeaturedSnippets) > 0 {
+		fs := r.FeaturedSnippets[0]
+		features.FeaturedSnippet = &models.FeaturedSnippet{Title: fs.Title, Snippet: fs.Description, URL: fs.Link}
+	}
+
+	if r.Knowledge != nil && r.Knowledge.Name != "" {
+		panel := &models.KnowledgePanel{
+			Title:       r.Knowledge.Name,
+			Subtitle:    r.Knowledge.Subtitle,
+			Description: r.Knowledge.Description,
+			URL:         r.Knowledge.DescriptionLink,
+		}
+		for _, fact := range r.Knowledge.Facts {
+			if value := knowledgeFactText(fact.Value); fact.Key != "" && value != "" {
+				if panel.Facts == nil {
+					panel.Facts = make(map[string]string)
+				}
+				panel.Facts[fact.Key] = value
+			}
+		}
+		features.KnowledgePanel = panel
+	}
+
+	return features
+}
+
+// knowledgeFactText 知识面板事实的值可能是字符串或 [{"text": ...}] 列表
+func knowledgeFactText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, ", ")
+}
+
 // BrightDataSERPProvider 使用 Bright Data 作为 SERP 提供者
 type BrightDataSERPProvider struct {
 	config     *BrightDataConfig
@@ -223,112 +352,70 @@ func NewBrightDataSERPProvider() (*BrightDataSERPProvider, error) {
 
 	return &BrightDataSERPProvider{
 		config: config,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		httpClient: transport.NewClient(60 * time.Second),
 	}, nil
 }
 
-// GetAIOverview 获取 AI Overview
-func (p *BrightDataSERPProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
-	if query == "" {
-		return nil, fmt.Errorf("搜索查询不能为空")
-	}
-
-	// 构建请求体（匹配官方 API 格式）
-	requestBody := map[string]any{
-		"zone":             p.config.Zone,
-		"url":              fmt.Sprintf("https://www.google.com/search?q=%s", url.QueryEscape(query)),
-		"format":           "raw",
-		"brd_ai_overview":  "2", // 启用 AI Overview
-	}
-
-	bodyBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("构建请求体失败: %w", err)
-	}
+// Name 返回提供者名称
+func (p *BrightDataSERPProvider) Name() string {
+	return ProviderBrightData
+}
 
-	// 创建 POST 请求
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Endpoint, bytes.NewReader(bodyBytes))
+// GetAIOverview 获取 AI Overview，没有时使用自然结果生成摘要
+func (p *BrightDataSERPProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	serpResp, err := requestBrightDataSERP(ctx, p.httpClient, p.config, query, true)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, err
 	}
 
-	// 设置请求头
-	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// 发送请求
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Bright Data API 请求失败: %w", err)
-	}
-	defer resp.Body.Close()
+	results := serpResp.organicResults()
+	features := serpResp.features()
 
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+	ai := serpResp.AIOverview
+	if ai == nil {
+		overview := fallbackOverview(query, results)
+		overview.Features = features
+		return overview, nil
 	}
 
-	// 检查状态码
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Bright Data API 返回错误: %s - %s", resp.Status, string(body))
+	parts := make([]string, 0, 2+len(ai.Texts))
+	for _, text := range []string{ai.Title, ai.Snippet} {
+		if text != "" {
+			parts = append(parts, text)
+		}
 	}
-
-	// 解析响应
-	var serpResp BrightDataSERPResponse
-	if err := json.Unmarshal(body, &serpResp); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+	for _, t := range ai.Texts {
+		if t.Snippet != "" {
+			parts = append(parts, t.Snippet)
+		}
 	}
 
-	// 提取 AI Overview 或生成摘要
-	aiOverview := &models.AIOverview{
-		Query:   query,
-		Sources: make([]string, 0),
+	sources := make([]string, 0, len(ai.Sources)+len(ai.References))
+	for _, src := range ai.Sources {
+		sources = appendUnique(sources, src)
 	}
-
-	// 如果响应中包含 AI Overview，使用它
-	if serpResp.AIOverview != nil {
-		aiOverview.Summary = serpResp.AIOverview.Title + "\n\n" + serpResp.AIOverview.Snippet
-		aiOverview.Sources = serpResp.AIOverview.Sources
-		if len(aiOverview.Sources) == 0 {
-			// 从有机结果中提取来源
-			for _, result := range serpResp.OrganicResults {
-				if result.URL != "" {
-					aiOverview.Sources = append(aiOverview.Sources, result.URL)
-				}
-			}
-		}
-	} else {
-		// 生成基于搜索结果的摘要
-		aiOverview.Summary = generateSummaryFromResults(query, serpResp.OrganicResults)
-
-		// 提取来源
-		for _, result := range serpResp.OrganicResults {
-			if result.URL != "" {
-				aiOverview.Sources = append(aiOverview.Sources, result.URL)
-			}
+	for _, ref := range ai.References {
+		if ref.Href != "" {
+			sources = appendUnique(sources, ref.Href)
 		}
 	}
-
-	// 提取片段（使用第一个结果的摘要）
-	if len(serpResp.OrganicResults) > 0 && serpResp.OrganicResults[0].Snippet != "" {
-		aiOverview.Snippet = serpResp.OrganicResults[0].Snippet
-	} else {
-		aiOverview.Snippet = fmt.Sprintf("关于「%s」的搜索结果", query)
+	if len(sources) == 0 {
+		// 从自然结果中提取来源
+		sources = resultURLs(results)
 	}
 
-	return aiOverview, nil
+	return &models.AIOverview{
+		Query:    query,
+		Summary:  strings.Join(parts, "\n\n"),
+		Sources:  sources,
+		Snippet:  resultSnippet(query, results),
+		Found:    true,
+		Features: features,
+	}, nil
 }
 
 // generateSummaryFromResults 从搜索结果生成摘要
-func generateSummaryFromResults(query string, results []struct {
-	Title      string `json:"title"`
-	URL        string `json:"link"`
-	Snippet    string `json:"snippet"`
-	DisplayURL string `json:"displayed_link"`
-}) string {
+func generateSummaryFromResults(query string, results []models.SearchResult) string {
 	if len(results) == 0 {
 		return fmt.Sprintf("关于「%s」未找到相关搜索结果。", query)
 	}
@@ -347,96 +434,6 @@ func generateSummaryFromResults(query string, results []struct {
 	return summary
 }
 
-// Info 返回搜索工具信息 (实现 tool.InvokableTool 接口)
-func (s *BrightDataSearcher) Info(ctx context.Context) (*schema.ToolInfo, error) {
-	return &schema.ToolInfo{
-		Name: "search_queries",
-		Desc: "基于查询词搜索相关内容",
-		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
-			"query": {
-				Type: "string",
-				Desc: "搜索查询词",
-			},
-		}),
-	}, nil
-}
-
-// InvokableRun 执行搜索工具 (实现 tool.InvokableTool 接口)
-func (s *BrightDataSearcher) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
-	var req struct {
-		Query string `json:"query"`
-	}
-	if err := json.Unmarshal([]byte(argumentsInJSON), &req); err != nil {
-		return "", fmt.Errorf("解析请求失败: %w", err)
-	}
-
-	result, err := s.Search(ctx, req.Query)
-	if err != nil {
-		return "", fmt.Errorf("搜索失败: %w", err)
-	}
-
-	resp := struct {
-		OriginalQuery  string   `json:"original_query"`
-		RelatedQueries []string `json:"related_queries"`
-	}{
-		OriginalQuery:  result.OriginalQuery,
-		RelatedQueries: result.RelatedQueries,
-	}
-
-	data, err := json.Marshal(resp)
-	if err != nil {
-		return "", fmt.Errorf("序列化响应失败: %w", err)
-	}
-
-	return string(data), nil
-}
-
-// Info 返回 AI Overview 工具信息 (实现 tool.InvokableTool 接口)
-func (p *BrightDataSERPProvider) Info(ctx context.Context) (*schema.ToolInfo, error) {
-	return &schema.ToolInfo{
-		Name: "get_ai_overview",
-		Desc: "获取生成式搜索引擎的 AI 摘要内容",
-		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
-			"query": {
-				Type: "string",
-				Desc: "要查询的内容",
-			},
-		}),
-	}, nil
-}
-
-// InvokableRun 执行工具 (实现 tool.InvokableTool 接口)
-func (p *BrightDataSERPProvider) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
-	var req struct {
-		Query string `json:"query"`
-	}
-	if err := json.Unmarshal([]byte(argumentsInJSON), &req); err != nil {
-		return "", fmt.Errorf("解析请求失败: %w", err)
-	}
-
-	result, err := p.GetAIOverview(ctx, req.Query)
-	if err != nil {
-		return "", fmt.Errorf("获取 AI Overview 失败: %w", err)
-	}
-
-	resp := struct {
-		Query   string   `json:"query"`
-		Summary string   `json:"summary"`
-		Sources []string `json:"sources"`
-	}{
-		Query:   result.Query,
-		Summary: result.Summary,
-		Sources: result.Sources,
-	}
-
-	data, err := json.Marshal(resp)
-	if err != nil {
-		return "", fmt.Errorf("序列化响应失败: %w", err)
-	}
-
-	return string(data), nil
-}
-
 // BrightDataWebScraper 使用 Bright Data Web Unlocker 的网页爬取实现
 type BrightDataWebScraper struct {
 	config     *BrightDataConfig
@@ -452,9 +449,7 @@ func NewBrightDataWebScraper() (*BrightDataWebScraper, error) {
 
 	return &BrightDataWebScraper{
 		config: config,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		httpClient: transport.NewClient(60 * time.Second),
 	}, nil
 }
 
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/pkg/cache"
+)
+
+// 缓存数据来源（用于缓存键命名空间和命中上报）
+const (
+	CacheSourceSearch = "search"
+	CacheSourceAnswer = "answer"
+	CacheSourceScrape = "scrape"
+	CacheSourceLLM    = "llm"
+)
+
+// CacheTTLs 各数据来源的缓存有效期
+type CacheTTLs struct {
+	Search time.Duration
+	Answer time.Duration
+	Scrape time.Duration
+	LLM    time.Duration
+}
+
+// DefaultCacheTTLs 默认缓存有效期：AI 回答变化较快，LLM 输出由输入唯一决定
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Search: 24 * time.Hour,
+		Answer: 6 * time.Hour,
+		Scrape: 12 * time.Hour,
+		LLM:    7 * 24 * time.Hour,
+	}
+}
+
+// LoadCacheTTLs 从环境变量 GEO_CACHE_TTL_SEARCH / _ANSWER / _SCRAPE / _LLM 加载缓存有效期（如 6h、30m）
+func LoadCacheTTLs() (CacheTTLs, error) {
+	ttls := DefaultCacheTTLs()
+	for key, target := range map[string]*time.Duration{
+		"GEO_CACHE_TTL_SEARCH": &ttls.Search,
+		"GEO_CACHE_TTL_ANSWER": &ttls.Answer,
+		"GEO_CACHE_TTL_SCRAPE": &ttls.Scrape,
+		"GEO_CACHE_TTL_LLM":    &ttls.LLM,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return ttls, fmt.Errorf("%s 格式错误: %w", key, err)
+		}
+		*target = d
+	}
+	return ttls, nil
+}
+
+// normalizeQuery 规范化查询词，使大小写和多余空白不同的请求命中同一缓存
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// CachedSearcher 带缓存的搜索提供者
+type CachedSearcher struct {
+	inner Searcher
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedSearcher 创建带缓存的搜索提供者
+func NewCachedSearcher(inner Searcher, store cache.Store, ttl time.Duration) *CachedSearcher {
+	return &CachedSearcher{inner: inner, store: store, ttl: ttl}
+}
+
+// Name 返回提供者名称
+func (c *CachedSearcher) Name() string {
+	return c.inner.Name()
+}
+
+// Search 以提供者、规范化查询和搜索参数为键缓存搜索结果
+func (c *CachedSearcher) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	key := cache.Key("geo:"+CacheSourceSearch, c.inner.Name(), normalizeQuery(query), SearchOptionsFromContext(ctx))
+	return cache.Fetch(ctx, c.store, key, c.ttl, CacheSourceSearch, query, func() (*models.QueryFanout, error) {
+		return c.inner.Search(ctx, query)
+	})
+}
+
+// CachedAnswerProvider 带缓存的 AI 回答提供者
+type CachedAnswerProvider struct {
+	inner AnswerProvider
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedAnswerProvider 创建带缓存的 AI 回答提供者
+func NewCachedAnswerProvider(inner AnswerProvider, store cache.Store, ttl time.Duration) *CachedAnswerProvider {
+	return &CachedAnswerProvider{inner: inner, store: store, ttl: ttl}
+}
+
+// Name 返回提供者名称
+func (c *CachedAnswerProvider) Name() string {
+	return c.inner.Name()
+}
+
+// GetAIOverview 以提供者、规范化查询和搜索参数为键缓存 AI 回答
+// 没有找到 AI 摘要的回退结果不写入缓存，下次请求重新查询
+func (c *CachedAnswerProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	key := cache.Key("geo:"+CacheSourceAnswer, c.inner.Name(), normalizeQuery(query), SearchOptionsFromContext(ctx))
+	return cache.FetchIf(ctx, c.store, key, c.ttl, CacheSourceAnswer, query, func() (*models.AIOverview, error) {
+		return c.inner.GetAIOverview(ctx, query)
+	}, func(overview *models.AIOverview) bool {
+		return overview != nil && overview.Found
+	})
+}
+
+// CachedScraper 带缓存的网页爬取
+type CachedScraper struct {
+	inner WebScraper
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedScraper 创建带缓存的网页爬取
+func NewCachedScraper(inner WebScraper, store cache.Store, ttl time.Duration) *CachedScraper {
+	return &CachedScraper{inner: inner, store: store, ttl: ttl}
+}
+
+// Scrape 以去除首尾空白和末尾斜杠的 URL 为键缓存爬取结果
+func (c *CachedScraper) Scrape(ctx context.Context, url string) (*models.ScrapedTitle, error) {
+	key := cache.Key("geo:"+CacheSourceScrape, strings.TrimRight(strings.TrimSpace(url), "/"))
+	return cache.Fetch(ctx, c.store, key, c.ttl, CacheSourceScrape, url, func() (*models.ScrapedTitle, error) {
+		return c.inner.Scrape(ctx, url)
+	})
+}
@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// DataForSEOConfig DataForSEO 配置
+type DataForSEOConfig struct {
+	Login        string
+	Password     string
+	Endpoint     string
+	LocationCode int
+	LanguageCode string
+}
+
+// LoadDataForSEOConfig 从环境变量加载 DataForSEO 配置
+func LoadDataForSEOConfig() (*DataForSEOConfig, error) {
+	login := os.Getenv("DATAFORSEO_LOGIN")
+	password := os.Getenv("DATAFORSEO_PASSWORD")
+	if login == "" || password == "" {
+		return nil, fmt.Errorf("未设置 DATAFORSEO_LOGIN 或 DATAFORSEO_PASSWORD 环境变量")
+	}
+
+	endpoint := os.Getenv("DATAFORSEO_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://api.dataforseo.com/v3/serp/google/organic/live/advanced"
+	}
+
+	locationCode := 2840 // 美国
+	if v := os.Getenv("DATAFORSEO_LOCATION_CODE"); v != "" {
+		code, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("DATAFORSEO_LOCATION_CODE 格式错误: %w", err)
+		}
+		locationCode = code
+	}
+
+	languageCode := os.Getenv("DATAFORSEO_LANGUAGE_CODE")
+	if languageCode == "" {
+		languageCode = "en"
+	}
+
+	return &DataForSEOConfig{
+		Login:        login,
+		Password:     password,
+		Endpoint:     endpoint,
+		LocationCode: locationCode,
+		LanguageCode: languageCode,
+	}, nil
+}
+
+// DataForSEOProvider 使用 DataForSEO SERP API 的实现
+type DataForSEOProvider struct {
+	config     *DataForSEOConfig
+	httpClient *http.Client
+}
+
+// NewDataForSEOProvider 创建 DataForSEO 提供者
+func NewDataForSEOProvider() (*DataForSEOProvider, error) {
+	config, err := LoadDataForSEOConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataForSEOProvider{
+		config:     config,
+		httpClient: newProviderHTTPClient(),
+	}, nil
+}
+
+// dataForSEOReference AI Overview 引用
+type dataForSEOReference struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// dataForSEOItem SERP 元素（不同 type 的 items 字段结构不同，延迟解析）
+type dataForSEOItem struct {
+	Type        string                `json:"type"`
+	Title       string                `json:"title"`
+	URL         string                `json:"url"`
+	Description string                `json:"description"`
+	Text        string                `json:"text"`
+	Markdown    string                `json:"markdown"`
+	Items       json.RawMessage       `json:"items"`
+	References  []dataForSEOReference `json:"references"`
+}
+
+// dataForSEOResponse DataForSEO 响应结构
+type dataForSEOResponse struct {
+	StatusCode    int    `json:"status_code"`
+	StatusMessage string `json:"status_message"`
+	Tasks         []struct {
+		StatusCode    int    `json:"status_code"`
+		StatusMessage string `json:"status_message"`
+		Result        []struct {
+			Items []dataForSEOItem `json:"items"`
+		} `json:"result"`
+	} `json:"tasks"`
+}
+
+// Name 返回提供者名称
+func (p *DataForSEOProvider) Name() string {
+	return ProviderDataForSEO
+}
+
+// search 调用 SERP 接口，返回全部 SERP 元素
+func (p *DataForSEOProvider) search(ctx context.Context, query string) ([]dataForSEOItem, error) {
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	params := map[string]any{
+		"keyword":                query,
+		"location_code":          p.config.LocationCode,
+		"language_code":          p.config.LanguageCode,
+		"load_async_ai_overview": true,
+	}
+	// DataForSEO 按 location_code 定位，国家参数不做映射，使用配置值
+	opts := SearchOptionsFromContext(ctx)
+	if opts.Language != "" {
+		params["language_code"] = opts.Language
+	}
+	if opts.Device != "" {
+		params["device"] = opts.Device
+	}
+	if opts.Page > 1 {
+		params["depth"] = opts.Page * 10
+	}
+
+	body, err := json.Marshal([]map[string]any{params})
+	if err != nil {
+		return nil, fmt.Errorf("构建请求体失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.SetBasicAuth(p.config.Login, p.config.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp dataForSEOResponse
+	if err := doJSONRequest(p.httpClient, req, "DataForSEO", &resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 20000 {
+		return nil, fmt.Errorf("DataForSEO 返回错误: %d %s", resp.StatusCode, resp.StatusMessage)
+	}
+	if len(resp.Tasks) == 0 {
+		return nil, fmt.Errorf("DataForSEO 未返回任务结果")
+	}
+
+	task := resp.Tasks[0]
+	if task.StatusCode != 20000 {
+		return nil, fmt.Errorf("DataForSEO 任务失败: %d %s", task.StatusCode, task.StatusMessage)
+	}
+
+	items := make([]dataForSEOItem, 0)
+	for _, r := range task.Result {
+		items = append(items, r.Items...)
+	}
+	return items, nil
+}
+
+// Search 执行搜索，获取查询发散
+func (p *DataForSEOProvider) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	items, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	features := dataForSEOFeatures(items)
+	fanout := buildFanout(query, features.RelatedSearches)
+	fanout.Features = features
+	return fanout, nil
+}
+
+// dataForSEOFeatures 提取相关搜索、相关问题、精选摘要和知识面板
+func dataForSEOFeatures(items []dataForSEOItem) *models.SERPFeatures {
+	related := make([]string, 0)
+	questions := make([]string, 0)
+	features := &models.SERPFeatures{}
+	for _, item := range items {
+		switch item.Type {
+		case "related_searches":
+			var queries []string
+			_ = json.Unmarshal(item.Items, &queries)
+			related = append(related, queries...)
+		case "people_also_ask":
+			var elements []struct {
+				Title string `json:"title"`
+			}
+			_ = json.Unmarshal(item.Items, &elements)
+			for _, e := range elements {
+				questions = append(questions, e.Title)
+			}
+		case "featured_snippet":
+			if features.FeaturedSnippet == nil {
+				features.FeaturedSnippet = &models.FeaturedSnippet{Title: item.Title, Snippet: item.Description, URL: item.URL}
+			}
+		case "knowledge_graph":
+			if features.KnowledgePanel == nil && item.Title != "" {
+				features.KnowledgePanel = &models.KnowledgePanel{Title: item.Title, Description: item.Description, URL: item.URL}
+			}
+		}
+	}
+
+	features.RelatedSearches = buildFanout("", related).RelatedQueries
+	features.PeopleAlsoAsk = buildFanout("", questions).RelatedQueries
+	return features
+}
+
+// GetAIOverview 获取 AI Overview，没有时使用自然结果生成摘要
+func (p *DataForSEOProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	items, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0)
+	var overview *dataForSEOItem
+	for i, item := range items {
+		switch item.Type {
+		case "organic":
+			results = append(results, models.SearchResult{Title: item.Title, URL: item.URL, Snippet: item.Description})
+		case "ai_overview":
+			if overview == nil {
+				overview = &items[i]
+			}
+		}
+	}
+
+	if overview == nil {
+		fallback := fallbackOverview(query, results)
+		fallback.Features = dataForSEOFeatures(items)
+		return fallback, nil
+	}
+
+	var elements []dataForSEOItem
+	_ = json.Unmarshal(overview.Items, &elements)
+
+	summary := overview.Markdown
+	if summary == "" {
+		parts := make([]string, 0, len(elements))
+		for _, e := range elements {
+			if e.Title != "" {
+				parts = append(parts, "## "+e.Title)
+			}
+			if e.Text != "" {
+				parts = append(parts, e.Text)
+			}
+		}
+		summary = strings.Join(parts, "\n\n")
+	}
+
+	sources := make([]string, 0)
+	refs := overview.References
+	for _, e := range elements {
+		refs = append(refs, e.References...)
+	}
+	for _, ref := range refs {
+		if ref.URL != "" {
+			sources = appendUnique(sources, ref.URL)
+		}
+	}
+
+	return &models.AIOverview{
+		Query:    query,
+		Summary:  strings.TrimSpace(summary),
+		Sources:  sources,
+		Snippet:  resultSnippet(query, results),
+		Found:    true,
+		Features: dataForSEOFeatures(items),
+	}, nil
+}
@@ -0,0 +1,423 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
+	"github.com/solariswu/peanut/internal/pkg/logging"
+	"github.com/solariswu/peanut/internal/pkg/telemetry"
+	"go.uber.org/zap"
+)
+
+// 搜索/SERP 提供者名称（用于 GEO_SEARCH_PROVIDERS、GEO_ANSWER_PROVIDERS 配置）
+const (
+	ProviderBrightData = "brightdata"
+	ProviderSerpAPI    = "serpapi"
+	ProviderDataForSEO = "dataforseo"
+	ProviderBaidu      = "baidu"
+	ProviderBing       = "bing"
+	ProviderSearxNG    = "searxng"
+)
+
+// Searcher 搜索提供者：获取查询的相关搜索（查询发散）
+type Searcher interface {
+	Name() string
+	Search(ctx context.Context, query string) (*models.QueryFanout, error)
+}
+
+// AnswerProvider AI 回答提供者：获取搜索引擎对查询给出的 AI 摘要及引用来源
+type AnswerProvider interface {
+	Name() string
+	GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error)
+}
+
+// FailoverSearcher 按顺序尝试多个搜索提供者，返回第一个成功的结果
+type FailoverSearcher struct {
+	providers []Searcher
+}
+
+// NewFailoverSearcher 创建故障转移搜索器
+func NewFailoverSearcher(providers ...Searcher) *FailoverSearcher {
+	return &FailoverSearcher{providers: providers}
+}
+
+// Name 返回提供者名称
+func (f *FailoverSearcher) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Search 依次调用各提供者，全部失败时返回合并后的错误
+func (f *FailoverSearcher) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	errs := make([]error, 0, len(f.providers))
+	for _, p := range f.providers {
+		result, err := p.Search(ctx, query)
+		if err == nil {
+			return result, nil
+		}
+		telemetry.SERPErrors.WithLabelValues(p.Name(), "search").Inc()
+		logging.FromContext(ctx).Warn("SERP 提供者调用失败", zap.String("provider", p.Name()), zap.String("kind", "search"), zap.Error(err))
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("所有搜索提供者均失败: %w", errors.Join(errs...))
+}
+
+// FailoverAnswerProvider 按顺序尝试多个 AI 回答提供者，返回第一个找到 AI 摘要的结果
+type FailoverAnswerProvider struct {
+	providers []AnswerProvider
+}
+
+// NewFailoverAnswerProvider 创建故障转移 AI 回答提供者
+func NewFailoverAnswerProvider(providers ...AnswerProvider) *FailoverAnswerProvider {
+	return &FailoverAnswerProvider{providers: providers}
+}
+
+// Name 返回提供者名称
+func (f *FailoverAnswerProvider) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// GetAIOverview 依次调用各提供者，返回第一个找到 AI 摘要的结果；
+// 都没有找到时返回最后一个回退摘要，全部失败时返回合并后的错误
+func (f *FailoverAnswerProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	var miss *models.AIOverview
+	errs := make([]error, 0, len(f.providers))
+	for _, p := range f.providers {
+		result, err := p.GetAIOverview(ctx, query)
+		if err == nil {
+			if result.Found {
+				return result, nil
+			}
+			miss = result
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		telemetry.SERPErrors.WithLabelValues(p.Name(), "answer").Inc()
+		logging.FromContext(ctx).Warn("SERP 提供者调用失败", zap.String("provider", p.Name()), zap.String("kind", "answer"), zap.Error(err))
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if miss != nil {
+		return miss, nil
+	}
+	return nil, fmt.Errorf("所有 AI 回答提供者均失败: %w", errors.Join(errs...))
+}
+
+// NewSearcher 根据名称创建搜索提供者
+func NewSearcher(name string) (Searcher, error) {
+	switch name {
+	case ProviderBrightData:
+		return NewBrightDataSearcher()
+	case ProviderSerpAPI:
+		return NewSerpAPIProvider()
+	case ProviderDataForSEO:
+		return NewDataForSEOProvider()
+	case ProviderBaidu:
+		return NewBaiduProvider()
+	case ProviderBing:
+		return NewBingProvider()
+	case ProviderSearxNG:
+		return NewSearxNGProvider()
+	default:
+		return nil, fmt.Errorf("不支持的搜索提供者: %s", name)
+	}
+}
+
+// NewAnswerProvider 根据名称创建 AI 回答提供者
+func NewAnswerProvider(name string) (AnswerProvider, error) {
+	switch name {
+	case ProviderBrightData:
+		return NewBrightDataSERPProvider()
+	case ProviderSerpAPI:
+		return NewSerpAPIProvider()
+	case ProviderDataForSEO:
+		return NewDataForSEOProvider()
+	case ProviderBaidu:
+		return NewBaiduProvider()
+	case ProviderBing:
+		return NewBingProvider()
+	case ProviderSearxNG:
+		return NewSearxNGProvider()
+	default:
+		return nil, fmt.Errorf("不支持的 AI 回答提供者: %s", name)
+	}
+}
+
+// providerNamesFromEnv 读取逗号分隔的提供者列表，未设置时使用 fallback
+func providerNamesFromEnv(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NewSearcherFromEnv 按 GEO_SEARCH_PROVIDERS（逗号分隔，顺序即故障转移顺序，默认 brightdata）创建搜索器
+// 未配置凭证的提供者会被跳过，全部不可用时返回错误
+func NewSearcherFromEnv() (Searcher, error) {
+	names := providerNamesFromEnv("GEO_SEARCH_PROVIDERS", []string{ProviderBrightData})
+
+	providers := make([]Searcher, 0, len(names))
+	errs := make([]error, 0)
+	for _, name := range names {
+		p, err := NewSearcher(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		providers = append(providers, p)
+	}
+
+	// 只有一个提供者时同样包装，以便统一统计调用失败次数
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("没有可用的搜索提供者: %w", errors.Join(errs...))
+	}
+	return NewFailoverSearcher(providers...), nil
+}
+
+// NewAnswerProviderFromEnv 按 GEO_ANSWER_PROVIDERS 创建 AI 回答提供者（未设置时沿用 GEO_SEARCH_PROVIDERS）
+// 未配置凭证的提供者会被跳过，全部不可用时返回错误
+func NewAnswerProviderFromEnv() (AnswerProvider, error) {
+	names := providerNamesFromEnv("GEO_ANSWER_PROVIDERS",
+		providerNamesFromEnv("GEO_SEARCH_PROVIDERS", []string{ProviderBrightData}))
+
+	providers := make([]AnswerProvider, 0, len(names))
+	errs := make([]error, 0)
+	for _, name := range names {
+		p, err := NewAnswerProvider(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		providers = append(providers, p)
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("没有可用的 AI 回答提供者: %w", errors.Join(errs...))
+	}
+	return NewFailoverAnswerProvider(providers...), nil
+}
+
+// searchTool 将 Searcher 包装为 eino 工具
+type searchTool struct {
+	searcher Searcher
+}
+
+// NewSearchTool 将搜索提供者包装为 search_queries 工具
+func NewSearchTool(searcher Searcher) tool.InvokableTool {
+	return &searchTool{searcher: searcher}
+}
+
+// Info 返回搜索工具信息 (实现 tool.InvokableTool 接口)
+func (t *searchTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "search_queries",
+		Desc: "基于查询词搜索相关内容",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"query": {
+				Type: "string",
+				Desc: "搜索查询词",
+			},
+		}),
+	}, nil
+}
+
+// InvokableRun 执行搜索工具 (实现 tool.InvokableTool 接口)
+func (t *searchTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &req); err != nil {
+		return "", fmt.Errorf("解析请求失败: %w", err)
+	}
+
+	result, err := t.searcher.Search(ctx, req.Query)
+	if err != nil {
+		return "", fmt.Errorf("搜索失败: %w", err)
+	}
+	recordSERPFeatures(ctx, result.Features)
+
+	resp := struct {
+		OriginalQuery  string   `json:"original_query"`
+		RelatedQueries []string `json:"related_queries"`
+		PeopleAlsoAsk  []string `json:"people_also_ask,omitempty"`
+	}{
+		OriginalQuery:  result.OriginalQuery,
+		RelatedQueries: result.RelatedQueries,
+	}
+	if result.Features != nil {
+		resp.PeopleAlsoAsk = result.Features.PeopleAlsoAsk
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return "", fmt.Errorf("序列化响应失败: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// answerTool 将 AnswerProvider 包装为 eino 工具
+type answerTool struct {
+	provider AnswerProvider
+}
+
+// NewAnswerTool 将 AI 回答提供者包装为 get_ai_overview 工具
+func NewAnswerTool(provider AnswerProvider) tool.InvokableTool {
+	return &answerTool{provider: provider}
+}
+
+// Info 返回 AI Overview 工具信息 (实现 tool.InvokableTool 接口)
+func (t *answerTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_ai_overview",
+		Desc: "获取生成式搜索引擎的 AI 摘要内容",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"query": {
+				Type: "string",
+				Desc: "要查询的内容",
+			},
+		}),
+	}, nil
+}
+
+// InvokableRun 执行工具 (实现 tool.InvokableTool 接口)
+func (t *answerTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &req); err != nil {
+		return "", fmt.Errorf("解析请求失败: %w", err)
+	}
+
+	result, err := t.provider.GetAIOverview(ctx, req.Query)
+	if err != nil {
+		return "", fmt.Errorf("获取 AI Overview 失败: %w", err)
+	}
+	recordSERPFeatures(ctx, result.Features)
+
+	resp := struct {
+		Query   string   `json:"query"`
+		Summary string   `json:"summary"`
+		Sources []string `json:"sources"`
+	}{
+		Query:   result.Query,
+		Summary: result.Summary,
+		Sources: result.Sources,
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return "", fmt.Errorf("序列化响应失败: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// newProviderHTTPClient 创建提供者使用的 HTTP 客户端
+func newProviderHTTPClient() *http.Client {
+	return transport.NewClient(60 * time.Second)
+}
+
+// doJSONRequest 发送请求并将 JSON 响应解析到 out，非 2xx 状态码视为错误
+func doJSONRequest(client *http.Client, req *http.Request, provider string, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s API 请求失败: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s API 返回错误: %s - %s", provider, resp.Status, string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// buildFanout 按顺序合并多组相关查询（去重、去空白）为查询发散结果
+func buildFanout(query string, groups ...[]string) *models.QueryFanout {
+	related := make([]string, 0)
+	for _, group := range groups {
+		for _, q := range group {
+			if q = strings.TrimSpace(q); q != "" {
+				related = appendUnique(related, q)
+			}
+		}
+	}
+
+	return &models.QueryFanout{
+		OriginalQuery:  query,
+		RelatedQueries: related,
+		Timestamp:      time.Now(),
+	}
+}
+
+// resultURLs 提取搜索结果的 URL
+func resultURLs(results []models.SearchResult) []string {
+	urls := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.URL != "" {
+			urls = append(urls, r.URL)
+		}
+	}
+	return urls
+}
+
+// resultSnippet 使用第一个结果的摘要作为片段
+func resultSnippet(query string, results []models.SearchResult) string {
+	if len(results) > 0 && results[0].Snippet != "" {
+		return results[0].Snippet
+	}
+	return fmt.Sprintf("关于「%s」的搜索结果", query)
+}
+
+// fallbackOverview 在没有真实 AI 摘要时，用自然结果生成摘要（Found 为 false）
+func fallbackOverview(query string, results []models.SearchResult) *models.AIOverview {
+	return &models.AIOverview{
+		Query:   query,
+		Summary: generateSummaryFromResults(query, results),
+		Sources: resultURLs(results),
+		Snippet: resultSnippet(query, results),
+	}
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/pkg/cache"
+)
+
+// fakeSearcher 测试用搜索提供者
+type fakeSearcher struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (f *fakeSearcher) Name() string { return f.name }
+
+func (f *fakeSearcher) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return buildFanout(query, []string{f.name}), nil
+}
+
+// TestFailoverSearcher 测试故障转移顺序
+func TestFailoverSearcher(t *testing.T) {
+	first := &fakeSearcher{name: "first", err: errors.New("quota exceeded")}
+	second := &fakeSearcher{name: "second"}
+	third := &fakeSearcher{name: "third"}
+
+	result, err := NewFailoverSearcher(first, second, third).Search(context.Background(), "q")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.RelatedQueries, []string{"second"}) {
+		t.Errorf("RelatedQueries = %v, want [second]", result.RelatedQueries)
+	}
+	if third.calls != 0 {
+		t.Errorf("third.calls = %d, want 0", third.calls)
+	}
+
+	_, err = NewFailoverSearcher(first, &fakeSearcher{name: "down", err: errors.New("timeout")}).Search(context.Background(), "q")
+	if err == nil {
+		t.Fatal("Search() error = nil, want error when all providers fail")
+	}
+}
+
+// fakeAnswerProvider 测试用 AI 回答提供者
+type fakeAnswerProvider struct {
+	name  string
+	found bool
+	err   error
+	calls int
+}
+
+func (f *fakeAnswerProvider) Name() string { return f.name }
+
+func (f *fakeAnswerProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.AIOverview{Query: query, Summary: f.name, Found: f.found}, nil
+}
+
+// TestFailoverAnswerProvider 测试没有找到 AI 摘要时继续尝试下一个提供者
+func TestFailoverAnswerProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		providers   []*fakeAnswerProvider
+		wantSummary string
+		wantFound   bool
+		wantErr     bool
+		wantCalls   []int
+	}{
+		{name: "跳过未找到的提供者",
+			providers:   []*fakeAnswerProvider{{name: "bing"}, {name: "down", err: errors.New("timeout")}, {name: "serpapi", found: true}, {name: "third", found: true}},
+			wantSummary: "serpapi", wantFound: true, wantCalls: []int{1, 1, 1, 0}},
+		{name: "都没有找到时返回最后一个回退摘要",
+			providers:   []*fakeAnswerProvider{{name: "baidu"}, {name: "bing"}, {name: "down", err: errors.New("timeout")}},
+			wantSummary: "bing", wantCalls: []int{1, 1, 1}},
+		{name: "全部失败",
+			providers: []*fakeAnswerProvider{{name: "a", err: errors.New("quota exceeded")}, {name: "b", err: errors.New("timeout")}},
+			wantErr:   true, wantCalls: []int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]AnswerProvider, len(tt.providers))
+			for i, p := range tt.providers {
+				providers[i] = p
+			}
+			result, err := NewFailoverAnswerProvider(providers...).GetAIOverview(context.Background(), "q")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("GetAIOverview() error = nil, want error when all providers fail")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("GetAIOverview() error = %v", err)
+				}
+				if result.Summary != tt.wantSummary || result.Found != tt.wantFound {
+					t.Errorf("GetAIOverview() = %s (found %v), want %s (found %v)", result.Summary, result.Found, tt.wantSummary, tt.wantFound)
+				}
+			}
+			for i, p := range tt.providers {
+				if p.calls != tt.wantCalls[i] {
+					t.Errorf("%s.calls = %d, want %d", p.name, p.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSearxNGProvider 测试 SearxNG 响应解析
+func TestSearxNGProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"results": [{"title": "A", "url": "https://a.example.com/x", "content": "aaa"}],
+			"suggestions": ["coffee grinder", " coffee grinder ", "espresso"]
+		}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("SEARXNG_URL", srv.URL+"/")
+	p, err := NewSearxNGProvider()
+	if err != nil {
+		t.Fatalf("NewSearxNGProvider() error = %v", err)
+	}
+
+	fanout, err := p.Search(context.Background(), "coffee")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if want := []string{"coffee grinder", "espresso"}; !reflect.DeepEqual(fanout.RelatedQueries, want) {
+		t.Errorf("RelatedQueries = %v, want %v", fanout.RelatedQueries, want)
+	}
+
+	overview, err := p.GetAIOverview(context.Background(), "coffee")
+	if err != nil {
+		t.Fatalf("GetAIOverview() error = %v", err)
+	}
+	if overview.Found {
+		t.Error("Found = true, want false for SearxNG")
+	}
+	if want := []string{"https://a.example.com/x"}; !reflect.DeepEqual(overview.Sources, want) {
+		t.Errorf("Sources = %v, want %v", overview.Sources, want)
+	}
+}
+
+// TestBrightDataSearcher_Features 测试搜索参数透传和 SERP 模块解析
+func TestBrightDataSearcher_Features(t *testing.T) {
+	var target *url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URL string `json:"url"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		target, _ = url.Parse(body.URL)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"organic": [{"title": "Best grinders", "link": "https://a.example.com", "description": "aaa"}],
+			"related": [{"text": "burr grinder"}, {"text": "burr grinder"}],
+			"people_also_ask": [{"question": "Which grinder is best?"}],
+			"featured_snippets": [{"title": "Grinder guide", "description": "Burr grinders ...", "link": "https://b.example.com"}],
+			"knowledge": {"name": "Coffee grinder", "facts": [{"key": "Type", "value": [{"text": "Kitchen tool"}]}]}
+		}`))
+	}))
+	defer srv.Close()
+
+	s := &BrightDataSearcher{
+		config:     &BrightDataConfig{APIKey: "test", Zone: "serp", Endpoint: srv.URL},
+		httpClient: srv.Client(),
+	}
+	ctx := WithSearchOptions(context.Background(), models.SearchOptions{Country: "US", Language: "en", Device: "mobile", Page: 2})
+	ctx = WithSERPFeatureCollector(ctx)
+
+	fanout, err := s.Search(ctx, "coffee grinder")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	q := target.Query()
+	for key, want := range map[string]string{"gl": "us", "hl": "en", "start": "10", "brd_mobile": "1", "brd_json": "1"} {
+		if got := q.Get(key); got != want {
+			t.Errorf("url param %s = %q, want %q", key, got, want)
+		}
+	}
+
+	// 相关搜索不再用自然结果标题补齐
+	if want := []string{"burr grinder"}; !reflect.DeepEqual(fanout.RelatedQueries, want) {
+		t.Errorf("RelatedQueries = %v, want %v", fanout.RelatedQueries, want)
+	}
+	f := fanout.Features
+	if want := []string{"Which grinder is best?"}; !reflect.DeepEqual(f.PeopleAlsoAsk, want) {
+		t.Errorf("PeopleAlsoAsk = %v, want %v", f.PeopleAlsoAsk, want)
+	}
+	if f.FeaturedSnippet == nil || f.FeaturedSnippet.URL != "https://b.example.com" {
+		t.Errorf("FeaturedSnippet = %+v", f.FeaturedSnippet)
+	}
+	if f.KnowledgePanel == nil || f.KnowledgePanel.Facts["Type"] != "Kitchen tool" {
+		t.Errorf("KnowledgePanel = %+v", f.KnowledgePanel)
+	}
+
+	// 工具调用会把模块记录到上下文的收集器中
+	if _, err := NewSearchTool(s).InvokableRun(ctx, `{"query": "coffee grinder"}`); err != nil {
+		t.Fatalf("InvokableRun() error = %v", err)
+	}
+	if got := SERPFeaturesFromContext(ctx); got == nil || len(got.PeopleAlsoAsk) != 1 {
+		t.Errorf("SERPFeaturesFromContext() = %+v", got)
+	}
+}
+
+// TestCachedSearcher 测试查询规范化后命中缓存，且搜索参数不同的请求不共用缓存
+func TestCachedSearcher(t *testing.T) {
+	inner := &fakeSearcher{name: "fake"}
+	s := NewCachedSearcher(inner, cache.NewMemoryStore(10), time.Hour)
+
+	ctx := context.Background()
+	_, _ = s.Search(ctx, "Coffee  Grinder")
+	_, _ = s.Search(ctx, "coffee grinder")
+	if inner.calls != 1 {
+		t.Errorf("calls = %d, want 1", inner.calls)
+	}
+
+	_, _ = s.Search(WithSearchOptions(ctx, models.SearchOptions{Country: "de"}), "coffee grinder")
+	if inner.calls != 2 {
+		t.Errorf("calls = %d, want 2 for different country", inner.calls)
+	}
+}
+
+// TestCachedAnswerProvider 测试找到的 AI 摘要命中缓存，未找到的回退摘要不写入缓存
+func TestCachedAnswerProvider(t *testing.T) {
+	ctx := context.Background()
+
+	found := &fakeAnswerProvider{name: "found", found: true}
+	p := NewCachedAnswerProvider(found, cache.NewMemoryStore(10), time.Hour)
+	_, _ = p.GetAIOverview(ctx, "coffee grinder")
+	_, _ = p.GetAIOverview(ctx, "Coffee Grinder")
+	if found.calls != 1 {
+		t.Errorf("found.calls = %d, want 1", found.calls)
+	}
+
+	miss := &fakeAnswerProvider{name: "miss"}
+	p = NewCachedAnswerProvider(miss, cache.NewMemoryStore(10), time.Hour)
+	_, _ = p.GetAIOverview(ctx, "coffee grinder")
+	_, _ = p.GetAIOverview(ctx, "coffee grinder")
+	if miss.calls != 2 {
+		t.Errorf("miss.calls = %d, want 2", miss.calls)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/transport"
 )
 
 // WebScraper 网页爬取工具接口
@@ -28,9 +29,7 @@ type HTTPScraper struct {
 // NewHTTPScraper 创建新的 HTTP 爬取工具
 func NewHTTPScraper() *HTTPScraper {
 	return &HTTPScraper{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: transport.NewClient(30 * time.Second),
 	}
 }
 
@@ -152,3 +151,49 @@ func (s *HTTPScraper) InvokableRun(ctx context.Context, argumentsInJSON string,
 
 	return string(data), nil
 }
+
+// scrapeTool 将 WebScraper 包装为 eino 工具
+type scrapeTool struct {
+	scraper WebScraper
+}
+
+// NewScrapeTool 将网页爬取包装为 scrape_webpage 工具
+func NewScrapeTool(scraper WebScraper) tool.InvokableTool {
+	return &scrapeTool{scraper: scraper}
+}
+
+// Info 返回工具信息 (实现 tool.InvokableTool 接口)
+func (t *scrapeTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "scrape_webpage",
+		Desc: "爬取网页内容，提取标题和主要文本",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"url": {
+				Type: "string",
+				Desc: "要爬取的网页 URL",
+			},
+		}),
+	}, nil
+}
+
+// InvokableRun 执行工具 (实现 tool.InvokableTool 接口)
+func (t *scrapeTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &req); err != nil {
+		return "", fmt.Errorf("解析请求失败: %w", err)
+	}
+
+	result, err := t.scraper.Scrape(ctx, req.URL)
+	if err != nil {
+		return "", fmt.Errorf("爬取网页失败: %w", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("序列化响应失败: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// SearxNGConfig SearxNG 配置
+type SearxNGConfig struct {
+	BaseURL  string
+	Language string
+}
+
+// LoadSearxNGConfig 从环境变量加载 SearxNG 配置
+func LoadSearxNGConfig() (*SearxNGConfig, error) {
+	baseURL := strings.TrimRight(os.Getenv("SEARXNG_URL"), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("未设置 SEARXNG_URL 环境变量")
+	}
+
+	return &SearxNGConfig{
+		BaseURL:  baseURL,
+		Language: os.Getenv("SEARXNG_LANGUAGE"),
+	}, nil
+}
+
+// SearxNGProvider 使用本地 SearxNG 实例的实现（需在 settings.yml 中启用 json 格式）
+// SearxNG 不提供 AI 回答，GetAIOverview 返回基于自然结果生成的摘要（Found 为 false）
+type SearxNGProvider struct {
+	config     *SearxNGConfig
+	httpClient *http.Client
+}
+
+// NewSearxNGProvider 创建 SearxNG 提供者
+func NewSearxNGProvider() (*SearxNGProvider, error) {
+	config, err := LoadSearxNGConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearxNGProvider{
+		config:     config,
+		httpClient: newProviderHTTPClient(),
+	}, nil
+}
+
+// searxNGResponse SearxNG 响应结构
+type searxNGResponse struct {
+	Results []struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Content string `json:"content"`
+	} `json:"results"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// Name 返回提供者名称
+func (p *SearxNGProvider) Name() string {
+	return ProviderSearxNG
+}
+
+// search 调用 SearxNG 搜索
+func (p *SearxNGProvider) search(ctx context.Context, query string) (*searxNGResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	opts := SearchOptionsFromContext(ctx)
+	if opts.Language != "" {
+		params.Set("language", opts.Language)
+	} else if p.config.Language != "" {
+		params.Set("language", p.config.Language)
+	}
+	if opts.Page > 1 {
+		params.Set("pageno", strconv.Itoa(opts.Page))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.BaseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	var resp searxNGResponse
+	if err := doJSONRequest(p.httpClient, req, "SearxNG", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Search 执行搜索，使用搜索建议作为查询发散
+func (p *SearxNGProvider) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return buildFanout(query, resp.Suggestions), nil
+}
+
+// GetAIOverview 基于搜索结果生成摘要
+func (p *SearxNGProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, models.SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return fallbackOverview(query, results), nil
+}
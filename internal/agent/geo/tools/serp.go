@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// searchOptionsKey 是存储搜索参数的上下文键
+type searchOptionsKey struct{}
+
+// WithSearchOptions 将搜索参数（国家、语言、设备、页码）添加到上下文，各提供者据此构造请求
+func WithSearchOptions(ctx context.Context, opts models.SearchOptions) context.Context {
+	return context.WithValue(ctx, searchOptionsKey{}, normalizeSearchOptions(opts))
+}
+
+// SearchOptionsFromContext 从上下文获取搜索参数，未设置时返回零值
+func SearchOptionsFromContext(ctx context.Context) models.SearchOptions {
+	if opts, ok := ctx.Value(searchOptionsKey{}).(models.SearchOptions); ok {
+		return opts
+	}
+	return models.SearchOptions{}
+}
+
+// normalizeSearchOptions 规范化搜索参数
+func normalizeSearchOptions(opts models.SearchOptions) models.SearchOptions {
+	opts.Country = strings.ToLower(strings.TrimSpace(opts.Country))
+	opts.Language = strings.TrimSpace(opts.Language)
+	opts.Device = strings.ToLower(strings.TrimSpace(opts.Device))
+	if opts.Device != models.DeviceMobile {
+		opts.Device = ""
+	}
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	return opts
+}
+
+// resultOffset 页码对应的结果偏移量（每页 10 条）
+func resultOffset(opts models.SearchOptions) int {
+	if opts.Page < 1 {
+		return 0
+	}
+	return (opts.Page - 1) * 10
+}
+
+// SERPFeatureCollector 收集一次分析中各次 SERP 请求返回的结构化模块
+type SERPFeatureCollector struct {
+	mu       sync.Mutex
+	features models.SERPFeatures
+}
+
+// serpCollectorKey 是存储 SERP 模块收集器的上下文键
+type serpCollectorKey struct{}
+
+// WithSERPFeatureCollector 在上下文中放置新的 SERP 模块收集器
+func WithSERPFeatureCollector(ctx context.Context) context.Context {
+	return context.WithValue(ctx, serpCollectorKey{}, &SERPFeatureCollector{})
+}
+
+// SERPFeaturesFromContext 返回上下文收集器中已合并的 SERP 模块副本，没有收集器时返回 nil
+func SERPFeaturesFromContext(ctx context.Context) *models.SERPFeatures {
+	c, ok := ctx.Value(serpCollectorKey{}).(*SERPFeatureCollector)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	features := models.SERPFeatures{}
+	features.Merge(&c.features)
+	return &features
+}
+
+// recordSERPFeatures 将 SERP 模块合并到上下文的收集器中
+func recordSERPFeatures(ctx context.Context, features *models.SERPFeatures) {
+	c, ok := ctx.Value(serpCollectorKey{}).(*SERPFeatureCollector)
+	if !ok || features.IsEmpty() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.features.Merge(features)
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// SerpAPIConfig SerpAPI 配置
+type SerpAPIConfig struct {
+	APIKey   string
+	Endpoint string
+}
+
+// LoadSerpAPIConfig 从环境变量加载 SerpAPI 配置
+func LoadSerpAPIConfig() (*SerpAPIConfig, error) {
+	apiKey := os.Getenv("SERPAPI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("未设置 SERPAPI_API_KEY 环境变量")
+	}
+
+	endpoint := os.Getenv("SERPAPI_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://serpapi.com/search.json"
+	}
+
+	return &SerpAPIConfig{
+		APIKey:   apiKey,
+		Endpoint: endpoint,
+	}, nil
+}
+
+// SerpAPIProvider 使用 SerpAPI 的 Google 搜索和 AI Overview 实现
+type SerpAPIProvider struct {
+	config     *SerpAPIConfig
+	httpClient *http.Client
+}
+
+// NewSerpAPIProvider 创建 SerpAPI 提供者
+func NewSerpAPIProvider() (*SerpAPIProvider, error) {
+	config, err := LoadSerpAPIConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SerpAPIProvider{
+		config:     config,
+		httpClient: newProviderHTTPClient(),
+	}, nil
+}
+
+// serpAPITextBlock AI Overview 文本块（列表可嵌套）
+type serpAPITextBlock struct {
+	Type    string             `json:"type"`
+	Snippet string             `json:"snippet"`
+	List    []serpAPITextBlock `json:"list"`
+}
+
+// serpAPIAIOverview SerpAPI AI Overview 结构
+type serpAPIAIOverview struct {
+	TextBlocks []serpAPITextBlock `json:"text_blocks"`
+	References []struct {
+		Title string `json:"title"`
+		Link  string `json:"link"`
+	} `json:"references"`
+	PageToken string `json:"page_token"` // AI Overview 需要单独请求时返回
+}
+
+// serpAPIResponse SerpAPI 响应结构
+type serpAPIResponse struct {
+	Error          string `json:"error"`
+	OrganicResults []struct {
+		Title   string `json:"title"`
+		Link    string `json:"link"`
+		Snippet string `json:"snippet"`
+	} `json:"organic_results"`
+	RelatedSearches []struct {
+		Query string `json:"query"`
+	} `json:"related_searches"`
+	RelatedQuestions []struct {
+		Question string `json:"question"`
+	} `json:"related_questions"`
+	AnswerBox *struct {
+		Title   string `json:"title"`
+		Snippet string `json:"snippet"`
+		Answer  string `json:"answer"`
+		Link    string `json:"link"`
+	} `json:"answer_box"`
+	KnowledgeGraph *struct {
+		Title       string `json:"title"`
+		Type        string `json:"type"`
+		Description string `json:"description"`
+		Website     string `json:"website"`
+	} `json:"knowledge_graph"`
+	AIOverview *serpAPIAIOverview `json:"ai_overview"`
+}
+
+// features 提取相关搜索、相关问题、精选摘要和知识面板
+func (r *serpAPIResponse) features() *models.SERPFeatures {
+	related := make([]string, 0, len(r.RelatedSearches))
+	for _, item := range r.RelatedSearches {
+		related = append(related, item.Query)
+	}
+	questions := make([]string, 0, len(r.RelatedQuestions))
+	for _, item := range r.RelatedQuestions {
+		questions = append(questions, item.Question)
+	}
+
+	features := &models.SERPFeatures{
+		RelatedSearches: buildFanout("", related).RelatedQueries,
+		PeopleAlsoAsk:   buildFanout("", questions).RelatedQueries,
+	}
+	if r.AnswerBox != nil {
+		snippet := r.AnswerBox.Snippet
+		if snippet == "" {
+			snippet = r.AnswerBox.Answer
+		}
+		if snippet != "" {
+			features.FeaturedSnippet = &models.FeaturedSnippet{Title: r.AnswerBox.Title, Snippet: snippet, URL: r.AnswerBox.Link}
+		}
+	}
+	if r.KnowledgeGraph != nil && r.KnowledgeGraph.Title != "" {
+		features.KnowledgePanel = &models.KnowledgePanel{
+			Title:       r.KnowledgeGraph.Title,
+			Subtitle:    r.KnowledgeGraph.Type,
+			Description: r.KnowledgeGraph.Description,
+			URL:         r.KnowledgeGraph.Website,
+		}
+	}
+	return features
+}
+
+// Name 返回提供者名称
+func (p *SerpAPIProvider) Name() string {
+	return ProviderSerpAPI
+}
+
+// request 调用 SerpAPI
+func (p *SerpAPIProvider) request(ctx context.Context, params url.Values) (*serpAPIResponse, error) {
+	params.Set("api_key", p.config.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	var resp serpAPIResponse
+	if err := doJSONRequest(p.httpClient, req, "SerpAPI", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("SerpAPI 返回错误: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// search 执行 Google 搜索
+func (p *SerpAPIProvider) search(ctx context.Context, query string) (*serpAPIResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("搜索查询不能为空")
+	}
+
+	params := url.Values{}
+	params.Set("engine", "google")
+	params.Set("q", query)
+
+	opts := SearchOptionsFromContext(ctx)
+	if opts.Country != "" {
+		params.Set("gl", opts.Country)
+	}
+	if opts.Language != "" {
+		params.Set("hl", opts.Language)
+	}
+	if opts.Device != "" {
+		params.Set("device", opts.Device)
+	}
+	if offset := resultOffset(opts); offset > 0 {
+		params.Set("start", strconv.Itoa(offset))
+	}
+	return p.request(ctx, params)
+}
+
+// Search 执行搜索，获取查询发散
+func (p *SerpAPIProvider) Search(ctx context.Context, query string) (*models.QueryFanout, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	features := resp.features()
+	fanout := buildFanout(query, features.RelatedSearches)
+	fanout.Features = features
+	return fanout, nil
+}
+
+// GetAIOverview 获取 AI Overview，搜索结果只返回 page_token 时再单独请求一次
+func (p *SerpAPIProvider) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	resp, err := p.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0, len(resp.OrganicResults))
+	for _, r := range resp.OrganicResults {
+		results = append(results, models.SearchResult{Title: r.Title, URL: r.Link, Snippet: r.Snippet})
+	}
+
+	overview := resp.AIOverview
+	if overview != nil && len(overview.TextBlocks) == 0 && overview.PageToken != "" {
+		params := url.Values{}
+		params.Set("engine", "google_ai_overview")
+		params.Set("page_token", overview.PageToken)
+		detail, err := p.request(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("获取 AI Overview 详情失败: %w", err)
+		}
+		overview = detail.AIOverview
+	}
+
+	if overview == nil || len(overview.TextBlocks) == 0 {
+		fallback := fallbackOverview(query, results)
+		fallback.Features = resp.features()
+		return fallback, nil
+	}
+
+	var sb strings.Builder
+	writeSerpAPITextBlocks(&sb, overview.TextBlocks, 0)
+
+	sources := make([]string, 0, len(overview.References))
+	for _, ref := range overview.References {
+		if ref.Link != "" {
+			sources = append(sources, ref.Link)
+		}
+	}
+
+	return &models.AIOverview{
+		Query:    query,
+		Summary:  strings.TrimSpace(sb.String()),
+		Sources:  sources,
+		Snippet:  resultSnippet(query, results),
+		Found:    true,
+		Features: resp.features(),
+	}, nil
+}
+
+// writeSerpAPITextBlocks 将 AI Overview 文本块展开为 Markdown 文本
+func writeSerpAPITextBlocks(sb *strings.Builder, blocks []serpAPITextBlock, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, b := range blocks {
+		if b.Snippet != "" {
+			if depth > 0 || b.Type == "list" {
+				sb.WriteString(indent + "- ")
+			}
+			sb.WriteString(b.Snippet)
+			sb.WriteString("\n")
+		}
+		if len(b.List) > 0 {
+			writeSerpAPITextBlocks(sb, b.List, depth+1)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// 内容形式常量
+const (
+	FormatTable = "table"
+	FormatList  = "list"
+	FormatSteps = "steps"
+	FormatFAQ   = "faq"
+	FormatCode  = "code"
+	FormatImage = "image"
+)
+
+var (
+	orderedItemRe = regexp.MustCompile(`^\d+[.)、]\s+`)
+	imageRe       = regexp.MustCompile(`!\[[^\]]*\]\([^)]+\)`)
+)
+
+// ExtractPageStructure 从 Markdown 网页内容中提取结构信息（标题、小标题、内容形式、字数、节选）
+func ExtractPageStructure(pageURL, content string, excerptLen int) *models.CitedPage {
+	page := &models.CitedPage{URL: pageURL}
+
+	var (
+		hasTable, hasList, hasSteps, hasFAQ, hasCode bool
+		inCode                                       bool
+		body                                         strings.Builder
+	)
+
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+
+		if strings.HasPrefix(line, "```") {
+			hasCode = true
+			inCode = !inCode
+			continue
+		}
+		if inCode || line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "# "):
+			if page.Title == "" {
+				page.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			}
+			continue
+		case strings.HasPrefix(line, "## "), strings.HasPrefix(line, "### "):
+			heading := strings.TrimSpace(strings.TrimLeft(line, "# "))
+			page.Headings = append(page.Headings, heading)
+			if strings.HasSuffix(heading, "?") || strings.HasSuffix(heading, "？") {
+				hasFAQ = true
+			}
+			continue
+		case strings.HasPrefix(line, "|"):
+			hasTable = true
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "):
+			hasList = true
+		case orderedItemRe.MatchString(line):
+			hasSteps = true
+		}
+
+		if imageRe.MatchString(line) {
+			page.Formats = appendUnique(page.Formats, FormatImage)
+			line = imageRe.ReplaceAllString(line, "")
+		}
+
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+
+	if hasTable {
+		page.Formats = appendUnique(page.Formats, FormatTable)
+	}
+	if hasList {
+		page.Formats = appendUnique(page.Formats, FormatList)
+	}
+	if hasSteps {
+		page.Formats = appendUnique(page.Formats, FormatSteps)
+	}
+	if hasFAQ {
+		page.Formats = appendUnique(page.Formats, FormatFAQ)
+	}
+	if hasCode {
+		page.Formats = appendUnique(page.Formats, FormatCode)
+	}
+
+	text := body.String()
+	page.WordCount = countWords(text)
+	page.Excerpt = truncateRunes(strings.TrimSpace(text), excerptLen)
+
+	return page
+}
+
+// countWords 统计字数：CJK 字符按字计，其他按空白分词计
+func countWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// truncateRunes 按字符数截断文本
+func truncateRunes(text string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:n]) + "..."
+}
+
+// appendUnique 追加不重复的元素
+func appendUnique(list []string, item string) []string {
+	for _, v := range list {
+		if v == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
+
+// Domain 返回 URL 的主机名（去掉 www. 前缀），解析失败返回空字符串
+func Domain(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// IsHTTPURL 判断字符串是否为 http(s) URL
+func IsHTTPURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
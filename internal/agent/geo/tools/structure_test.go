@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExtractPageStructure 测试网页结构提取
+func TestExtractPageStructure(t *testing.T) {
+	content := `# 咖啡机选购指南
+
+## 如何选择咖啡机？
+
+- 预算
+- 容量
+
+1. 确定需求
+2. 对比参数
+
+| 型号 | 价格 |
+|------|------|
+| A    | 999  |
+
+![示意图](https://example.com/a.png)
+
+` + "```go\nfmt.Println(\"ignored\")\n```"
+
+	page := ExtractPageStructure("https://example.com/guide", content, 0)
+
+	if page.Title != "咖啡机选购指南" {
+		t.Errorf("Title = %q, want %q", page.Title, "咖啡机选购指南")
+	}
+
+	wantHeadings := []string{"如何选择咖啡机？"}
+	if !reflect.DeepEqual(page.Headings, wantHeadings) {
+		t.Errorf("Headings = %v, want %v", page.Headings, wantHeadings)
+	}
+
+	wantFormats := []string{FormatImage, FormatTable, FormatList, FormatSteps, FormatFAQ, FormatCode}
+	if !reflect.DeepEqual(page.Formats, wantFormats) {
+		t.Errorf("Formats = %v, want %v", page.Formats, wantFormats)
+	}
+
+	if page.WordCount == 0 {
+		t.Errorf("WordCount = 0, want > 0")
+	}
+}
+
+// TestCountWords 测试中英文混合字数统计
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "英文", input: "hello world", expected: 2},
+		{name: "中文", input: "你好世界", expected: 4},
+		{name: "混合", input: "GEO 优化 2025", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countWords(tt.input); got != tt.expected {
+				t.Errorf("countWords(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDomain 测试域名提取
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://www.Example.com/path?q=1", "example.com"},
+		{"http://blog.example.com", "blog.example.com"},
+		{"来源1", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Domain(tt.input); got != tt.expected {
+			t.Errorf("Domain(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
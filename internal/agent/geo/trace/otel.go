@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Trace - 将节点执行导出为 OpenTelemetry span，并统计 Agent 耗时
+ */
+
+package trace
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/solariswu/peanut/internal/pkg/telemetry"
+)
+
+// otelSpanKey 是当前 OTel 节点的上下文键
+type otelSpanKey struct{}
+
+// otelNode 一次节点执行对应的 OTel span
+type otelNode struct {
+	span  oteltrace.Span
+	start time.Time
+	agent string
+	depth int // 0 为顶层 Graph，1 为各 Agent 子图
+}
+
+// NewOTelHandler 创建将每个节点执行导出为 OTel span 的回调处理器，
+// 同时统计各 Agent 子图的执行耗时（peanut_geo_agent_duration_seconds）
+// 通过 callbacks.AppendGlobalHandlers 注册后对所有 Graph 生效
+func NewOTelHandler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			return startOTelSpan(ctx, info)
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			node, ok := ctx.Value(otelSpanKey{}).(*otelNode)
+			if !ok {
+				return ctx
+			}
+			if info.Component == components.ComponentOfChatModel {
+				if out := model.ConvCallbackOutput(output); out != nil && out.TokenUsage != nil {
+					node.span.SetAttributes(
+						attribute.Int("gen_ai.usage.input_tokens", out.TokenUsage.PromptTokens),
+						attribute.Int("gen_ai.usage.output_tokens", out.TokenUsage.CompletionTokens),
+					)
+				}
+			}
+			endOTelSpan(node, nil)
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if node, ok := ctx.Value(otelSpanKey{}).(*otelNode); ok {
+				endOTelSpan(node, err)
+			}
+			return ctx
+		}).
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			input.Close()
+			return startOTelSpan(ctx, info)
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			node, ok := ctx.Value(otelSpanKey{}).(*otelNode)
+			if !ok {
+				output.Close()
+				return ctx
+			}
+			// 流结束时节点才算执行完成
+			go func() {
+				drain(output)
+				endOTelSpan(node, nil)
+			}()
+			return ctx
+		}).
+		Build()
+}
+
+// startOTelSpan 为节点创建子 span
+func startOTelSpan(ctx context.Context, info *callbacks.RunInfo) context.Context {
+	node := &otelNode{start: time.Now()}
+	if parent, ok := ctx.Value(otelSpanKey{}).(*otelNode); ok {
+		node.depth = parent.depth + 1
+		node.agent = parent.agent
+		if node.depth == 1 {
+			node.agent = info.Name
+		}
+	}
+
+	name := info.Name
+	if name == "" {
+		name = string(info.Component)
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("eino.component", string(info.Component)),
+		attribute.String("eino.type", info.Type),
+	}
+	if node.agent != "" {
+		attrs = append(attrs, attribute.String("geo.agent", node.agent))
+	}
+
+	ctx, node.span = telemetry.Tracer().Start(ctx, name, oteltrace.WithAttributes(attrs...))
+	return context.WithValue(ctx, otelSpanKey{}, node)
+}
+
+// endOTelSpan 结束 span，Agent 子图同时记录耗时
+func endOTelSpan(node *otelNode, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+		node.span.RecordError(err)
+		node.span.SetStatus(codes.Error, err.Error())
+	}
+	node.span.End()
+
+	if node.depth == 1 && node.agent != "" {
+		telemetry.AgentDuration.WithLabelValues(node.agent, status).Observe(time.Since(node.start).Seconds())
+	}
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Trace - 通过 eino callbacks 记录每个节点的执行过程
+ */
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+)
+
+// MaxFieldLength 单个字段（输入、输出、状态快照）保存的最大字节数
+const MaxFieldLength = 64 * 1024
+
+// routerNodeName 各 Agent 子图中解析模型输出并更新 FlowState 的节点
+const routerNodeName = "router"
+
+// tracerKey 是追踪器的上下文键
+type tracerKey struct{}
+
+// spanKey 是当前 span 的上下文键，子节点据此确定父 span
+type spanKey struct{}
+
+// WithTracer 在上下文中设置追踪器
+func WithTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// FromContext 从上下文获取追踪器
+func FromContext(ctx context.Context) *Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(*Tracer)
+	return tracer
+}
+
+// Tracer 收集一次运行中所有节点的执行记录，并发安全
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*models.TraceSpan
+}
+
+// NewTracer 创建追踪器
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Spans 按开始顺序返回已记录的 span
+func (t *Tracer) Spans() []models.TraceSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]models.TraceSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		spans = append(spans, *s)
+	}
+	return spans
+}
+
+// Handler 返回 eino 回调处理器，通过 compose.WithCallbacks 传给 Graph
+func (t *Tracer) Handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			span := t.start(ctx, info)
+			t.update(span, func(s *models.TraceSpan) {
+				s.Input = formatInput(info, input)
+			})
+			return context.WithValue(ctx, spanKey{}, span)
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			span := spanFromContext(ctx)
+			if span == nil {
+				return ctx
+			}
+			state := ""
+			if info.Name == routerNodeName {
+				state = snapshotState(ctx)
+			}
+			t.update(span, func(s *models.TraceSpan) {
+				s.Output = formatOutput(info, output)
+				s.State = state
+			})
+			t.finish(span)
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			span := spanFromContext(ctx)
+			if span == nil {
+				return ctx
+			}
+			t.update(span, func(s *models.TraceSpan) {
+				s.Error = truncate(err.Error())
+			})
+			t.finish(span)
+			return ctx
+		}).
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			span := t.start(ctx, info)
+			go func() {
+				chunks := drain(input)
+				t.update(span, func(s *models.TraceSpan) {
+					s.Input = formatChunks(chunks, func(c callbacks.CallbackInput) string { return formatInput(info, c) })
+				})
+			}()
+			return context.WithValue(ctx, spanKey{}, span)
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			span := spanFromContext(ctx)
+			if span == nil {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				chunks := drain(output)
+				t.update(span, func(s *models.TraceSpan) {
+					s.Output = formatStreamOutput(info, chunks)
+				})
+				t.finish(span)
+			}()
+			return ctx
+		}).
+		Build()
+}
+
+// start 创建 span，父 span 和所属 Agent 从上下文继承
+func (t *Tracer) start(ctx context.Context, info *callbacks.RunInfo) *models.TraceSpan {
+	span := &models.TraceSpan{
+		Name:      info.Name,
+		Type:      info.Type,
+		Component: string(info.Component),
+		StartedAt: time.Now(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span.ID = len(t.spans) + 1
+	if parent := spanFromContext(ctx); parent != nil {
+		span.ParentID = parent.ID
+		// 顶层 Graph 的直接子节点是各 Agent 子图
+		span.Agent = parent.Agent
+		if span.Agent == "" && parent.ParentID == 0 {
+			span.Agent = info.Name
+		}
+	}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// update 在锁内修改 span
+func (t *Tracer) update(span *models.TraceSpan, fn func(s *models.TraceSpan)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(span)
+}
+
+// finish 记录 span 耗时
+func (t *Tracer) finish(span *models.TraceSpan) {
+	t.update(span, func(s *models.TraceSpan) {
+		s.DurationMs = time.Since(s.StartedAt).Milliseconds()
+	})
+}
+
+// spanFromContext 获取上下文中的当前 span
+func spanFromContext(ctx context.Context) *models.TraceSpan {
+	span, _ := ctx.Value(spanKey{}).(*models.TraceSpan)
+	return span
+}
+
+// snapshotState 序列化 router 执行后的 FlowState（不含网页正文）
+func snapshotState(ctx context.Context) string {
+	var snapshot string
+	_ = compose.ProcessState[*models.FlowState](ctx, func(_ context.Context, state *models.FlowState) error {
+		copied := *state
+		copied.Content = ""
+		snapshot = toJSON(&copied)
+		return nil
+	})
+	return snapshot
+}
+
+// formatInput 将回调输入转换为可读文本，ChatModel 为渲染后的 prompt，Tool 为调用参数
+func formatInput(info *callbacks.RunInfo, input callbacks.CallbackInput) string {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if in := model.ConvCallbackInput(input); in != nil {
+			return formatMessages(in.Messages)
+		}
+	case components.ComponentOfTool:
+		if in := tool.ConvCallbackInput(input); in != nil {
+			return truncate(in.ArgumentsInJSON)
+		}
+	}
+	return formatValue(input)
+}
+
+// formatOutput 将回调输出转换为可读文本，ChatModel 为原始输出（含工具调用），Tool 为调用结果
+func formatOutput(info *callbacks.RunInfo, output callbacks.CallbackOutput) string {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if out := model.ConvCallbackOutput(output); out != nil && out.Message != nil {
+			return formatMessages([]*schema.Message{out.Message})
+		}
+	case components.ComponentOfTool:
+		if out := tool.ConvCallbackOutput(output); out != nil {
+			return truncate(out.Response)
+		}
+	}
+	return formatValue(output)
+}
+
+// formatStreamOutput 合并流式输出，ChatModel 的消息片段拼接为完整消息
+func formatStreamOutput(info *callbacks.RunInfo, chunks []callbacks.CallbackOutput) string {
+	if info.Component == components.ComponentOfChatModel {
+		messages := make([]*schema.Message, 0, len(chunks))
+		for _, c := range chunks {
+			if out := model.ConvCallbackOutput(c); out != nil && out.Message != nil {
+				messages = append(messages, out.Message)
+			}
+		}
+		if msg, err := schema.ConcatMessages(messages); err == nil {
+			return formatMessages([]*schema.Message{msg})
+		}
+	}
+	return formatChunks(chunks, func(c callbacks.CallbackOutput) string { return formatOutput(info, c) })
+}
+
+// formatMessages 按角色输出消息内容和工具调用
+func formatMessages(messages []*schema.Message) string {
+	var sb strings.Builder
+	for i, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "[%s]\n%s", msg.Role, msg.Content)
+		for _, call := range msg.ToolCalls {
+			fmt.Fprintf(&sb, "\n-> %s(%s)", call.Function.Name, call.Function.Arguments)
+		}
+	}
+	return truncate(sb.String())
+}
+
+// formatValue 序列化任意值，字符串原样保留
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return truncate(val)
+	case *schema.Message:
+		return formatMessages([]*schema.Message{val})
+	case []*schema.Message:
+		return formatMessages(val)
+	}
+	return toJSON(v)
+}
+
+// formatChunks 按行拼接流式片段
+func formatChunks[T any](chunks []T, format func(T) string) string {
+	parts := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		parts = append(parts, format(c))
+	}
+	return truncate(strings.Join(parts, "\n"))
+}
+
+// drain 读取并关闭回调流
+func drain[T any](sr *schema.StreamReader[T]) []T {
+	defer sr.Close()
+
+	var chunks []T
+	for {
+		chunk, err := sr.Recv()
+		if err != nil {
+			return chunks
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+// toJSON 序列化为 JSON，失败时使用 %v 格式
+func toJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return truncate(fmt.Sprintf("%v", v))
+	}
+	return truncate(string(data))
+}
+
+// truncate 截断超长字段，保证 UTF-8 完整
+func truncate(s string) string {
+	if len(s) <= MaxFieldLength {
+		return s
+	}
+	cut := MaxFieldLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…(已截断)"
+}
@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode 录制器工作模式
+type Mode string
+
+const (
+	// ModeRecord 转发请求并把请求和响应写入 fixture 文件
+	ModeRecord Mode = "record"
+	// ModeReplay 只从 fixture 文件返回响应，不访问网络
+	ModeReplay Mode = "replay"
+)
+
+// secretParams 不参与请求匹配、也不写入 fixture 的查询参数
+var secretParams = []string{"api_key", "key", "token", "access_token"}
+
+// Fixture 一次 HTTP 交互的录制结果
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest 录制的请求（不含认证头）
+type FixtureRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// FixtureResponse 录制的响应
+type FixtureResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Recorder 录制/回放 HTTP 交互的 RoundTripper
+// 请求按方法、主机、路径、查询参数和规范化后的请求体匹配，认证信息不参与匹配
+type Recorder struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+
+	// passthrough 中主机的请求直接转发，不录制也不回放
+	passthrough map[string]bool
+
+	mu     sync.Mutex
+	misses []string
+}
+
+// NewRecorder 创建录制器，next 为录制模式下实际发送请求的 RoundTripper
+func NewRecorder(mode Mode, dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{mode: mode, dir: dir, next: next}
+}
+
+// Passthrough 设置直接转发的主机（如评测时实时调用 LLM、回放搜索结果），返回录制器本身
+func (r *Recorder) Passthrough(hosts ...string) *Recorder {
+	if r.passthrough == nil {
+		r.passthrough = make(map[string]bool, len(hosts))
+	}
+	for _, host := range hosts {
+		r.passthrough[strings.ToLower(host)] = true
+	}
+	return r
+}
+
+// Misses 返回回放模式下没有找到 fixture 的请求
+func (r *Recorder) Misses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.misses...)
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.passthrough[strings.ToLower(req.URL.Hostname())] {
+		return r.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取请求体失败: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	fixtureReq := FixtureRequest{
+		Method: req.Method,
+		URL:    redactURL(req),
+		Body:   canonicalBody(body),
+	}
+	path := filepath.Join(r.dir, fixtureName(req, fixtureReq))
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			r.mu.Lock()
+			r.misses = append(r.misses, fixtureReq.Method+" "+fixtureReq.URL)
+			r.mu.Unlock()
+			return nil, fmt.Errorf("没有找到回放 fixture %s: %s %s", filepath.Base(path), fixtureReq.Method, fixtureReq.URL)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("解析 fixture %s 失败: %w", path, err)
+		}
+		return fixture.Response.toHTTP(req), nil
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if r.mode == ModeRecord {
+		fixture := Fixture{
+			Request: fixtureReq,
+			Response: FixtureResponse{
+				StatusCode:  resp.StatusCode,
+				ContentType: resp.Header.Get("Content-Type"),
+				Body:        string(respBody),
+			},
+		}
+		if err := writeFixture(path, &fixture); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// toHTTP 转换为 HTTP 响应
+func (f FixtureResponse) toHTTP(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+	return &http.Response{
+		StatusCode:    f.StatusCode,
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
+
+// writeFixture 写入 fixture 文件
+func writeFixture(path string, fixture *Fixture) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建 fixture 目录失败: %w", err)
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 fixture 失败: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// redactURL 去掉认证类查询参数并按键排序
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	for _, p := range secretParams {
+		q.Del(p)
+	}
+	u.RawQuery = q.Encode()
+	u.User = nil
+	return u.String()
+}
+
+// canonicalBody 将 JSON 请求体规范化（键排序、去除多余空白），非 JSON 原样以字符串保存
+func canonicalBody(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if data, err := json.Marshal(v); err == nil {
+			return data
+		}
+	}
+	data, _ := json.Marshal(string(body))
+	return data
+}
+
+// fixtureName 以主机名和请求摘要命名 fixture 文件
+func fixtureName(req *http.Request, fr FixtureRequest) string {
+	h := sha256.New()
+	h.Write([]byte(fr.Method + "\n" + fr.URL + "\n"))
+	h.Write(fr.Body)
+	sum := hex.EncodeToString(h.Sum(nil))[:16]
+
+	host := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(req.URL.Hostname()))
+	return host + "-" + sum + ".json"
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestRecorder_RecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+		}, nil
+	})
+
+	do := func(rt http.RoundTripper, apiKey, body string) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/search?q=go&api_key="+apiKey, strings.NewReader(body))
+		return rt.RoundTrip(req)
+	}
+
+	if _, err := do(NewRecorder(ModeRecord, dir, upstream), "secret", `{"b":1, "a":2}`); err != nil {
+		t.Fatalf("record error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("fixtures = %v, want 1", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("fixture contains api key: %s", data)
+	}
+
+	// 回放时认证参数和 JSON 键顺序不影响匹配
+	replay := NewRecorder(ModeReplay, dir, upstream)
+	resp, err := do(replay, "other", `{"a":2,"b":1}`)
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != `{"ok":true}` || resp.StatusCode != http.StatusOK {
+		t.Errorf("replay response = %d %s", resp.StatusCode, got)
+	}
+	if calls != 1 {
+		t.Errorf("upstream calls = %d, want 1", calls)
+	}
+
+	if _, err := do(replay, "other", `{"a":3}`); err == nil {
+		t.Error("replay of unknown request: want error")
+	}
+	if misses := replay.Misses(); len(misses) != 1 {
+		t.Errorf("Misses() = %v, want 1", misses)
+	}
+}
+
+func TestRecorder_Passthrough(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("live"))}, nil
+	})
+
+	// 直接转发的主机在回放模式下也访问上游，且不写入 fixture
+	rec := NewRecorder(ModeReplay, dir, upstream).Passthrough("LLM.example.com")
+	req, _ := http.NewRequest(http.MethodPost, "https://llm.example.com/chat", strings.NewReader(`{}`))
+	if _, err := rec.RoundTrip(req); err != nil || calls != 1 {
+		t.Fatalf("passthrough RoundTrip() error = %v, calls = %d", err, calls)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://serp.example.com/search", nil)
+	if _, err := rec.RoundTrip(req); err == nil || calls != 1 {
+		t.Errorf("replay RoundTrip() error = %v, calls = %d, want fixture miss", err, calls)
+	}
+
+	rec = NewRecorder(ModeRecord, dir, upstream).Passthrough("llm.example.com")
+	req, _ = http.NewRequest(http.MethodPost, "https://llm.example.com/chat", strings.NewReader(`{}`))
+	if _, err := rec.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 0 {
+		t.Errorf("passthrough request recorded: %v", files)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 Peanut Authors
+ *
+ * GEO Transport - 外部 HTTP 调用的统一客户端与录制/回放
+ */
+
+package transport
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+var (
+	mu               sync.RWMutex
+	defaultTransport http.RoundTripper = http.DefaultTransport
+)
+
+// SetDefault 设置 GEO 外部调用（LLM、搜索、爬取）使用的 RoundTripper，返回恢复函数
+// 需在创建 GEO 服务之前调用，已创建的客户端不受影响
+func SetDefault(rt http.RoundTripper) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	prev := defaultTransport
+	defaultTransport = rt
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		defaultTransport = prev
+	}
+}
+
+// Default 返回当前的默认 RoundTripper
+func Default() http.RoundTripper {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultTransport
+}
+
+// NewClient 创建使用默认 RoundTripper 的 HTTP 客户端，timeout 为 0 表示不超时
+// 每次请求都会创建 OpenTelemetry 客户端 span
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.NewTransport(Default()),
+	}
+}
+
+// RecorderFromEnv 根据 GEO_HTTP_MODE（record、replay）和 GEO_HTTP_FIXTURES（默认 testdata/fixtures）创建录制器
+// 未设置 GEO_HTTP_MODE 时返回 nil
+func RecorderFromEnv() *Recorder {
+	mode := Mode(os.Getenv("GEO_HTTP_MODE"))
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil
+	}
+
+	dir := os.Getenv("GEO_HTTP_FIXTURES")
+	if dir == "" {
+		dir = "testdata/fixtures"
+	}
+	return NewRecorder(mode, dir, http.DefaultTransport)
+}
@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -22,8 +23,17 @@ type ServerConfig struct {
 	Mode string `mapstructure:"mode"`
 }
 
-// DatabaseConfig PostgreSQL 数据库配置
+// 数据库类型
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// DatabaseConfig 数据库配置：SQLite 使用 path，PostgreSQL 使用 dsn 或 host 等连接参数
 type DatabaseConfig struct {
+	Driver          string        `mapstructure:"driver"` // sqlite（默认）或 postgres
+	Path            string        `mapstructure:"path"`   // SQLite 数据库文件
+	URL             string        `mapstructure:"dsn"`    // PostgreSQL 连接串，设置后忽略 host 等参数
 	Host            string        `mapstructure:"host"`
 	Port            int           `mapstructure:"port"`
 	User            string        `mapstructure:"user"`
@@ -38,6 +48,9 @@ type DatabaseConfig struct {
 
 // DSN 返回 PostgreSQL 连接字符串
 func (c *DatabaseConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode,
@@ -71,8 +84,10 @@ func Load(configPath string) (*Config, error) {
 	v.SetConfigFile(configPath)
 	v.SetConfigType("yaml")
 
-	// 允许环境变量覆盖
+	// 允许环境变量覆盖，如 DATABASE_DRIVER 覆盖 database.driver
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	setDefaults(v)
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
@@ -86,6 +101,17 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// setDefaults 设置默认值，未在配置文件中出现的键也可以由环境变量覆盖
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("database.driver", DriverSQLite)
+	v.SetDefault("database.path", "peanut.db")
+	v.SetDefault("database.dsn", "")
+	v.SetDefault("database.max_conns", 10)
+	v.SetDefault("database.min_conns", 2)
+	v.SetDefault("database.max_conn_lifetime", time.Hour)
+	v.SetDefault("database.max_conn_idle_time", 30*time.Minute)
+}
+
 // LoadDefault 加载默认配置（用于开发环境）
 func LoadDefault() (*Config, error) {
 	return Load("configs/config.yaml")
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// BrandHandler 品牌提及分析处理器
+type BrandHandler struct {
+	service *service.BrandService
+}
+
+// NewBrandHandler 创建处理器
+func NewBrandHandler(service *service.BrandService) *BrandHandler {
+	return &BrandHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *BrandHandler) RegisterRoutes(r *gin.RouterGroup) {
+	brands := r.Group("/geo/brands")
+	{
+		brands.POST("", h.Create)
+		brands.GET("", h.List)
+		brands.GET("/dashboard", h.Dashboard)
+		brands.GET("/:id", h.GetByID)
+		brands.PUT("/:id", h.Update)
+		brands.DELETE("/:id", h.Delete)
+	}
+
+	analysis := r.Group("/geo/analysis")
+	{
+		analysis.GET("/:id/brands", h.GetAnalysisBrands)
+		analysis.POST("/:id/brands/reanalyze", h.Reanalyze)
+	}
+}
+
+// Create 创建品牌
+// @Summary 创建品牌
+// @Description 创建品牌词典（品牌名、别名、产品名及已知事实）
+// @Tags 品牌提及
+// @Accept json
+// @Produce json
+// @Param request body model.BrandCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.BrandResponse}
+// @Router /api/v1/geo/brands [post]
+func (h *BrandHandler) Create(c *gin.Context) {
+	var req model.BrandCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// TODO: 从 JWT 获取 userID
+	var userID *int64
+
+	brand, err := h.service.Create(c.Request.Context(), &req, userID)
+	if err != nil {
+		response.ServerError(c, "创建品牌失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(brand))
+}
+
+// List 查询品牌列表
+// @Summary 获取品牌列表
+// @Tags 品牌提及
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.BrandResponse}
+// @Router /api/v1/geo/brands [get]
+func (h *BrandHandler) List(c *gin.Context) {
+	// TODO: 从 JWT 获取 userID
+	list, err := h.service.List(c.Request.Context(), nil)
+	if err != nil {
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// GetByID 获取品牌详情
+// @Summary 获取品牌详情
+// @Tags 品牌提及
+// @Produce json
+// @Param id path int true "品牌 ID"
+// @Success 200 {object} response.Response{data=model.BrandResponse}
+// @Router /api/v1/geo/brands/{id} [get]
+func (h *BrandHandler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	brand, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "获取品牌失败")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(brand))
+}
+
+// Update 更新品牌
+// @Summary 更新品牌
+// @Description 数组字段传入时整体替换
+// @Tags 品牌提及
+// @Accept json
+// @Produce json
+// @Param id path int true "品牌 ID"
+// @Param request body model.BrandUpdateRequest true "更新请求"
+// @Success 200 {object} response.Response{data=model.BrandResponse}
+// @Router /api/v1/geo/brands/{id} [put]
+func (h *BrandHandler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.BrandUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	brand, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "更新品牌失败")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(brand))
+}
+
+// Delete 删除品牌
+// @Summary 删除品牌
+// @Description 删除品牌及其全部提及记录
+// @Tags 品牌提及
+// @Produce json
+// @Param id path int true "品牌 ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/geo/brands/{id} [delete]
+func (h *BrandHandler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "删除失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Dashboard 品牌看板
+// @Summary 获取品牌看板
+// @Description 统计各品牌在 AI 回答中的提及率、情感分布、命中词和最近的错误说法
+// @Tags 品牌提及
+// @Produce json
+// @Param from query string false "起始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param brand_id query int false "品牌 ID"
+// @Success 200 {object} response.Response{data=model.BrandDashboardResponse}
+// @Router /api/v1/geo/brands/dashboard [get]
+func (h *BrandHandler) Dashboard(c *gin.Context) {
+	var req model.BrandDashboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.service.Dashboard(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "统计品牌数据失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetAnalysisBrands 获取分析的品牌提及结果
+// @Summary 获取分析的品牌提及
+// @Tags 品牌提及
+// @Produce json
+// @Param id path int true "分析 ID"
+// @Success 200 {object} response.Response{data=[]model.BrandAnalysisResponse}
+// @Router /api/v1/geo/analysis/{id}/brands [get]
+func (h *BrandHandler) GetAnalysisBrands(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetAnalysisBrands(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "获取品牌提及失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Reanalyze 重新分析品牌提及
+// @Summary 重新分析品牌提及
+// @Description 使用当前品牌词典重新分析已完成分析的 AI 回答
+// @Tags 品牌提及
+// @Produce json
+// @Param id path int true "分析 ID"
+// @Success 200 {object} response.Response{data=[]model.BrandAnalysisResponse}
+// @Router /api/v1/geo/analysis/{id}/brands/reanalyze [post]
+func (h *BrandHandler) Reanalyze(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Reanalyze(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "品牌提及分析失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleError 将服务层错误映射为响应
+func (h *BrandHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrBrandNotFound):
+		response.NotFound(c, "品牌不存在")
+	case errors.Is(err, service.ErrAnalysisNotFound):
+		response.NotFound(c, "分析记录不存在")
+	case errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, "日期格式应为 YYYY-MM-DD，且起始日期不晚于结束日期")
+	default:
+		response.ServerError(c, message+": "+err.Error())
+	}
+}
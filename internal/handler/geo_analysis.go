@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/agent/geo/export"
 	"github.com/solariswu/peanut/internal/agent/geo/models"
 	"github.com/solariswu/peanut/internal/model"
 	"github.com/solariswu/peanut/internal/pkg/progress"
@@ -15,13 +17,15 @@ import (
 type GEOAnalysisHandler struct {
 	service     *service.GEOAnalysisService
 	progressMgr *progress.Manager
+	reports     *service.ReportService
 }
 
 // NewGEOAnalysisHandler 创建处理器
-func NewGEOAnalysisHandler(service *service.GEOAnalysisService, progressMgr *progress.Manager) *GEOAnalysisHandler {
+func NewGEOAnalysisHandler(service *service.GEOAnalysisService, progressMgr *progress.Manager, reports *service.ReportService) *GEOAnalysisHandler {
 	return &GEOAnalysisHandler{
 		service:     service,
 		progressMgr: progressMgr,
+		reports:     reports,
 	}
 }
 
@@ -35,7 +39,12 @@ func (h *GEOAnalysisHandler) RegisterRoutes(r *gin.RouterGroup) {
 		analysis.GET("/:id", h.GetByID)
 		analysis.DELETE("/:id", h.Delete)
 		analysis.GET("/:id/progress", h.GetProgress)
+		analysis.GET("/:id/trace", h.GetTrace)
+		analysis.GET("/:id/logs", h.GetLogs)
+		analysis.POST("/:id/rating", h.Rate)
+		analysis.GET("/:id/export", h.Export)
 	}
+	r.GET("/geo/usage", h.UsageSummary)
 }
 
 // Create 创建分析任务
@@ -59,6 +68,10 @@ func (h *GEOAnalysisHandler) Create(c *gin.Context) {
 
 	analysis, err := h.service.Create(c.Request.Context(), &req, userID)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidOutputLanguage) || errors.Is(err, service.ErrAnalysisInProgress) {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.ServerError(c, "创建分析任务失败: "+err.Error())
 		return
 	}
@@ -94,13 +107,25 @@ func (h *GEOAnalysisHandler) GetByID(c *gin.Context) {
 
 // List 查询分析列表
 // @Summary 获取 GEO 分析列表
-// @Description 分页查询分析记录
+// @Description 分页查询分析记录，支持按字段筛选和在 AI 概览、优化报告、优化后文章中全文搜索（返回命中片段）
 // @Tags GEO 分析
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Param status query string false "状态筛选"
+// @Param order_by query string false "排序字段：created_at、updated_at、completed_at、overall_score、optimized_score" default(created_at)
+// @Param order_desc query bool false "是否倒序" default(true)
+// @Param url query string false "URL 子串"
+// @Param domain query string false "域名（包括子域名）"
+// @Param title query string false "标题子串"
+// @Param main_query query string false "主查询子串"
+// @Param platform query string false "目标平台"
+// @Param from query string false "创建日期起始 YYYY-MM-DD"
+// @Param to query string false "创建日期截止 YYYY-MM-DD（含）"
+// @Param min_score query int false "总评分下限"
+// @Param max_score query int false "总评分上限"
+// @Param q query string false "全文搜索关键词"
 // @Success 200 {object} response.PageResponse
 // @Router /api/v1/geo/analysis [get]
 func (h *GEOAnalysisHandler) List(c *gin.Context) {
@@ -117,6 +142,10 @@ func (h *GEOAnalysisHandler) List(c *gin.Context) {
 
 	list, total, err := h.service.List(&req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) || errors.Is(err, service.ErrInvalidScoreRange) {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.ServerError(c, "查询失败: "+err.Error())
 		return
 	}
@@ -149,6 +178,163 @@ func (h *GEOAnalysisHandler) Delete(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// GetTrace 获取分析执行追踪
+// @Summary 获取 GEO 分析执行追踪
+// @Description 获取分析中每个节点的执行记录：渲染后的 prompt、模型原始输出、工具调用、router 更新后的 FlowState、耗时和错误
+// @Tags GEO 分析
+// @Produce json
+// @Param id path int true "分析 ID"
+// @Success 200 {object} response.Response{data=model.GEOAnalysisTraceResponse}
+// @Router /api/v1/geo/analysis/{id}/trace [get]
+func (h *GEOAnalysisHandler) GetTrace(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	result, err := h.service.GetTrace(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrAnalysisNotFound) {
+			response.NotFound(c, "分析记录不存在")
+			return
+		}
+		response.ServerError(c, "获取执行追踪失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetLogs 获取分析执行日志
+// @Summary 获取 GEO 分析执行日志
+// @Description 获取分析执行期间的结构化日志（含 analysis_id、agent、request_id 等字段），用于排查问题；分析执行中返回实时缓冲
+// @Tags GEO 分析
+// @Produce json
+// @Param id path int true "分析 ID"
+// @Param level query string false "最低日志级别：debug、info、warn、error"
+// @Success 200 {object} response.Response{data=model.GEOAnalysisLogResponse}
+// @Router /api/v1/geo/analysis/{id}/logs [get]
+func (h *GEOAnalysisHandler) GetLogs(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	result, err := h.service.GetLogs(c.Request.Context(), id, c.Query("level"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLogLevel):
+			response.BadRequest(c, err.Error())
+		case errors.Is(err, service.ErrAnalysisNotFound):
+			response.NotFound(c, "分析记录不存在")
+		default:
+			response.ServerError(c, "获取执行日志失败: "+err.Error())
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Rate 为分析结果评分
+// @Summary 为 GEO 分析结果评分
+// @Description 用户为已完成的分析结果打分（1-5），用于比较 prompt A/B 实验各变体的效果
+// @Tags GEO 分析
+// @Accept json
+// @Produce json
+// @Param id path int true "分析 ID"
+// @Param request body model.GEOAnalysisRatingRequest true "评分请求"
+// @Success 200 {object} response.Response{data=model.GEOAnalysisResponse}
+// @Router /api/v1/geo/analysis/{id}/rating [post]
+func (h *GEOAnalysisHandler) Rate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	var req model.GEOAnalysisRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	analysis, err := h.service.Rate(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAnalysisNotFound):
+			response.NotFound(c, "分析记录不存在")
+		case errors.Is(err, service.ErrAnalysisNotCompleted):
+			response.BadRequest(c, err.Error())
+		default:
+			response.ServerError(c, "评分失败: "+err.Error())
+		}
+		return
+	}
+
+	response.Success(c, analysis)
+}
+
+// Export 导出分析报告
+// @Summary 导出 GEO 分析报告
+// @Description 将已完成的分析报告导出为 Markdown、HTML、PDF、DOCX 或 JSON 文件，指定工作区时使用该工作区的白标模板，否则使用默认品牌
+// @Tags GEO 分析
+// @Produce text/markdown,text/html,application/pdf,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/json
+// @Param id path int true "分析 ID"
+// @Param format query string false "导出格式：md、html、pdf、docx、json，默认 md"
+// @Param workspace query string false "工作区标识"
+// @Success 200 {file} file
+// @Router /api/v1/geo/analysis/{id}/export [get]
+func (h *GEOAnalysisHandler) Export(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatMarkdown)
+	file, err := h.reports.Export(c.Request.Context(), id, c.Query("workspace"), format)
+	if err != nil {
+		writeReportError(c, err, "导出报告失败: ")
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	c.Data(200, file.ContentType, file.Data)
+}
+
+// UsageSummary 获取 LLM 用量汇总
+// @Summary 获取 LLM 用量汇总
+// @Description 按用户和模型汇总时间范围内的 LLM token 用量和估算费用
+// @Tags GEO 分析
+// @Produce json
+// @Param from query string false "起始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param user_id query int false "用户 ID"
+// @Success 200 {object} response.Response{data=model.GEOUsageSummaryResponse}
+// @Router /api/v1/geo/usage [get]
+func (h *GEOAnalysisHandler) UsageSummary(c *gin.Context) {
+	var req model.GEOUsageSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.service.UsageSummary(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			response.BadRequest(c, "日期格式应为 YYYY-MM-DD，且起始日期不晚于结束日期")
+			return
+		}
+		response.ServerError(c, "统计用量失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // GetProgress 获取分析进度（SSE）
 // @Summary 获取分析进度
 // @Description 通过 Server-Sent Events 获取实时进度
@@ -2,17 +2,26 @@
 package handler
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/solariswu/peanut/internal/pkg/database"
 	"github.com/solariswu/peanut/internal/pkg/response"
 )
 
+// readyTimeout 就绪检查中数据库 ping 的超时时间
+const readyTimeout = 2 * time.Second
+
 // HealthHandler 健康检查处理器
-type HealthHandler struct{}
+type HealthHandler struct {
+	db database.DB
+}
 
 // NewHealthHandler 创建健康检查处理器
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(db database.DB) *HealthHandler {
+	return &HealthHandler{db: db}
 }
 
 // Health 健康检查
@@ -30,15 +39,24 @@ func (h *HealthHandler) Health(c *gin.Context) {
 
 // Ready 就绪检查
 // @Summary 就绪检查
-// @Description 检查服务是否就绪
+// @Description 检查服务是否就绪，数据库无法连接时返回 503
 // @Tags 系统
 // @Produce json
 // @Success 200 {object} response.Response
+// @Failure 503 {object} response.Response
 // @Router /ready [get]
 func (h *HealthHandler) Ready(c *gin.Context) {
-	// 可以在这里添加数据库、Redis 等的检查
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+
+	driver := h.db.DB().Dialector.Name()
+	if err := h.db.Ping(ctx); err != nil {
+		response.ServiceUnavailable(c, "数据库连接失败: "+err.Error())
+		return
+	}
 	response.SuccessWithMessage(c, "ready", gin.H{
-		"status": "ready",
+		"status":   "ready",
+		"database": driver,
 	})
 }
 
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// PromptHandler prompt 管理处理器
+type PromptHandler struct {
+	service *service.PromptService
+}
+
+// NewPromptHandler 创建处理器
+func NewPromptHandler(service *service.PromptService) *PromptHandler {
+	return &PromptHandler{service: service}
+}
+
+// RegisterRoutes 注册路由，middlewares 用于管理接口鉴权
+func (h *PromptHandler) RegisterRoutes(r *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	prompts := r.Group("/admin/prompts", middlewares...)
+	{
+		prompts.GET("", h.List)
+		prompts.GET("/:name", h.Get)
+		prompts.POST("/:name/versions", h.Create)
+		prompts.POST("/:name/versions/:version/activate", h.Activate)
+		prompts.POST("/:name/reset", h.Reset)
+	}
+}
+
+// List 列出 prompt
+// @Summary 获取 prompt 列表
+// @Description 列出所有 Agent 的 prompt 及当前生效的来源（builtin、file、db）和版本
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.PromptSummary}
+// @Router /api/v1/admin/prompts [get]
+func (h *PromptHandler) List(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, list)
+}
+
+// Get 获取 prompt 详情
+// @Summary 获取 prompt 详情
+// @Description 获取当前生效的内容、内嵌默认内容及数据库中的所有版本
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "prompt 名称（Agent 名称）"
+// @Success 200 {object} response.Response{data=model.PromptDetail}
+// @Router /api/v1/admin/prompts/{name} [get]
+func (h *PromptHandler) Get(c *gin.Context) {
+	detail, err := h.service.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, service.ErrPromptNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, detail)
+}
+
+// Create 创建 prompt 版本
+// @Summary 创建 prompt 版本
+// @Description 保存新版本（Jinja2 模板），默认立即激活，无需重启即对新的分析生效
+// @Tags Prompt 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "prompt 名称（Agent 名称）"
+// @Param request body model.PromptCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.GEOPrompt}
+// @Router /api/v1/admin/prompts/{name}/versions [post]
+func (h *PromptHandler) Create(c *gin.Context) {
+	var req model.PromptCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// TODO: 从 JWT 获取 userID
+	var userID *int64
+
+	p, err := h.service.Create(c.Request.Context(), c.Param("name"), &req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPromptNotFound):
+			response.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrInvalidPrompt):
+			response.BadRequest(c, err.Error())
+		default:
+			response.ServerError(c, "创建失败: "+err.Error())
+		}
+		return
+	}
+	response.Success(c, p)
+}
+
+// Activate 激活 prompt 版本
+// @Summary 激活 prompt 版本
+// @Description 激活指定版本（可用于回滚），同名其他版本自动停用
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "prompt 名称（Agent 名称）"
+// @Param version path int true "版本号"
+// @Success 200 {object} response.Response{data=model.GEOPrompt}
+// @Router /api/v1/admin/prompts/{name}/versions/{version}/activate [post]
+func (h *PromptHandler) Activate(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.BadRequest(c, "无效的版本号")
+		return
+	}
+
+	p, err := h.service.Activate(c.Request.Context(), c.Param("name"), version)
+	if err != nil {
+		if errors.Is(err, service.ErrPromptVersionNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.ServerError(c, "激活失败: "+err.Error())
+		return
+	}
+	response.Success(c, p)
+}
+
+// Reset 恢复默认 prompt
+// @Summary 恢复默认 prompt
+// @Description 停用数据库中的版本，恢复使用覆盖目录或内嵌的默认 prompt
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "prompt 名称（Agent 名称）"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/prompts/{name}/reset [post]
+func (h *PromptHandler) Reset(c *gin.Context) {
+	if err := h.service.Reset(c.Request.Context(), c.Param("name")); err != nil {
+		response.ServerError(c, "恢复失败: "+err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// PromptExperimentHandler prompt A/B 实验处理器
+type PromptExperimentHandler struct {
+	service *service.PromptExperimentService
+}
+
+// NewPromptExperimentHandler 创建处理器
+func NewPromptExperimentHandler(service *service.PromptExperimentService) *PromptExperimentHandler {
+	return &PromptExperimentHandler{service: service}
+}
+
+// RegisterRoutes 注册路由，middlewares 用于管理接口鉴权
+func (h *PromptExperimentHandler) RegisterRoutes(r *gin.RouterGroup, middlewares ...gin.HandlerFunc) {
+	experiments := r.Group("/admin/prompt-experiments", middlewares...)
+	{
+		experiments.POST("", h.Create)
+		experiments.GET("", h.List)
+		experiments.GET("/:id", h.Results)
+		experiments.POST("/:id/stop", h.Stop)
+	}
+}
+
+// Create 创建实验
+// @Summary 创建 prompt A/B 实验
+// @Description 为某个 Agent 的 prompt 创建实验，新的分析按比例（之和为 100）分配到各变体；变体 prompt_version 为数据库中的版本号，0 表示当前生效的 prompt
+// @Tags Prompt 管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.PromptExperimentCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.GEOPromptExperiment}
+// @Router /api/v1/admin/prompt-experiments [post]
+func (h *PromptExperimentHandler) Create(c *gin.Context) {
+	var req model.PromptExperimentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	exp, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPromptNotFound):
+			response.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrInvalidExperiment):
+			response.BadRequest(c, err.Error())
+		default:
+			response.ServerError(c, "创建失败: "+err.Error())
+		}
+		return
+	}
+	response.Success(c, exp)
+}
+
+// List 查询实验列表
+// @Summary 获取 prompt A/B 实验列表
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "状态筛选：running、stopped"
+// @Success 200 {object} response.Response{data=[]model.GEOPromptExperiment}
+// @Router /api/v1/admin/prompt-experiments [get]
+func (h *PromptExperimentHandler) List(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, list)
+}
+
+// Results 获取实验结果对比
+// @Summary 获取 prompt A/B 实验结果
+// @Description 对比各变体的分析数、失败率、平均评分、输出解析失败率、该 Agent 的平均 token 用量和费用、用户评分
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "实验 ID"
+// @Success 200 {object} response.Response{data=model.PromptExperimentResults}
+// @Router /api/v1/admin/prompt-experiments/{id} [get]
+func (h *PromptExperimentHandler) Results(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	results, err := h.service.Results(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrExperimentNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, results)
+}
+
+// Stop 停止实验
+// @Summary 停止 prompt A/B 实验
+// @Description 停止后新的分析不再分配变体，已有结果保留
+// @Tags Prompt 管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "实验 ID"
+// @Success 200 {object} response.Response{data=model.GEOPromptExperiment}
+// @Router /api/v1/admin/prompt-experiments/{id}/stop [post]
+func (h *PromptExperimentHandler) Stop(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return
+	}
+
+	exp, err := h.service.Stop(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrExperimentNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.ServerError(c, "停止失败: "+err.Error())
+		return
+	}
+	response.Success(c, exp)
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// QuerySetHandler 查询集（引用份额追踪）处理器
+type QuerySetHandler struct {
+	service *service.ShareOfVoiceService
+}
+
+// NewQuerySetHandler 创建处理器
+func NewQuerySetHandler(service *service.ShareOfVoiceService) *QuerySetHandler {
+	return &QuerySetHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *QuerySetHandler) RegisterRoutes(r *gin.RouterGroup) {
+	sets := r.Group("/geo/query-sets")
+	{
+		sets.POST("", h.Create)
+		sets.GET("", h.List)
+		sets.GET("/:id", h.GetByID)
+		sets.PUT("/:id", h.Update)
+		sets.DELETE("/:id", h.Delete)
+		sets.POST("/:id/run", h.Run)
+		sets.GET("/:id/runs", h.ListRuns)
+		sets.GET("/:id/share-of-voice", h.ShareOfVoice)
+	}
+}
+
+// Create 创建查询集
+// @Summary 创建查询集
+// @Description 创建一组目标查询及我方域名，定时采集 AI 回答中的引用来源
+// @Tags 引用份额
+// @Accept json
+// @Produce json
+// @Param request body model.QuerySetCreateRequest true "创建请求"
+// @Success 200 {object} response.Response{data=model.QuerySetResponse}
+// @Router /api/v1/geo/query-sets [post]
+func (h *QuerySetHandler) Create(c *gin.Context) {
+	var req model.QuerySetCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// TODO: 从 JWT 获取 userID
+	var userID *int64
+
+	set, err := h.service.Create(c.Request.Context(), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "创建查询集失败")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(set))
+}
+
+// List 查询查询集列表
+// @Summary 获取查询集列表
+// @Tags 引用份额
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.QuerySetResponse}
+// @Router /api/v1/geo/query-sets [get]
+func (h *QuerySetHandler) List(c *gin.Context) {
+	// TODO: 从 JWT 获取 userID
+	list, err := h.service.List(c.Request.Context(), nil)
+	if err != nil {
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// GetByID 获取查询集详情
+// @Summary 获取查询集详情
+// @Tags 引用份额
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Success 200 {object} response.Response{data=model.QuerySetResponse}
+// @Router /api/v1/geo/query-sets/{id} [get]
+func (h *QuerySetHandler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	set, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "获取查询集失败")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(set))
+}
+
+// Update 更新查询集
+// @Summary 更新查询集
+// @Tags 引用份额
+// @Accept json
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Param request body model.QuerySetUpdateRequest true "更新请求"
+// @Success 200 {object} response.Response{data=model.QuerySetResponse}
+// @Router /api/v1/geo/query-sets/{id} [put]
+func (h *QuerySetHandler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.QuerySetUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	set, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "更新查询集失败")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(set))
+}
+
+// Delete 删除查询集
+// @Summary 删除查询集
+// @Description 删除查询集及其全部采集数据
+// @Tags 引用份额
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/geo/query-sets/{id} [delete]
+func (h *QuerySetHandler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "删除失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Run 立即采集
+// @Summary 立即采集查询集
+// @Description 异步触发一次采集，返回采集记录
+// @Tags 引用份额
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Success 200 {object} response.Response{data=model.QuerySetRun}
+// @Router /api/v1/geo/query-sets/{id}/run [post]
+func (h *QuerySetHandler) Run(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.RunNow(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "触发采集失败")
+		return
+	}
+
+	response.Success(c, run)
+}
+
+// ListRuns 查询采集记录
+// @Summary 获取查询集采集记录
+// @Tags 引用份额
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Param limit query int false "返回数量" default(20)
+// @Success 200 {object} response.Response{data=[]model.QuerySetRun}
+// @Router /api/v1/geo/query-sets/{id}/runs [get]
+func (h *QuerySetHandler) ListRuns(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		response.BadRequest(c, "limit 取值范围为 1-100")
+		return
+	}
+
+	runs, err := h.service.ListRuns(c.Request.Context(), id, limit)
+	if err != nil {
+		h.handleError(c, err, "查询采集记录失败")
+		return
+	}
+
+	response.Success(c, runs)
+}
+
+// ShareOfVoice 获取引用份额趋势
+// @Summary 获取引用份额趋势
+// @Description 按天或按周统计我方域名与竞品域名在 AI 回答中的引用份额、覆盖率和平均引用位置
+// @Tags 引用份额
+// @Produce json
+// @Param id path int true "查询集 ID"
+// @Param from query string false "起始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param granularity query string false "时间粒度 day/week" default(day)
+// @Param top query int false "额外返回的其他域名数量" default(10)
+// @Success 200 {object} response.Response{data=model.ShareOfVoiceResponse}
+// @Router /api/v1/geo/query-sets/{id}/share-of-voice [get]
+func (h *QuerySetHandler) ShareOfVoice(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ShareOfVoiceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.service.ShareOfVoice(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "统计引用份额失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleError 将服务层错误映射为响应
+func (h *QuerySetHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrQuerySetNotFound):
+		response.NotFound(c, "查询集不存在")
+	case errors.Is(err, service.ErrQuerySetRunning), errors.Is(err, service.ErrUnsupportedPlatform):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, "日期格式应为 YYYY-MM-DD，且起始日期不晚于结束日期")
+	default:
+		response.ServerError(c, message+": "+err.Error())
+	}
+}
+
+// parseID 解析路径中的 ID，失败时直接返回错误响应
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的 ID")
+		return 0, false
+	}
+	return id, true
+}
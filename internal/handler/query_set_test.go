@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/repository"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// TestQuerySetCreateUnsupportedPlatform 测试创建不支持平台的查询集返回 400
+func TestQuerySetCreateUnsupportedPlatform(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewQuerySetHandler(service.NewShareOfVoiceService(repository.NewQuerySetRepository(nil), nil))
+	h.RegisterRoutes(r.Group("/api/v1"))
+
+	body := `{"name":"test","queries":["q"],"domains":["example.com"],"platform":"bing"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/geo/query-sets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var resp response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if resp.Code != response.CodeBadRequest || resp.Message != service.ErrUnsupportedPlatform.Error() {
+		t.Errorf("response = %+v, want code %d with %q", resp, response.CodeBadRequest, service.ErrUnsupportedPlatform)
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solariswu/peanut/internal/agent/geo/export"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// ReportTemplateHandler 白标报告模板处理器
+type ReportTemplateHandler struct {
+	service *service.ReportService
+}
+
+// NewReportTemplateHandler 创建处理器
+func NewReportTemplateHandler(service *service.ReportService) *ReportTemplateHandler {
+	return &ReportTemplateHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *ReportTemplateHandler) RegisterRoutes(r *gin.RouterGroup) {
+	templates := r.Group("/geo/report-templates")
+	{
+		templates.GET("", h.List)
+		templates.POST("/preview", h.Preview)
+		templates.GET("/:workspace", h.Get)
+		templates.PUT("/:workspace", h.Save)
+		templates.DELETE("/:workspace", h.Delete)
+		templates.GET("/:workspace/preview", h.PreviewSaved)
+	}
+}
+
+// List 查询报告模板列表
+// @Summary 获取报告模板列表
+// @Description 获取所有工作区的白标报告模板
+// @Tags 报告模板
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.ReportTemplateResponse}
+// @Router /api/v1/geo/report-templates [get]
+func (h *ReportTemplateHandler) List(c *gin.Context) {
+	templates, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.ServerError(c, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, templates)
+}
+
+// Get 获取工作区的报告模板
+// @Summary 获取报告模板
+// @Tags 报告模板
+// @Produce json
+// @Param workspace path string true "工作区标识"
+// @Success 200 {object} response.Response{data=model.ReportTemplateResponse}
+// @Router /api/v1/geo/report-templates/{workspace} [get]
+func (h *ReportTemplateHandler) Get(c *gin.Context) {
+	tmpl, err := h.service.Get(c.Request.Context(), c.Param("workspace"))
+	if err != nil {
+		writeReportError(c, err, "获取报告模板失败: ")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(tmpl))
+}
+
+// Save 创建或更新工作区的报告模板
+// @Summary 保存报告模板
+// @Description 创建或整体替换工作区的白标报告模板（品牌名称、主色、Logo、页眉页脚、章节顺序、语言），所有导出格式共用
+// @Tags 报告模板
+// @Accept json
+// @Produce json
+// @Param workspace path string true "工作区标识（小写字母、数字、- 和 _）"
+// @Param request body model.ReportTemplateRequest true "模板配置"
+// @Success 200 {object} response.Response{data=model.ReportTemplateResponse}
+// @Router /api/v1/geo/report-templates/{workspace} [put]
+func (h *ReportTemplateHandler) Save(c *gin.Context) {
+	var req model.ReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	tmpl, err := h.service.Save(c.Request.Context(), c.Param("workspace"), &req)
+	if err != nil {
+		writeReportError(c, err, "保存报告模板失败: ")
+		return
+	}
+
+	response.Success(c, h.service.ToResponse(tmpl))
+}
+
+// Delete 删除工作区的报告模板
+// @Summary 删除报告模板
+// @Tags 报告模板
+// @Produce json
+// @Param workspace path string true "工作区标识"
+// @Success 200 {object} response.Response
+// @Router /api/v1/geo/report-templates/{workspace} [delete]
+func (h *ReportTemplateHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("workspace")); err != nil {
+		writeReportError(c, err, "删除报告模板失败: ")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Preview 预览未保存的报告模板
+// @Summary 预览报告模板
+// @Description 使用请求中的模板配置渲染报告，未指定 analysis_id 时使用示例报告，format 默认 html
+// @Tags 报告模板
+// @Accept json
+// @Produce text/html,text/markdown,application/pdf,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/json
+// @Param request body model.ReportTemplatePreviewRequest true "模板配置"
+// @Success 200 {file} file
+// @Router /api/v1/geo/report-templates/preview [post]
+func (h *ReportTemplateHandler) Preview(c *gin.Context) {
+	var req model.ReportTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	file, err := h.service.Preview(c.Request.Context(), &req)
+	if err != nil {
+		writeReportError(c, err, "预览报告失败: ")
+		return
+	}
+
+	writePreview(c, file)
+}
+
+// PreviewSaved 预览已保存的报告模板
+// @Summary 预览工作区报告模板
+// @Description 使用工作区已保存的模板渲染报告，未指定 analysis_id 时使用示例报告，format 默认 html
+// @Tags 报告模板
+// @Produce text/html,text/markdown,application/pdf,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/json
+// @Param workspace path string true "工作区标识"
+// @Param analysis_id query int false "分析 ID"
+// @Param format query string false "预览格式：html、md、pdf、docx、json，默认 html"
+// @Success 200 {file} file
+// @Router /api/v1/geo/report-templates/{workspace}/preview [get]
+func (h *ReportTemplateHandler) PreviewSaved(c *gin.Context) {
+	var analysisID int64
+	if v := c.Query("analysis_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "无效的分析 ID")
+			return
+		}
+		analysisID = id
+	}
+
+	file, err := h.service.PreviewSaved(c.Request.Context(), c.Param("workspace"), analysisID, c.Query("format"))
+	if err != nil {
+		writeReportError(c, err, "预览报告失败: ")
+		return
+	}
+
+	writePreview(c, file)
+}
+
+// writePreview 以内联方式返回预览文件，浏览器可直接打开
+func writePreview(c *gin.Context, file *export.File) {
+	c.Header("Content-Disposition", `inline; filename="`+file.Name+`"`)
+	c.Data(200, file.ContentType, file.Data)
+}
+
+// writeReportError 将报告导出和模板错误映射为响应
+func writeReportError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, service.ErrAnalysisNotFound):
+		response.NotFound(c, "分析记录不存在")
+	case errors.Is(err, service.ErrReportTemplateNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrAnalysisNotCompleted),
+		errors.Is(err, service.ErrInvalidReportTemplate),
+		errors.Is(err, export.ErrUnsupportedFormat):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, prefix+err.Error())
+	}
+}
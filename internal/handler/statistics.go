@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// StatisticsHandler 域名统计处理器
+type StatisticsHandler struct {
+	service *service.StatisticsService
+}
+
+// NewStatisticsHandler 创建域名统计处理器
+func NewStatisticsHandler(service *service.StatisticsService) *StatisticsHandler {
+	return &StatisticsHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *StatisticsHandler) RegisterRoutes(r *gin.RouterGroup) {
+	stats := r.Group("/geo/stats")
+	{
+		stats.GET("/domains", h.Domains)
+		stats.GET("/domains/:domain", h.Domain)
+	}
+}
+
+// Domains 按域名汇总分析
+// @Summary 域名汇总
+// @Description 按域名统计时间范围内的分析次数、页面数和平均评分
+// @Tags 统计
+// @Produce json
+// @Param from query string false "起始日期 YYYY-MM-DD，默认 90 天前"
+// @Param to query string false "结束日期 YYYY-MM-DD，默认今天"
+// @Success 200 {object} response.Response{data=model.DomainOverviewResponse}
+// @Router /api/v1/geo/stats/domains [get]
+func (h *StatisticsHandler) Domains(c *gin.Context) {
+	var req model.DomainStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// TODO: 从 JWT 获取 userID
+
+	result, err := h.service.Domains(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "统计域名失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Domain 单个域名的统计看板
+// @Summary 域名统计看板
+// @Description 统计域名的平均评分和趋势、最常见的内容差距、薄弱维度（按优化建议类别）以及评分最高和最低的页面
+// @Tags 统计
+// @Produce json
+// @Param domain path string true "域名，如 example.com"
+// @Param from query string false "起始日期 YYYY-MM-DD，默认 90 天前"
+// @Param to query string false "结束日期 YYYY-MM-DD，默认今天"
+// @Param granularity query string false "趋势时间粒度 day/week/month" default(week)
+// @Param top query int false "内容差距和最好/最差页面的返回数量" default(10)
+// @Success 200 {object} response.Response{data=model.DomainDashboardResponse}
+// @Router /api/v1/geo/stats/domains/{domain} [get]
+func (h *StatisticsHandler) Domain(c *gin.Context) {
+	var req model.DomainStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.service.Domain(c.Request.Context(), c.Param("domain"), &req)
+	if err != nil {
+		h.handleError(c, err, "统计域名失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleError 将服务层错误映射为响应
+func (h *StatisticsHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDomain):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrInvalidDateRange):
+		response.BadRequest(c, "日期格式应为 YYYY-MM-DD，且起始日期不晚于结束日期")
+	default:
+		response.ServerError(c, message+": "+err.Error())
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/response"
+	"github.com/solariswu/peanut/internal/service"
+)
+
+// SuggestionHandler 优化建议处理器
+type SuggestionHandler struct {
+	service *service.SuggestionService
+}
+
+// NewSuggestionHandler 创建优化建议处理器
+func NewSuggestionHandler(service *service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *SuggestionHandler) RegisterRoutes(r *gin.RouterGroup) {
+	suggestions := r.Group("/geo/suggestions")
+	{
+		suggestions.GET("", h.List)
+		suggestions.GET("/:id", h.GetByID)
+		suggestions.PUT("/:id", h.Update)
+		suggestions.POST("/:id/comments", h.AddComment)
+		suggestions.POST("/:id/complete", h.Complete)
+	}
+}
+
+// List 跨分析查询优化建议
+// @Summary 查询优化建议
+// @Description 跨分析按优先级、类别、状态、负责人和关键词查询优化建议
+// @Tags 优化建议
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param analysis_id query int false "分析 ID"
+// @Param priority query string false "优先级：high、medium、low"
+// @Param category query string false "类别"
+// @Param status query string false "状态：open、in_progress、done、wont_fix"
+// @Param assignee query string false "负责人"
+// @Param overdue query bool false "只返回已过截止日期且未完成的建议"
+// @Param keyword query string false "在问题和建议中搜索"
+// @Success 200 {object} response.PageResponse
+// @Router /api/v1/geo/suggestions [get]
+func (h *SuggestionHandler) List(c *gin.Context) {
+	var req model.GEOSuggestionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), &req)
+	if err != nil {
+		response.ServerError(c, "查询优化建议失败: "+err.Error())
+		return
+	}
+
+	response.SuccessPage(c, list, total, req.Page, req.PageSize)
+}
+
+// GetByID 获取优化建议详情
+// @Summary 获取优化建议详情
+// @Description 返回优化建议、评论和完成后重新分析的验证结果
+// @Tags 优化建议
+// @Produce json
+// @Param id path int true "优化建议 ID"
+// @Success 200 {object} response.Response{data=model.GEOSuggestionDetailResponse}
+// @Router /api/v1/geo/suggestions/{id} [get]
+func (h *SuggestionHandler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "获取优化建议失败")
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// Update 更新优化建议
+// @Summary 更新优化建议
+// @Description 更新状态、负责人和截止日期，未传入的字段不修改
+// @Tags 优化建议
+// @Accept json
+// @Produce json
+// @Param id path int true "优化建议 ID"
+// @Param request body model.GEOSuggestionUpdateRequest true "更新请求"
+// @Success 200 {object} response.Response{data=model.GEOSuggestionDetailResponse}
+// @Router /api/v1/geo/suggestions/{id} [put]
+func (h *SuggestionHandler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.GEOSuggestionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	detail, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "更新优化建议失败")
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// AddComment 添加评论
+// @Summary 为优化建议添加评论
+// @Tags 优化建议
+// @Accept json
+// @Produce json
+// @Param id path int true "优化建议 ID"
+// @Param request body model.GEOSuggestionCommentRequest true "评论"
+// @Success 200 {object} response.Response{data=model.GEOSuggestionComment}
+// @Router /api/v1/geo/suggestions/{id}/comments [post]
+func (h *SuggestionHandler) AddComment(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.GEOSuggestionCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	comment, err := h.service.AddComment(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err, "添加评论失败")
+		return
+	}
+
+	response.Success(c, comment)
+}
+
+// Complete 标记优化建议完成
+// @Summary 标记优化建议完成
+// @Description 标记为完成，默认重新分析同一 URL（跳过缓存），通过详情中的 verification 查看评分是否提高
+// @Tags 优化建议
+// @Accept json
+// @Produce json
+// @Param id path int true "优化建议 ID"
+// @Param request body model.GEOSuggestionCompleteRequest false "完成说明和是否重新分析"
+// @Success 200 {object} response.Response{data=model.GEOSuggestionDetailResponse}
+// @Router /api/v1/geo/suggestions/{id}/complete [post]
+func (h *SuggestionHandler) Complete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	// 请求体可选
+	var req model.GEOSuggestionCompleteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+	}
+
+	// TODO: 从 JWT 获取 userID
+	var userID *int64
+
+	detail, err := h.service.Complete(c.Request.Context(), id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "标记优化建议完成失败")
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// handleError 将服务层错误映射为响应
+func (h *SuggestionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrSuggestionNotFound):
+		response.NotFound(c, "优化建议不存在")
+	case errors.Is(err, service.ErrAnalysisNotFound):
+		response.NotFound(c, "分析记录不存在")
+	case errors.Is(err, service.ErrInvalidDueDate), errors.Is(err, service.ErrAnalysisInProgress),
+		errors.Is(err, service.ErrReanalysisUnavailable):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, message+": "+err.Error())
+	}
+}
@@ -2,12 +2,45 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/pkg/logging"
+	"github.com/solariswu/peanut/internal/pkg/response"
 )
 
+// RequestIDHeader 请求 ID 的 HTTP 头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 请求 ID 中间件：沿用客户端传入的 X-Request-ID 或生成新 ID，
+// 写入响应头，并将带 request_id 字段的日志器放入请求上下文
+func RequestID(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := logging.WithRequestID(logging.WithLogger(c.Request.Context(), logger), id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// newRequestID 生成 16 字节随机十六进制 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // Logger 日志中间件
 func Logger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -32,10 +65,28 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 			zap.Duration("latency", latency),
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
+			zap.String("request_id", logging.RequestIDFromContext(c.Request.Context())),
 		)
 	}
 }
 
+// AdminToken 管理接口鉴权中间件：要求请求头 Authorization: Bearer <token>
+// token 为空时拒绝所有请求，避免漏配令牌导致管理接口对外开放
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			response.Forbidden(c, "未配置管理令牌，管理接口已禁用")
+			return
+		}
+		got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			response.Unauthorized(c, "管理令牌无效")
+			return
+		}
+		c.Next()
+	}
+}
+
 // CORS 跨域中间件
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
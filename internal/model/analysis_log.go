@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// GEOAnalysisLog 分析执行日志，对应一条结构化日志
+type GEOAnalysisLog struct {
+	ID         int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"-" gorm:"not null;index"`
+	Seq        int       `json:"seq" gorm:"type:int"` // 日志顺序
+	Time       time.Time `json:"time"`
+	Level      string    `json:"level" gorm:"type:varchar(10)"`
+	Message    string    `json:"message" gorm:"type:text"`
+	Fields     string    `json:"fields,omitempty" gorm:"type:text"` // JSON 格式的日志字段
+}
+
+// TableName 指定表名
+func (GEOAnalysisLog) TableName() string {
+	return "geo_analysis_logs"
+}
+
+// GEOAnalysisLogResponse 分析执行日志响应
+type GEOAnalysisLogResponse struct {
+	AnalysisID int64            `json:"analysis_id"`
+	Running    bool             `json:"running"` // 分析仍在执行，日志来自内存缓冲
+	Dropped    int              `json:"dropped"` // 超过缓冲上限被丢弃的最早日志条数
+	Logs       []GEOAnalysisLog `json:"logs"`
+}
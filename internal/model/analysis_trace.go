@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// GEOTraceSpan 分析执行追踪记录，对应一次节点执行
+type GEOTraceSpan struct {
+	ID         int64           `json:"-" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64           `json:"-" gorm:"not null;index"`
+	SpanID     int             `json:"id" gorm:"type:int"`
+	ParentID   int             `json:"parent_id" gorm:"type:int"` // 0 表示顶层节点
+	Agent      string          `json:"agent" gorm:"type:varchar(50)"`
+	Name       string          `json:"name" gorm:"type:varchar(100)"`
+	Type       string          `json:"type" gorm:"type:varchar(100)"`
+	Component  string          `json:"component" gorm:"type:varchar(50)"`
+	Input      string          `json:"input,omitempty" gorm:"type:text"`
+	Output     string          `json:"output,omitempty" gorm:"type:text"`
+	State      string          `json:"state,omitempty" gorm:"type:text"` // router 节点执行后的 FlowState 快照
+	Error      string          `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMs int64           `json:"duration_ms"`
+	Children   []*GEOTraceSpan `json:"children,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (GEOTraceSpan) TableName() string {
+	return "geo_trace_spans"
+}
+
+// GEOAnalysisTraceResponse 分析执行追踪响应
+type GEOAnalysisTraceResponse struct {
+	AnalysisID int64           `json:"analysis_id"`
+	Spans      []*GEOTraceSpan `json:"spans"` // 按执行顺序排列的 span 树
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"time"
+)
+
+// Brand 品牌词典
+type Brand struct {
+	BaseModel
+	Name     string `json:"name" gorm:"type:varchar(100);not null"`
+	Aliases  string `json:"-" gorm:"type:text"` // JSON 数组：别名
+	Products string `json:"-" gorm:"type:text"` // JSON 数组：产品名
+	Facts    string `json:"-" gorm:"type:text"` // JSON 数组：已知事实（用于识别错误说法）
+	Enabled  bool   `json:"enabled" gorm:"default:true;index"`
+	UserID   *int64 `json:"user_id,omitempty" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Brand) TableName() string {
+	return "geo_brands"
+}
+
+// BrandMentionRecord 分析中 AI 回答的品牌提及记录
+type BrandMentionRecord struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"analysis_id" gorm:"not null;index"`
+	BrandID    int64     `json:"brand_id" gorm:"not null;index"`
+	Term       string    `json:"term" gorm:"type:varchar(100)"`
+	Kind       string    `json:"kind" gorm:"type:varchar(20)"` // name, alias, product
+	Offset     int       `json:"offset" gorm:"column:char_offset;type:int"`
+	Snippet    string    `json:"snippet" gorm:"type:text"`
+	Sentiment  string    `json:"sentiment" gorm:"type:varchar(10)"` // positive, neutral, negative
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (BrandMentionRecord) TableName() string {
+	return "geo_brand_mentions"
+}
+
+// BrandAnalysisRecord 分析中单个品牌的汇总结果
+type BrandAnalysisRecord struct {
+	ID             int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID     int64     `json:"analysis_id" gorm:"not null;uniqueIndex:idx_brand_analysis"`
+	BrandID        int64     `json:"brand_id" gorm:"not null;uniqueIndex:idx_brand_analysis"`
+	Mentioned      bool      `json:"mentioned"`
+	MentionCount   int       `json:"mention_count" gorm:"type:int;default:0"`
+	Sentiment      string    `json:"sentiment" gorm:"type:varchar(10)"`
+	SentimentScore float64   `json:"sentiment_score"`
+	Summary        string    `json:"summary" gorm:"type:text"`
+	WrongClaims    string    `json:"-" gorm:"type:text"` // JSON 数组
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (BrandAnalysisRecord) TableName() string {
+	return "geo_brand_analyses"
+}
+
+// BrandCreateRequest 创建品牌请求
+type BrandCreateRequest struct {
+	Name     string   `json:"name" binding:"required,max=100"`
+	Aliases  []string `json:"aliases"`
+	Products []string `json:"products"`
+	Facts    []string `json:"facts"`
+}
+
+// BrandUpdateRequest 更新品牌请求
+type BrandUpdateRequest struct {
+	Name     string   `json:"name" binding:"omitempty,max=100"`
+	Aliases  []string `json:"aliases"`
+	Products []string `json:"products"`
+	Facts    []string `json:"facts"`
+	Enabled  *bool    `json:"enabled"`
+}
+
+// BrandResponse 品牌响应
+type BrandResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	Products  []string  `json:"products"`
+	Facts     []string  `json:"facts"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BrandWrongClaim 错误说法
+type BrandWrongClaim struct {
+	AnalysisID int64     `json:"analysis_id"`
+	Claim      string    `json:"claim"`
+	Correction string    `json:"correction"`
+	Severity   string    `json:"severity"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BrandAnalysisResponse 单次分析中单个品牌的结果
+type BrandAnalysisResponse struct {
+	BrandID        int64                `json:"brand_id"`
+	Brand          string               `json:"brand"`
+	Mentioned      bool                 `json:"mentioned"`
+	Sentiment      string               `json:"sentiment"`
+	SentimentScore float64              `json:"sentiment_score"`
+	Summary        string               `json:"summary"`
+	Mentions       []BrandMentionRecord `json:"mentions"`
+	WrongClaims    []BrandWrongClaim    `json:"wrong_claims"`
+}
+
+// BrandDashboardRequest 品牌看板查询请求
+type BrandDashboardRequest struct {
+	From    string `form:"from"` // 起始日期 YYYY-MM-DD，默认 30 天前
+	To      string `form:"to"`   // 结束日期 YYYY-MM-DD，默认今天
+	BrandID *int64 `form:"brand_id"`
+}
+
+// BrandDashboardPoint 品牌看板的按天统计
+type BrandDashboardPoint struct {
+	Date         string  `json:"date"`
+	Analyses     int     `json:"analyses"`
+	Mentions     int     `json:"mentions"`
+	MentionRate  float64 `json:"mention_rate"`  // 提及率（%）
+	AvgSentiment float64 `json:"avg_sentiment"` // 平均情感分数
+}
+
+// BrandDashboardItem 单个品牌的看板数据
+type BrandDashboardItem struct {
+	BrandID      int64                 `json:"brand_id"`
+	Brand        string                `json:"brand"`
+	Analyses     int                   `json:"analyses"`      // 统计的 AI 回答数
+	Mentioned    int                   `json:"mentioned"`     // 提及该品牌的回答数
+	MentionRate  float64               `json:"mention_rate"`  // 提及率（%）
+	AvgSentiment float64               `json:"avg_sentiment"` // 平均情感分数（仅统计提及的回答）
+	Sentiments   map[string]int        `json:"sentiments"`    // 情感分布
+	TopTerms     map[string]int        `json:"top_terms"`     // 命中词分布
+	WrongClaims  []BrandWrongClaim     `json:"wrong_claims"`  // 最近的错误说法
+	Trend        []BrandDashboardPoint `json:"trend"`
+}
+
+// BrandDashboardResponse 品牌看板响应
+type BrandDashboardResponse struct {
+	From   string               `json:"from"`
+	To     string               `json:"to"`
+	Brands []BrandDashboardItem `json:"brands"`
+}
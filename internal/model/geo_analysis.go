@@ -9,14 +9,19 @@ import (
 // GEOAnalysis GEO 分析记录
 type GEOAnalysis struct {
 	BaseModel
-	URL           string  `json:"url" gorm:"type:varchar(500);not null;index"`
-	Title         string  `json:"title" gorm:"type:varchar(500)"`
-	MainQuery     string  `json:"main_query" gorm:"type:varchar(200)"`
-	Platform      string  `json:"platform" gorm:"type:varchar(20);default:'google'"` // 目标平台：google
-	OverallScore  int     `json:"overall_score" gorm:"type:int;default:0"`
+	URL            string `json:"url" gorm:"type:varchar(500);not null;index"`
+	Title          string `json:"title" gorm:"type:varchar(500)"`
+	MainQuery      string `json:"main_query" gorm:"type:varchar(200)"`
+	Platform       string `json:"platform" gorm:"type:varchar(20);default:'google'"` // 目标平台：google
+	Country        string `json:"country,omitempty" gorm:"type:varchar(8)"`          // 目标国家（gl）
+	Language       string `json:"language,omitempty" gorm:"type:varchar(16)"`        // 目标语言（hl）
+	Device         string `json:"device,omitempty" gorm:"type:varchar(10)"`          // desktop, mobile
+	Page           int    `json:"page,omitempty" gorm:"type:int;default:1"`          // 搜索结果页码
+	OutputLanguage string `json:"output_language,omitempty" gorm:"type:varchar(8)"`  // 报告输出语言，未指定时为检测到的网页语言
+	OverallScore   int    `json:"overall_score" gorm:"type:int;default:0"`
 	OptimizedScore int    `json:"optimized_score" gorm:"type:int;default:0"` // 优化后评分
-	Status        string  `json:"status" gorm:"type:varchar(20);index"` // pending, processing, completed, failed
-	ErrorMessage  string  `json:"error_message,omitempty" gorm:"type:text"`
+	Status         string `json:"status" gorm:"type:varchar(20);index"`      // pending, processing, completed, failed
+	ErrorMessage   string `json:"error_message,omitempty" gorm:"type:text"`
 
 	// 中间结果
 	QueryFanout        string `json:"query_fanout,omitempty" gorm:"type:text"`
@@ -26,14 +31,23 @@ type GEOAnalysis struct {
 	OptimizedArticle   string `json:"optimized_article,omitempty" gorm:"type:text"`
 
 	// 统计信息
-	ContentGaps           string `json:"content_gaps,omitempty" gorm:"type:text"` // JSON 数组
+	ContentGaps             string `json:"content_gaps,omitempty" gorm:"type:text"`             // JSON 数组
 	OptimizationSuggestions string `json:"optimization_suggestions,omitempty" gorm:"type:text"` // JSON 数组
+	CompetitorAnalysis      string `json:"competitor_analysis,omitempty" gorm:"type:text"`      // JSON 格式的竞品引用分析
+	SERPFeatures            string `json:"serp_features,omitempty" gorm:"type:text"`            // JSON 格式的搜索结果页模块
+	PromptVersions          string `json:"prompt_versions,omitempty" gorm:"type:text"`          // JSON 格式的各 Agent prompt 版本
+	ResultsNormalized       bool   `json:"-" gorm:"default:false;index"`                        // 结构化结果已写入子表（查询、来源、建议等）
 
 	// 验证结果
 	ValidationResult string `json:"validation_result,omitempty" gorm:"type:text"` // JSON 格式的验证结果
+	ValidationScore  *int   `json:"validation_score,omitempty" gorm:"type:int"`   // 验证 Agent 对优化后文章的总分（0-100），无法解析时为空
+
+	// 用户评分
+	Rating        *int   `json:"rating,omitempty" gorm:"type:int"` // 1-5
+	RatingComment string `json:"rating_comment,omitempty" gorm:"type:varchar(500)"`
 
 	// 元数据
-	UserID     *int64    `json:"user_id,omitempty" gorm:"index"`
+	UserID      *int64     `json:"user_id,omitempty" gorm:"index"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
 
@@ -52,40 +66,75 @@ func (g *GEOAnalysis) BeforeCreate(tx *gorm.DB) error {
 
 // GEOAnalysisCreateRequest 创建请求
 type GEOAnalysisCreateRequest struct {
-	URL      string `json:"url" binding:"required"`
-	Platform string `json:"platform"` // 目标平台：google
+	URL            string `json:"url" binding:"required"`
+	Platform       string `json:"platform"`                                        // 目标平台：google
+	Country        string `json:"country" binding:"omitempty,max=8"`               // 目标国家代码，如 us、cn
+	Language       string `json:"language" binding:"omitempty,max=16"`             // 目标语言代码，如 en、zh-CN
+	Device         string `json:"device" binding:"omitempty,oneof=desktop mobile"` // 设备类型，默认 desktop
+	Page           int    `json:"page" binding:"omitempty,min=1,max=10"`           // 搜索结果页码，默认 1
+	OutputLanguage string `json:"output_language" binding:"omitempty,max=16"`      // 报告输出语言，zh 或 en，默认使用网页语言（其他网页语言输出英文）
+	ForceRefresh   bool   `json:"force_refresh"`                                   // 跳过缓存，重新请求搜索、爬取和 LLM
 }
 
 // GEOAnalysisListRequest 列表查询请求
 type GEOAnalysisListRequest struct {
-	Page      int     `form:"page,default=1"`
-	PageSize  int     `form:"page_size,default=10"`
-	Status    string  `form:"status"`
-	UserID    *int64  `form:"user_id"`
-	OrderBy   string  `form:"order_by,default=created_at"`
-	OrderDesc bool    `form:"order_desc,default=true"`
+	Page      int    `form:"page,default=1"`
+	PageSize  int    `form:"page_size,default=10"`
+	Status    string `form:"status"`
+	UserID    *int64 `form:"user_id"`
+	OrderBy   string `form:"order_by,default=created_at" binding:"omitempty,oneof=created_at updated_at completed_at overall_score optimized_score"`
+	OrderDesc bool   `form:"order_desc,default=true"`
+
+	// 搜索条件
+	URL       string `form:"url"`        // URL 子串
+	Domain    string `form:"domain"`     // 域名（包括子域名），如 example.com
+	Title     string `form:"title"`      // 标题子串
+	MainQuery string `form:"main_query"` // 主查询子串
+	Platform  string `form:"platform"`
+	From      string `form:"from"`      // 创建日期起始（含），YYYY-MM-DD
+	To        string `form:"to"`        // 创建日期截止（含），YYYY-MM-DD
+	MinScore  *int   `form:"min_score"` // 总评分下限（含）
+	MaxScore  *int   `form:"max_score"` // 总评分上限（含）
+	Q         string `form:"q"`         // 在 AI 概览、优化报告和优化后文章中全文搜索
+
+	// 由服务层解析 From、To 后填充
+	CreatedFrom *time.Time `form:"-"`
+	CreatedTo   *time.Time `form:"-"` // 不含
 }
 
 // GEOAnalysisResponse 响应
 type GEOAnalysisResponse struct {
-	ID             int64     `json:"id"`
-	URL            string    `json:"url"`
-	Title          string    `json:"title"`
-	MainQuery      string    `json:"main_query"`
-	Platform       string    `json:"platform"`        // 目标平台
-	OverallScore   int       `json:"overall_score"`
-	OptimizedScore int       `json:"optimized_score"` // 优化后评分
-	Status         string    `json:"status"`
-	ErrorMessage   string    `json:"error_message,omitempty"`
-	QueryFanout        string `json:"query_fanout,omitempty"`
-	AIOverview         string `json:"ai_overview,omitempty"`
-	QueryFanoutSummary string `json:"query_fanout_summary,omitempty"`
-	OptimizationReport string `json:"optimization_report,omitempty"`
-	OptimizedArticle   string `json:"optimized_article,omitempty"`
-	ContentGaps           string `json:"content_gaps,omitempty"`
-	OptimizationSuggestions string `json:"optimization_suggestions,omitempty"`
-	ValidationResult   string `json:"validation_result,omitempty"` // 验证结果
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ID                      int64               `json:"id"`
+	URL                     string              `json:"url"`
+	Title                   string              `json:"title"`
+	MainQuery               string              `json:"main_query"`
+	Platform                string              `json:"platform"` // 目标平台
+	Country                 string              `json:"country,omitempty"`
+	Language                string              `json:"language,omitempty"`
+	Device                  string              `json:"device,omitempty"`
+	Page                    int                 `json:"page,omitempty"`            // 搜索结果页码
+	OutputLanguage          string              `json:"output_language,omitempty"` // 报告输出语言
+	OverallScore            int                 `json:"overall_score"`
+	OptimizedScore          int                 `json:"optimized_score"` // 优化后评分
+	Status                  string              `json:"status"`
+	ErrorMessage            string              `json:"error_message,omitempty"`
+	QueryFanout             string              `json:"query_fanout,omitempty"`
+	AIOverview              string              `json:"ai_overview,omitempty"`
+	QueryFanoutSummary      string              `json:"query_fanout_summary,omitempty"`
+	OptimizationReport      string              `json:"optimization_report,omitempty"`
+	OptimizedArticle        string              `json:"optimized_article,omitempty"`
+	ContentGaps             string              `json:"content_gaps,omitempty"`
+	OptimizationSuggestions string              `json:"optimization_suggestions,omitempty"`
+	CompetitorAnalysis      string              `json:"competitor_analysis,omitempty"` // 竞品引用分析
+	SERPFeatures            string              `json:"serp_features,omitempty"`       // 搜索结果页模块
+	ValidationResult        string              `json:"validation_result,omitempty"`   // 验证结果
+	PromptVersions          string              `json:"prompt_versions,omitempty"`     // 各 Agent 使用的 prompt 版本
+	Rating                  *int                `json:"rating,omitempty"`              // 用户评分（1-5）
+	RatingComment           string              `json:"rating_comment,omitempty"`      // 用户评价
+	LLMUsage                *GEOLLMUsageSummary `json:"llm_usage,omitempty"`           // LLM 用量和估算费用（仅详情返回）
+	Results                 *GEOAnalysisResults `json:"results,omitempty"`             // 结构化结果：查询、来源、对比项、优化建议等（仅详情返回）
+	Highlights              map[string]string   `json:"highlights,omitempty"`          // 全文搜索命中的片段，字段名 -> 片段（仅全文搜索返回）
+	CreatedAt               time.Time           `json:"created_at"`
+	UpdatedAt               time.Time           `json:"updated_at"`
+	CompletedAt             *time.Time          `json:"completed_at,omitempty"`
 }
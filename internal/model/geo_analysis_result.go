@@ -0,0 +1,197 @@
+package model
+
+import (
+	"time"
+)
+
+// 优化建议状态常量
+const (
+	SuggestionStatusOpen       = "open"
+	SuggestionStatusInProgress = "in_progress"
+	SuggestionStatusDone       = "done"
+	SuggestionStatusWontFix    = "wont_fix"
+)
+
+// GEOAnalysisQuery 分析的查询发散结果（相关查询）
+type GEOAnalysisQuery struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"analysis_id" gorm:"not null;index"`
+	Position   int       `json:"position" gorm:"type:int"` // 在结果中的位置（从 1 开始）
+	Query      string    `json:"query" gorm:"type:varchar(500);not null;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOAnalysisQuery) TableName() string {
+	return "geo_analysis_queries"
+}
+
+// GEOAnalysisSearchResult 查询发散时的搜索结果
+type GEOAnalysisSearchResult struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"analysis_id" gorm:"not null;index"`
+	Position   int       `json:"position" gorm:"type:int"`
+	Title      string    `json:"title" gorm:"type:varchar(500)"`
+	URL        string    `json:"url" gorm:"type:varchar(1000)"`
+	Domain     string    `json:"domain" gorm:"type:varchar(255);index"`
+	Snippet    string    `json:"snippet" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOAnalysisSearchResult) TableName() string {
+	return "geo_analysis_search_results"
+}
+
+// GEOAnalysisSource AI 摘要引用的来源（合并竞品引用分析的结果）
+type GEOAnalysisSource struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"analysis_id" gorm:"not null;index"`
+	Position   int       `json:"position" gorm:"type:int"` // 在 AI 摘要引用列表中的位置
+	URL        string    `json:"url" gorm:"type:varchar(1000);not null"`
+	Domain     string    `json:"domain" gorm:"type:varchar(255);index"`
+	Title      string    `json:"title" gorm:"type:varchar(500)"`
+	Strengths  string    `json:"strengths,omitempty" gorm:"type:text"` // 被 AI 引用的可能原因
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOAnalysisSource) TableName() string {
+	return "geo_analysis_sources"
+}
+
+// GEOAnalysisContentGap 分析得出的内容差距
+type GEOAnalysisContentGap struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID int64     `json:"analysis_id" gorm:"not null;index"`
+	Position   int       `json:"position" gorm:"type:int"`
+	Gap        string    `json:"gap" gorm:"type:text;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOAnalysisContentGap) TableName() string {
+	return "geo_analysis_content_gaps"
+}
+
+// GEOAnalysisComparisonItem 优化报告对比表格的一行
+type GEOAnalysisComparisonItem struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AnalysisID  int64     `json:"analysis_id" gorm:"not null;index"`
+	Position    int       `json:"position" gorm:"type:int"`
+	Dimension   string    `json:"dimension" gorm:"type:varchar(200);not null"`
+	YourContent string    `json:"your_content" gorm:"type:text"`
+	AIOverview  string    `json:"ai_overview" gorm:"type:text"`
+	Similarity  string    `json:"similarity" gorm:"type:text"`
+	Difference  string    `json:"difference" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOAnalysisComparisonItem) TableName() string {
+	return "geo_analysis_comparison_items"
+}
+
+// GEOSuggestion 优化建议，作为待办任务跟踪状态、负责人和截止日期
+type GEOSuggestion struct {
+	BaseModel
+	AnalysisID             int64      `json:"analysis_id" gorm:"not null;index"`
+	Position               int        `json:"position" gorm:"type:int"`
+	Priority               string     `json:"priority" gorm:"type:varchar(10);index"` // high, medium, low
+	Category               string     `json:"category" gorm:"type:varchar(50);index"`
+	Issue                  string     `json:"issue,omitempty" gorm:"type:text"`
+	Suggestion             string     `json:"suggestion" gorm:"type:text;not null"`
+	Status                 string     `json:"status" gorm:"type:varchar(20);default:'open';index"` // open, in_progress, done, wont_fix
+	Assignee               string     `json:"assignee,omitempty" gorm:"type:varchar(100);index"`
+	DueDate                *time.Time `json:"due_date,omitempty" gorm:"index"`
+	CompletedAt            *time.Time `json:"completed_at,omitempty"`
+	VerificationAnalysisID *int64     `json:"verification_analysis_id,omitempty" gorm:"index"` // 完成后为验证效果发起的重新分析
+}
+
+// TableName 指定表名
+func (GEOSuggestion) TableName() string {
+	return "geo_suggestions"
+}
+
+// GEOSuggestionComment 优化建议的评论
+type GEOSuggestionComment struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SuggestionID int64     `json:"suggestion_id" gorm:"not null;index"`
+	Author       string    `json:"author,omitempty" gorm:"type:varchar(100)"`
+	Content      string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (GEOSuggestionComment) TableName() string {
+	return "geo_suggestion_comments"
+}
+
+// GEOAnalysisResults 一次分析的结构化结果（各子表的记录）
+type GEOAnalysisResults struct {
+	Queries         []GEOAnalysisQuery          `json:"queries"`
+	SearchResults   []GEOAnalysisSearchResult   `json:"search_results"`
+	Sources         []GEOAnalysisSource         `json:"sources"`
+	ContentGaps     []GEOAnalysisContentGap     `json:"content_gaps"`
+	ComparisonItems []GEOAnalysisComparisonItem `json:"comparison_items"`
+	Suggestions     []GEOSuggestion             `json:"suggestions"`
+}
+
+// GEOSuggestionListRequest 跨分析查询优化建议的请求
+type GEOSuggestionListRequest struct {
+	Page       int    `form:"page,default=1"`
+	PageSize   int    `form:"page_size,default=20"`
+	AnalysisID *int64 `form:"analysis_id"`
+	Priority   string `form:"priority" binding:"omitempty,oneof=high medium low"`
+	Category   string `form:"category"`
+	Status     string `form:"status" binding:"omitempty,oneof=open in_progress done wont_fix"`
+	Assignee   string `form:"assignee"`
+	Overdue    bool   `form:"overdue"` // 只返回已过截止日期且未完成的建议
+	Keyword    string `form:"keyword"` // 在问题和建议中搜索
+	UserID     *int64 `form:"-"`
+}
+
+// GEOSuggestionUpdateRequest 更新优化建议请求（未传入的字段不修改）
+type GEOSuggestionUpdateRequest struct {
+	Status   *string `json:"status" binding:"omitempty,oneof=open in_progress done wont_fix"`
+	Assignee *string `json:"assignee" binding:"omitempty,max=100"`
+	DueDate  *string `json:"due_date"` // YYYY-MM-DD，空字符串清除截止日期
+}
+
+// GEOSuggestionCommentRequest 添加评论请求
+type GEOSuggestionCommentRequest struct {
+	Author  string `json:"author" binding:"omitempty,max=100"`
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+// GEOSuggestionCompleteRequest 标记建议完成请求
+type GEOSuggestionCompleteRequest struct {
+	Comment   string `json:"comment" binding:"omitempty,max=2000"` // 完成说明，作为评论保存
+	Author    string `json:"author" binding:"omitempty,max=100"`
+	Reanalyze *bool  `json:"reanalyze"` // 是否重新分析以验证评分是否提高，默认 true
+}
+
+// GEOSuggestionResponse 优化建议响应（附带所属分析的 URL、标题和评分）
+type GEOSuggestionResponse struct {
+	GEOSuggestion
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	OverallScore int    `json:"overall_score"`
+}
+
+// GEOSuggestionVerification 完成建议后重新分析的验证结果
+type GEOSuggestionVerification struct {
+	AnalysisID    int64  `json:"analysis_id"`
+	Status        string `json:"status"`         // 重新分析的状态
+	BaselineScore int    `json:"baseline_score"` // 原分析的评分
+	Score         int    `json:"score"`          // 重新分析的评分（完成后）
+	ScoreDelta    int    `json:"score_delta"`
+	Improved      *bool  `json:"improved,omitempty"` // 重新分析完成后评分是否提高
+}
+
+// GEOSuggestionDetailResponse 优化建议详情
+type GEOSuggestionDetailResponse struct {
+	GEOSuggestionResponse
+	Comments     []GEOSuggestionComment     `json:"comments"`
+	Verification *GEOSuggestionVerification `json:"verification,omitempty"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// GEOLLMUsage 单次分析中某个 Agent 使用某个模型的 LLM 用量
+type GEOLLMUsage struct {
+	ID               int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	AnalysisID       int64     `json:"-" gorm:"not null;index"`
+	UserID           *int64    `json:"-" gorm:"index"`
+	Agent            string    `json:"agent" gorm:"type:varchar(50)"`
+	Model            string    `json:"model" gorm:"type:varchar(100)"`
+	Calls            int       `json:"calls" gorm:"type:int;default:0"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"type:int;default:0"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"type:int;default:0"`
+	TotalTokens      int       `json:"total_tokens" gorm:"type:int;default:0"`
+	LatencyMs        int64     `json:"latency_ms" gorm:"default:0"`
+	Cost             float64   `json:"cost" gorm:"default:0"`
+	Currency         string    `json:"currency" gorm:"type:varchar(8)"`
+	EstimatedCalls   int       `json:"estimated_calls" gorm:"type:int;default:0"` // 按字数估算 token 的调用次数
+	CreatedAt        time.Time `json:"-" gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (GEOLLMUsage) TableName() string {
+	return "geo_llm_usages"
+}
+
+// GEOLLMUsageSummary 单次分析的 LLM 用量汇总
+type GEOLLMUsageSummary struct {
+	Calls            int           `json:"calls"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	TotalTokens      int           `json:"total_tokens"`
+	LatencyMs        int64         `json:"latency_ms"`
+	Cost             float64       `json:"cost"`
+	Currency         string        `json:"currency"`
+	Agents           []GEOLLMUsage `json:"agents"`
+}
+
+// GEOUsageSummaryRequest 用量汇总查询请求
+type GEOUsageSummaryRequest struct {
+	From   string `form:"from"` // 起始日期 YYYY-MM-DD，默认 30 天前
+	To     string `form:"to"`   // 结束日期 YYYY-MM-DD，默认今天
+	UserID *int64 `form:"user_id"`
+}
+
+// GEOModelUsage 按模型汇总的用量
+type GEOModelUsage struct {
+	Model            string  `json:"model"`
+	Currency         string  `json:"currency"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// GEOUserUsage 单个用户的用量汇总
+type GEOUserUsage struct {
+	UserID           *int64             `json:"user_id"` // 为空表示未登录用户
+	Analyses         int                `json:"analyses"`
+	Calls            int                `json:"calls"`
+	PromptTokens     int                `json:"prompt_tokens"`
+	CompletionTokens int                `json:"completion_tokens"`
+	TotalTokens      int                `json:"total_tokens"`
+	Cost             map[string]float64 `json:"cost"` // 按币种汇总的估算费用
+	Models           []GEOModelUsage    `json:"models"`
+}
+
+// GEOUsageSummaryResponse 用量汇总响应
+type GEOUsageSummaryResponse struct {
+	From  string         `json:"from"`
+	To    string         `json:"to"`
+	Users []GEOUserUsage `json:"users"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// GEOPrompt prompt 模板版本，同一名称最多一个激活版本
+type GEOPrompt struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_geo_prompts_name_version"`
+	Version   int       `json:"version" gorm:"type:int;not null;uniqueIndex:idx_geo_prompts_name_version"`
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	Comment   string    `json:"comment,omitempty" gorm:"type:varchar(255)"`
+	Active    bool      `json:"active" gorm:"index"`
+	CreatedBy *int64    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (GEOPrompt) TableName() string {
+	return "geo_prompts"
+}
+
+// PromptCreateRequest 创建 prompt 版本请求
+type PromptCreateRequest struct {
+	Content  string `json:"content" binding:"required"`
+	Comment  string `json:"comment" binding:"max=255"`
+	Activate *bool  `json:"activate"` // 是否立即激活，默认 true
+}
+
+// PromptSummary prompt 概览
+type PromptSummary struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`   // 生效来源：builtin、file、db
+	Version  string `json:"version"`  // 生效版本
+	Versions int    `json:"versions"` // 数据库中的版本数
+}
+
+// PromptDetail prompt 详情
+type PromptDetail struct {
+	Name     string      `json:"name"`
+	Source   string      `json:"source"`
+	Version  string      `json:"version"`
+	Content  string      `json:"content"`        // 生效的内容
+	Path     string      `json:"path,omitempty"` // 来自覆盖目录时的文件路径
+	Builtin  string      `json:"builtin"`        // 内嵌的默认内容
+	Versions []GEOPrompt `json:"versions"`       // 数据库中的版本，按版本号倒序
+}
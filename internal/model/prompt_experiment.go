@@ -0,0 +1,103 @@
+package model
+
+import "time"
+
+// 实验状态
+const (
+	ExperimentStatusRunning = "running"
+	ExperimentStatusStopped = "stopped"
+)
+
+// GEOPromptExperiment prompt A/B 实验，同一 prompt 同时最多一个进行中的实验
+type GEOPromptExperiment struct {
+	ID          int64                        `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string                       `json:"name" gorm:"type:varchar(100);not null"`
+	Prompt      string                       `json:"prompt" gorm:"type:varchar(50);not null;index"` // prompt 名称（Agent 名称）
+	Description string                       `json:"description,omitempty" gorm:"type:text"`
+	Status      string                       `json:"status" gorm:"type:varchar(20);index"` // running, stopped
+	Variants    []GEOPromptExperimentVariant `json:"variants" gorm:"foreignKey:ExperimentID"`
+	StoppedAt   *time.Time                   `json:"stopped_at,omitempty"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (GEOPromptExperiment) TableName() string {
+	return "geo_prompt_experiments"
+}
+
+// GEOPromptExperimentVariant 实验变体
+type GEOPromptExperimentVariant struct {
+	ID            int64  `json:"-" gorm:"primaryKey;autoIncrement"`
+	ExperimentID  int64  `json:"-" gorm:"not null;index"`
+	Key           string `json:"key" gorm:"type:varchar(20);not null"`     // 变体标识，如 A、B
+	PromptVersion int    `json:"prompt_version" gorm:"type:int;default:0"` // 数据库中的 prompt 版本号，0 表示当前生效的 prompt
+	Weight        int    `json:"weight" gorm:"type:int;not null"`          // 分配比例（百分比）
+}
+
+// TableName 指定表名
+func (GEOPromptExperimentVariant) TableName() string {
+	return "geo_prompt_experiment_variants"
+}
+
+// GEOPromptExperimentAssignment 分析被分配到的实验变体
+type GEOPromptExperimentAssignment struct {
+	ID            int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	ExperimentID  int64     `json:"experiment_id" gorm:"not null;index"`
+	AnalysisID    int64     `json:"analysis_id" gorm:"not null;index"`
+	VariantKey    string    `json:"variant_key" gorm:"type:varchar(20)"`
+	PromptVersion string    `json:"prompt_version" gorm:"type:varchar(50)"` // 实际使用的 prompt 版本
+	ParseFailures int       `json:"parse_failures" gorm:"type:int;default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (GEOPromptExperimentAssignment) TableName() string {
+	return "geo_prompt_experiment_assignments"
+}
+
+// PromptExperimentCreateRequest 创建实验请求
+type PromptExperimentCreateRequest struct {
+	Name        string                           `json:"name" binding:"required,max=100"`
+	Prompt      string                           `json:"prompt" binding:"required"`
+	Description string                           `json:"description"`
+	Variants    []PromptExperimentVariantRequest `json:"variants" binding:"required,min=2,dive"`
+}
+
+// PromptExperimentVariantRequest 实验变体请求
+type PromptExperimentVariantRequest struct {
+	Key           string `json:"key" binding:"required,max=20"`
+	PromptVersion int    `json:"prompt_version" binding:"min=0"`
+	Weight        int    `json:"weight" binding:"required,min=1,max=100"`
+}
+
+// PromptExperimentResults 实验结果对比
+type PromptExperimentResults struct {
+	Experiment *GEOPromptExperiment      `json:"experiment"`
+	Variants   []PromptExperimentOutcome `json:"variants"`
+}
+
+// PromptExperimentOutcome 单个变体的结果
+type PromptExperimentOutcome struct {
+	Key                string  `json:"key"`
+	PromptVersion      int     `json:"prompt_version"`
+	Weight             int     `json:"weight"`
+	Analyses           int     `json:"analyses"`             // 分配的分析数
+	Completed          int     `json:"completed"`            // 完成的分析数
+	FailureRate        float64 `json:"failure_rate"`         // 分析失败率（不含执行中）
+	AvgOverallScore    float64 `json:"avg_overall_score"`    // 完成分析的平均 overall_score（内容优化 Agent 对原页面的评分）
+	Validated          int     `json:"validated"`            // 有验证评分的完成分析数
+	AvgValidationScore float64 `json:"avg_validation_score"` // 验证 Agent 对优化后文章的平均总分（0-100）
+	ParseFailureRate   float64 `json:"parse_failure_rate"`   // 模型输出解析失败的分析占比
+	AvgTokens          float64 `json:"avg_tokens"`           // 该 Agent 每次分析的平均 token 数
+	AvgCost            float64 `json:"avg_cost"`             // 该 Agent 每次分析的平均估算费用
+	Currency           string  `json:"currency,omitempty"`
+	Ratings            int     `json:"ratings"`    // 用户评分数
+	AvgRating          float64 `json:"avg_rating"` // 平均用户评分（1-5）
+}
+
+// GEOAnalysisRatingRequest 分析评分请求
+type GEOAnalysisRatingRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=500"`
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"time"
+)
+
+// QuerySet 引用份额（share of voice）追踪的查询集
+type QuerySet struct {
+	BaseModel
+	Name              string     `json:"name" gorm:"type:varchar(100);not null"`
+	Queries           string     `json:"-" gorm:"type:text;not null"` // JSON 数组：目标查询
+	Domains           string     `json:"-" gorm:"type:text;not null"` // JSON 数组：我方域名
+	CompetitorDomains string     `json:"-" gorm:"type:text"`          // JSON 数组：重点关注的竞品域名（可选）
+	Platform          string     `json:"platform" gorm:"type:varchar(20);default:'google'"`
+	IntervalMinutes   int        `json:"interval_minutes" gorm:"type:int;default:1440"` // 采集间隔（分钟）
+	Enabled           bool       `json:"enabled" gorm:"default:true;index"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	UserID            *int64     `json:"user_id,omitempty" gorm:"index"`
+}
+
+// TableName 指定表名
+func (QuerySet) TableName() string {
+	return "geo_query_sets"
+}
+
+// 查询集采集状态常量
+const (
+	QuerySetRunRunning   = "running"
+	QuerySetRunCompleted = "completed"
+	QuerySetRunFailed    = "failed"
+	QuerySetRunCancelled = "cancelled" // 服务关闭时中断，不计入引用份额
+)
+
+// QuerySetRun 查询集的一次采集
+type QuerySetRun struct {
+	BaseModel
+	QuerySetID      int64      `json:"query_set_id" gorm:"not null;index"`
+	Status          string     `json:"status" gorm:"type:varchar(20);index"` // running, completed, failed, cancelled
+	QueriesTotal    int        `json:"queries_total" gorm:"type:int;default:0"`
+	QueriesAnswered int        `json:"queries_answered" gorm:"type:int;default:0"` // 成功获取 AI 回答的查询数
+	QueriesFailed   int        `json:"queries_failed" gorm:"type:int;default:0"`
+	ErrorMessage    string     `json:"error_message,omitempty" gorm:"type:text"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableName 指定表名
+func (QuerySetRun) TableName() string {
+	return "geo_query_set_runs"
+}
+
+// CitationSnapshot AI 回答中的一次域名引用记录
+type CitationSnapshot struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	QuerySetID int64     `json:"query_set_id" gorm:"not null;index:idx_citation_set_time"`
+	RunID      int64     `json:"run_id" gorm:"not null;index"`
+	Query      string    `json:"query" gorm:"type:varchar(500);not null"`
+	Domain     string    `json:"domain" gorm:"type:varchar(255);not null;index"`
+	URL        string    `json:"url" gorm:"type:varchar(1000)"`
+	Position   int       `json:"position" gorm:"type:int"` // 在 AI 回答引用列表中的位置（从 1 开始）
+	CitedAt    time.Time `json:"cited_at" gorm:"not null;index:idx_citation_set_time"`
+}
+
+// TableName 指定表名
+func (CitationSnapshot) TableName() string {
+	return "geo_citation_snapshots"
+}
+
+// QuerySetCreateRequest 创建查询集请求
+type QuerySetCreateRequest struct {
+	Name              string   `json:"name" binding:"required,max=100"`
+	Queries           []string `json:"queries" binding:"required,min=1,max=500"`
+	Domains           []string `json:"domains" binding:"required,min=1"`
+	CompetitorDomains []string `json:"competitor_domains"`
+	Platform          string   `json:"platform"`
+	IntervalMinutes   int      `json:"interval_minutes" binding:"omitempty,min=5"`
+}
+
+// QuerySetUpdateRequest 更新查询集请求
+type QuerySetUpdateRequest struct {
+	Name              string   `json:"name" binding:"omitempty,max=100"`
+	Queries           []string `json:"queries" binding:"omitempty,min=1,max=500"`
+	Domains           []string `json:"domains" binding:"omitempty,min=1"`
+	CompetitorDomains []string `json:"competitor_domains"`
+	IntervalMinutes   int      `json:"interval_minutes" binding:"omitempty,min=5"`
+	Enabled           *bool    `json:"enabled"`
+}
+
+// QuerySetResponse 查询集响应
+type QuerySetResponse struct {
+	ID                int64      `json:"id"`
+	Name              string     `json:"name"`
+	Queries           []string   `json:"queries"`
+	Domains           []string   `json:"domains"`
+	CompetitorDomains []string   `json:"competitor_domains"`
+	Platform          string     `json:"platform"`
+	IntervalMinutes   int        `json:"interval_minutes"`
+	Enabled           bool       `json:"enabled"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ShareOfVoiceRequest 引用份额趋势查询请求
+type ShareOfVoiceRequest struct {
+	From        string `form:"from"`                                  // 起始日期 YYYY-MM-DD，默认 30 天前
+	To          string `form:"to"`                                    // 结束日期 YYYY-MM-DD，默认今天
+	Granularity string `form:"granularity,default=day"`               // day, week
+	Top         int    `form:"top,default=10" binding:"min=1,max=50"` // 除我方和重点竞品外，额外返回的竞品域名数量
+}
+
+// ShareOfVoicePoint 某个时间桶内单个域名的引用份额
+type ShareOfVoicePoint struct {
+	Period      string  `json:"period"`       // 时间桶（日期或周一日期）
+	Citations   int     `json:"citations"`    // 引用次数
+	Share       float64 `json:"share"`        // 引用份额：该域名引用数 / 全部引用数（%）
+	Coverage    float64 `json:"coverage"`     // 覆盖率：引用该域名的查询数 / 有 AI 回答的查询数（%）
+	AvgPosition float64 `json:"avg_position"` // 平均引用位置
+}
+
+// DomainShareOfVoice 单个域名的引用份额趋势
+type DomainShareOfVoice struct {
+	Domain      string              `json:"domain"`
+	Role        string              `json:"role"` // own, competitor, other
+	Citations   int                 `json:"citations"`
+	Share       float64             `json:"share"`
+	Coverage    float64             `json:"coverage"`
+	AvgPosition float64             `json:"avg_position"`
+	Trend       []ShareOfVoicePoint `json:"trend"`
+}
+
+// ShareOfVoiceResponse 引用份额趋势响应
+type ShareOfVoiceResponse struct {
+	QuerySetID      int64                `json:"query_set_id"`
+	From            string               `json:"from"`
+	To              string               `json:"to"`
+	Granularity     string               `json:"granularity"`
+	Runs            int                  `json:"runs"`
+	QueriesAnswered int                  `json:"queries_answered"`
+	TotalCitations  int                  `json:"total_citations"`
+	Domains         []DomainShareOfVoice `json:"domains"`
+}
+
+// 域名角色常量
+const (
+	DomainRoleOwn        = "own"
+	DomainRoleCompetitor = "competitor"
+	DomainRoleOther      = "other"
+)
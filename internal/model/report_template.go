@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// GEOReportTemplate 工作区的白标报告模板，用于所有导出格式
+type GEOReportTemplate struct {
+	BaseModel
+	Workspace string `json:"workspace" gorm:"type:varchar(64);not null;uniqueIndex"`
+	BrandName string `json:"brand_name" gorm:"type:varchar(100)"`
+	Color     string `json:"color" gorm:"type:varchar(7)"` // 主色，#RRGGBB
+	LogoURL   string `json:"logo_url,omitempty" gorm:"type:varchar(500)"`
+	Logo      []byte `json:"-"` // 保存时下载的 Logo（PNG），导出 PDF 和 DOCX 时嵌入
+	Header    string `json:"header,omitempty" gorm:"type:varchar(200)"`
+	Footer    string `json:"footer,omitempty" gorm:"type:varchar(200)"`
+	Sections  string `json:"-" gorm:"type:text"`              // JSON 数组：章节顺序
+	Language  string `json:"language" gorm:"type:varchar(8)"` // 为空时使用分析的输出语言
+}
+
+// TableName 指定表名
+func (GEOReportTemplate) TableName() string {
+	return "geo_report_templates"
+}
+
+// ReportTemplateRequest 创建或更新报告模板请求（整体替换）
+type ReportTemplateRequest struct {
+	BrandName string   `json:"brand_name" binding:"required,max=100"`
+	Color     string   `json:"color"` // 默认 #2563eb
+	LogoURL   string   `json:"logo_url" binding:"omitempty,url,max=500"`
+	Header    string   `json:"header" binding:"max=200"` // 页眉文字，默认为品牌名称
+	Footer    string   `json:"footer" binding:"max=200"`
+	Sections  []string `json:"sections"` // 章节顺序，默认全部章节
+	Language  string   `json:"language"` // zh、en 等，为空时使用分析的输出语言
+}
+
+// ReportTemplatePreviewRequest 预览报告模板请求
+type ReportTemplatePreviewRequest struct {
+	ReportTemplateRequest
+	AnalysisID int64  `json:"analysis_id"` // 使用的分析，为 0 时使用示例报告
+	Format     string `json:"format"`      // 默认 html
+}
+
+// ReportTemplateResponse 报告模板响应
+type ReportTemplateResponse struct {
+	ID        int64     `json:"id"`
+	Workspace string    `json:"workspace"`
+	BrandName string    `json:"brand_name"`
+	Color     string    `json:"color"`
+	LogoURL   string    `json:"logo_url,omitempty"`
+	HasLogo   bool      `json:"has_logo"` // 是否已保存 Logo 图片
+	Header    string    `json:"header,omitempty"`
+	Footer    string    `json:"footer,omitempty"`
+	Sections  []string  `json:"sections"`
+	Language  string    `json:"language"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// DomainStatsRequest 域名统计查询请求
+type DomainStatsRequest struct {
+	From        string `form:"from"`                                  // 起始日期 YYYY-MM-DD，默认 90 天前
+	To          string `form:"to"`                                    // 结束日期 YYYY-MM-DD，默认今天
+	Granularity string `form:"granularity,default=week"`              // 趋势时间粒度：day, week, month
+	Top         int    `form:"top,default=10" binding:"min=1,max=50"` // 内容差距和最好/最差页面的返回数量
+	UserID      *int64 `form:"-"`
+}
+
+// DomainSummary 单个域名在时间范围内的分析汇总
+type DomainSummary struct {
+	Domain         string     `json:"domain"`
+	Analyses       int        `json:"analyses"`  // 分析次数（含失败和进行中）
+	Completed      int        `json:"completed"` // 已完成的分析次数
+	Failed         int        `json:"failed"`    // 失败的分析次数
+	Pages          int        `json:"pages"`     // 分析过的页面数（按 URL 去重）
+	AvgScore       float64    `json:"avg_score"` // 已完成分析的平均总评分
+	LastAnalyzedAt *time.Time `json:"last_analyzed_at,omitempty"`
+}
+
+// DomainOverviewResponse 所有域名的汇总
+type DomainOverviewResponse struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Domains []DomainSummary `json:"domains"` // 按分析次数降序
+}
+
+// DomainScorePoint 某个时间桶内的评分
+type DomainScorePoint struct {
+	Period    string  `json:"period"`    // 时间桶（日期、周一日期或月初日期）
+	Analyses  int     `json:"analyses"`  // 分析次数
+	Completed int     `json:"completed"` // 已完成的分析次数
+	AvgScore  float64 `json:"avg_score"`
+}
+
+// ContentGapCount 内容差距出现的页面数（忽略大小写和多余空白）
+type ContentGapCount struct {
+	Gap   string `json:"gap"`
+	Pages int    `json:"pages"`
+}
+
+// MissingDimension 优化建议指出的薄弱维度（authority、freshness 等建议类别）
+type MissingDimension struct {
+	Dimension    string  `json:"dimension"`     // 建议类别
+	Pages        int     `json:"pages"`         // 有该类别建议的页面数
+	Share        float64 `json:"share"`         // 占有分析结果页面的比例（%）
+	HighPriority int     `json:"high_priority"` // 有该类别高优先级建议的页面数
+}
+
+// PageScore 页面最近一次完成的分析评分
+type PageScore struct {
+	URL        string    `json:"url"`
+	Title      string    `json:"title,omitempty"`
+	AnalysisID int64     `json:"analysis_id"`
+	Score      int       `json:"score"`
+	Analyses   int       `json:"analyses"` // 时间范围内该页面已完成的分析次数
+	AnalyzedAt time.Time `json:"analyzed_at"`
+}
+
+// DomainDashboardResponse 单个域名的统计看板
+// 内容差距、薄弱维度和最好/最差页面只统计每个页面最近一次完成的分析
+type DomainDashboardResponse struct {
+	DomainSummary
+	From              string             `json:"from"`
+	To                string             `json:"to"`
+	Granularity       string             `json:"granularity"`
+	Trend             []DomainScorePoint `json:"trend"`
+	ContentGaps       []ContentGapCount  `json:"content_gaps"`
+	MissingDimensions []MissingDimension `json:"missing_dimensions"`
+	TopPages          []PageScore        `json:"top_pages"`
+	BottomPages       []PageScore        `json:"bottom_pages"`
+}
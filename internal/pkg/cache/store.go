@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store 字节级缓存存储，供外部 API 响应缓存使用
+type Store interface {
+	// Load 读取缓存，未命中或已过期时返回 false
+	Load(ctx context.Context, key string) ([]byte, bool, error)
+	// Save 写入缓存，ttl <= 0 表示不过期
+	Save(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Load 实现 Store 接口
+func (r *Redis) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Save 实现 Store 接口
+func (r *Redis) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// memoryEntry 内存缓存条目
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore 进程内 LRU 缓存
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // 队首为最近使用
+}
+
+// NewMemoryStore 创建内存 LRU 缓存，capacity 为最大条目数
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemoryStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Load 实现 Store 接口
+func (m *MemoryStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.order.Remove(elem)
+		delete(m.items, key)
+		return nil, false, nil
+	}
+	m.order.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+// Save 实现 Store 接口
+func (m *MemoryStore) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := m.items[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len 返回当前条目数
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Key 根据命名空间和请求内容生成内容寻址的缓存键
+func Key(namespace string, parts ...any) string {
+	h := sha256.New()
+	for _, p := range parts {
+		data, _ := json.Marshal(p)
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return namespace + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// forceRefreshKey 是强制刷新标记的上下文键
+type forceRefreshKey struct{}
+
+// WithForceRefresh 标记本次调用跳过缓存读取（结果仍会写入缓存）
+func WithForceRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceRefreshKey{}, true)
+}
+
+// IsForceRefresh 是否要求跳过缓存读取
+func IsForceRefresh(ctx context.Context) bool {
+	v, _ := ctx.Value(forceRefreshKey{}).(bool)
+	return v
+}
+
+// HitCallback 缓存命中回调，source 为数据来源（如 search、llm），detail 为可读的请求描述
+type HitCallback func(source, detail string)
+
+// hitCallbackKey 是缓存命中回调的上下文键
+type hitCallbackKey struct{}
+
+// WithHitCallback 将缓存命中回调添加到上下文
+func WithHitCallback(ctx context.Context, callback HitCallback) context.Context {
+	return context.WithValue(ctx, hitCallbackKey{}, callback)
+}
+
+// ReportHit 通知上下文中的缓存命中回调
+func ReportHit(ctx context.Context, source, detail string) {
+	if cb, ok := ctx.Value(hitCallbackKey{}).(HitCallback); ok && cb != nil {
+		cb(source, detail)
+	}
+}
+
+// Fetch 读取 JSON 缓存，未命中时调用 fn 并写入缓存
+// 缓存读写失败不影响调用结果；上下文要求强制刷新时跳过读取
+func Fetch[T any](ctx context.Context, store Store, key string, ttl time.Duration, source, detail string, fn func() (T, error)) (T, error) {
+	return FetchIf(ctx, store, key, ttl, source, detail, fn, nil)
+}
+
+// FetchIf 与 Fetch 相同，但只有 keep 返回 true 的结果才写入缓存，keep 为 nil 时全部写入
+func FetchIf[T any](ctx context.Context, store Store, key string, ttl time.Duration, source, detail string, fn func() (T, error), keep func(T) bool) (T, error) {
+	if store != nil && !IsForceRefresh(ctx) {
+		if data, ok, err := store.Load(ctx, key); err == nil && ok {
+			var cached T
+			if err := json.Unmarshal(data, &cached); err == nil {
+				ReportHit(ctx, source, detail)
+				return cached, nil
+			}
+		}
+	}
+
+	result, err := fn()
+	if err != nil || store == nil || (keep != nil && !keep(result)) {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		_ = store.Save(ctx, key, data, ttl)
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMemoryStore 测试 LRU 淘汰和过期
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore(2)
+
+	_ = m.Save(ctx, "a", []byte("1"), 0)
+	_ = m.Save(ctx, "b", []byte("2"), 0)
+	if _, ok, _ := m.Load(ctx, "a"); !ok {
+		t.Fatal("Load(a) miss, want hit")
+	}
+	// a 最近被访问，写入 c 时淘汰 b
+	_ = m.Save(ctx, "c", []byte("3"), 0)
+	if _, ok, _ := m.Load(ctx, "b"); ok {
+		t.Error("Load(b) hit, want evicted")
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	_ = m.Save(ctx, "d", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok, _ := m.Load(ctx, "d"); ok {
+		t.Error("Load(d) hit, want expired")
+	}
+}
+
+// TestFetch 测试缓存命中上报和强制刷新
+func TestFetch(t *testing.T) {
+	store := NewMemoryStore(10)
+	hits := 0
+	ctx := WithHitCallback(context.Background(), func(source, detail string) { hits++ })
+
+	calls := 0
+	fn := func() (string, error) {
+		calls++
+		return "value", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		v, err := Fetch(ctx, store, "k", time.Hour, "test", "k", fn)
+		if err != nil || v != "value" {
+			t.Fatalf("Fetch() = %q, %v", v, err)
+		}
+	}
+	if calls != 1 || hits != 1 {
+		t.Errorf("calls = %d, hits = %d, want 1, 1", calls, hits)
+	}
+
+	if _, err := Fetch(WithForceRefresh(ctx), store, "k", time.Hour, "test", "k", fn); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d after force refresh, want 2", calls)
+	}
+}
+
+// TestKey 测试缓存键与请求内容一一对应
+func TestKey(t *testing.T) {
+	if Key("ns", "a", 1) != Key("ns", "a", 1) {
+		t.Error("Key() not deterministic")
+	}
+	if Key("ns", "ab", "c") == Key("ns", "a", "bc") {
+		t.Error("Key() collides on different parts")
+	}
+}
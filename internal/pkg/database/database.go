@@ -0,0 +1,42 @@
+// Package database 提供数据库连接管理
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/config"
+)
+
+// DB 数据库连接，SQLite 和 PostgreSQL 共用
+type DB interface {
+	// DB 获取 GORM DB 实例
+	DB() *gorm.DB
+	// Ping 测试连接
+	Ping(ctx context.Context) error
+	// Close 关闭数据库连接
+	Close()
+}
+
+var (
+	_ DB = (*SQLite)(nil)
+	_ DB = (*PostgreSQL)(nil)
+)
+
+// New 根据配置的数据库类型创建连接，未配置时使用 SQLite
+func New(cfg *config.DatabaseConfig) (DB, error) {
+	switch cfg.Driver {
+	case "", config.DriverSQLite:
+		path := cfg.Path
+		if path == "" {
+			path = "peanut.db"
+		}
+		return NewSQLite(path)
+	case config.DriverPostgres:
+		return NewPostgreSQL(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.Driver)
+	}
+}
@@ -1,9 +1,9 @@
-// Package database 提供 PostgreSQL 数据库连接管理
 package database
 
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -12,6 +12,14 @@ import (
 	"github.com/solariswu/peanut/internal/config"
 )
 
+// 连接池默认参数
+const (
+	defaultMaxConns        = 10
+	defaultMinConns        = 2
+	defaultConnMaxLifetime = time.Hour
+	defaultConnMaxIdleTime = 30 * time.Minute
+)
+
 // PostgreSQL 数据库连接
 type PostgreSQL struct {
 	db *gorm.DB
@@ -33,11 +41,25 @@ func NewPostgreSQL(cfg *config.DatabaseConfig) (*PostgreSQL, error) {
 		return nil, fmt.Errorf("获取数据库连接失败: %w", err)
 	}
 
-	// 设置连接池参数
-	sqlDB.SetMaxOpenConns(int(cfg.MaxConns))
-	sqlDB.SetMaxIdleConns(int(cfg.MinConns))
-	sqlDB.SetConnMaxLifetime(cfg.MaxConnLifetime)
-	sqlDB.SetConnMaxIdleTime(cfg.MaxConnIdleTime)
+	// 设置连接池参数，未配置时使用默认值
+	maxConns, minConns := int(cfg.MaxConns), int(cfg.MinConns)
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+	if minConns <= 0 || minConns > maxConns {
+		minConns = min(defaultMinConns, maxConns)
+	}
+	lifetime, idleTime := cfg.MaxConnLifetime, cfg.MaxConnIdleTime
+	if lifetime <= 0 {
+		lifetime = defaultConnMaxLifetime
+	}
+	if idleTime <= 0 {
+		idleTime = defaultConnMaxIdleTime
+	}
+	sqlDB.SetMaxOpenConns(maxConns)
+	sqlDB.SetMaxIdleConns(minConns)
+	sqlDB.SetConnMaxLifetime(lifetime)
+	sqlDB.SetConnMaxIdleTime(idleTime)
 
 	// 测试连接
 	if err := sqlDB.Ping(); err != nil {
@@ -1,8 +1,8 @@
-// Package database 提供 SQLite 数据库连接管理
 package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"gorm.io/driver/sqlite"
@@ -10,6 +10,9 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// ErrFTS5Unavailable SQLite 驱动未编译 FTS5，全文搜索迁移无法执行
+var ErrFTS5Unavailable = errors.New("SQLite 未启用 FTS5，请使用 -tags sqlite_fts5 构建")
+
 // SQLite 数据库连接
 type SQLite struct {
 	db *gorm.DB
@@ -31,7 +34,7 @@ func NewSQLite(dsn string) (*SQLite, error) {
 		return nil, fmt.Errorf("获取数据库连接失败: %w", err)
 	}
 
-	// 设置连接池参数（SQLite 单连接）
+	// 设置连接池参数（SQLite 单连接，写入串行，不使用 max_conns 等配置）
 	sqlDB.SetMaxOpenConns(1)
 	sqlDB.SetMaxIdleConns(1)
 
@@ -40,6 +43,13 @@ func NewSQLite(dsn string) (*SQLite, error) {
 		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 
+	// 全文搜索依赖 FTS5，mattn/go-sqlite3 默认不编译
+	var fts5 bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil || !fts5 {
+		sqlDB.Close()
+		return nil, ErrFTS5Unavailable
+	}
+
 	return &SQLite{db: db}, nil
 }
 
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultBufferSize 单次分析缓冲的最大日志条数
+const DefaultBufferSize = 2000
+
+// Entry 一条缓冲的日志
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Buffer 在内存中保存最近的日志（超过上限时丢弃最早的条目），并发安全
+// 缓冲记录所有级别的日志，不受全局日志级别影响，便于排查问题
+type Buffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	limit   int
+	dropped int
+}
+
+// NewBuffer 创建日志缓冲，limit 不大于 0 时使用 DefaultBufferSize
+func NewBuffer(limit int) *Buffer {
+	if limit <= 0 {
+		limit = DefaultBufferSize
+	}
+	return &Buffer{limit: limit}
+}
+
+// Entries 返回缓冲中的日志
+func (b *Buffer) Entries() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]Entry, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+// Dropped 返回因超过上限被丢弃的条数
+func (b *Buffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// Tee 返回同时写入 logger 和缓冲的日志器
+func (b *Buffer) Tee(logger *zap.Logger) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, &bufferCore{buf: b})
+	}))
+}
+
+// add 追加一条日志
+func (b *Buffer) add(entry Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) >= b.limit {
+		b.entries = b.entries[1:]
+		b.dropped++
+	}
+	b.entries = append(b.entries, entry)
+}
+
+// bufferCore 将日志写入 Buffer 的 zapcore.Core
+type bufferCore struct {
+	buf    *Buffer
+	fields []zapcore.Field
+}
+
+// Enabled 记录所有级别
+func (c *bufferCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With 返回追加字段后的 Core
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &bufferCore{buf: c.buf, fields: merged}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *bufferCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+// Write 将日志及字段写入缓冲
+func (c *bufferCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	e := Entry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+	}
+	if len(enc.Fields) > 0 {
+		e.Fields = enc.Fields
+	}
+	c.buf.add(e)
+	return nil
+}
+
+// Sync 实现 zapcore.Core 接口
+func (c *bufferCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBuffer_Tee(t *testing.T) {
+	buf := NewBuffer(2)
+	// 缓冲不受原日志器级别影响
+	logger := buf.Tee(zap.NewNop()).With(zap.Int64("analysis_id", 7))
+	ctx := With(WithLogger(context.Background(), logger), zap.String("agent", "title_scraper"))
+
+	FromContext(ctx).Debug("first")
+	FromContext(ctx).Info("second", zap.Int("step", 1))
+	FromContext(ctx).Warn("third")
+
+	entries := buf.Entries()
+	if len(entries) != 2 || buf.Dropped() != 1 {
+		t.Fatalf("entries = %d, dropped = %d, want 2 and 1", len(entries), buf.Dropped())
+	}
+	if entries[0].Message != "second" || entries[0].Level != "info" {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	fields := entries[0].Fields
+	if fields["analysis_id"] != int64(7) || fields["agent"] != "title_scraper" || fields["step"] != int64(1) {
+		t.Errorf("fields = %v", fields)
+	}
+	if entries[1].Level != "warn" {
+		t.Errorf("entries[1].Level = %s, want warn", entries[1].Level)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Errorf("RequestIDFromContext = %q, want req-1", got)
+	}
+}
@@ -0,0 +1,44 @@
+// Package logging 提供随上下文传递的 zap 日志器和按分析缓冲的日志
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// loggerKey 是日志器的上下文键
+type loggerKey struct{}
+
+// requestIDKey 是请求 ID 的上下文键
+type requestIDKey struct{}
+
+// WithLogger 在上下文中设置日志器
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 返回上下文中的日志器，未设置时返回全局日志器 zap.L()
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return zap.L()
+}
+
+// With 为上下文中的日志器追加字段
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(fields...))
+}
+
+// WithRequestID 在上下文中设置请求 ID，并为日志器追加 request_id 字段
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return With(ctx, zap.String("request_id", requestID))
+}
+
+// RequestIDFromContext 返回上下文中的请求 ID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
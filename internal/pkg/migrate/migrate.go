@@ -0,0 +1,308 @@
+// Package migrate 提供内嵌的版本化数据库迁移（SQLite 和 PostgreSQL）
+//
+// 迁移文件位于 migrations/<dialect>/<版本>_<名称>.up.sql 和 .down.sql，
+// 已执行的版本记录在 schema_migrations 表中，每个迁移在一个事务中执行。
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// ErrSchemaOutdated 数据库有未执行的迁移
+var ErrSchemaOutdated = errors.New("数据库结构版本落后，需要执行迁移")
+
+// ErrSchemaTooNew 数据库执行过当前程序不认识的迁移
+var ErrSchemaTooNew = errors.New("数据库结构版本高于当前程序")
+
+// ErrUnsupportedDialect 不支持的数据库类型
+var ErrUnsupportedDialect = errors.New("不支持的数据库类型")
+
+// migrationFile 迁移文件名：0001_init.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"` // 未执行时为空
+}
+
+// appliedMigration schema_migrations 表中的记录
+type appliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator 数据库迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New 根据数据库类型加载内嵌迁移
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load 加载指定数据库类型（sqlite、postgres）的内嵌迁移，按版本升序返回
+func Load(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, dialect)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		m := migrationFile.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("迁移文件名格式错误: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件失败: %w", err)
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s 和 %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 或 down 文件", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest 返回最新的迁移版本
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// ensureTable 创建迁移记录表
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if err := m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`).Error; err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+	return nil
+}
+
+// Legacy 数据库是否由 AutoMigrate 创建：已有数据表但没有迁移记录表
+func (m *Migrator) Legacy(ctx context.Context) bool {
+	migrator := m.db.WithContext(ctx).Migrator()
+	return !migrator.HasTable("schema_migrations") && migrator.HasTable("geo_analyses")
+}
+
+// createTable 初始迁移中的建表语句，每行一个列定义
+var createTable = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*?)\n\);`)
+
+// UpgradeLegacy 按初始迁移（0001）的建表语句为 AutoMigrate 创建的已有表补齐缺少的列，
+// 缺少的表和索引随后由 Up 执行 0001 时创建。初始迁移的内容不再修改，因此之后的模型变更不会影响这里
+func (m *Migrator) UpgradeLegacy(ctx context.Context) error {
+	if len(m.migrations) == 0 || m.migrations[0].Version != 1 {
+		return nil
+	}
+	db := m.db.WithContext(ctx)
+	migrator := db.Migrator()
+	for _, table := range createTable.FindAllStringSubmatch(m.migrations[0].Up, -1) {
+		name := table[1]
+		if !migrator.HasTable(name) {
+			continue
+		}
+		for _, line := range strings.Split(table[2], "\n") {
+			def := strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if def == "" || strings.HasPrefix(def, "CONSTRAINT") || strings.HasPrefix(def, "--") {
+				continue
+			}
+			column := strings.Fields(def)[0]
+			if migrator.HasColumn(name, column) {
+				continue
+			}
+			// 已有记录的新列为空，去掉 NOT NULL 约束
+			def = strings.Replace(def, " NOT NULL", "", 1)
+			if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", name, def)).Error; err != nil {
+				return fmt.Errorf("为 %s 添加列 %s 失败: %w", name, column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applied 查询已执行的迁移，按版本升序
+func (m *Migrator) applied(ctx context.Context) ([]appliedMigration, error) {
+	if !m.db.WithContext(ctx).Migrator().HasTable("schema_migrations") {
+		return nil, nil
+	}
+	var rows []appliedMigration
+	if err := m.db.WithContext(ctx).Table("schema_migrations").Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+	return rows, nil
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := map[int]bool{}
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	var executed []Migration
+	for _, migration := range m.migrations {
+		if done[migration.Version] {
+			continue
+		}
+		if err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				migration.Version, migration.Name, time.Now()).Error
+		}); err != nil {
+			return executed, fmt.Errorf("执行迁移 %04d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		executed = append(executed, migration)
+	}
+	return executed, nil
+}
+
+// Down 按版本倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := map[int]Migration{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var reverted []Migration
+	for i := len(applied) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration, ok := known[applied[i].Version]
+		if !ok {
+			return reverted, fmt.Errorf("%w: 无法回滚未知的迁移 %04d_%s", ErrSchemaTooNew, applied[i].Version, applied[i].Name)
+		}
+		if err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+		}); err != nil {
+			return reverted, fmt.Errorf("回滚迁移 %04d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Status 返回所有迁移（包括数据库中存在但程序不认识的）的执行状态，按版本升序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Status{}
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = &Status{Version: migration.Version, Name: migration.Name}
+	}
+	for _, a := range applied {
+		appliedAt := a.AppliedAt
+		if s, ok := byVersion[a.Version]; ok {
+			s.AppliedAt = &appliedAt
+		} else {
+			byVersion[a.Version] = &Status{Version: a.Version, Name: a.Name, AppliedAt: &appliedAt}
+		}
+	}
+
+	statuses := make([]Status, 0, len(byVersion))
+	for _, s := range byVersion {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check 检查数据库结构与程序内嵌的迁移是否一致：
+// 有未执行的迁移时返回 ErrSchemaOutdated，执行过程序不认识的迁移时返回 ErrSchemaTooNew
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	known := map[int]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	var pending []string
+	for _, s := range statuses {
+		if !known[s.Version] {
+			return fmt.Errorf("%w: 数据库已执行迁移 %04d_%s，程序最新版本为 %04d", ErrSchemaTooNew, s.Version, s.Name, m.Latest())
+		}
+		if s.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%04d_%s", s.Version, s.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: 未执行 %v", ErrSchemaOutdated, pending)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+//go:build sqlite_fts5
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/database"
+)
+
+// models 迁移需要覆盖的所有模型
+var models = []any{
+	&model.User{},
+	&model.GEOAnalysis{},
+	&model.QuerySet{},
+	&model.QuerySetRun{},
+	&model.CitationSnapshot{},
+	&model.Brand{},
+	&model.BrandAnalysisRecord{},
+	&model.BrandMentionRecord{},
+	&model.GEOLLMUsage{},
+	&model.GEOTraceSpan{},
+	&model.GEOAnalysisLog{},
+	&model.GEOPrompt{},
+	&model.GEOPromptExperiment{},
+	&model.GEOPromptExperimentVariant{},
+	&model.GEOPromptExperimentAssignment{},
+	&model.GEOReportTemplate{},
+	&model.GEOAnalysisQuery{},
+	&model.GEOAnalysisSearchResult{},
+	&model.GEOAnalysisSource{},
+	&model.GEOAnalysisContentGap{},
+	&model.GEOAnalysisComparisonItem{},
+	&model.GEOSuggestion{},
+	&model.GEOSuggestionComment{},
+}
+
+// openSQLite 创建临时 SQLite 数据库
+// 全文搜索迁移依赖 FTS5，依赖数据库的测试放在带 sqlite_fts5 构建标签的文件中（make test 已包含该标签）
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLite() error = %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db.DB()
+}
+
+// TestMigrator 测试执行、检查和回滚迁移，执行后的结构包含模型的所有列和索引
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := m.Check(ctx); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Check() before Up error = %v, want ErrSchemaOutdated", err)
+	}
+
+	executed, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(executed) != len(m.migrations) {
+		t.Errorf("Up() executed %d migrations, want %d", len(executed), len(m.migrations))
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check() after Up error = %v", err)
+	}
+	assertSchema(t, db)
+
+	// 再次执行没有待执行的迁移
+	if executed, err := m.Up(ctx); err != nil || len(executed) != 0 {
+		t.Errorf("Up() again = %d, %v, want 0, nil", len(executed), err)
+	}
+
+	reverted, err := m.Down(ctx, len(m.migrations))
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != len(m.migrations) {
+		t.Errorf("Down() reverted %d migrations, want %d", len(reverted), len(m.migrations))
+	}
+	if db.Migrator().HasTable(&model.GEOAnalysis{}) {
+		t.Error("geo_analyses exists after Down()")
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() after Down error = %v", err)
+	}
+	assertSchema(t, db)
+}
+
+// TestCheckTooNew 测试数据库执行过程序不认识的迁移
+func TestCheckTooNew(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, CURRENT_TIMESTAMP)",
+		m.Latest()+1, "future").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Check(ctx); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Check() error = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+// TestUpExistingDatabase 测试升级 AutoMigrate 创建的较旧数据库：按初始迁移补齐列后执行所有迁移，保留已有记录
+func TestUpExistingDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, created_at DATETIME, updated_at DATETIME, username VARCHAR(32) NOT NULL, email VARCHAR(255) NOT NULL, password VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE geo_analyses (id INTEGER PRIMARY KEY AUTOINCREMENT, created_at DATETIME, updated_at DATETIME, url VARCHAR(500) NOT NULL, title VARCHAR(500), overall_score INTEGER DEFAULT 0, status VARCHAR(20))`,
+		`INSERT INTO geo_analyses (url, title, overall_score, status) VALUES ('https://example.com', 'Example', 80, 'completed')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !m.Legacy(ctx) {
+		t.Fatal("Legacy() = false, want true")
+	}
+	if err := m.UpgradeLegacy(ctx); err != nil {
+		t.Fatalf("UpgradeLegacy() error = %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	assertSchema(t, db)
+
+	var analysis model.GEOAnalysis
+	if err := db.First(&analysis).Error; err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	if analysis.URL != "https://example.com" || analysis.OverallScore != 80 {
+		t.Errorf("analysis = %+v, want existing record", analysis)
+	}
+}
+
+// assertSchema 检查每个模型的表、列和索引都已由迁移创建
+func assertSchema(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	migrator := db.Migrator()
+	for _, dst := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(dst); err != nil {
+			t.Fatalf("Parse(%T) error = %v", dst, err)
+		}
+		table := stmt.Schema.Table
+		if !migrator.HasTable(table) {
+			t.Errorf("table %s missing", table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !migrator.HasColumn(dst, field.DBName) {
+				t.Errorf("column %s.%s missing", table, field.DBName)
+			}
+		}
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(dst, idx.Name) {
+				t.Errorf("index %s on %s missing", idx.Name, table)
+			}
+		}
+	}
+}
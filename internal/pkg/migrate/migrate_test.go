@@ -0,0 +1,31 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLoad 测试两种数据库的迁移版本一致
+func TestLoad(t *testing.T) {
+	sqlite, err := Load("sqlite")
+	if err != nil {
+		t.Fatalf("Load(sqlite) error = %v", err)
+	}
+	postgres, err := Load("postgres")
+	if err != nil {
+		t.Fatalf("Load(postgres) error = %v", err)
+	}
+	if len(sqlite) == 0 || len(sqlite) != len(postgres) {
+		t.Fatalf("len(sqlite) = %d, len(postgres) = %d", len(sqlite), len(postgres))
+	}
+	for i := range sqlite {
+		if sqlite[i].Version != postgres[i].Version || sqlite[i].Name != postgres[i].Name {
+			t.Errorf("migration %d: sqlite %04d_%s, postgres %04d_%s", i,
+				sqlite[i].Version, sqlite[i].Name, postgres[i].Version, postgres[i].Name)
+		}
+	}
+
+	if _, err := Load("mysql"); !errors.Is(err, ErrUnsupportedDialect) {
+		t.Errorf("Load(mysql) error = %v, want ErrUnsupportedDialect", err)
+	}
+}
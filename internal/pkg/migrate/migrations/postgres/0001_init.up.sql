@@ -0,0 +1,366 @@
+-- 初始结构：与此前 AutoMigrate 创建的表一致，已有数据库执行时跳过已存在的表和索引
+
+-- 用户
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    username   VARCHAR(32) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    status     SMALLINT NOT NULL DEFAULT 1
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username);
+
+-- GEO 分析
+CREATE TABLE IF NOT EXISTS geo_analyses (
+    id                       BIGSERIAL PRIMARY KEY,
+    created_at               TIMESTAMPTZ,
+    updated_at               TIMESTAMPTZ,
+    url                      VARCHAR(500) NOT NULL,
+    title                    VARCHAR(500),
+    main_query               VARCHAR(200),
+    platform                 VARCHAR(20) DEFAULT 'google',
+    country                  VARCHAR(8),
+    language                 VARCHAR(16),
+    device                   VARCHAR(10),
+    output_language          VARCHAR(8),
+    overall_score            BIGINT DEFAULT 0,
+    optimized_score          BIGINT DEFAULT 0,
+    status                   VARCHAR(20),
+    error_message            TEXT,
+    query_fanout             TEXT,
+    ai_overview              TEXT,
+    query_fanout_summary     TEXT,
+    optimization_report      TEXT,
+    optimized_article        TEXT,
+    content_gaps             TEXT,
+    optimization_suggestions TEXT,
+    competitor_analysis      TEXT,
+    serp_features            TEXT,
+    prompt_versions          TEXT,
+    results_normalized       BOOLEAN DEFAULT FALSE,
+    validation_result        TEXT,
+    rating                   BIGINT,
+    rating_comment           VARCHAR(500),
+    user_id                  BIGINT,
+    completed_at             TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_results_normalized ON geo_analyses(results_normalized);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_status ON geo_analyses(status);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_url ON geo_analyses(url);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_user_id ON geo_analyses(user_id);
+
+-- 引用份额追踪：查询集
+CREATE TABLE IF NOT EXISTS geo_query_sets (
+    id                 BIGSERIAL PRIMARY KEY,
+    created_at         TIMESTAMPTZ,
+    updated_at         TIMESTAMPTZ,
+    name               VARCHAR(100) NOT NULL,
+    queries            TEXT NOT NULL,
+    domains            TEXT NOT NULL,
+    competitor_domains TEXT,
+    platform           VARCHAR(20) DEFAULT 'google',
+    interval_minutes   BIGINT DEFAULT 1440,
+    enabled            BOOLEAN DEFAULT TRUE,
+    last_run_at        TIMESTAMPTZ,
+    user_id            BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_query_sets_enabled ON geo_query_sets(enabled);
+CREATE INDEX IF NOT EXISTS idx_geo_query_sets_user_id ON geo_query_sets(user_id);
+
+-- 查询集采集记录
+CREATE TABLE IF NOT EXISTS geo_query_set_runs (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    query_set_id     BIGINT NOT NULL,
+    status           VARCHAR(20),
+    queries_total    BIGINT DEFAULT 0,
+    queries_answered BIGINT DEFAULT 0,
+    queries_failed   BIGINT DEFAULT 0,
+    error_message    TEXT,
+    finished_at      TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_query_set_runs_query_set_id ON geo_query_set_runs(query_set_id);
+CREATE INDEX IF NOT EXISTS idx_geo_query_set_runs_status ON geo_query_set_runs(status);
+
+-- 引用快照
+CREATE TABLE IF NOT EXISTS geo_citation_snapshots (
+    id           BIGSERIAL PRIMARY KEY,
+    query_set_id BIGINT NOT NULL,
+    run_id       BIGINT NOT NULL,
+    query        VARCHAR(500) NOT NULL,
+    domain       VARCHAR(255) NOT NULL,
+    url          VARCHAR(1000),
+    position     BIGINT,
+    cited_at     TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_citation_set_time ON geo_citation_snapshots(query_set_id, cited_at);
+CREATE INDEX IF NOT EXISTS idx_geo_citation_snapshots_domain ON geo_citation_snapshots(domain);
+CREATE INDEX IF NOT EXISTS idx_geo_citation_snapshots_run_id ON geo_citation_snapshots(run_id);
+
+-- 品牌词典
+CREATE TABLE IF NOT EXISTS geo_brands (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name       VARCHAR(100) NOT NULL,
+    aliases    TEXT,
+    products   TEXT,
+    facts      TEXT,
+    enabled    BOOLEAN DEFAULT TRUE,
+    user_id    BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_brands_enabled ON geo_brands(enabled);
+CREATE INDEX IF NOT EXISTS idx_geo_brands_user_id ON geo_brands(user_id);
+
+-- 品牌分析结果
+CREATE TABLE IF NOT EXISTS geo_brand_analyses (
+    id              BIGSERIAL PRIMARY KEY,
+    analysis_id     BIGINT NOT NULL,
+    brand_id        BIGINT NOT NULL,
+    mentioned       BOOLEAN,
+    mention_count   BIGINT DEFAULT 0,
+    sentiment       VARCHAR(10),
+    sentiment_score DOUBLE PRECISION,
+    summary         TEXT,
+    wrong_claims    TEXT,
+    created_at      TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brand_analysis ON geo_brand_analyses(analysis_id, brand_id);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_analyses_created_at ON geo_brand_analyses(created_at);
+
+-- 品牌提及
+CREATE TABLE IF NOT EXISTS geo_brand_mentions (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    brand_id    BIGINT NOT NULL,
+    term        VARCHAR(100),
+    kind        VARCHAR(20),
+    char_offset BIGINT,
+    snippet     TEXT,
+    sentiment   VARCHAR(10),
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_mentions_analysis_id ON geo_brand_mentions(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_mentions_brand_id ON geo_brand_mentions(brand_id);
+
+-- LLM 用量
+CREATE TABLE IF NOT EXISTS geo_llm_usages (
+    id                BIGSERIAL PRIMARY KEY,
+    analysis_id       BIGINT NOT NULL,
+    user_id           BIGINT,
+    agent             VARCHAR(50),
+    model             VARCHAR(100),
+    calls             BIGINT DEFAULT 0,
+    prompt_tokens     BIGINT DEFAULT 0,
+    completion_tokens BIGINT DEFAULT 0,
+    total_tokens      BIGINT DEFAULT 0,
+    latency_ms        BIGINT DEFAULT 0,
+    cost              DOUBLE PRECISION DEFAULT 0,
+    currency          VARCHAR(8),
+    estimated_calls   BIGINT DEFAULT 0,
+    created_at        TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_analysis_id ON geo_llm_usages(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_created_at ON geo_llm_usages(created_at);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_user_id ON geo_llm_usages(user_id);
+
+-- 分析执行追踪
+CREATE TABLE IF NOT EXISTS geo_trace_spans (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    span_id     BIGINT,
+    parent_id   BIGINT,
+    agent       VARCHAR(50),
+    name        VARCHAR(100),
+    type        VARCHAR(100),
+    component   VARCHAR(50),
+    input       TEXT,
+    output      TEXT,
+    state       TEXT,
+    error       TEXT,
+    started_at  TIMESTAMPTZ,
+    duration_ms BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_trace_spans_analysis_id ON geo_trace_spans(analysis_id);
+
+-- 分析日志
+CREATE TABLE IF NOT EXISTS geo_analysis_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    seq         BIGINT,
+    time        TIMESTAMPTZ,
+    level       VARCHAR(10),
+    message     TEXT,
+    fields      TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_logs_analysis_id ON geo_analysis_logs(analysis_id);
+
+-- prompt 版本
+CREATE TABLE IF NOT EXISTS geo_prompts (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    version    BIGINT NOT NULL,
+    content    TEXT NOT NULL,
+    comment    VARCHAR(255),
+    active     BOOLEAN,
+    created_by BIGINT,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompts_active ON geo_prompts(active);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geo_prompts_name_version ON geo_prompts(name, version);
+
+-- prompt 实验
+CREATE TABLE IF NOT EXISTS geo_prompt_experiments (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    prompt      VARCHAR(50) NOT NULL,
+    description TEXT,
+    status      VARCHAR(20),
+    stopped_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiments_prompt ON geo_prompt_experiments(prompt);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiments_status ON geo_prompt_experiments(status);
+
+-- prompt 实验变体
+CREATE TABLE IF NOT EXISTS geo_prompt_experiment_variants (
+    id             BIGSERIAL PRIMARY KEY,
+    experiment_id  BIGINT NOT NULL,
+    key            VARCHAR(20) NOT NULL,
+    prompt_version BIGINT DEFAULT 0,
+    weight         BIGINT NOT NULL,
+    CONSTRAINT fk_geo_prompt_experiments_variants FOREIGN KEY (experiment_id) REFERENCES geo_prompt_experiments(id)
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_variants_experiment_id ON geo_prompt_experiment_variants(experiment_id);
+
+-- prompt 实验分配
+CREATE TABLE IF NOT EXISTS geo_prompt_experiment_assignments (
+    id             BIGSERIAL PRIMARY KEY,
+    experiment_id  BIGINT NOT NULL,
+    analysis_id    BIGINT NOT NULL,
+    variant_key    VARCHAR(20),
+    prompt_version VARCHAR(50),
+    parse_failures BIGINT DEFAULT 0,
+    created_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_assignments_analysis_id ON geo_prompt_experiment_assignments(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_assignments_experiment_id ON geo_prompt_experiment_assignments(experiment_id);
+
+-- 报告模板
+CREATE TABLE IF NOT EXISTS geo_report_templates (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    workspace  VARCHAR(64) NOT NULL,
+    brand_name VARCHAR(100),
+    color      VARCHAR(7),
+    logo_url   VARCHAR(500),
+    header     VARCHAR(200),
+    footer     VARCHAR(200),
+    sections   TEXT,
+    language   VARCHAR(8)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geo_report_templates_workspace ON geo_report_templates(workspace);
+
+-- 分析结构化结果：相关查询
+CREATE TABLE IF NOT EXISTS geo_analysis_queries (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    position    BIGINT,
+    query       VARCHAR(500) NOT NULL,
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_queries_analysis_id ON geo_analysis_queries(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_queries_query ON geo_analysis_queries(query);
+
+-- 分析结构化结果：搜索结果
+CREATE TABLE IF NOT EXISTS geo_analysis_search_results (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    position    BIGINT,
+    title       VARCHAR(500),
+    url         VARCHAR(1000),
+    domain      VARCHAR(255),
+    snippet     TEXT,
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_search_results_analysis_id ON geo_analysis_search_results(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_search_results_domain ON geo_analysis_search_results(domain);
+
+-- 分析结构化结果：AI 摘要引用的来源
+CREATE TABLE IF NOT EXISTS geo_analysis_sources (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    position    BIGINT,
+    url         VARCHAR(1000) NOT NULL,
+    domain      VARCHAR(255),
+    title       VARCHAR(500),
+    strengths   TEXT,
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_sources_analysis_id ON geo_analysis_sources(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_sources_domain ON geo_analysis_sources(domain);
+
+-- 分析结构化结果：内容差距
+CREATE TABLE IF NOT EXISTS geo_analysis_content_gaps (
+    id          BIGSERIAL PRIMARY KEY,
+    analysis_id BIGINT NOT NULL,
+    position    BIGINT,
+    gap         TEXT NOT NULL,
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_content_gaps_analysis_id ON geo_analysis_content_gaps(analysis_id);
+
+-- 分析结构化结果：对比项
+CREATE TABLE IF NOT EXISTS geo_analysis_comparison_items (
+    id           BIGSERIAL PRIMARY KEY,
+    analysis_id  BIGINT NOT NULL,
+    position     BIGINT,
+    dimension    VARCHAR(200) NOT NULL,
+    your_content TEXT,
+    ai_overview  TEXT,
+    similarity   TEXT,
+    difference   TEXT,
+    created_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_comparison_items_analysis_id ON geo_analysis_comparison_items(analysis_id);
+
+-- 优化建议
+CREATE TABLE IF NOT EXISTS geo_suggestions (
+    id                       BIGSERIAL PRIMARY KEY,
+    created_at               TIMESTAMPTZ,
+    updated_at               TIMESTAMPTZ,
+    analysis_id              BIGINT NOT NULL,
+    position                 BIGINT,
+    priority                 VARCHAR(10),
+    category                 VARCHAR(50),
+    issue                    TEXT,
+    suggestion               TEXT NOT NULL,
+    status                   VARCHAR(20) DEFAULT 'open',
+    assignee                 VARCHAR(100),
+    due_date                 TIMESTAMPTZ,
+    completed_at             TIMESTAMPTZ,
+    verification_analysis_id BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_analysis_id ON geo_suggestions(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_assignee ON geo_suggestions(assignee);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_category ON geo_suggestions(category);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_due_date ON geo_suggestions(due_date);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_priority ON geo_suggestions(priority);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_status ON geo_suggestions(status);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_verification_analysis_id ON geo_suggestions(verification_analysis_id);
+
+-- 优化建议评论
+CREATE TABLE IF NOT EXISTS geo_suggestion_comments (
+    id            BIGSERIAL PRIMARY KEY,
+    suggestion_id BIGINT NOT NULL,
+    author        VARCHAR(100),
+    content       TEXT NOT NULL,
+    created_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestion_comments_suggestion_id ON geo_suggestion_comments(suggestion_id);
@@ -0,0 +1,3 @@
+-- 回滚全文搜索
+DROP INDEX IF EXISTS idx_geo_analyses_search_vector;
+ALTER TABLE geo_analyses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+-- 全文搜索：AI 概览、优化报告和优化后文章
+-- 使用 simple 配置（不做词干提取，适用于多语言内容），生成列需要 PostgreSQL 12+
+ALTER TABLE geo_analyses ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple'::regconfig,
+            coalesce(ai_overview, '') || ' ' ||
+            coalesce(optimization_report, '') || ' ' ||
+            coalesce(optimized_article, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_search_vector ON geo_analyses USING GIN (search_vector);
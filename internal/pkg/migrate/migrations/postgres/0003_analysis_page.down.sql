@@ -0,0 +1,2 @@
+-- 回滚搜索结果页码
+ALTER TABLE geo_analyses DROP COLUMN IF EXISTS page;
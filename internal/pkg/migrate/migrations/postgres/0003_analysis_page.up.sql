@@ -0,0 +1,2 @@
+-- 分析记录的搜索结果页码
+ALTER TABLE geo_analyses ADD COLUMN IF NOT EXISTS page INTEGER DEFAULT 1;
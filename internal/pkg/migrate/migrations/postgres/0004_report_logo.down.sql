@@ -0,0 +1,2 @@
+-- 回滚报告模板的 Logo 图片
+ALTER TABLE geo_report_templates DROP COLUMN IF EXISTS logo;
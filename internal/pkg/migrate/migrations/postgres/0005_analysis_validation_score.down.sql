@@ -0,0 +1,2 @@
+-- 回滚分析的验证评分
+ALTER TABLE geo_analyses DROP COLUMN IF EXISTS validation_score;
@@ -0,0 +1,2 @@
+-- 分析的验证评分
+ALTER TABLE geo_analyses ADD COLUMN IF NOT EXISTS validation_score INTEGER;
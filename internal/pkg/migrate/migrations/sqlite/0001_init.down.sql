@@ -0,0 +1,24 @@
+-- 回滚初始结构
+DROP TABLE IF EXISTS geo_suggestion_comments;
+DROP TABLE IF EXISTS geo_suggestions;
+DROP TABLE IF EXISTS geo_analysis_comparison_items;
+DROP TABLE IF EXISTS geo_analysis_content_gaps;
+DROP TABLE IF EXISTS geo_analysis_sources;
+DROP TABLE IF EXISTS geo_analysis_search_results;
+DROP TABLE IF EXISTS geo_analysis_queries;
+DROP TABLE IF EXISTS geo_report_templates;
+DROP TABLE IF EXISTS geo_prompt_experiment_assignments;
+DROP TABLE IF EXISTS geo_prompt_experiment_variants;
+DROP TABLE IF EXISTS geo_prompt_experiments;
+DROP TABLE IF EXISTS geo_prompts;
+DROP TABLE IF EXISTS geo_analysis_logs;
+DROP TABLE IF EXISTS geo_trace_spans;
+DROP TABLE IF EXISTS geo_llm_usages;
+DROP TABLE IF EXISTS geo_brand_mentions;
+DROP TABLE IF EXISTS geo_brand_analyses;
+DROP TABLE IF EXISTS geo_brands;
+DROP TABLE IF EXISTS geo_citation_snapshots;
+DROP TABLE IF EXISTS geo_query_set_runs;
+DROP TABLE IF EXISTS geo_query_sets;
+DROP TABLE IF EXISTS geo_analyses;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,366 @@
+-- 初始结构：与此前 AutoMigrate 创建的表一致，已有数据库执行时跳过已存在的表和索引
+
+-- 用户
+CREATE TABLE IF NOT EXISTS users (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    username   VARCHAR(32) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    status     SMALLINT NOT NULL DEFAULT 1
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username);
+
+-- GEO 分析
+CREATE TABLE IF NOT EXISTS geo_analyses (
+    id                       INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at               DATETIME,
+    updated_at               DATETIME,
+    url                      VARCHAR(500) NOT NULL,
+    title                    VARCHAR(500),
+    main_query               VARCHAR(200),
+    platform                 VARCHAR(20) DEFAULT 'google',
+    country                  VARCHAR(8),
+    language                 VARCHAR(16),
+    device                   VARCHAR(10),
+    output_language          VARCHAR(8),
+    overall_score            INTEGER DEFAULT 0,
+    optimized_score          INTEGER DEFAULT 0,
+    status                   VARCHAR(20),
+    error_message            TEXT,
+    query_fanout             TEXT,
+    ai_overview              TEXT,
+    query_fanout_summary     TEXT,
+    optimization_report      TEXT,
+    optimized_article        TEXT,
+    content_gaps             TEXT,
+    optimization_suggestions TEXT,
+    competitor_analysis      TEXT,
+    serp_features            TEXT,
+    prompt_versions          TEXT,
+    results_normalized       NUMERIC DEFAULT FALSE,
+    validation_result        TEXT,
+    rating                   INTEGER,
+    rating_comment           VARCHAR(500),
+    user_id                  INTEGER,
+    completed_at             DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_results_normalized ON geo_analyses(results_normalized);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_status ON geo_analyses(status);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_url ON geo_analyses(url);
+CREATE INDEX IF NOT EXISTS idx_geo_analyses_user_id ON geo_analyses(user_id);
+
+-- 引用份额追踪：查询集
+CREATE TABLE IF NOT EXISTS geo_query_sets (
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at         DATETIME,
+    updated_at         DATETIME,
+    name               VARCHAR(100) NOT NULL,
+    queries            TEXT NOT NULL,
+    domains            TEXT NOT NULL,
+    competitor_domains TEXT,
+    platform           VARCHAR(20) DEFAULT 'google',
+    interval_minutes   INTEGER DEFAULT 1440,
+    enabled            NUMERIC DEFAULT TRUE,
+    last_run_at        DATETIME,
+    user_id            INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_geo_query_sets_enabled ON geo_query_sets(enabled);
+CREATE INDEX IF NOT EXISTS idx_geo_query_sets_user_id ON geo_query_sets(user_id);
+
+-- 查询集采集记录
+CREATE TABLE IF NOT EXISTS geo_query_set_runs (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at       DATETIME,
+    updated_at       DATETIME,
+    query_set_id     INTEGER NOT NULL,
+    status           VARCHAR(20),
+    queries_total    INTEGER DEFAULT 0,
+    queries_answered INTEGER DEFAULT 0,
+    queries_failed   INTEGER DEFAULT 0,
+    error_message    TEXT,
+    finished_at      DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_query_set_runs_query_set_id ON geo_query_set_runs(query_set_id);
+CREATE INDEX IF NOT EXISTS idx_geo_query_set_runs_status ON geo_query_set_runs(status);
+
+-- 引用快照
+CREATE TABLE IF NOT EXISTS geo_citation_snapshots (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    query_set_id INTEGER NOT NULL,
+    run_id       INTEGER NOT NULL,
+    query        VARCHAR(500) NOT NULL,
+    domain       VARCHAR(255) NOT NULL,
+    url          VARCHAR(1000),
+    position     INTEGER,
+    cited_at     DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_citation_set_time ON geo_citation_snapshots(query_set_id, cited_at);
+CREATE INDEX IF NOT EXISTS idx_geo_citation_snapshots_domain ON geo_citation_snapshots(domain);
+CREATE INDEX IF NOT EXISTS idx_geo_citation_snapshots_run_id ON geo_citation_snapshots(run_id);
+
+-- 品牌词典
+CREATE TABLE IF NOT EXISTS geo_brands (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    name       VARCHAR(100) NOT NULL,
+    aliases    TEXT,
+    products   TEXT,
+    facts      TEXT,
+    enabled    NUMERIC DEFAULT TRUE,
+    user_id    INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_geo_brands_enabled ON geo_brands(enabled);
+CREATE INDEX IF NOT EXISTS idx_geo_brands_user_id ON geo_brands(user_id);
+
+-- 品牌分析结果
+CREATE TABLE IF NOT EXISTS geo_brand_analyses (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id     INTEGER NOT NULL,
+    brand_id        INTEGER NOT NULL,
+    mentioned       NUMERIC,
+    mention_count   INTEGER DEFAULT 0,
+    sentiment       VARCHAR(10),
+    sentiment_score REAL,
+    summary         TEXT,
+    wrong_claims    TEXT,
+    created_at      DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brand_analysis ON geo_brand_analyses(analysis_id, brand_id);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_analyses_created_at ON geo_brand_analyses(created_at);
+
+-- 品牌提及
+CREATE TABLE IF NOT EXISTS geo_brand_mentions (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    brand_id    INTEGER NOT NULL,
+    term        VARCHAR(100),
+    kind        VARCHAR(20),
+    char_offset INTEGER,
+    snippet     TEXT,
+    sentiment   VARCHAR(10),
+    created_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_mentions_analysis_id ON geo_brand_mentions(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_brand_mentions_brand_id ON geo_brand_mentions(brand_id);
+
+-- LLM 用量
+CREATE TABLE IF NOT EXISTS geo_llm_usages (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id       INTEGER NOT NULL,
+    user_id           INTEGER,
+    agent             VARCHAR(50),
+    model             VARCHAR(100),
+    calls             INTEGER DEFAULT 0,
+    prompt_tokens     INTEGER DEFAULT 0,
+    completion_tokens INTEGER DEFAULT 0,
+    total_tokens      INTEGER DEFAULT 0,
+    latency_ms        INTEGER DEFAULT 0,
+    cost              REAL DEFAULT 0,
+    currency          VARCHAR(8),
+    estimated_calls   INTEGER DEFAULT 0,
+    created_at        DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_analysis_id ON geo_llm_usages(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_created_at ON geo_llm_usages(created_at);
+CREATE INDEX IF NOT EXISTS idx_geo_llm_usages_user_id ON geo_llm_usages(user_id);
+
+-- 分析执行追踪
+CREATE TABLE IF NOT EXISTS geo_trace_spans (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    span_id     INTEGER,
+    parent_id   INTEGER,
+    agent       VARCHAR(50),
+    name        VARCHAR(100),
+    type        VARCHAR(100),
+    component   VARCHAR(50),
+    input       TEXT,
+    output      TEXT,
+    state       TEXT,
+    error       TEXT,
+    started_at  DATETIME,
+    duration_ms INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_geo_trace_spans_analysis_id ON geo_trace_spans(analysis_id);
+
+-- 分析日志
+CREATE TABLE IF NOT EXISTS geo_analysis_logs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    seq         INTEGER,
+    time        DATETIME,
+    level       VARCHAR(10),
+    message     TEXT,
+    fields      TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_logs_analysis_id ON geo_analysis_logs(analysis_id);
+
+-- prompt 版本
+CREATE TABLE IF NOT EXISTS geo_prompts (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(50) NOT NULL,
+    version    INTEGER NOT NULL,
+    content    TEXT NOT NULL,
+    comment    VARCHAR(255),
+    active     NUMERIC,
+    created_by INTEGER,
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompts_active ON geo_prompts(active);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geo_prompts_name_version ON geo_prompts(name, version);
+
+-- prompt 实验
+CREATE TABLE IF NOT EXISTS geo_prompt_experiments (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(100) NOT NULL,
+    prompt      VARCHAR(50) NOT NULL,
+    description TEXT,
+    status      VARCHAR(20),
+    stopped_at  DATETIME,
+    created_at  DATETIME,
+    updated_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiments_prompt ON geo_prompt_experiments(prompt);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiments_status ON geo_prompt_experiments(status);
+
+-- prompt 实验变体
+CREATE TABLE IF NOT EXISTS geo_prompt_experiment_variants (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    experiment_id  INTEGER NOT NULL,
+    key            VARCHAR(20) NOT NULL,
+    prompt_version INTEGER DEFAULT 0,
+    weight         INTEGER NOT NULL,
+    CONSTRAINT fk_geo_prompt_experiments_variants FOREIGN KEY (experiment_id) REFERENCES geo_prompt_experiments(id)
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_variants_experiment_id ON geo_prompt_experiment_variants(experiment_id);
+
+-- prompt 实验分配
+CREATE TABLE IF NOT EXISTS geo_prompt_experiment_assignments (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    experiment_id  INTEGER NOT NULL,
+    analysis_id    INTEGER NOT NULL,
+    variant_key    VARCHAR(20),
+    prompt_version VARCHAR(50),
+    parse_failures INTEGER DEFAULT 0,
+    created_at     DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_assignments_analysis_id ON geo_prompt_experiment_assignments(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_prompt_experiment_assignments_experiment_id ON geo_prompt_experiment_assignments(experiment_id);
+
+-- 报告模板
+CREATE TABLE IF NOT EXISTS geo_report_templates (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    workspace  VARCHAR(64) NOT NULL,
+    brand_name VARCHAR(100),
+    color      VARCHAR(7),
+    logo_url   VARCHAR(500),
+    header     VARCHAR(200),
+    footer     VARCHAR(200),
+    sections   TEXT,
+    language   VARCHAR(8)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geo_report_templates_workspace ON geo_report_templates(workspace);
+
+-- 分析结构化结果：相关查询
+CREATE TABLE IF NOT EXISTS geo_analysis_queries (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    position    INTEGER,
+    query       VARCHAR(500) NOT NULL,
+    created_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_queries_analysis_id ON geo_analysis_queries(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_queries_query ON geo_analysis_queries(query);
+
+-- 分析结构化结果：搜索结果
+CREATE TABLE IF NOT EXISTS geo_analysis_search_results (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    position    INTEGER,
+    title       VARCHAR(500),
+    url         VARCHAR(1000),
+    domain      VARCHAR(255),
+    snippet     TEXT,
+    created_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_search_results_analysis_id ON geo_analysis_search_results(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_search_results_domain ON geo_analysis_search_results(domain);
+
+-- 分析结构化结果：AI 摘要引用的来源
+CREATE TABLE IF NOT EXISTS geo_analysis_sources (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    position    INTEGER,
+    url         VARCHAR(1000) NOT NULL,
+    domain      VARCHAR(255),
+    title       VARCHAR(500),
+    strengths   TEXT,
+    created_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_sources_analysis_id ON geo_analysis_sources(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_sources_domain ON geo_analysis_sources(domain);
+
+-- 分析结构化结果：内容差距
+CREATE TABLE IF NOT EXISTS geo_analysis_content_gaps (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id INTEGER NOT NULL,
+    position    INTEGER,
+    gap         TEXT NOT NULL,
+    created_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_content_gaps_analysis_id ON geo_analysis_content_gaps(analysis_id);
+
+-- 分析结构化结果：对比项
+CREATE TABLE IF NOT EXISTS geo_analysis_comparison_items (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    analysis_id  INTEGER NOT NULL,
+    position     INTEGER,
+    dimension    VARCHAR(200) NOT NULL,
+    your_content TEXT,
+    ai_overview  TEXT,
+    similarity   TEXT,
+    difference   TEXT,
+    created_at   DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_analysis_comparison_items_analysis_id ON geo_analysis_comparison_items(analysis_id);
+
+-- 优化建议
+CREATE TABLE IF NOT EXISTS geo_suggestions (
+    id                       INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at               DATETIME,
+    updated_at               DATETIME,
+    analysis_id              INTEGER NOT NULL,
+    position                 INTEGER,
+    priority                 VARCHAR(10),
+    category                 VARCHAR(50),
+    issue                    TEXT,
+    suggestion               TEXT NOT NULL,
+    status                   VARCHAR(20) DEFAULT 'open',
+    assignee                 VARCHAR(100),
+    due_date                 DATETIME,
+    completed_at             DATETIME,
+    verification_analysis_id INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_analysis_id ON geo_suggestions(analysis_id);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_assignee ON geo_suggestions(assignee);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_category ON geo_suggestions(category);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_due_date ON geo_suggestions(due_date);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_priority ON geo_suggestions(priority);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_status ON geo_suggestions(status);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestions_verification_analysis_id ON geo_suggestions(verification_analysis_id);
+
+-- 优化建议评论
+CREATE TABLE IF NOT EXISTS geo_suggestion_comments (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    suggestion_id INTEGER NOT NULL,
+    author        VARCHAR(100),
+    content       TEXT NOT NULL,
+    created_at    DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_geo_suggestion_comments_suggestion_id ON geo_suggestion_comments(suggestion_id);
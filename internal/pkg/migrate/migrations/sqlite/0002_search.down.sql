@@ -0,0 +1,5 @@
+-- 回滚全文搜索
+DROP TRIGGER IF EXISTS geo_analyses_fts_au;
+DROP TRIGGER IF EXISTS geo_analyses_fts_ad;
+DROP TRIGGER IF EXISTS geo_analyses_fts_ai;
+DROP TABLE IF EXISTS geo_analyses_fts;
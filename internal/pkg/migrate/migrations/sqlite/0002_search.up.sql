@@ -0,0 +1,30 @@
+-- 全文搜索：AI 概览、优化报告和优化后文章
+-- 使用 FTS5 外部内容表（mattn/go-sqlite3 需要 sqlite_fts5 构建标签），由触发器与 geo_analyses 同步
+CREATE VIRTUAL TABLE IF NOT EXISTS geo_analyses_fts USING fts5(
+    ai_overview,
+    optimization_report,
+    optimized_article,
+    content='geo_analyses',
+    content_rowid='id',
+    tokenize='unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS geo_analyses_fts_ai AFTER INSERT ON geo_analyses BEGIN
+    INSERT INTO geo_analyses_fts(rowid, ai_overview, optimization_report, optimized_article)
+    VALUES (new.id, new.ai_overview, new.optimization_report, new.optimized_article);
+END;
+
+CREATE TRIGGER IF NOT EXISTS geo_analyses_fts_ad AFTER DELETE ON geo_analyses BEGIN
+    INSERT INTO geo_analyses_fts(geo_analyses_fts, rowid, ai_overview, optimization_report, optimized_article)
+    VALUES ('delete', old.id, old.ai_overview, old.optimization_report, old.optimized_article);
+END;
+
+CREATE TRIGGER IF NOT EXISTS geo_analyses_fts_au AFTER UPDATE OF ai_overview, optimization_report, optimized_article ON geo_analyses BEGIN
+    INSERT INTO geo_analyses_fts(geo_analyses_fts, rowid, ai_overview, optimization_report, optimized_article)
+    VALUES ('delete', old.id, old.ai_overview, old.optimization_report, old.optimized_article);
+    INSERT INTO geo_analyses_fts(rowid, ai_overview, optimization_report, optimized_article)
+    VALUES (new.id, new.ai_overview, new.optimization_report, new.optimized_article);
+END;
+
+-- 为已有记录建立索引
+INSERT INTO geo_analyses_fts(geo_analyses_fts) VALUES ('rebuild');
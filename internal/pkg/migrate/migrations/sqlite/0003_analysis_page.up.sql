@@ -0,0 +1,2 @@
+-- 分析记录的搜索结果页码
+ALTER TABLE geo_analyses ADD COLUMN page INTEGER DEFAULT 1;
@@ -0,0 +1,2 @@
+-- 报告模板的 Logo 图片
+ALTER TABLE geo_report_templates ADD COLUMN logo BLOB;
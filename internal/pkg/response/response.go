@@ -24,12 +24,13 @@ type PageData struct {
 
 // 响应状态码
 const (
-	CodeSuccess      = 0
-	CodeBadRequest   = 400
-	CodeUnauthorized = 401
-	CodeForbidden    = 403
-	CodeNotFound     = 404
-	CodeServerError  = 500
+	CodeSuccess            = 0
+	CodeBadRequest         = 400
+	CodeUnauthorized       = 401
+	CodeForbidden          = 403
+	CodeNotFound           = 404
+	CodeServerError        = 500
+	CodeServiceUnavailable = 503
 )
 
 // Success 成功响应
@@ -129,3 +130,14 @@ func ServerError(c *gin.Context, message string) {
 		Message: message,
 	})
 }
+
+// ServiceUnavailable 503 错误
+func ServiceUnavailable(c *gin.Context, message string) {
+	if message == "" {
+		message = "服务不可用"
+	}
+	c.JSON(http.StatusServiceUnavailable, Response{
+		Code:    CodeServiceUnavailable,
+		Message: message,
+	})
+}
@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace Prometheus 指标前缀
+const metricsNamespace = "peanut"
+
+var (
+	// AnalysesTotal 按状态（completed、failed）和平台统计的分析次数
+	AnalysesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "geo",
+		Name:      "analyses_total",
+		Help:      "GEO 分析次数，按状态和平台统计",
+	}, []string{"status", "platform"})
+
+	// AnalysisQueueDepth 已创建但尚未结束的分析任务数
+	AnalysisQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "geo",
+		Name:      "analysis_queue_depth",
+		Help:      "排队或执行中的 GEO 分析任务数",
+	})
+
+	// AgentDuration 各 Agent 子图的执行耗时
+	AgentDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "geo",
+		Name:      "agent_duration_seconds",
+		Help:      "GEO Agent 执行耗时（秒）",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"agent", "status"})
+
+	// LLMTokens LLM token 用量（不含缓存命中），type 为 prompt 或 completion
+	LLMTokens = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "geo",
+		Name:      "llm_tokens_total",
+		Help:      "LLM token 用量，按 Agent、模型和类型统计",
+	}, []string{"agent", "model", "type"})
+
+	// SERPErrors 搜索和 AI 回答提供者的调用失败次数，kind 为 search 或 answer
+	SERPErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "geo",
+		Name:      "serp_errors_total",
+		Help:      "SERP 提供者调用失败次数",
+	}, []string{"provider", "kind"})
+)
+
+// MetricsHandler 返回 Prometheus 指标的 HTTP 处理器
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
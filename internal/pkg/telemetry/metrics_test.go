@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	AnalysesTotal.WithLabelValues("completed", "google").Inc()
+	SERPErrors.WithLabelValues("serpapi", "search").Inc()
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`peanut_geo_analyses_total{platform="google",status="completed"} 1`,
+		`peanut_geo_serp_errors_total{kind="search",provider="serpapi"} 1`,
+		"peanut_geo_analysis_queue_depth 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
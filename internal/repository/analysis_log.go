@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// AnalysisLogRepository 分析执行日志仓储
+type AnalysisLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalysisLogRepository 创建分析执行日志仓储
+func NewAnalysisLogRepository(db *gorm.DB) *AnalysisLogRepository {
+	return &AnalysisLogRepository{db: db}
+}
+
+// ReplaceByAnalysis 替换某次分析的执行日志
+func (r *AnalysisLogRepository) ReplaceByAnalysis(ctx context.Context, analysisID int64, logs []model.GEOAnalysisLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("analysis_id = ?", analysisID).Delete(&model.GEOAnalysisLog{}).Error; err != nil {
+			return fmt.Errorf("删除执行日志失败: %w", err)
+		}
+		if len(logs) > 0 {
+			if err := tx.CreateInBatches(&logs, 200).Error; err != nil {
+				return fmt.Errorf("写入执行日志失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByAnalysis 删除某次分析的执行日志
+func (r *AnalysisLogRepository) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	if err := r.db.WithContext(ctx).Where("analysis_id = ?", analysisID).Delete(&model.GEOAnalysisLog{}).Error; err != nil {
+		return fmt.Errorf("删除执行日志失败: %w", err)
+	}
+	return nil
+}
+
+// ListByAnalysis 按顺序查询某次分析的执行日志，levels 为空时返回全部级别
+func (r *AnalysisLogRepository) ListByAnalysis(ctx context.Context, analysisID int64, levels []string) ([]model.GEOAnalysisLog, error) {
+	query := r.db.WithContext(ctx).Where("analysis_id = ?", analysisID)
+	if len(levels) > 0 {
+		query = query.Where("level IN ?", levels)
+	}
+
+	var logs []model.GEOAnalysisLog
+	if err := query.Order("seq ASC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("获取执行日志失败: %w", err)
+	}
+	return logs, nil
+}
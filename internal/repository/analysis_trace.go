@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// TraceRepository 分析执行追踪仓储
+type TraceRepository struct {
+	db *gorm.DB
+}
+
+// NewTraceRepository 创建分析执行追踪仓储
+func NewTraceRepository(db *gorm.DB) *TraceRepository {
+	return &TraceRepository{db: db}
+}
+
+// ReplaceByAnalysis 替换某次分析的追踪记录
+func (r *TraceRepository) ReplaceByAnalysis(ctx context.Context, analysisID int64, spans []model.GEOTraceSpan) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("analysis_id = ?", analysisID).Delete(&model.GEOTraceSpan{}).Error; err != nil {
+			return fmt.Errorf("删除追踪记录失败: %w", err)
+		}
+		if len(spans) > 0 {
+			if err := tx.CreateInBatches(&spans, 100).Error; err != nil {
+				return fmt.Errorf("写入追踪记录失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByAnalysis 删除某次分析的追踪记录
+func (r *TraceRepository) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	if err := r.db.WithContext(ctx).Where("analysis_id = ?", analysisID).Delete(&model.GEOTraceSpan{}).Error; err != nil {
+		return fmt.Errorf("删除追踪记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListByAnalysis 按执行顺序查询某次分析的追踪记录
+func (r *TraceRepository) ListByAnalysis(ctx context.Context, analysisID int64) ([]model.GEOTraceSpan, error) {
+	var spans []model.GEOTraceSpan
+	if err := r.db.WithContext(ctx).
+		Where("analysis_id = ?", analysisID).
+		Order("span_id ASC").
+		Find(&spans).Error; err != nil {
+		return nil, fmt.Errorf("获取追踪记录失败: %w", err)
+	}
+	return spans, nil
+}
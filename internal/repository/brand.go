@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrBrandNotFound 品牌不存在
+var ErrBrandNotFound = errors.New("品牌不存在")
+
+// BrandRepository 品牌仓储
+type BrandRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandRepository 创建品牌仓储
+func NewBrandRepository(db *gorm.DB) *BrandRepository {
+	return &BrandRepository{db: db}
+}
+
+// Create 创建品牌
+func (r *BrandRepository) Create(ctx context.Context, brand *model.Brand) error {
+	if err := r.db.WithContext(ctx).Create(brand).Error; err != nil {
+		return fmt.Errorf("创建品牌失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取品牌
+func (r *BrandRepository) GetByID(ctx context.Context, id int64) (*model.Brand, error) {
+	var brand model.Brand
+	if err := r.db.WithContext(ctx).First(&brand, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("获取品牌失败: %w", err)
+	}
+	return &brand, nil
+}
+
+// List 查询品牌列表，enabledOnly 为 true 时只返回启用的品牌
+func (r *BrandRepository) List(ctx context.Context, userID *int64, enabledOnly bool) ([]model.Brand, error) {
+	var brands []model.Brand
+	query := r.db.WithContext(ctx).Model(&model.Brand{})
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	if err := query.Order("id ASC").Find(&brands).Error; err != nil {
+		return nil, fmt.Errorf("获取品牌列表失败: %w", err)
+	}
+	return brands, nil
+}
+
+// Update 更新品牌
+func (r *BrandRepository) Update(ctx context.Context, brand *model.Brand) error {
+	if err := r.db.WithContext(ctx).Save(brand).Error; err != nil {
+		return fmt.Errorf("更新品牌失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除品牌及其分析记录
+func (r *BrandRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("brand_id = ?", id).Delete(&model.BrandMentionRecord{}).Error; err != nil {
+			return fmt.Errorf("删除品牌提及记录失败: %w", err)
+		}
+		if err := tx.Where("brand_id = ?", id).Delete(&model.BrandAnalysisRecord{}).Error; err != nil {
+			return fmt.Errorf("删除品牌分析记录失败: %w", err)
+		}
+		result := tx.Delete(&model.Brand{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("删除品牌失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrBrandNotFound
+		}
+		return nil
+	})
+}
+
+// ReplaceAnalysisResults 替换某次分析的品牌分析结果
+func (r *BrandRepository) ReplaceAnalysisResults(ctx context.Context, analysisID int64, records []model.BrandAnalysisRecord, mentions []model.BrandMentionRecord) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteAnalysisResults(tx, analysisID); err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			if err := tx.Create(&records).Error; err != nil {
+				return fmt.Errorf("写入品牌分析记录失败: %w", err)
+			}
+		}
+		if len(mentions) > 0 {
+			if err := tx.CreateInBatches(mentions, 100).Error; err != nil {
+				return fmt.Errorf("写入品牌提及记录失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByAnalysis 删除某次分析的品牌分析结果
+func (r *BrandRepository) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	return deleteAnalysisResults(r.db.WithContext(ctx), analysisID)
+}
+
+func deleteAnalysisResults(tx *gorm.DB, analysisID int64) error {
+	if err := tx.Where("analysis_id = ?", analysisID).Delete(&model.BrandMentionRecord{}).Error; err != nil {
+		return fmt.Errorf("删除品牌提及记录失败: %w", err)
+	}
+	if err := tx.Where("analysis_id = ?", analysisID).Delete(&model.BrandAnalysisRecord{}).Error; err != nil {
+		return fmt.Errorf("删除品牌分析记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListResultsByAnalysis 查询某次分析的品牌分析结果
+func (r *BrandRepository) ListResultsByAnalysis(ctx context.Context, analysisID int64) ([]model.BrandAnalysisRecord, error) {
+	var records []model.BrandAnalysisRecord
+	if err := r.db.WithContext(ctx).
+		Where("analysis_id = ?", analysisID).
+		Order("brand_id ASC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("获取品牌分析记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// ListMentionsByAnalysis 查询某次分析的品牌提及
+func (r *BrandRepository) ListMentionsByAnalysis(ctx context.Context, analysisID int64) ([]model.BrandMentionRecord, error) {
+	var mentions []model.BrandMentionRecord
+	if err := r.db.WithContext(ctx).
+		Where("analysis_id = ?", analysisID).
+		Order("brand_id ASC, char_offset ASC").
+		Find(&mentions).Error; err != nil {
+		return nil, fmt.Errorf("获取品牌提及记录失败: %w", err)
+	}
+	return mentions, nil
+}
+
+// ListResultsBetween 查询时间范围内的品牌分析结果
+func (r *BrandRepository) ListResultsBetween(ctx context.Context, brandIDs []int64, from, to time.Time) ([]model.BrandAnalysisRecord, error) {
+	var records []model.BrandAnalysisRecord
+	if len(brandIDs) == 0 {
+		return records, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("brand_id IN ? AND created_at >= ? AND created_at < ?", brandIDs, from, to).
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("获取品牌分析记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// ListMentionsBetween 查询时间范围内的品牌提及
+func (r *BrandRepository) ListMentionsBetween(ctx context.Context, brandIDs []int64, from, to time.Time) ([]model.BrandMentionRecord, error) {
+	var mentions []model.BrandMentionRecord
+	if len(brandIDs) == 0 {
+		return mentions, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("brand_id IN ? AND created_at >= ? AND created_at < ?", brandIDs, from, to).
+		Find(&mentions).Error; err != nil {
+		return nil, fmt.Errorf("获取品牌提及记录失败: %w", err)
+	}
+	return mentions, nil
+}
@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/solariswu/peanut/internal/model"
 	"gorm.io/gorm"
@@ -59,6 +63,42 @@ func (r *GEOAnalysisRepository) List(req *model.GEOAnalysisListRequest) ([]model
 		query = query.Where("user_id = ?", *req.UserID)
 	}
 
+	// 字段筛选
+	like := r.likeOperator()
+	if req.URL != "" {
+		query = query.Where("url "+like+" ?"+likeEscape, "%"+escapeLike(req.URL)+"%")
+	}
+	if domain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(req.Domain)), "www."); domain != "" {
+		cond, args := domainCondition(like, domain)
+		query = query.Where(cond, args...)
+	}
+	if req.Title != "" {
+		query = query.Where("title "+like+" ?"+likeEscape, "%"+escapeLike(req.Title)+"%")
+	}
+	if req.MainQuery != "" {
+		query = query.Where("main_query "+like+" ?"+likeEscape, "%"+escapeLike(req.MainQuery)+"%")
+	}
+	if req.Platform != "" {
+		query = query.Where("platform = ?", req.Platform)
+	}
+	if req.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *req.CreatedFrom)
+	}
+	if req.CreatedTo != nil {
+		query = query.Where("created_at < ?", *req.CreatedTo)
+	}
+	if req.MinScore != nil {
+		query = query.Where("overall_score >= ?", *req.MinScore)
+	}
+	if req.MaxScore != nil {
+		query = query.Where("overall_score <= ?", *req.MaxScore)
+	}
+
+	// 全文搜索
+	if terms := SearchTerms(req.Q); len(terms) > 0 {
+		query = r.fullText(query, terms)
+	}
+
 	// 计数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -81,6 +121,174 @@ func (r *GEOAnalysisRepository) List(req *model.GEOAnalysisListRequest) ([]model
 	return analyses, total, nil
 }
 
+// fullText 添加全文搜索条件，所有词都需要出现在 AI 概览、优化报告或优化后文章中
+// 分词器按空白和标点切分，无法切分中文、日文等连续书写的文本，这些词回退到子串匹配
+func (r *GEOAnalysisRepository) fullText(query *gorm.DB, terms []string) *gorm.DB {
+	var words []string
+	like := r.likeOperator()
+	for _, term := range terms {
+		if !unsegmented(term) {
+			words = append(words, term)
+			continue
+		}
+		pattern := "%" + term + "%"
+		query = query.Where("ai_overview "+like+" ? OR optimization_report "+like+" ? OR optimized_article "+like+" ?",
+			pattern, pattern, pattern)
+	}
+	if len(words) == 0 {
+		return query
+	}
+
+	// 词已去除运算符和标点，按前缀匹配
+	if r.db.Dialector.Name() == "postgres" {
+		return query.Where("search_vector @@ to_tsquery('simple', ?)", strings.Join(words, ":* & ")+":*")
+	}
+	return query.Where("id IN (SELECT rowid FROM geo_analyses_fts WHERE geo_analyses_fts MATCH ?)", matchQuery(words))
+}
+
+// matchQuery 生成 FTS5 查询：所有词按前缀匹配，加引号避免被当作列名或运算符
+func matchQuery(words []string) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = `"` + w + `"*`
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// 全文搜索片段中命中词的标记，使用控制字符以便调用方先转义 HTML 再替换为标签
+const (
+	SnippetStart = "\x02"
+	SnippetEnd   = "\x03"
+)
+
+// snippetTokens FTS5 片段包含的最大词数
+const snippetTokens = 24
+
+// Snippets 返回 SQLite FTS5 为记录生成的命中片段，记录 ID -> 字段名 -> 片段（未命中的字段不返回）
+// 只匹配可分词的词；PostgreSQL 或没有可分词的词时返回 nil
+func (r *GEOAnalysisRepository) Snippets(ids []int64, terms []string) (map[int64]map[string]string, error) {
+	var words []string
+	for _, term := range terms {
+		if !unsegmented(term) {
+			words = append(words, term)
+		}
+	}
+	if r.db.Dialector.Name() == "postgres" || len(words) == 0 || len(ids) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		ID                 int64
+		AIOverview         string
+		OptimizationReport string
+		OptimizedArticle   string
+	}
+	snippet := func(column int) string {
+		return fmt.Sprintf("snippet(geo_analyses_fts, %d, char(2), char(3), '…', %d)", column, snippetTokens)
+	}
+	if err := r.db.Table("geo_analyses_fts").
+		Select("rowid AS id, "+snippet(0)+" AS ai_overview, "+snippet(1)+" AS optimization_report, "+snippet(2)+" AS optimized_article").
+		Where("geo_analyses_fts MATCH ? AND rowid IN ?", matchQuery(words), ids).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("生成搜索片段失败: %w", err)
+	}
+
+	snippets := make(map[int64]map[string]string, len(rows))
+	for _, row := range rows {
+		fields := map[string]string{}
+		for name, text := range map[string]string{
+			"ai_overview":         row.AIOverview,
+			"optimization_report": row.OptimizationReport,
+			"optimized_article":   row.OptimizedArticle,
+		} {
+			if strings.Contains(text, SnippetStart) {
+				fields[name] = text
+			}
+		}
+		snippets[row.ID] = fields
+	}
+	return snippets, nil
+}
+
+// likeOperator 返回不区分大小写的子串匹配运算符（SQLite 的 LIKE 对 ASCII 字母不区分大小写）
+func (r *GEOAnalysisRepository) likeOperator() string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// likeEscape LIKE 模式的转义子句，与 escapeLike 配合使用
+const likeEscape = ` ESCAPE '\'`
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike 转义 LIKE 通配符，使输入按字面匹配
+func escapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// domainCondition 返回匹配 URL 主机名为指定域名或其子域名（包括 www.）的条件和参数
+func domainCondition(like, domain string) (string, []any) {
+	match := "url " + like + " ?" + likeEscape
+	cond := match + " OR " + match + " OR " + match + " OR " + match
+	domain = escapeLike(domain)
+	return cond, []any{
+		"%://" + domain, "%://" + domain + "/%",
+		"%." + domain, "%." + domain + "/%",
+	}
+}
+
+// SearchTerms 将全文搜索关键词切分为小写的词，去除标点和搜索运算符
+func SearchTerms(q string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := map[string]bool{}
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// unsegmented 判断词是否包含不以空格分词的文字（中文、日文）
+func unsegmented(term string) bool {
+	for _, r := range term {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
+
+// statsColumns 统计所需的字段，不读取报告和文章等大字段
+var statsColumns = []string{"id", "url", "title", "status", "overall_score", "created_at", "completed_at"}
+
+// ListForStats 查询时间范围内的分析记录用于统计，按创建时间升序
+// domain 不为空时只返回该域名及其子域名的记录
+func (r *GEOAnalysisRepository) ListForStats(ctx context.Context, from, to time.Time, domain string, userID *int64) ([]model.GEOAnalysis, error) {
+	query := r.db.WithContext(ctx).Model(&model.GEOAnalysis{}).
+		Select(statsColumns).
+		Where("created_at >= ? AND created_at < ?", from, to)
+	if domain != "" {
+		cond, args := domainCondition(r.likeOperator(), domain)
+		query = query.Where(cond, args...)
+	}
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var analyses []model.GEOAnalysis
+	if err := query.Order("created_at ASC").Find(&analyses).Error; err != nil {
+		return nil, fmt.Errorf("查询统计数据失败: %w", err)
+	}
+	return analyses, nil
+}
+
 // GetByURL 根据 URL 获取最新的分析记录
 func (r *GEOAnalysisRepository) GetByURL(url string) (*model.GEOAnalysis, error) {
 	var analysis model.GEOAnalysis
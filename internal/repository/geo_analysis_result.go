@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// AnalysisResultRepository 分析结构化结果（查询、搜索结果、来源、内容差距、对比项、优化建议）仓储
+type AnalysisResultRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalysisResultRepository 创建分析结果仓储
+func NewAnalysisResultRepository(db *gorm.DB) *AnalysisResultRepository {
+	return &AnalysisResultRepository{db: db}
+}
+
+// resultTables 分析结果的子表，删除时按此顺序
+var resultTables = []any{
+	&model.GEOAnalysisQuery{},
+	&model.GEOAnalysisSearchResult{},
+	&model.GEOAnalysisSource{},
+	&model.GEOAnalysisContentGap{},
+	&model.GEOAnalysisComparisonItem{},
+	&model.GEOSuggestion{},
+}
+
+// Replace 替换某次分析的结构化结果，并标记分析结果已写入子表
+func (r *AnalysisResultRepository) Replace(ctx context.Context, analysisID int64, results *model.GEOAnalysisResults) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteResults(tx, analysisID); err != nil {
+			return err
+		}
+
+		batches := []struct {
+			name string
+			rows any
+			n    int
+		}{
+			{"查询", results.Queries, len(results.Queries)},
+			{"搜索结果", results.SearchResults, len(results.SearchResults)},
+			{"来源", results.Sources, len(results.Sources)},
+			{"内容差距", results.ContentGaps, len(results.ContentGaps)},
+			{"对比项", results.ComparisonItems, len(results.ComparisonItems)},
+			{"优化建议", results.Suggestions, len(results.Suggestions)},
+		}
+		for _, b := range batches {
+			if b.n == 0 {
+				continue
+			}
+			if err := tx.CreateInBatches(b.rows, 100).Error; err != nil {
+				return fmt.Errorf("写入分析%s失败: %w", b.name, err)
+			}
+		}
+
+		if err := tx.Model(&model.GEOAnalysis{}).Where("id = ?", analysisID).
+			Update("results_normalized", true).Error; err != nil {
+			return fmt.Errorf("标记分析结果失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get 查询某次分析的结构化结果
+func (r *AnalysisResultRepository) Get(ctx context.Context, analysisID int64) (*model.GEOAnalysisResults, error) {
+	results := &model.GEOAnalysisResults{}
+	db := r.db.WithContext(ctx)
+	queries := []struct {
+		name string
+		dst  any
+	}{
+		{"查询", &results.Queries},
+		{"搜索结果", &results.SearchResults},
+		{"来源", &results.Sources},
+		{"内容差距", &results.ContentGaps},
+		{"对比项", &results.ComparisonItems},
+		{"优化建议", &results.Suggestions},
+	}
+	for _, q := range queries {
+		if err := db.Where("analysis_id = ?", analysisID).Order("position ASC, id ASC").Find(q.dst).Error; err != nil {
+			return nil, fmt.Errorf("获取分析%s失败: %w", q.name, err)
+		}
+	}
+	return results, nil
+}
+
+// DeleteByAnalysis 删除某次分析的结构化结果
+func (r *AnalysisResultRepository) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	return deleteResults(r.db.WithContext(ctx), analysisID)
+}
+
+func deleteResults(tx *gorm.DB, analysisID int64) error {
+	if err := tx.Where("suggestion_id IN (?)", tx.Model(&model.GEOSuggestion{}).Select("id").Where("analysis_id = ?", analysisID)).
+		Delete(&model.GEOSuggestionComment{}).Error; err != nil {
+		return fmt.Errorf("删除优化建议评论失败: %w", err)
+	}
+	for _, table := range resultTables {
+		if err := tx.Where("analysis_id = ?", analysisID).Delete(table).Error; err != nil {
+			return fmt.Errorf("删除分析结果失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListUnnormalized 查询已完成但结构化结果尚未写入子表的分析（用于回填）
+func (r *AnalysisResultRepository) ListUnnormalized(ctx context.Context, limit int) ([]model.GEOAnalysis, error) {
+	var analyses []model.GEOAnalysis
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND results_normalized = ?", "completed", false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&analyses).Error; err != nil {
+		return nil, fmt.Errorf("查询待回填的分析失败: %w", err)
+	}
+	return analyses, nil
+}
+
+// ContentGapCounts 统计内容差距出现的分析数，按出现次数降序返回前 limit 个
+// 差距忽略大小写和首尾空白合并，同一分析中重复的差距只计一次
+func (r *AnalysisResultRepository) ContentGapCounts(ctx context.Context, analysisIDs []int64, limit int) ([]model.ContentGapCount, error) {
+	gaps := make([]model.ContentGapCount, 0)
+	if len(analysisIDs) == 0 {
+		return gaps, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&model.GEOAnalysisContentGap{}).
+		Select("MIN(TRIM(gap)) AS gap, COUNT(DISTINCT analysis_id) AS pages").
+		Where("analysis_id IN ?", analysisIDs).
+		Group("LOWER(TRIM(gap))").
+		Order("pages DESC, gap ASC").
+		Limit(limit).
+		Scan(&gaps).Error; err != nil {
+		return nil, fmt.Errorf("统计内容差距失败: %w", err)
+	}
+	return gaps, nil
+}
+
+// suggestionCategory 规范化后的建议类别，空类别归为 general
+const suggestionCategory = "COALESCE(NULLIF(LOWER(TRIM(category)), ''), 'general')"
+
+// SuggestionCategoryCount 有某类别优化建议的分析数
+type SuggestionCategoryCount struct {
+	Category     string
+	Analyses     int
+	HighPriority int // 有该类别高优先级建议的分析数
+}
+
+// SuggestionCategoryCounts 按类别统计有优化建议的分析数，同时返回有优化建议的分析总数
+func (r *AnalysisResultRepository) SuggestionCategoryCounts(ctx context.Context, analysisIDs []int64) ([]SuggestionCategoryCount, int, error) {
+	if len(analysisIDs) == 0 {
+		return nil, 0, nil
+	}
+
+	var counts []SuggestionCategoryCount
+	if err := r.db.WithContext(ctx).Model(&model.GEOSuggestion{}).
+		Select(suggestionCategory+" AS category, COUNT(DISTINCT analysis_id) AS analyses, "+
+			"COUNT(DISTINCT CASE WHEN priority = 'high' THEN analysis_id END) AS high_priority").
+		Where("analysis_id IN ?", analysisIDs).
+		Group(suggestionCategory).
+		Scan(&counts).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计优化建议类别失败: %w", err)
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.GEOSuggestion{}).
+		Where("analysis_id IN ?", analysisIDs).
+		Distinct("analysis_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计优化建议失败: %w", err)
+	}
+	return counts, int(total), nil
+}
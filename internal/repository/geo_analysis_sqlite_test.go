@@ -0,0 +1,135 @@
+//go:build sqlite_fts5
+
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/database"
+	"github.com/solariswu/peanut/internal/pkg/migrate"
+)
+
+// openTestDB 创建执行过全部迁移的临时 SQLite 数据库
+// 全文搜索迁移依赖 FTS5，依赖数据库的测试放在带 sqlite_fts5 构建标签的文件中（make test 已包含该标签）
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLite() error = %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	m, err := migrate.New(db.DB())
+	if err != nil {
+		t.Fatalf("migrate.New() error = %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return db.DB()
+}
+
+// TestListFilters 测试域名、子串和全文搜索条件
+func TestListFilters(t *testing.T) {
+	repo := NewGEOAnalysisRepository(openTestDB(t))
+	analyses := []model.GEOAnalysis{
+		{URL: "https://example.com/a", Title: "100% 指南",
+			AIOverview: "Generative engine optimization improves citations", OptimizationReport: "增加权威引用来源"},
+		{URL: "https://blog.example.com/b", Title: "100 guides", OptimizedArticle: "Search engine basics"},
+		{URL: "https://www.example.com", Title: "home_page"},
+		{URL: "https://notexample.com/x", Title: "homeXpage", AIOverview: "Generative models"},
+		{URL: "https://example.com.evil.io/x", MainQuery: "example"},
+	}
+	for i := range analyses {
+		if err := repo.Create(&analyses[i]); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  model.GEOAnalysisListRequest
+		want []string
+	}{
+		{name: "域名包括子域名", req: model.GEOAnalysisListRequest{Domain: "example.com"},
+			want: []string{"https://blog.example.com/b", "https://example.com/a", "https://www.example.com"}},
+		{name: "www 前缀", req: model.GEOAnalysisListRequest{Domain: "WWW.Example.com"},
+			want: []string{"https://blog.example.com/b", "https://example.com/a", "https://www.example.com"}},
+		{name: "子域名", req: model.GEOAnalysisListRequest{Domain: "blog.example.com"},
+			want: []string{"https://blog.example.com/b"}},
+		{name: "标题中的 % 按字面匹配", req: model.GEOAnalysisListRequest{Title: "100%"},
+			want: []string{"https://example.com/a"}},
+		{name: "标题中的 _ 按字面匹配", req: model.GEOAnalysisListRequest{Title: "home_page"},
+			want: []string{"https://www.example.com"}},
+		{name: "全文前缀匹配", req: model.GEOAnalysisListRequest{Q: "generat engine"},
+			want: []string{"https://example.com/a"}},
+		{name: "全文跨字段", req: model.GEOAnalysisListRequest{Q: "engine"},
+			want: []string{"https://blog.example.com/b", "https://example.com/a"}},
+		{name: "全文去除运算符", req: model.GEOAnalysisListRequest{Q: `"engine"* -basics`},
+			want: []string{"https://blog.example.com/b"}},
+		{name: "中文回退到子串匹配", req: model.GEOAnalysisListRequest{Q: "权威"},
+			want: []string{"https://example.com/a"}},
+		{name: "中文和英文", req: model.GEOAnalysisListRequest{Q: "engine 权威"},
+			want: []string{"https://example.com/a"}},
+		{name: "没有命中", req: model.GEOAnalysisListRequest{Q: "engine models"},
+			want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.Page, req.PageSize, req.OrderBy = 1, 100, "id"
+			got, total, err := repo.List(&req)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			urls := make([]string, len(got))
+			for i := range got {
+				urls[i] = got[i].URL
+			}
+			sort.Strings(urls)
+			if !reflect.DeepEqual(urls, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("List() = %q (total %d), want %q", urls, total, tt.want)
+			}
+		})
+	}
+}
+
+// TestSnippets 测试 FTS5 片段只返回命中的字段并标记命中词
+func TestSnippets(t *testing.T) {
+	repo := NewGEOAnalysisRepository(openTestDB(t))
+	analyses := []model.GEOAnalysis{
+		{URL: "https://example.com/a", AIOverview: "Generative engine optimization", OptimizationReport: "增加权威引用来源"},
+		{URL: "https://example.com/b", OptimizedArticle: "Search <b>engines</b> basics"},
+		{URL: "https://example.com/c", AIOverview: "engine"},
+	}
+	for i := range analyses {
+		if err := repo.Create(&analyses[i]); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	snippets, err := repo.Snippets([]int64{analyses[0].ID, analyses[1].ID}, []string{"engine", "权威"})
+	if err != nil {
+		t.Fatalf("Snippets() error = %v", err)
+	}
+	want := map[int64]map[string]string{
+		analyses[0].ID: {"ai_overview": "Generative " + SnippetStart + "engine" + SnippetEnd + " optimization"},
+		analyses[1].ID: {"optimized_article": "Search <b>" + SnippetStart + "engines" + SnippetEnd + "</b> basics"},
+	}
+	if !reflect.DeepEqual(snippets, want) {
+		t.Errorf("Snippets() = %q, want %q", snippets, want)
+	}
+
+	// 只有不分词的词时由调用方在文本中查找
+	if snippets, err := repo.Snippets([]int64{analyses[0].ID}, []string{"权威"}); err != nil || snippets != nil {
+		t.Errorf("Snippets(权威) = %v, %v, want nil, nil", snippets, err)
+	}
+}
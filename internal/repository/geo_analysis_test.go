@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSearchTerms 测试搜索词切分、去除标点和运算符、去重
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []string
+	}{
+		{name: "标点", q: "Hello, World!", want: []string{"hello", "world"}},
+		{name: "FTS 运算符", q: `"engine"* -basics NEAR(a b) title:geo^`, want: []string{"engine", "basics", "near", "a", "b", "title", "geo"}},
+		{name: "大小写去重", q: "GEO geo Geo", want: []string{"geo"}},
+		{name: "中文", q: "生成式，引擎优化", want: []string{"生成式", "引擎优化"}},
+		{name: "字母和数字", q: "c++ 2024年", want: []string{"c", "2024年"}},
+		{name: "只有标点", q: " ... *** ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchTerms(tt.q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchTerms(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchQuery 测试 FTS5 查询中的词加引号并按前缀匹配
+func TestMatchQuery(t *testing.T) {
+	if got, want := matchQuery([]string{"engine", "and"}), `"engine"* AND "and"*`; got != want {
+		t.Errorf("matchQuery() = %q, want %q", got, want)
+	}
+}
+
+// TestEscapeLike 测试转义 LIKE 通配符
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"example.com", "example.com"},
+		{"100%", `100\%`},
+		{"home_page", `home\_page`},
+		{`a\b`, `a\\b`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.in); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
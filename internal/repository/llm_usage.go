@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// LLMUsageRepository LLM 用量仓储
+type LLMUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewLLMUsageRepository 创建 LLM 用量仓储
+func NewLLMUsageRepository(db *gorm.DB) *LLMUsageRepository {
+	return &LLMUsageRepository{db: db}
+}
+
+// ReplaceByAnalysis 替换某次分析的用量记录
+func (r *LLMUsageRepository) ReplaceByAnalysis(ctx context.Context, analysisID int64, usages []model.GEOLLMUsage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("analysis_id = ?", analysisID).Delete(&model.GEOLLMUsage{}).Error; err != nil {
+			return fmt.Errorf("删除 LLM 用量记录失败: %w", err)
+		}
+		if len(usages) > 0 {
+			if err := tx.Create(&usages).Error; err != nil {
+				return fmt.Errorf("写入 LLM 用量记录失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByAnalysis 删除某次分析的用量记录
+func (r *LLMUsageRepository) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	if err := r.db.WithContext(ctx).Where("analysis_id = ?", analysisID).Delete(&model.GEOLLMUsage{}).Error; err != nil {
+		return fmt.Errorf("删除 LLM 用量记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListByAnalysis 查询某次分析的用量记录
+func (r *LLMUsageRepository) ListByAnalysis(ctx context.Context, analysisID int64) ([]model.GEOLLMUsage, error) {
+	var usages []model.GEOLLMUsage
+	if err := r.db.WithContext(ctx).
+		Where("analysis_id = ?", analysisID).
+		Order("id ASC").
+		Find(&usages).Error; err != nil {
+		return nil, fmt.Errorf("获取 LLM 用量记录失败: %w", err)
+	}
+	return usages, nil
+}
+
+// ListBetween 查询时间范围内的用量记录，userID 为 nil 时返回所有用户
+func (r *LLMUsageRepository) ListBetween(ctx context.Context, from, to time.Time, userID *int64) ([]model.GEOLLMUsage, error) {
+	var usages []model.GEOLLMUsage
+	query := r.db.WithContext(ctx).Where("created_at >= ? AND created_at < ?", from, to)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if err := query.Order("id ASC").Find(&usages).Error; err != nil {
+		return nil, fmt.Errorf("获取 LLM 用量记录失败: %w", err)
+	}
+	return usages, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrPromptVersionNotFound prompt 版本不存在
+var ErrPromptVersionNotFound = errors.New("prompt 版本不存在")
+
+// PromptRepository prompt 版本仓储
+type PromptRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptRepository 创建 prompt 版本仓储
+func NewPromptRepository(db *gorm.DB) *PromptRepository {
+	return &PromptRepository{db: db}
+}
+
+// Create 创建新版本，版本号为该名称当前最大版本号加一；prompt.Active 为 true 时同时停用其他版本
+func (r *PromptRepository) Create(ctx context.Context, prompt *model.GEOPrompt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.GEOPrompt{}).
+			Where("name = ?", prompt.Name).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("获取 prompt 最新版本失败: %w", err)
+		}
+		prompt.Version = latest + 1
+
+		if prompt.Active {
+			if err := tx.Model(&model.GEOPrompt{}).
+				Where("name = ? AND active = ?", prompt.Name, true).
+				Update("active", false).Error; err != nil {
+				return fmt.Errorf("停用 prompt 版本失败: %w", err)
+			}
+		}
+		if err := tx.Create(prompt).Error; err != nil {
+			return fmt.Errorf("创建 prompt 版本失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListByName 按版本号倒序查询某个 prompt 的所有版本
+func (r *PromptRepository) ListByName(ctx context.Context, name string) ([]model.GEOPrompt, error) {
+	var prompts []model.GEOPrompt
+	if err := r.db.WithContext(ctx).
+		Where("name = ?", name).
+		Order("version DESC").
+		Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("获取 prompt 版本失败: %w", err)
+	}
+	return prompts, nil
+}
+
+// GetVersion 获取某个 prompt 的指定版本
+func (r *PromptRepository) GetVersion(ctx context.Context, name string, version int) (*model.GEOPrompt, error) {
+	var prompt model.GEOPrompt
+	if err := r.db.WithContext(ctx).Where("name = ? AND version = ?", name, version).First(&prompt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromptVersionNotFound
+		}
+		return nil, fmt.Errorf("获取 prompt 版本失败: %w", err)
+	}
+	return &prompt, nil
+}
+
+// CountByName 统计各 prompt 的版本数
+func (r *PromptRepository) CountByName(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Name  string
+		Count int
+	}
+	if err := r.db.WithContext(ctx).Model(&model.GEOPrompt{}).
+		Select("name, COUNT(*) AS count").
+		Group("name").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计 prompt 版本失败: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Name] = row.Count
+	}
+	return counts, nil
+}
+
+// ListActive 查询所有激活的版本
+func (r *PromptRepository) ListActive(ctx context.Context) ([]model.GEOPrompt, error) {
+	var prompts []model.GEOPrompt
+	if err := r.db.WithContext(ctx).
+		Where("active = ?", true).
+		Find(&prompts).Error; err != nil {
+		return nil, fmt.Errorf("获取激活的 prompt 失败: %w", err)
+	}
+	return prompts, nil
+}
+
+// Activate 激活指定版本并停用同名其他版本
+func (r *PromptRepository) Activate(ctx context.Context, name string, version int) (*model.GEOPrompt, error) {
+	var prompt model.GEOPrompt
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name = ? AND version = ?", name, version).First(&prompt).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPromptVersionNotFound
+			}
+			return fmt.Errorf("获取 prompt 版本失败: %w", err)
+		}
+		if err := tx.Model(&model.GEOPrompt{}).
+			Where("name = ? AND version <> ?", name, version).
+			Update("active", false).Error; err != nil {
+			return fmt.Errorf("停用 prompt 版本失败: %w", err)
+		}
+		if err := tx.Model(&prompt).Update("active", true).Error; err != nil {
+			return fmt.Errorf("激活 prompt 版本失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+// Deactivate 停用某个 prompt 的所有版本
+func (r *PromptRepository) Deactivate(ctx context.Context, name string) error {
+	if err := r.db.WithContext(ctx).Model(&model.GEOPrompt{}).
+		Where("name = ? AND active = ?", name, true).
+		Update("active", false).Error; err != nil {
+		return fmt.Errorf("停用 prompt 版本失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrExperimentNotFound 实验不存在
+var ErrExperimentNotFound = errors.New("实验不存在")
+
+// PromptExperimentRepository prompt A/B 实验仓储
+type PromptExperimentRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptExperimentRepository 创建 prompt A/B 实验仓储
+func NewPromptExperimentRepository(db *gorm.DB) *PromptExperimentRepository {
+	return &PromptExperimentRepository{db: db}
+}
+
+// Create 创建实验及其变体
+func (r *PromptExperimentRepository) Create(ctx context.Context, exp *model.GEOPromptExperiment) error {
+	if err := r.db.WithContext(ctx).Create(exp).Error; err != nil {
+		return fmt.Errorf("创建实验失败: %w", err)
+	}
+	return nil
+}
+
+// orderVariants 变体按创建顺序排列，保证分配结果稳定
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// GetByID 获取实验及其变体
+func (r *PromptExperimentRepository) GetByID(ctx context.Context, id int64) (*model.GEOPromptExperiment, error) {
+	var exp model.GEOPromptExperiment
+	if err := r.db.WithContext(ctx).Preload("Variants", orderVariants).First(&exp, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExperimentNotFound
+		}
+		return nil, fmt.Errorf("获取实验失败: %w", err)
+	}
+	return &exp, nil
+}
+
+// List 查询实验列表，status 为空时返回全部
+func (r *PromptExperimentRepository) List(ctx context.Context, status string) ([]model.GEOPromptExperiment, error) {
+	query := r.db.WithContext(ctx).Preload("Variants", orderVariants)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var exps []model.GEOPromptExperiment
+	if err := query.Order("id DESC").Find(&exps).Error; err != nil {
+		return nil, fmt.Errorf("获取实验列表失败: %w", err)
+	}
+	return exps, nil
+}
+
+// HasRunning 检查 prompt 是否已有进行中的实验
+func (r *PromptExperimentRepository) HasRunning(ctx context.Context, prompt string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.GEOPromptExperiment{}).
+		Where("prompt = ? AND status = ?", prompt, model.ExperimentStatusRunning).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查询进行中的实验失败: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Stop 停止实验
+func (r *PromptExperimentRepository) Stop(ctx context.Context, id int64) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&model.GEOPromptExperiment{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": model.ExperimentStatusStopped, "stopped_at": &now}).Error; err != nil {
+		return fmt.Errorf("停止实验失败: %w", err)
+	}
+	return nil
+}
+
+// CreateAssignments 保存分析的变体分配
+func (r *PromptExperimentRepository) CreateAssignments(ctx context.Context, assignments []model.GEOPromptExperimentAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Create(&assignments).Error; err != nil {
+		return fmt.Errorf("保存实验分配失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteAssignmentsByAnalysis 删除分析的变体分配
+func (r *PromptExperimentRepository) DeleteAssignmentsByAnalysis(ctx context.Context, analysisID int64) error {
+	if err := r.db.WithContext(ctx).Where("analysis_id = ?", analysisID).Delete(&model.GEOPromptExperimentAssignment{}).Error; err != nil {
+		return fmt.Errorf("删除实验分配失败: %w", err)
+	}
+	return nil
+}
+
+// ExperimentVariantStats 变体的分析结果统计
+type ExperimentVariantStats struct {
+	VariantKey         string
+	Analyses           int
+	Completed          int
+	Failed             int
+	AvgOverallScore    float64
+	Validated          int
+	AvgValidationScore float64
+	ParseFailed        int
+	Ratings            int
+	AvgRating          float64
+}
+
+// VariantStats 按变体统计分配分析的状态、评分、验证评分、解析失败和用户评分
+func (r *PromptExperimentRepository) VariantStats(ctx context.Context, experimentID int64) ([]ExperimentVariantStats, error) {
+	var stats []ExperimentVariantStats
+	if err := r.db.WithContext(ctx).
+		Table("geo_prompt_experiment_assignments AS a").
+		Select(`a.variant_key,
+			COUNT(*) AS analyses,
+			SUM(CASE WHEN g.status = 'completed' THEN 1 ELSE 0 END) AS completed,
+			SUM(CASE WHEN g.status = 'failed' THEN 1 ELSE 0 END) AS failed,
+			COALESCE(AVG(CASE WHEN g.status = 'completed' THEN g.overall_score END), 0) AS avg_overall_score,
+			COUNT(CASE WHEN g.status = 'completed' THEN g.validation_score END) AS validated,
+			COALESCE(AVG(CASE WHEN g.status = 'completed' THEN g.validation_score END), 0) AS avg_validation_score,
+			SUM(CASE WHEN a.parse_failures > 0 THEN 1 ELSE 0 END) AS parse_failed,
+			COUNT(g.rating) AS ratings,
+			COALESCE(AVG(g.rating), 0) AS avg_rating`).
+		Joins("JOIN geo_analyses AS g ON g.id = a.analysis_id").
+		Where("a.experiment_id = ?", experimentID).
+		Group("a.variant_key").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("统计实验结果失败: %w", err)
+	}
+	return stats, nil
+}
+
+// ExperimentUsageStats 变体中实验 Agent 的 LLM 用量统计
+type ExperimentUsageStats struct {
+	VariantKey string
+	Analyses   int
+	Tokens     int
+	Cost       float64
+	Currency   string
+}
+
+// UsageStats 按变体统计实验 Agent（agent）的 LLM 用量
+func (r *PromptExperimentRepository) UsageStats(ctx context.Context, experimentID int64, agent string) ([]ExperimentUsageStats, error) {
+	var stats []ExperimentUsageStats
+	if err := r.db.WithContext(ctx).
+		Table("geo_prompt_experiment_assignments AS a").
+		Select(`a.variant_key,
+			COUNT(DISTINCT a.analysis_id) AS analyses,
+			COALESCE(SUM(u.total_tokens), 0) AS tokens,
+			COALESCE(SUM(u.cost), 0) AS cost,
+			MAX(u.currency) AS currency`).
+		Joins("JOIN geo_llm_usages AS u ON u.analysis_id = a.analysis_id AND u.agent = ?", agent).
+		Where("a.experiment_id = ?", experimentID).
+		Group("a.variant_key").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("统计实验用量失败: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrQuerySetNotFound 查询集不存在
+var ErrQuerySetNotFound = errors.New("查询集不存在")
+
+// QuerySetRepository 查询集仓储
+type QuerySetRepository struct {
+	db *gorm.DB
+}
+
+// NewQuerySetRepository 创建查询集仓储
+func NewQuerySetRepository(db *gorm.DB) *QuerySetRepository {
+	return &QuerySetRepository{db: db}
+}
+
+// Create 创建查询集
+func (r *QuerySetRepository) Create(ctx context.Context, set *model.QuerySet) error {
+	if err := r.db.WithContext(ctx).Create(set).Error; err != nil {
+		return fmt.Errorf("创建查询集失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取查询集
+func (r *QuerySetRepository) GetByID(ctx context.Context, id int64) (*model.QuerySet, error) {
+	var set model.QuerySet
+	if err := r.db.WithContext(ctx).First(&set, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuerySetNotFound
+		}
+		return nil, fmt.Errorf("获取查询集失败: %w", err)
+	}
+	return &set, nil
+}
+
+// List 查询查询集列表
+func (r *QuerySetRepository) List(ctx context.Context, userID *int64) ([]model.QuerySet, error) {
+	var sets []model.QuerySet
+	query := r.db.WithContext(ctx).Model(&model.QuerySet{})
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if err := query.Order("id DESC").Find(&sets).Error; err != nil {
+		return nil, fmt.Errorf("获取查询集列表失败: %w", err)
+	}
+	return sets, nil
+}
+
+// ListDue 获取已到采集时间的查询集
+func (r *QuerySetRepository) ListDue(ctx context.Context, now time.Time) ([]model.QuerySet, error) {
+	var sets []model.QuerySet
+	if err := r.db.WithContext(ctx).Where("enabled = ?", true).Find(&sets).Error; err != nil {
+		return nil, fmt.Errorf("获取待采集查询集失败: %w", err)
+	}
+
+	due := make([]model.QuerySet, 0, len(sets))
+	for _, set := range sets {
+		interval := time.Duration(set.IntervalMinutes) * time.Minute
+		if set.LastRunAt == nil || !now.Before(set.LastRunAt.Add(interval)) {
+			due = append(due, set)
+		}
+	}
+	return due, nil
+}
+
+// Update 更新查询集
+func (r *QuerySetRepository) Update(ctx context.Context, set *model.QuerySet) error {
+	if err := r.db.WithContext(ctx).Save(set).Error; err != nil {
+		return fmt.Errorf("更新查询集失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateFields 更新指定字段
+func (r *QuerySetRepository) UpdateFields(ctx context.Context, id int64, fields map[string]any) error {
+	return r.db.WithContext(ctx).Model(&model.QuerySet{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// Delete 删除查询集及其采集数据
+func (r *QuerySetRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("query_set_id = ?", id).Delete(&model.CitationSnapshot{}).Error; err != nil {
+			return fmt.Errorf("删除引用记录失败: %w", err)
+		}
+		if err := tx.Where("query_set_id = ?", id).Delete(&model.QuerySetRun{}).Error; err != nil {
+			return fmt.Errorf("删除采集记录失败: %w", err)
+		}
+		result := tx.Delete(&model.QuerySet{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("删除查询集失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrQuerySetNotFound
+		}
+		return nil
+	})
+}
+
+// CreateRun 创建采集记录
+func (r *QuerySetRepository) CreateRun(ctx context.Context, run *model.QuerySetRun) error {
+	if err := r.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("创建采集记录失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateRun 更新采集记录
+func (r *QuerySetRepository) UpdateRun(ctx context.Context, run *model.QuerySetRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// ListRuns 查询采集记录（按时间倒序）
+func (r *QuerySetRepository) ListRuns(ctx context.Context, querySetID int64, limit int) ([]model.QuerySetRun, error) {
+	var runs []model.QuerySetRun
+	if err := r.db.WithContext(ctx).
+		Where("query_set_id = ?", querySetID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("获取采集记录失败: %w", err)
+	}
+	return runs, nil
+}
+
+// ListCompletedRunsBetween 查询时间范围内已完成的采集记录
+func (r *QuerySetRepository) ListCompletedRunsBetween(ctx context.Context, querySetID int64, from, to time.Time) ([]model.QuerySetRun, error) {
+	var runs []model.QuerySetRun
+	if err := r.db.WithContext(ctx).
+		Where("query_set_id = ? AND status = ? AND created_at >= ? AND created_at < ?", querySetID, model.QuerySetRunCompleted, from, to).
+		Order("created_at ASC").
+		Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("获取采集记录失败: %w", err)
+	}
+	return runs, nil
+}
+
+// CreateSnapshots 批量写入引用记录
+func (r *QuerySetRepository) CreateSnapshots(ctx context.Context, snapshots []model.CitationSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(snapshots, 100).Error; err != nil {
+		return fmt.Errorf("写入引用记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshotsByRuns 查询指定采集的引用记录
+func (r *QuerySetRepository) ListSnapshotsByRuns(ctx context.Context, runIDs []int64) ([]model.CitationSnapshot, error) {
+	var snapshots []model.CitationSnapshot
+	if len(runIDs) == 0 {
+		return snapshots, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("run_id IN ?", runIDs).
+		Order("cited_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("获取引用记录失败: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrReportTemplateNotFound 报告模板不存在
+var ErrReportTemplateNotFound = errors.New("报告模板不存在")
+
+// ReportTemplateRepository 报告模板仓储
+type ReportTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewReportTemplateRepository 创建报告模板仓储
+func NewReportTemplateRepository(db *gorm.DB) *ReportTemplateRepository {
+	return &ReportTemplateRepository{db: db}
+}
+
+// GetByWorkspace 获取工作区的报告模板
+func (r *ReportTemplateRepository) GetByWorkspace(ctx context.Context, workspace string) (*model.GEOReportTemplate, error) {
+	var tmpl model.GEOReportTemplate
+	if err := r.db.WithContext(ctx).Where("workspace = ?", workspace).First(&tmpl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportTemplateNotFound
+		}
+		return nil, fmt.Errorf("获取报告模板失败: %w", err)
+	}
+	return &tmpl, nil
+}
+
+// List 查询所有报告模板
+func (r *ReportTemplateRepository) List(ctx context.Context) ([]model.GEOReportTemplate, error) {
+	var templates []model.GEOReportTemplate
+	if err := r.db.WithContext(ctx).Order("workspace ASC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("获取报告模板列表失败: %w", err)
+	}
+	return templates, nil
+}
+
+// Save 创建或更新报告模板
+func (r *ReportTemplateRepository) Save(ctx context.Context, tmpl *model.GEOReportTemplate) error {
+	if err := r.db.WithContext(ctx).Save(tmpl).Error; err != nil {
+		return fmt.Errorf("保存报告模板失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除工作区的报告模板
+func (r *ReportTemplateRepository) Delete(ctx context.Context, workspace string) error {
+	result := r.db.WithContext(ctx).Where("workspace = ?", workspace).Delete(&model.GEOReportTemplate{})
+	if result.Error != nil {
+		return fmt.Errorf("删除报告模板失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrReportTemplateNotFound
+	}
+	return nil
+}
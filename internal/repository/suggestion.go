@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// ErrSuggestionNotFound 优化建议不存在
+var ErrSuggestionNotFound = errors.New("优化建议不存在")
+
+// SuggestionRepository 优化建议任务仓储
+type SuggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository 创建优化建议仓储
+func NewSuggestionRepository(db *gorm.DB) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// suggestionQuery 关联所属分析的 URL、标题和评分
+func (r *SuggestionRepository) suggestionQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("geo_suggestions AS s").
+		Joins("JOIN geo_analyses AS a ON a.id = s.analysis_id")
+}
+
+// GetByID 根据 ID 获取优化建议
+func (r *SuggestionRepository) GetByID(ctx context.Context, id int64) (*model.GEOSuggestionResponse, error) {
+	var suggestions []model.GEOSuggestionResponse
+	if err := r.suggestionQuery(ctx).
+		Select("s.*, a.url AS url, a.title AS title, a.overall_score AS overall_score").
+		Where("s.id = ?", id).
+		Limit(1).
+		Scan(&suggestions).Error; err != nil {
+		return nil, fmt.Errorf("获取优化建议失败: %w", err)
+	}
+	if len(suggestions) == 0 {
+		return nil, ErrSuggestionNotFound
+	}
+	return &suggestions[0], nil
+}
+
+// List 跨分析查询优化建议
+func (r *SuggestionRepository) List(ctx context.Context, req *model.GEOSuggestionListRequest) ([]model.GEOSuggestionResponse, int64, error) {
+	query := r.suggestionQuery(ctx)
+
+	if req.AnalysisID != nil {
+		query = query.Where("s.analysis_id = ?", *req.AnalysisID)
+	}
+	if req.Priority != "" {
+		query = query.Where("s.priority = ?", req.Priority)
+	}
+	if req.Category != "" {
+		query = query.Where("s.category = ?", req.Category)
+	}
+	if req.Status != "" {
+		query = query.Where("s.status = ?", req.Status)
+	}
+	if req.Assignee != "" {
+		query = query.Where("s.assignee = ?", req.Assignee)
+	}
+	if req.Overdue {
+		query = query.Where("s.due_date < ? AND s.status IN ?", time.Now(),
+			[]string{model.SuggestionStatusOpen, model.SuggestionStatusInProgress})
+	}
+	if req.Keyword != "" {
+		like := "%" + req.Keyword + "%"
+		query = query.Where("s.issue LIKE ? OR s.suggestion LIKE ?", like, like)
+	}
+	if req.UserID != nil {
+		query = query.Where("a.user_id = ?", *req.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计优化建议失败: %w", err)
+	}
+
+	var suggestions []model.GEOSuggestionResponse
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Select("s.*, a.url AS url, a.title AS title, a.overall_score AS overall_score").
+		Order("s.created_at DESC, s.position ASC").
+		Offset(offset).Limit(req.PageSize).
+		Scan(&suggestions).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询优化建议失败: %w", err)
+	}
+	return suggestions, total, nil
+}
+
+// UpdateFields 更新优化建议的指定字段
+func (r *SuggestionRepository) UpdateFields(ctx context.Context, id int64, fields map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&model.GEOSuggestion{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return fmt.Errorf("更新优化建议失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSuggestionNotFound
+	}
+	return nil
+}
+
+// AddComment 添加评论
+func (r *SuggestionRepository) AddComment(ctx context.Context, comment *model.GEOSuggestionComment) error {
+	if err := r.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return fmt.Errorf("添加评论失败: %w", err)
+	}
+	return nil
+}
+
+// ListComments 查询优化建议的评论
+func (r *SuggestionRepository) ListComments(ctx context.Context, suggestionID int64) ([]model.GEOSuggestionComment, error) {
+	comments := []model.GEOSuggestionComment{}
+	if err := r.db.WithContext(ctx).
+		Where("suggestion_id = ?", suggestionID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+	return comments, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/logging"
+)
+
+// ErrInvalidLogLevel 日志级别无效
+var ErrInvalidLogLevel = errors.New("日志级别无效，可选 debug、info、warn、error")
+
+// logLevels 按严重程度排列的日志级别
+var logLevels = []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel}
+
+// levelsAtLeast 返回不低于 minLevel 的日志级别，minLevel 为空时返回 nil（不过滤）
+func levelsAtLeast(minLevel string) ([]string, error) {
+	if minLevel == "" {
+		return nil, nil
+	}
+	min, err := zapcore.ParseLevel(minLevel)
+	if err != nil {
+		return nil, ErrInvalidLogLevel
+	}
+
+	var levels []string
+	for _, l := range logLevels {
+		if l >= min {
+			levels = append(levels, l.String())
+		}
+	}
+	return levels, nil
+}
+
+// toAnalysisLogs 将缓冲日志转换为持久化记录，dropped 大于 0 时在开头追加一条说明
+func toAnalysisLogs(analysisID int64, entries []logging.Entry, dropped int) []model.GEOAnalysisLog {
+	logs := make([]model.GEOAnalysisLog, 0, len(entries)+1)
+	if dropped > 0 && len(entries) > 0 {
+		logs = append(logs, model.GEOAnalysisLog{
+			AnalysisID: analysisID,
+			Time:       entries[0].Time,
+			Level:      zapcore.WarnLevel.String(),
+			Message:    fmt.Sprintf("日志超过缓冲上限，已丢弃最早的 %d 条", dropped),
+		})
+	}
+	for _, e := range entries {
+		record := model.GEOAnalysisLog{
+			AnalysisID: analysisID,
+			Time:       e.Time,
+			Level:      e.Level,
+			Message:    e.Message,
+		}
+		if len(e.Fields) > 0 {
+			if data, err := json.Marshal(e.Fields); err == nil {
+				record.Fields = string(data)
+			}
+		}
+		logs = append(logs, record)
+	}
+	for i := range logs {
+		logs[i].Seq = i + 1
+	}
+	return logs
+}
+
+// saveLogs 保存分析的执行日志（失败只记录日志）
+func (s *GEOAnalysisService) saveLogs(analysisID int64, buf *logging.Buffer) {
+	if s.logRepo == nil {
+		return
+	}
+
+	// 分析上下文可能已取消，使用独立的超时上下文写入
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	logs := toAnalysisLogs(analysisID, buf.Entries(), buf.Dropped())
+	if err := s.logRepo.ReplaceByAnalysis(ctx, analysisID, logs); err != nil {
+		zap.L().Error("保存执行日志失败",
+			zap.Int64("analysis_id", analysisID),
+			zap.Error(err))
+	}
+}
+
+// GetLogs 获取分析的执行日志，minLevel 不为空时只返回不低于该级别的日志
+// 分析执行中返回内存缓冲中的日志，结束后返回已保存的日志
+func (s *GEOAnalysisService) GetLogs(ctx context.Context, id int64, minLevel string) (*model.GEOAnalysisLogResponse, error) {
+	levels, err := levelsAtLeast(minLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+
+	result := &model.GEOAnalysisLogResponse{
+		AnalysisID: id,
+		Logs:       []model.GEOAnalysisLog{},
+	}
+
+	if buf, ok := s.liveLogs.Load(id); ok {
+		b := buf.(*logging.Buffer)
+		result.Running = true
+		result.Dropped = b.Dropped()
+
+		allowed := make(map[string]bool, len(levels))
+		for _, l := range levels {
+			allowed[l] = true
+		}
+		for _, l := range toAnalysisLogs(id, b.Entries(), 0) {
+			if len(allowed) == 0 || allowed[l.Level] {
+				result.Logs = append(result.Logs, l)
+			}
+		}
+		return result, nil
+	}
+
+	if s.logRepo != nil {
+		logs, err := s.logRepo.ListByAnalysis(ctx, id, levels)
+		if err != nil {
+			return nil, err
+		}
+		result.Logs = append(result.Logs, logs...)
+	}
+	return result, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/parser"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// backfillBatchSize 每批回填的分析数量
+const backfillBatchSize = 100
+
+// AnalysisResultService 分析结构化结果服务：拆分保存优化报告和回填旧记录
+type AnalysisResultService struct {
+	repo *repository.AnalysisResultRepository
+}
+
+// NewAnalysisResultService 创建分析结果服务
+func NewAnalysisResultService(repo *repository.AnalysisResultRepository) *AnalysisResultService {
+	return &AnalysisResultService{repo: repo}
+}
+
+// Save 将优化报告拆分为查询、搜索结果、来源、内容差距、对比项和优化建议保存
+func (s *AnalysisResultService) Save(ctx context.Context, analysisID int64, report *models.OptimizationReport) error {
+	return s.repo.Replace(ctx, analysisID, buildAnalysisResults(analysisID, report))
+}
+
+// Get 获取分析的结构化结果
+func (s *AnalysisResultService) Get(ctx context.Context, analysisID int64) (*model.GEOAnalysisResults, error) {
+	return s.repo.Get(ctx, analysisID)
+}
+
+// DeleteByAnalysis 删除分析的结构化结果
+func (s *AnalysisResultService) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	return s.repo.DeleteByAnalysis(ctx, analysisID)
+}
+
+// Backfill 将结构化结果尚未写入子表的已完成分析（JSON 字段和报告原文）拆分写入子表，返回回填的分析数量
+func (s *AnalysisResultService) Backfill(ctx context.Context) (int, error) {
+	var count int
+	for {
+		analyses, err := s.repo.ListUnnormalized(ctx, backfillBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(analyses) == 0 {
+			return count, nil
+		}
+		for i := range analyses {
+			analysis := &analyses[i]
+			if err := s.repo.Replace(ctx, analysis.ID, buildAnalysisResults(analysis.ID, legacyReport(analysis))); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
+
+// buildAnalysisResults 将优化报告拆分为各子表的记录
+func buildAnalysisResults(analysisID int64, report *models.OptimizationReport) *model.GEOAnalysisResults {
+	results := &model.GEOAnalysisResults{}
+
+	for _, q := range report.RelatedQueries {
+		if q = strings.TrimSpace(q); q != "" {
+			results.Queries = append(results.Queries, model.GEOAnalysisQuery{
+				AnalysisID: analysisID,
+				Position:   len(results.Queries) + 1,
+				Query:      q,
+			})
+		}
+	}
+
+	for _, r := range report.SearchResults {
+		results.SearchResults = append(results.SearchResults, model.GEOAnalysisSearchResult{
+			AnalysisID: analysisID,
+			Position:   len(results.SearchResults) + 1,
+			Title:      r.Title,
+			URL:        r.URL,
+			Domain:     tools.Domain(r.URL),
+			Snippet:    r.Snippet,
+		})
+	}
+
+	// AI 摘要引用的来源按引用顺序保存，竞品引用分析补充标题和被引用原因
+	competitors := map[string]models.CompetitorSource{}
+	var competitorURLs []string
+	if report.CompetitorAnalysis != nil {
+		for _, src := range report.CompetitorAnalysis.Sources {
+			competitors[src.URL] = src
+			competitorURLs = append(competitorURLs, src.URL)
+		}
+	}
+	seen := map[string]bool{}
+	for _, u := range append(append([]string(nil), report.Sources...), competitorURLs...) {
+		if u = strings.TrimSpace(u); u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		src := competitors[u]
+		results.Sources = append(results.Sources, model.GEOAnalysisSource{
+			AnalysisID: analysisID,
+			Position:   len(results.Sources) + 1,
+			URL:        u,
+			Domain:     tools.Domain(u),
+			Title:      src.Title,
+			Strengths:  src.Strengths,
+		})
+	}
+
+	for _, gap := range report.ContentGaps {
+		if gap = strings.Join(strings.Fields(gap), " "); gap != "" {
+			results.ContentGaps = append(results.ContentGaps, model.GEOAnalysisContentGap{
+				AnalysisID: analysisID,
+				Position:   len(results.ContentGaps) + 1,
+				Gap:        gap,
+			})
+		}
+	}
+
+	for _, item := range report.ComparisonTable {
+		results.ComparisonItems = append(results.ComparisonItems, model.GEOAnalysisComparisonItem{
+			AnalysisID:  analysisID,
+			Position:    len(results.ComparisonItems) + 1,
+			Dimension:   item.Dimension,
+			YourContent: item.YourContent,
+			AIOverview:  item.AIOverview,
+			Similarity:  item.Similarity,
+			Difference:  item.Difference,
+		})
+	}
+
+	for _, sg := range report.OptimizationSuggestions {
+		results.Suggestions = append(results.Suggestions, model.GEOSuggestion{
+			AnalysisID: analysisID,
+			Position:   len(results.Suggestions) + 1,
+			Priority:   sg.Priority,
+			Category:   sg.Category,
+			Issue:      sg.Issue,
+			Suggestion: sg.Suggestion,
+			Status:     model.SuggestionStatusOpen,
+		})
+	}
+	return results
+}
+
+// legacyReport 从分析记录的 JSON 字段和报告原文还原优化报告（结构化结果写入子表之前的存储格式）
+func legacyReport(analysis *model.GEOAnalysis) *models.OptimizationReport {
+	report := &models.OptimizationReport{
+		URL:                analysis.URL,
+		Title:              analysis.Title,
+		MainQuery:          analysis.MainQuery,
+		QueryFanout:        analysis.QueryFanout,
+		QueryFanoutSummary: analysis.QueryFanoutSummary,
+		AIOverview:         analysis.AIOverview,
+		OptimizationReport: analysis.OptimizationReport,
+		OptimizedArticle:   analysis.OptimizedArticle,
+		OverallScore:       analysis.OverallScore,
+		OutputLanguage:     analysis.OutputLanguage,
+		Timestamp:          analysis.UpdatedAt,
+	}
+	if analysis.CompletedAt != nil {
+		report.Timestamp = *analysis.CompletedAt
+	}
+	if analysis.QueryFanout != "" {
+		report.RelatedQueries = strings.Split(analysis.QueryFanout, ", ")
+	}
+
+	// 反序列化 JSON 字段，旧数据格式不符时忽略该字段
+	fields := []struct {
+		raw string
+		dst any
+	}{
+		{analysis.ContentGaps, &report.ContentGaps},
+		{analysis.OptimizationSuggestions, &report.OptimizationSuggestions},
+		{analysis.CompetitorAnalysis, &report.CompetitorAnalysis},
+		{analysis.SERPFeatures, &report.SERPFeatures},
+		{analysis.PromptVersions, &report.PromptVersions},
+	}
+	for _, f := range fields {
+		if f.raw != "" {
+			_ = json.Unmarshal([]byte(f.raw), f.dst)
+		}
+	}
+
+	// 对比表格未单独存储，从报告内容中解析
+	if analysis.OptimizationReport != "" {
+		parsed := parser.ParseOptimizationReport(analysis.OptimizationReport, analysis.URL)
+		report.ComparisonTable = parsed.ComparisonTable
+		if len(report.OptimizationSuggestions) == 0 {
+			report.OptimizationSuggestions = parsed.OptimizationSuggestions
+		}
+	}
+	return report
+}
+
+// applyAnalysisResults 用子表中的结构化结果覆盖报告的对应字段
+func applyAnalysisResults(report *models.OptimizationReport, results *model.GEOAnalysisResults) {
+	report.RelatedQueries = nil
+	for _, q := range results.Queries {
+		report.RelatedQueries = append(report.RelatedQueries, q.Query)
+	}
+
+	report.SearchResults = nil
+	for _, r := range results.SearchResults {
+		report.SearchResults = append(report.SearchResults, models.SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Snippet})
+	}
+
+	report.Sources = nil
+	for _, src := range results.Sources {
+		report.Sources = append(report.Sources, src.URL)
+	}
+
+	report.ContentGaps = nil
+	for _, gap := range results.ContentGaps {
+		report.ContentGaps = append(report.ContentGaps, gap.Gap)
+	}
+
+	report.ComparisonTable = nil
+	for _, item := range results.ComparisonItems {
+		report.ComparisonTable = append(report.ComparisonTable, models.ComparisonItem{
+			Dimension:   item.Dimension,
+			YourContent: item.YourContent,
+			AIOverview:  item.AIOverview,
+			Similarity:  item.Similarity,
+			Difference:  item.Difference,
+		})
+	}
+
+	report.OptimizationSuggestions = nil
+	for _, sg := range results.Suggestions {
+		report.OptimizationSuggestions = append(report.OptimizationSuggestions, models.OptimizationSuggestion{
+			Priority:   sg.Priority,
+			Category:   sg.Category,
+			Issue:      sg.Issue,
+			Suggestion: sg.Suggestion,
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// snippetRadius 片段在第一个命中词前后保留的字符数
+const snippetRadius = 60
+
+// 片段中命中词的标记
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// parseOptionalDateRange 解析列表查询的日期范围，未指定的一端不限制；返回的截止时间为 to 的次日零点（不含）
+func parseOptionalDateRange(fromStr, toStr string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromStr != "" {
+		f, err := time.ParseInLocation(dateLayout, fromStr, time.Local)
+		if err != nil {
+			return nil, nil, ErrInvalidDateRange
+		}
+		from = &f
+	}
+	if toStr != "" {
+		t, err := time.ParseInLocation(dateLayout, toStr, time.Local)
+		if err != nil {
+			return nil, nil, ErrInvalidDateRange
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// searchHighlights 返回全文搜索字段中包含搜索词的片段，字段名 -> 片段
+// 优先使用数据库生成的片段（SQLite FTS5），没有时在文本中查找搜索词（PostgreSQL、中文等不分词的词）
+func searchHighlights(analysis *model.GEOAnalysis, terms []string, snippets map[string]string) map[string]string {
+	fields := []struct {
+		name string
+		text string
+	}{
+		{"ai_overview", analysis.AIOverview},
+		{"optimization_report", analysis.OptimizationReport},
+		{"optimized_article", analysis.OptimizedArticle},
+	}
+
+	highlights := map[string]string{}
+	for _, f := range fields {
+		if snippet, ok := snippets[f.name]; ok {
+			highlights[f.name] = markSnippet(snippet)
+		} else if snippet := highlightSnippet(f.text, terms); snippet != "" {
+			highlights[f.name] = snippet
+		}
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// markSnippet 转义数据库生成的片段并将命中标记替换为 <mark>
+func markSnippet(snippet string) string {
+	escaped := html.EscapeString(strings.Join(strings.Fields(snippet), " "))
+	return strings.NewReplacer(repository.SnippetStart, highlightStart, repository.SnippetEnd, highlightEnd).Replace(escaped)
+}
+
+// span 命中词在文本中的位置（按字符计，左闭右开）
+type span struct {
+	start, end int
+}
+
+// highlightSnippet 截取第一个命中词附近的文本，命中词用 <mark> 标记，其余内容做 HTML 转义；没有命中时返回空字符串
+func highlightSnippet(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var spans []span
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == term {
+				spans = append(spans, span{i, i + len(t)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	// 合并重叠的命中
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	start := max(0, merged[0].start-snippetRadius)
+	end := min(len(runes), merged[0].end+snippetRadius)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, s := range merged {
+		if s.start >= end {
+			break
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		sb.WriteString(highlightStart)
+		sb.WriteString(html.EscapeString(string(runes[s.start:min(s.end, end)])))
+		sb.WriteString(highlightEnd)
+		pos = min(s.end, end)
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// TestParseOptionalDateRange 测试列表日期筛选，截止日期按次日零点（不含）返回
+func TestParseOptionalDateRange(t *testing.T) {
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom *time.Time
+		wantTo   *time.Time
+		wantErr  bool
+	}{
+		{name: "不限制"},
+		{name: "只有起始日期", from: "2026-10-01", wantFrom: ptr(date(1, 0))},
+		{name: "截止日期不含次日", to: "2026-10-14", wantTo: ptr(date(15, 0))},
+		{name: "同一天", from: "2026-10-14", to: "2026-10-14", wantFrom: ptr(date(14, 0)), wantTo: ptr(date(15, 0))},
+		{name: "起始晚于截止", from: "2026-10-15", to: "2026-10-14", wantErr: true},
+		{name: "格式错误", to: "14/10/2026", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := parseOptionalDateRange(tt.from, tt.to)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDateRange) {
+					t.Fatalf("parseOptionalDateRange() error = %v, want ErrInvalidDateRange", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOptionalDateRange() error = %v", err)
+			}
+			if !reflect.DeepEqual(from, tt.wantFrom) || !reflect.DeepEqual(to, tt.wantTo) {
+				t.Errorf("parseOptionalDateRange() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+// TestHighlightSnippet 测试命中词标记、重叠合并、HTML 转义和截取
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{name: "没有命中", text: "Hello World", terms: []string{"geo"}, want: ""},
+		{name: "忽略大小写", text: "Hello World", terms: []string{"world"}, want: "Hello <mark>World</mark>"},
+		{name: "重叠合并", text: "engineering", terms: []string{"engine", "engineer"}, want: "<mark>engineer</mark>ing"},
+		{name: "相邻合并", text: "ab c", terms: []string{"a", "b"}, want: "<mark>ab</mark> c"},
+		{name: "多处命中", text: "geo and seo", terms: []string{"seo", "geo"}, want: "<mark>geo</mark> and <mark>seo</mark>"},
+		{name: "HTML 转义", text: "<b>SEO</b> & geo", terms: []string{"seo"},
+			want: "&lt;b&gt;<mark>SEO</mark>&lt;/b&gt; &amp; geo"},
+		{name: "合并空白", text: "foo\n\n  bar", terms: []string{"bar"}, want: "foo <mark>bar</mark>"},
+		{name: "中文", text: "生成式引擎优化", terms: []string{"引擎"}, want: "生成式<mark>引擎</mark>优化"},
+		{name: "截取命中词附近", text: strings.Repeat("a ", 50) + "target" + strings.Repeat(" b", 50), terms: []string{"target"},
+			want: "…" + strings.Repeat("a ", 30) + "<mark>target</mark>" + strings.Repeat(" b", 30) + "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.text, tt.terms); got != tt.want {
+				t.Errorf("highlightSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearchHighlights 测试优先使用数据库片段，其余字段在文本中查找
+func TestSearchHighlights(t *testing.T) {
+	analysis := &model.GEOAnalysis{
+		AIOverview:         "Generative engine optimization",
+		OptimizationReport: "增加权威引用来源",
+		OptimizedArticle:   "nothing here",
+	}
+	snippets := map[string]string{
+		"ai_overview": "<i>Generative</i>\n" + repository.SnippetStart + "engine" + repository.SnippetEnd + " optimization",
+	}
+
+	got := searchHighlights(analysis, []string{"engine", "权威"}, snippets)
+	want := map[string]string{
+		"ai_overview":         "&lt;i&gt;Generative&lt;/i&gt; <mark>engine</mark> optimization",
+		"optimization_report": "增加<mark>权威</mark>引用来源",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("searchHighlights() = %q, want %q", got, want)
+	}
+
+	if got := searchHighlights(analysis, []string{"geo"}, nil); got != nil {
+		t.Errorf("searchHighlights(geo) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// saveTrace 保存分析的执行追踪（失败只记录日志）
+func (s *GEOAnalysisService) saveTrace(ctx context.Context, analysisID int64, spans []models.TraceSpan) {
+	if s.traceRepo == nil || len(spans) == 0 {
+		return
+	}
+
+	records := make([]model.GEOTraceSpan, 0, len(spans))
+	for _, span := range spans {
+		records = append(records, model.GEOTraceSpan{
+			AnalysisID: analysisID,
+			SpanID:     span.ID,
+			ParentID:   span.ParentID,
+			Agent:      span.Agent,
+			Name:       span.Name,
+			Type:       span.Type,
+			Component:  span.Component,
+			Input:      span.Input,
+			Output:     span.Output,
+			State:      span.State,
+			Error:      span.Error,
+			StartedAt:  span.StartedAt,
+			DurationMs: span.DurationMs,
+		})
+	}
+
+	if err := s.traceRepo.ReplaceByAnalysis(ctx, analysisID, records); err != nil {
+		zap.L().Error("保存执行追踪失败",
+			zap.Int64("analysis_id", analysisID),
+			zap.Error(err))
+	}
+}
+
+// GetTrace 获取分析的执行追踪，按父子关系组织为 span 树
+func (s *GEOAnalysisService) GetTrace(ctx context.Context, id int64) (*model.GEOAnalysisTraceResponse, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+
+	result := &model.GEOAnalysisTraceResponse{
+		AnalysisID: id,
+		Spans:      []*model.GEOTraceSpan{},
+	}
+	if s.traceRepo == nil {
+		return result, nil
+	}
+
+	spans, err := s.traceRepo.ListByAnalysis(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int]*model.GEOTraceSpan, len(spans))
+	for i := range spans {
+		nodes[spans[i].SpanID] = &spans[i]
+	}
+	for i := range spans {
+		span := &spans[i]
+		if parent, ok := nodes[span.ParentID]; ok && span.ParentID != 0 {
+			parent.Children = append(parent.Children, span)
+		} else {
+			result.Spans = append(result.Spans, span)
+		}
+	}
+	return result, nil
+}
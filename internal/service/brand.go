@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrBrandNotFound 品牌不存在
+var ErrBrandNotFound = repository.ErrBrandNotFound
+
+// maxDashboardWrongClaims 看板中每个品牌返回的最近错误说法数量
+const maxDashboardWrongClaims = 10
+
+// BrandMentionAnalyzer 分析 AI 回答中的品牌提及
+type BrandMentionAnalyzer interface {
+	Analyze(ctx context.Context, answer string, brands []models.BrandDictionary) ([]models.BrandAnalysis, error)
+}
+
+// BrandService 品牌提及分析服务
+type BrandService struct {
+	repo         *repository.BrandRepository
+	analysisRepo *repository.GEOAnalysisRepository
+	analyzer     BrandMentionAnalyzer
+}
+
+// NewBrandService 创建品牌提及分析服务
+func NewBrandService(repo *repository.BrandRepository, analysisRepo *repository.GEOAnalysisRepository, analyzer BrandMentionAnalyzer) *BrandService {
+	return &BrandService{
+		repo:         repo,
+		analysisRepo: analysisRepo,
+		analyzer:     analyzer,
+	}
+}
+
+// Create 创建品牌
+func (s *BrandService) Create(ctx context.Context, req *model.BrandCreateRequest, userID *int64) (*model.Brand, error) {
+	brand := &model.Brand{
+		Name:     strings.TrimSpace(req.Name),
+		Aliases:  marshalStrings(normalizeQueries(req.Aliases)),
+		Products: marshalStrings(normalizeQueries(req.Products)),
+		Facts:    marshalStrings(normalizeQueries(req.Facts)),
+		Enabled:  true,
+		UserID:   userID,
+	}
+
+	if err := s.repo.Create(ctx, brand); err != nil {
+		return nil, err
+	}
+	return brand, nil
+}
+
+// GetByID 获取品牌
+func (s *BrandService) GetByID(ctx context.Context, id int64) (*model.Brand, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List 查询品牌列表
+func (s *BrandService) List(ctx context.Context, userID *int64) ([]model.BrandResponse, error) {
+	brands, err := s.repo.List(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.BrandResponse, len(brands))
+	for i := range brands {
+		responses[i] = *s.ToResponse(&brands[i])
+	}
+	return responses, nil
+}
+
+// Update 更新品牌（数组字段传入时整体替换）
+func (s *BrandService) Update(ctx context.Context, id int64, req *model.BrandUpdateRequest) (*model.Brand, error) {
+	brand, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		brand.Name = name
+	}
+	if req.Aliases != nil {
+		brand.Aliases = marshalStrings(normalizeQueries(req.Aliases))
+	}
+	if req.Products != nil {
+		brand.Products = marshalStrings(normalizeQueries(req.Products))
+	}
+	if req.Facts != nil {
+		brand.Facts = marshalStrings(normalizeQueries(req.Facts))
+	}
+	if req.Enabled != nil {
+		brand.Enabled = *req.Enabled
+	}
+
+	if err := s.repo.Update(ctx, brand); err != nil {
+		return nil, err
+	}
+	return brand, nil
+}
+
+// Delete 删除品牌
+func (s *BrandService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ToResponse 转换为响应格式
+func (s *BrandService) ToResponse(brand *model.Brand) *model.BrandResponse {
+	return &model.BrandResponse{
+		ID:        brand.ID,
+		Name:      brand.Name,
+		Aliases:   unmarshalStrings(brand.Aliases),
+		Products:  unmarshalStrings(brand.Products),
+		Facts:     unmarshalStrings(brand.Facts),
+		Enabled:   brand.Enabled,
+		CreatedAt: brand.CreatedAt,
+		UpdatedAt: brand.UpdatedAt,
+	}
+}
+
+// AnalyzeAnswer 分析 AI 回答中的品牌提及并保存结果（覆盖该分析已有的结果）
+// LLM 情感分析失败时仍保存词典匹配结果，并返回错误供调用方记录
+func (s *BrandService) AnalyzeAnswer(ctx context.Context, analysisID int64, userID *int64, answer string) error {
+	if strings.TrimSpace(answer) == "" {
+		return nil
+	}
+
+	brands, err := s.repo.List(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	if len(brands) == 0 {
+		return nil
+	}
+
+	dicts := make([]models.BrandDictionary, len(brands))
+	hasFacts := make(map[int64]bool, len(brands))
+	for i, b := range brands {
+		dicts[i] = models.BrandDictionary{
+			ID:       b.ID,
+			Name:     b.Name,
+			Aliases:  unmarshalStrings(b.Aliases),
+			Products: unmarshalStrings(b.Products),
+			Facts:    unmarshalStrings(b.Facts),
+		}
+		hasFacts[b.ID] = len(dicts[i].Facts) > 0
+	}
+
+	results, analyzeErr := s.analyzer.Analyze(ctx, answer, dicts)
+
+	records := make([]model.BrandAnalysisRecord, 0, len(results))
+	mentions := make([]model.BrandMentionRecord, 0)
+	for _, r := range results {
+		// 错误说法必须有已知事实作为依据，没有事实的品牌丢弃模型给出的错误说法
+		wrongClaims := r.WrongClaims
+		if !hasFacts[r.BrandID] || wrongClaims == nil {
+			wrongClaims = make([]models.WrongClaim, 0)
+		}
+		claims, _ := json.Marshal(wrongClaims)
+		records = append(records, model.BrandAnalysisRecord{
+			AnalysisID:     analysisID,
+			BrandID:        r.BrandID,
+			Mentioned:      r.Mentioned,
+			MentionCount:   len(r.Mentions),
+			Sentiment:      r.Sentiment,
+			SentimentScore: r.SentimentScore,
+			Summary:        r.Summary,
+			WrongClaims:    string(claims),
+		})
+		for _, m := range r.Mentions {
+			mentions = append(mentions, model.BrandMentionRecord{
+				AnalysisID: analysisID,
+				BrandID:    r.BrandID,
+				Term:       m.Term,
+				Kind:       m.Kind,
+				Offset:     m.Offset,
+				Snippet:    m.Snippet,
+				Sentiment:  m.Sentiment,
+			})
+		}
+	}
+
+	if err := s.repo.ReplaceAnalysisResults(ctx, analysisID, records, mentions); err != nil {
+		return err
+	}
+	return analyzeErr
+}
+
+// Reanalyze 使用当前品牌词典重新分析已完成的分析记录
+func (s *BrandService) Reanalyze(ctx context.Context, analysisID int64) ([]model.BrandAnalysisResponse, error) {
+	analysis, err := s.analysisRepo.GetByID(analysisID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.AnalyzeAnswer(ctx, analysisID, analysis.UserID, analysis.AIOverview); err != nil {
+		return nil, err
+	}
+	return s.GetAnalysisBrands(ctx, analysisID)
+}
+
+// DeleteByAnalysis 删除某次分析的品牌分析结果
+func (s *BrandService) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	return s.repo.DeleteByAnalysis(ctx, analysisID)
+}
+
+// GetAnalysisBrands 获取某次分析的品牌提及结果
+func (s *BrandService) GetAnalysisBrands(ctx context.Context, analysisID int64) ([]model.BrandAnalysisResponse, error) {
+	records, err := s.repo.ListResultsByAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	mentions, err := s.repo.ListMentionsByAnalysis(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.brandNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byBrand := make(map[int64][]model.BrandMentionRecord)
+	for _, m := range mentions {
+		byBrand[m.BrandID] = append(byBrand[m.BrandID], m)
+	}
+
+	responses := make([]model.BrandAnalysisResponse, 0, len(records))
+	for _, r := range records {
+		brandMentions := byBrand[r.BrandID]
+		if brandMentions == nil {
+			brandMentions = make([]model.BrandMentionRecord, 0)
+		}
+		responses = append(responses, model.BrandAnalysisResponse{
+			BrandID:        r.BrandID,
+			Brand:          names[r.BrandID],
+			Mentioned:      r.Mentioned,
+			Sentiment:      r.Sentiment,
+			SentimentScore: r.SentimentScore,
+			Summary:        r.Summary,
+			Mentions:       brandMentions,
+			WrongClaims:    toBrandWrongClaims(&r),
+		})
+	}
+	return responses, nil
+}
+
+// brandAccumulator 品牌看板统计累加器
+type brandAccumulator struct {
+	analyses  int
+	mentioned int
+	scoreSum  float64
+}
+
+func (a *brandAccumulator) add(r *model.BrandAnalysisRecord) {
+	a.analyses++
+	if r.Mentioned {
+		a.mentioned++
+		a.scoreSum += r.SentimentScore
+	}
+}
+
+// Dashboard 汇总时间范围内各品牌在 AI 回答中的提及率、情感和错误说法
+func (s *BrandService) Dashboard(ctx context.Context, req *model.BrandDashboardRequest) (*model.BrandDashboardResponse, error) {
+	from, to, err := parseDateRange(req.From, req.To, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	var brands []model.Brand
+	if req.BrandID != nil {
+		brand, err := s.repo.GetByID(ctx, *req.BrandID)
+		if err != nil {
+			return nil, err
+		}
+		brands = []model.Brand{*brand}
+	} else {
+		brands, err = s.repo.List(ctx, nil, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	brandIDs := make([]int64, len(brands))
+	for i, b := range brands {
+		brandIDs[i] = b.ID
+	}
+
+	end := to.AddDate(0, 0, 1)
+	records, err := s.repo.ListResultsBetween(ctx, brandIDs, from, end)
+	if err != nil {
+		return nil, err
+	}
+	mentions, err := s.repo.ListMentionsBetween(ctx, brandIDs, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]string, 0)
+	seenDates := make(map[string]bool)
+	totals := make(map[int64]*brandAccumulator)
+	daily := make(map[int64]map[string]*brandAccumulator)
+	sentiments := make(map[int64]map[string]int)
+	claims := make(map[int64][]model.BrandWrongClaim)
+	for i := range records {
+		r := &records[i]
+		date := periodKey(r.CreatedAt, "day")
+		if !seenDates[date] {
+			seenDates[date] = true
+			dates = append(dates, date)
+		}
+
+		if totals[r.BrandID] == nil {
+			totals[r.BrandID] = &brandAccumulator{}
+			daily[r.BrandID] = make(map[string]*brandAccumulator)
+			sentiments[r.BrandID] = make(map[string]int)
+		}
+		if daily[r.BrandID][date] == nil {
+			daily[r.BrandID][date] = &brandAccumulator{}
+		}
+		totals[r.BrandID].add(r)
+		daily[r.BrandID][date].add(r)
+		if r.Mentioned {
+			sentiments[r.BrandID][r.Sentiment]++
+		}
+		claims[r.BrandID] = append(claims[r.BrandID], toBrandWrongClaims(r)...)
+	}
+	sort.Strings(dates)
+
+	terms := make(map[int64]map[string]int)
+	for _, m := range mentions {
+		if terms[m.BrandID] == nil {
+			terms[m.BrandID] = make(map[string]int)
+		}
+		terms[m.BrandID][m.Term]++
+	}
+
+	items := make([]model.BrandDashboardItem, 0, len(brands))
+	for _, b := range brands {
+		acc := totals[b.ID]
+		if acc == nil {
+			acc = &brandAccumulator{}
+		}
+
+		// 最近的错误说法在前
+		brandClaims := claims[b.ID]
+		sort.SliceStable(brandClaims, func(i, j int) bool {
+			return brandClaims[i].CreatedAt.After(brandClaims[j].CreatedAt)
+		})
+		if len(brandClaims) > maxDashboardWrongClaims {
+			brandClaims = brandClaims[:maxDashboardWrongClaims]
+		}
+		if brandClaims == nil {
+			brandClaims = make([]model.BrandWrongClaim, 0)
+		}
+
+		item := model.BrandDashboardItem{
+			BrandID:      b.ID,
+			Brand:        b.Name,
+			Analyses:     acc.analyses,
+			Mentioned:    acc.mentioned,
+			MentionRate:  percent(acc.mentioned, acc.analyses),
+			AvgSentiment: averageScore(acc.scoreSum, acc.mentioned),
+			Sentiments:   sentiments[b.ID],
+			TopTerms:     terms[b.ID],
+			WrongClaims:  brandClaims,
+			Trend:        make([]model.BrandDashboardPoint, 0, len(dates)),
+		}
+		if item.Sentiments == nil {
+			item.Sentiments = make(map[string]int)
+		}
+		if item.TopTerms == nil {
+			item.TopTerms = make(map[string]int)
+		}
+
+		for _, date := range dates {
+			d := daily[b.ID][date]
+			if d == nil {
+				d = &brandAccumulator{}
+			}
+			item.Trend = append(item.Trend, model.BrandDashboardPoint{
+				Date:         date,
+				Analyses:     d.analyses,
+				Mentions:     d.mentioned,
+				MentionRate:  percent(d.mentioned, d.analyses),
+				AvgSentiment: averageScore(d.scoreSum, d.mentioned),
+			})
+		}
+
+		items = append(items, item)
+	}
+
+	return &model.BrandDashboardResponse{
+		From:   from.Format(dateLayout),
+		To:     to.Format(dateLayout),
+		Brands: items,
+	}, nil
+}
+
+// brandNames 获取品牌 ID 到名称的映射
+func (s *BrandService) brandNames(ctx context.Context) (map[int64]string, error) {
+	brands, err := s.repo.List(ctx, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(brands))
+	for _, b := range brands {
+		names[b.ID] = b.Name
+	}
+	return names, nil
+}
+
+// toBrandWrongClaims 解析分析记录中的错误说法
+func toBrandWrongClaims(r *model.BrandAnalysisRecord) []model.BrandWrongClaim {
+	var raw []models.WrongClaim
+	_ = json.Unmarshal([]byte(r.WrongClaims), &raw)
+
+	claims := make([]model.BrandWrongClaim, 0, len(raw))
+	for _, c := range raw {
+		claims = append(claims, model.BrandWrongClaim{
+			AnalysisID: r.AnalysisID,
+			Claim:      c.Claim,
+			Correction: c.Correction,
+			Severity:   c.Severity,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+	return claims
+}
+
+// averageScore 计算平均情感分数（保留两位小数）
+func averageScore(sum float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(sum/float64(count)*100) / 100
+}
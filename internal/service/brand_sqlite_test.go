@@ -0,0 +1,186 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// fakeBrandAnalyzer 按回答返回预设的品牌分析结果
+type fakeBrandAnalyzer struct {
+	results map[string][]models.BrandAnalysis
+	err     error
+}
+
+func (a *fakeBrandAnalyzer) Analyze(ctx context.Context, answer string, brands []models.BrandDictionary) ([]models.BrandAnalysis, error) {
+	return a.results[answer], a.err
+}
+
+// TestBrandDashboard 测试品牌看板的提及率、情感、错误说法和按天趋势，没有已知事实的品牌不保存错误说法
+func TestBrandDashboard(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	analyzer := &fakeBrandAnalyzer{}
+	s := NewBrandService(repository.NewBrandRepository(db), repository.NewGEOAnalysisRepository(db), analyzer)
+
+	acme, err := s.Create(ctx, &model.BrandCreateRequest{Name: "Acme", Products: []string{"Acme Pro"}, Facts: []string{"成立于 2001 年"}}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	globex, err := s.Create(ctx, &model.BrandCreateRequest{Name: "Globex"}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	mention := func(term string) models.BrandMention {
+		return models.BrandMention{Term: term, Kind: "name", Snippet: term}
+	}
+	analysis := func(brand *model.Brand, sentiment string, score float64, claims []models.WrongClaim, terms ...string) models.BrandAnalysis {
+		r := models.BrandAnalysis{BrandID: brand.ID, Brand: brand.Name, Mentioned: len(terms) > 0,
+			Sentiment: sentiment, SentimentScore: score, WrongClaims: claims}
+		for _, term := range terms {
+			r.Mentions = append(r.Mentions, mention(term))
+		}
+		return r
+	}
+	analyzer.results = map[string][]models.BrandAnalysis{
+		"a1": {
+			analysis(acme, models.SentimentPositive, 0.8, []models.WrongClaim{{Claim: "成立于 1999 年", Correction: "成立于 2001 年", Severity: "high"}}, "Acme", "Acme Pro"),
+			analysis(globex, models.SentimentNegative, -0.4, []models.WrongClaim{{Claim: "已经倒闭"}}, "Globex"),
+		},
+		"a2": {
+			analysis(acme, "", 0, nil),
+			analysis(globex, models.SentimentNeutral, 0, nil, "Globex"),
+		},
+		"a3": {
+			analysis(acme, models.SentimentNegative, -0.2, []models.WrongClaim{{Claim: "总部在上海"}}, "Acme"),
+			analysis(globex, "", 0, nil),
+		},
+		"a4": {
+			analysis(acme, models.SentimentPositive, 1, nil, "Acme"),
+			analysis(globex, models.SentimentPositive, 1, nil, "Globex"),
+		},
+	}
+
+	// 分析 ID、回答和结果日期；第 20 天不在看板的时间范围内
+	seeds := []struct {
+		id     int64
+		answer string
+		day    int
+	}{
+		{1, "a1", 10},
+		{2, "a2", 10},
+		{3, "a3", 12},
+		{4, "a4", 20},
+	}
+	for _, seed := range seeds {
+		if err := s.AnalyzeAnswer(ctx, seed.id, nil, seed.answer); err != nil {
+			t.Fatalf("AnalyzeAnswer(%s) error = %v", seed.answer, err)
+		}
+		for _, table := range []any{&model.BrandAnalysisRecord{}, &model.BrandMentionRecord{}} {
+			if err := db.Model(table).Where("analysis_id = ?", seed.id).Update("created_at", date(seed.day, 9)).Error; err != nil {
+				t.Fatalf("update created_at error = %v", err)
+			}
+		}
+	}
+
+	// LLM 分析失败时仍保存匹配结果并返回错误
+	analyzer.err = errors.New("timeout")
+	if err := s.AnalyzeAnswer(ctx, 5, nil, "a4"); err == nil {
+		t.Error("AnalyzeAnswer() error = nil, want analyzer error")
+	}
+	if brands, err := s.GetAnalysisBrands(ctx, 5); err != nil || len(brands) != 2 {
+		t.Errorf("GetAnalysisBrands(5) = %+v, %v, want 2 results", brands, err)
+	}
+	if err := s.DeleteByAnalysis(ctx, 5); err != nil {
+		t.Fatalf("DeleteByAnalysis() error = %v", err)
+	}
+
+	brands, err := s.GetAnalysisBrands(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAnalysisBrands() error = %v", err)
+	}
+	if len(brands) != 2 || len(brands[0].WrongClaims) != 1 || len(brands[1].WrongClaims) != 0 {
+		t.Errorf("GetAnalysisBrands(1) = %+v, want wrong claims only for Acme", brands)
+	}
+
+	dashboard, err := s.Dashboard(ctx, &model.BrandDashboardRequest{From: "2026-10-01", To: "2026-10-15"})
+	if err != nil {
+		t.Fatalf("Dashboard() error = %v", err)
+	}
+	if len(dashboard.Brands) != 2 {
+		t.Fatalf("len(brands) = %d, want 2", len(dashboard.Brands))
+	}
+
+	claims := func(item model.BrandDashboardItem) []string {
+		result := make([]string, len(item.WrongClaims))
+		for i, c := range item.WrongClaims {
+			result[i] = c.Claim
+		}
+		return result
+	}
+
+	tests := []struct {
+		item         model.BrandDashboardItem
+		analyses     int
+		mentioned    int
+		mentionRate  float64
+		avgSentiment float64
+		sentiments   map[string]int
+		topTerms     map[string]int
+		wrongClaims  []string
+		trend        []model.BrandDashboardPoint
+	}{
+		{
+			item: dashboard.Brands[0], analyses: 3, mentioned: 2, mentionRate: 66.67, avgSentiment: 0.3,
+			sentiments:  map[string]int{models.SentimentPositive: 1, models.SentimentNegative: 1},
+			topTerms:    map[string]int{"Acme": 2, "Acme Pro": 1},
+			wrongClaims: []string{"总部在上海", "成立于 1999 年"},
+			trend: []model.BrandDashboardPoint{
+				{Date: "2026-10-10", Analyses: 2, Mentions: 1, MentionRate: 50, AvgSentiment: 0.8},
+				{Date: "2026-10-12", Analyses: 1, Mentions: 1, MentionRate: 100, AvgSentiment: -0.2},
+			},
+		},
+		{
+			item: dashboard.Brands[1], analyses: 3, mentioned: 2, mentionRate: 66.67, avgSentiment: -0.2,
+			sentiments:  map[string]int{models.SentimentNegative: 1, models.SentimentNeutral: 1},
+			topTerms:    map[string]int{"Globex": 2},
+			wrongClaims: []string{},
+			trend: []model.BrandDashboardPoint{
+				{Date: "2026-10-10", Analyses: 2, Mentions: 2, MentionRate: 100, AvgSentiment: -0.2},
+				{Date: "2026-10-12", Analyses: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item.Brand, func(t *testing.T) {
+			item := tt.item
+			if item.Analyses != tt.analyses || item.Mentioned != tt.mentioned ||
+				item.MentionRate != tt.mentionRate || item.AvgSentiment != tt.avgSentiment {
+				t.Errorf("item = %d/%d (%.2f%%, %.2f), want %d/%d (%.2f%%, %.2f)",
+					item.Mentioned, item.Analyses, item.MentionRate, item.AvgSentiment,
+					tt.mentioned, tt.analyses, tt.mentionRate, tt.avgSentiment)
+			}
+			if !reflect.DeepEqual(item.Sentiments, tt.sentiments) {
+				t.Errorf("sentiments = %v, want %v", item.Sentiments, tt.sentiments)
+			}
+			if !reflect.DeepEqual(item.TopTerms, tt.topTerms) {
+				t.Errorf("top terms = %v, want %v", item.TopTerms, tt.topTerms)
+			}
+			if got := claims(item); !reflect.DeepEqual(got, tt.wrongClaims) {
+				t.Errorf("wrong claims = %q, want %q", got, tt.wrongClaims)
+			}
+			if !reflect.DeepEqual(item.Trend, tt.trend) {
+				t.Errorf("trend = %+v, want %+v", item.Trend, tt.trend)
+			}
+		})
+	}
+}
@@ -3,39 +3,90 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/solariswu/peanut/internal/agent/geo/flow"
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/llm"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/agent/geo/trace"
 	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/pkg/cache"
+	"github.com/solariswu/peanut/internal/pkg/logging"
 	"github.com/solariswu/peanut/internal/pkg/progress"
+	"github.com/solariswu/peanut/internal/pkg/telemetry"
 	"github.com/solariswu/peanut/internal/repository"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// ErrAnalysisNotFound 分析记录不存在
+var ErrAnalysisNotFound = errors.New("分析记录不存在")
+
+// ErrAnalysisNotCompleted 分析尚未完成
+var ErrAnalysisNotCompleted = errors.New("分析尚未完成")
+
+// ErrInvalidOutputLanguage 不支持的输出语言
+var ErrInvalidOutputLanguage = errors.New("不支持的输出语言")
+
+// ErrAnalysisInProgress 该 URL 正在分析中
+var ErrAnalysisInProgress = errors.New("该 URL 正在分析中")
+
+// ErrInvalidScoreRange 评分下限大于上限
+var ErrInvalidScoreRange = errors.New("无效的评分范围")
+
 // GEOAnalysisService GEO 分析服务
 type GEOAnalysisService struct {
 	repo        *repository.GEOAnalysisRepository
 	agent       flow.AgentService
 	progressMgr *progress.Manager
+	brandSvc    *BrandService
+	usageRepo   *repository.LLMUsageRepository
+	traceRepo   *repository.TraceRepository
+	logRepo     *repository.AnalysisLogRepository
+	experiments *PromptExperimentService
+	results     *AnalysisResultService
+	liveLogs    sync.Map // 执行中分析的日志缓冲 analysisID -> *logging.Buffer
 	totalSteps  int
 }
 
 // NewGEOAnalysisService 创建服务
-func NewGEOAnalysisService(repo *repository.GEOAnalysisRepository, agent flow.AgentService, progressMgr *progress.Manager) *GEOAnalysisService {
+// brandSvc 可为 nil，此时跳过品牌提及分析；usageRepo、traceRepo、logRepo 可为 nil，此时不保存 LLM 用量、执行追踪和执行日志；
+// experiments 可为 nil，此时不参与 prompt A/B 实验；results 可为 nil，此时结构化结果只保存在分析记录的 JSON 字段中
+func NewGEOAnalysisService(repo *repository.GEOAnalysisRepository, agent flow.AgentService, progressMgr *progress.Manager, brandSvc *BrandService, usageRepo *repository.LLMUsageRepository, traceRepo *repository.TraceRepository, logRepo *repository.AnalysisLogRepository, experiments *PromptExperimentService, results *AnalysisResultService) *GEOAnalysisService {
 	return &GEOAnalysisService{
 		repo:        repo,
 		agent:       agent,
 		progressMgr: progressMgr,
-		totalSteps:  7, // GEO 分析的总步骤数
+		brandSvc:    brandSvc,
+		usageRepo:   usageRepo,
+		traceRepo:   traceRepo,
+		logRepo:     logRepo,
+		experiments: experiments,
+		results:     results,
+		totalSteps:  flow.TotalSteps, // GEO 分析的总步骤数
 	}
 }
 
 // Create 创建分析任务
 func (s *GEOAnalysisService) Create(ctx context.Context, req *model.GEOAnalysisCreateRequest, userID *int64) (*model.GEOAnalysis, error) {
+	// 输出语言未指定时由分析流程按网页语言选择
+	var outputLanguage string
+	if req.OutputLanguage != "" {
+		if outputLanguage = i18n.Normalize(req.OutputLanguage); outputLanguage == "" {
+			return nil, fmt.Errorf("%w: %s，可选 %s", ErrInvalidOutputLanguage, req.OutputLanguage, strings.Join(i18n.Languages, "、"))
+		}
+	}
+
 	// 检查是否已有正在运行的分析
 	if existing, _ := s.repo.GetByURL(req.URL); existing != nil && (existing.Status == "pending" || existing.Status == "processing") {
-		return nil, fmt.Errorf("该 URL 正在分析中")
+		return nil, ErrAnalysisInProgress
 	}
 
 	// 验证并设置默认平台
@@ -44,32 +95,127 @@ func (s *GEOAnalysisService) Create(ctx context.Context, req *model.GEOAnalysisC
 		platform = "google"
 	}
 
+	opts := models.SearchOptions{
+		Country:  strings.ToLower(strings.TrimSpace(req.Country)),
+		Language: strings.TrimSpace(req.Language),
+		Device:   req.Device,
+		Page:     max(req.Page, 1),
+	}
+
 	analysis := &model.GEOAnalysis{
-		URL:      req.URL,
-		Platform: platform,
-		Status:   "pending",
-		UserID:   userID,
+		URL:            req.URL,
+		Platform:       platform,
+		Country:        opts.Country,
+		Language:       opts.Language,
+		Device:         opts.Device,
+		Page:           opts.Page,
+		Status:         "pending",
+		UserID:         userID,
+		OutputLanguage: outputLanguage,
 	}
 
 	if err := s.repo.Create(analysis); err != nil {
 		return nil, err
 	}
 
-	// 异步执行分析
-	go s.executeAnalysis(context.Background(), analysis.ID, req.URL, platform)
+	// 异步执行分析，保留请求 ID 以便关联日志
+	requestID := logging.RequestIDFromContext(ctx)
+	ctx = context.Background()
+	if requestID != "" {
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
+	if req.ForceRefresh {
+		ctx = cache.WithForceRefresh(ctx)
+	}
+	if outputLanguage != "" {
+		ctx = i18n.WithLanguage(ctx, outputLanguage)
+	}
+	telemetry.AnalysisQueueDepth.Inc()
+	go s.executeAnalysis(ctx, analysis.ID, userID, req.URL, platform, opts)
 
 	return analysis, nil
 }
 
+// Reanalyze 使用原分析的平台、目标国家、语言、设备、页码和输出语言重新分析同一 URL（跳过缓存）
+// 该 URL 已有进行中的分析时直接返回该分析
+func (s *GEOAnalysisService) Reanalyze(ctx context.Context, id int64, userID *int64) (*model.GEOAnalysis, error) {
+	analysis, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+	if existing, _ := s.repo.GetByURL(analysis.URL); existing != nil && (existing.Status == "pending" || existing.Status == "processing") {
+		return existing, nil
+	}
+
+	return s.Create(ctx, &model.GEOAnalysisCreateRequest{
+		URL:            analysis.URL,
+		Platform:       analysis.Platform,
+		Country:        analysis.Country,
+		Language:       analysis.Language,
+		Device:         analysis.Device,
+		Page:           analysis.Page,
+		OutputLanguage: analysis.OutputLanguage,
+		ForceRefresh:   true,
+	}, userID)
+}
+
 // executeAnalysis 执行分析
-func (s *GEOAnalysisService) executeAnalysis(ctx context.Context, analysisID int64, url string, platform string) {
+func (s *GEOAnalysisService) executeAnalysis(ctx context.Context, analysisID int64, userID *int64, url string, platform string, opts models.SearchOptions) {
+	defer telemetry.AnalysisQueueDepth.Dec()
+
+	// 本次分析的日志同时写入全局日志器和缓冲，供 /geo/analysis/:id/logs 查看
+	logs := logging.NewBuffer(0)
+	logger := logs.Tee(logging.FromContext(ctx)).With(zap.Int64("analysis_id", analysisID))
+	ctx = logging.WithLogger(ctx, logger)
+	s.liveLogs.Store(analysisID, logs)
+	defer func() {
+		s.saveLogs(analysisID, logs)
+		s.liveLogs.Delete(analysisID)
+	}()
+
+	// 目标国家、语言和设备随上下文传递给搜索提供者
+	ctx = tools.WithSearchOptions(ctx, opts)
+
+	// 记录本次分析（含品牌提及分析）所有 LLM 调用的用量
+	usage := llm.NewUsageRecorder()
+	ctx = llm.WithUsageRecorder(ctx, usage)
+
+	// 记录各 Agent（含品牌提及分析）使用的 prompt 版本，便于复现结果
+	promptVersions := prompts.NewRecorder()
+	ctx = prompts.WithRecorder(ctx, promptVersions)
+
+	// prompt A/B 实验：按比例分配变体，分析结束后记录实际使用的版本和解析失败次数
+	if s.experiments != nil {
+		var (
+			assignments []promptAssignment
+			err         error
+		)
+		ctx, assignments, err = s.experiments.Assign(ctx, analysisID)
+		if err != nil {
+			logger.Warn("分配 prompt 实验变体失败", zap.Error(err))
+		}
+		defer func() {
+			if err := s.experiments.Record(ctx, assignments, promptVersions); err != nil {
+				logger.Warn("保存 prompt 实验分配失败", zap.Error(err))
+			}
+		}()
+	}
+
+	// 记录每个节点的执行过程，供 /geo/analysis/:id/trace 查看
+	tracer := trace.NewTracer()
+	ctx = trace.WithTracer(ctx, tracer)
+
+	logger.Info("开始分析", zap.String("url", url), zap.String("platform", platform))
+
 	// 更新状态为处理中
 	if err := s.repo.UpdateFields(analysisID, map[string]any{
 		"status": "processing",
 	}); err != nil {
 		// 记录错误但不中断，继续尝试执行分析
-		zap.L().Error("更新分析状态为 processing 失败",
-			zap.Int64("analysis_id", analysisID),
+		logger.Error("更新分析状态为 processing 失败",
 			zap.Error(err))
 	}
 
@@ -92,13 +238,18 @@ func (s *GEOAnalysisService) executeAnalysis(ctx context.Context, analysisID int
 			s.progressMgr.Fail(analysisID, err.Error())
 		}
 		if dbErr := s.repo.MarkFailed(analysisID, err.Error()); dbErr != nil {
-			zap.L().Error("标记分析失败状态失败",
-				zap.Int64("analysis_id", analysisID),
+			logger.Error("标记分析失败状态失败",
 				zap.Error(dbErr))
 		}
+		telemetry.AnalysesTotal.WithLabelValues("failed", platform).Inc()
+		// 失败前已发生的调用同样计费
+		s.saveUsage(ctx, analysisID, userID, usage.Summary())
+		s.saveTrace(ctx, analysisID, tracer.Spans())
 		return
 	}
 
+	s.saveTrace(ctx, analysisID, tracer.Spans())
+
 	// 更新最终结果
 	now := time.Now()
 	updates := map[string]any{
@@ -110,6 +261,9 @@ func (s *GEOAnalysisService) executeAnalysis(ctx context.Context, analysisID int
 	}
 
 	// 保存中间结果字段
+	if report.OutputLanguage != "" {
+		updates["output_language"] = report.OutputLanguage
+	}
 	if report.QueryFanout != "" {
 		updates["query_fanout"] = report.QueryFanout
 	}
@@ -137,22 +291,69 @@ func (s *GEOAnalysisService) executeAnalysis(ctx context.Context, analysisID int
 		updates["optimization_suggestions"] = string(suggestionsJSON)
 	}
 
-	// 注意：验证结果在第8步生成，需要从 stepOutputs 中解析
-	// 这里暂时跳过，后续可以扩展报告模型来包含验证结果
+	if report.CompetitorAnalysis != nil {
+		competitorJSON, _ := json.Marshal(report.CompetitorAnalysis)
+		updates["competitor_analysis"] = string(competitorJSON)
+	}
+
+	if report.SERPFeatures != nil {
+		featuresJSON, _ := json.Marshal(report.SERPFeatures)
+		updates["serp_features"] = string(featuresJSON)
+	}
+
+	if len(report.PromptVersions) > 0 {
+		versionsJSON, _ := json.Marshal(report.PromptVersions)
+		updates["prompt_versions"] = string(versionsJSON)
+	}
+
+	if report.Validation != nil {
+		validationJSON, _ := json.Marshal(report.Validation)
+		updates["validation_result"] = string(validationJSON)
+		updates["validation_score"] = report.Validation.OptimizedScore.Total
+	}
 
 	if err := s.repo.UpdateFields(analysisID, updates); err != nil {
-		zap.L().Error("更新分析结果失败",
-			zap.Int64("analysis_id", analysisID),
+		logger.Error("更新分析结果失败",
 			zap.Error(err))
+		telemetry.AnalysesTotal.WithLabelValues("failed", platform).Inc()
 		// 尝试至少标记为失败状态
 		if dbErr := s.repo.MarkFailed(analysisID, "保存分析结果失败: "+err.Error()); dbErr != nil {
-			zap.L().Error("标记分析失败状态也失败",
-				zap.Int64("analysis_id", analysisID),
+			logger.Error("标记分析失败状态也失败",
 				zap.Error(dbErr))
 		}
 		return
 	}
 
+	telemetry.AnalysesTotal.WithLabelValues("completed", platform).Inc()
+
+	// 结构化结果写入子表，供跨分析查询（失败不影响分析结果，JSON 字段中仍有完整数据）
+	if s.results != nil {
+		if err := s.results.Save(ctx, analysisID, report); err != nil {
+			logger.Warn("保存结构化分析结果失败", zap.Error(err))
+		}
+	}
+
+	// 品牌提及分析（失败不影响分析结果）
+	if s.brandSvc != nil && report.AIOverview != "" {
+		if s.progressMgr != nil {
+			s.progressMgr.Update(analysisID, s.totalSteps, s.totalSteps, "品牌提及分析", "分析 AI 回答中的品牌提及和情感")
+		}
+		if err := s.brandSvc.AnalyzeAnswer(ctx, analysisID, userID, report.AIOverview); err != nil {
+			logger.Warn("品牌提及分析失败",
+				zap.Error(err))
+		}
+		// 补充记录品牌提及分析使用的 prompt 版本
+		if versions := promptVersions.Versions(); len(versions) > len(report.PromptVersions) {
+			versionsJSON, _ := json.Marshal(versions)
+			if err := s.repo.UpdateFields(analysisID, map[string]any{"prompt_versions": string(versionsJSON)}); err != nil {
+				logger.Warn("更新 prompt 版本失败", zap.Error(err))
+			}
+		}
+	}
+
+	s.saveUsage(ctx, analysisID, userID, usage.Summary())
+	logger.Info("分析完成", zap.Int("overall_score", report.OverallScore))
+
 	// 标记完成
 	if s.progressMgr != nil {
 		s.progressMgr.Complete(analysisID, s.totalSteps, s.totalSteps, report.OverallScore)
@@ -166,19 +367,58 @@ func (s *GEOAnalysisService) GetByID(id int64) (*model.GEOAnalysisResponse, erro
 		return nil, err
 	}
 
-	return s.ToResponse(analysis), nil
+	resp := s.ToResponse(analysis)
+	if s.usageRepo != nil {
+		usages, err := s.usageRepo.ListByAnalysis(context.Background(), id)
+		if err != nil {
+			return nil, err
+		}
+		resp.LLMUsage = summarizeAnalysisUsage(usages)
+	}
+	if s.results != nil && analysis.ResultsNormalized {
+		if resp.Results, err = s.results.Get(context.Background(), id); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
 }
 
-// List 查询列表
+// List 查询列表，全文搜索时返回各字段命中的片段
 func (s *GEOAnalysisService) List(req *model.GEOAnalysisListRequest) ([]model.GEOAnalysisResponse, int64, error) {
+	if req.From != "" || req.To != "" {
+		from, to, err := parseOptionalDateRange(req.From, req.To)
+		if err != nil {
+			return nil, 0, err
+		}
+		req.CreatedFrom, req.CreatedTo = from, to
+	}
+	if req.MinScore != nil && req.MaxScore != nil && *req.MinScore > *req.MaxScore {
+		return nil, 0, ErrInvalidScoreRange
+	}
+
 	analyses, total, err := s.repo.List(req)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	terms := repository.SearchTerms(req.Q)
+	var snippets map[int64]map[string]string
+	if len(terms) > 0 {
+		ids := make([]int64, len(analyses))
+		for i := range analyses {
+			ids[i] = analyses[i].ID
+		}
+		if snippets, err = s.repo.Snippets(ids, terms); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	responses := make([]model.GEOAnalysisResponse, len(analyses))
 	for i, analysis := range analyses {
 		responses[i] = *s.ToResponse(&analysis)
+		if len(terms) > 0 {
+			responses[i].Highlights = searchHighlights(&analysis, terms, snippets[analysis.ID])
+		}
 	}
 
 	return responses, total, nil
@@ -186,9 +426,88 @@ func (s *GEOAnalysisService) List(req *model.GEOAnalysisListRequest) ([]model.GE
 
 // Delete 删除分析
 func (s *GEOAnalysisService) Delete(id int64) error {
+	if s.brandSvc != nil {
+		if err := s.brandSvc.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	if s.usageRepo != nil {
+		if err := s.usageRepo.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	if s.traceRepo != nil {
+		if err := s.traceRepo.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	if s.logRepo != nil {
+		if err := s.logRepo.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	if s.experiments != nil {
+		if err := s.experiments.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	if s.results != nil {
+		if err := s.results.DeleteByAnalysis(context.Background(), id); err != nil {
+			return err
+		}
+	}
 	return s.repo.Delete(id)
 }
 
+// Rate 用户为分析结果评分（1-5），用于比较 prompt 实验变体
+func (s *GEOAnalysisService) Rate(id int64, req *model.GEOAnalysisRatingRequest) (*model.GEOAnalysisResponse, error) {
+	analysis, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+	if analysis.Status != "completed" {
+		return nil, ErrAnalysisNotCompleted
+	}
+
+	if err := s.repo.UpdateFields(id, map[string]any{
+		"rating":         req.Rating,
+		"rating_comment": req.Comment,
+	}); err != nil {
+		return nil, err
+	}
+	analysis.Rating = &req.Rating
+	analysis.RatingComment = req.Comment
+	return s.ToResponse(analysis), nil
+}
+
+// Report 从已完成的分析记录还原优化报告，用于导出
+func (s *GEOAnalysisService) Report(id int64) (*models.OptimizationReport, error) {
+	analysis, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, err
+	}
+	if analysis.Status != "completed" {
+		return nil, ErrAnalysisNotCompleted
+	}
+
+	// 结构化结果已写入子表时以子表为准，旧记录从 JSON 字段和报告原文还原
+	report := legacyReport(analysis)
+	if s.results != nil && analysis.ResultsNormalized {
+		results, err := s.results.Get(context.Background(), id)
+		if err != nil {
+			return nil, err
+		}
+		applyAnalysisResults(report, results)
+	}
+	return report, nil
+}
+
 // ToResponse 转换为响应格式（公开方法）
 func (s *GEOAnalysisService) ToResponse(analysis *model.GEOAnalysis) *model.GEOAnalysisResponse {
 	return &model.GEOAnalysisResponse{
@@ -197,6 +516,11 @@ func (s *GEOAnalysisService) ToResponse(analysis *model.GEOAnalysis) *model.GEOA
 		Title:                   analysis.Title,
 		MainQuery:               analysis.MainQuery,
 		Platform:                analysis.Platform,
+		Country:                 analysis.Country,
+		Language:                analysis.Language,
+		Device:                  analysis.Device,
+		Page:                    analysis.Page,
+		OutputLanguage:          analysis.OutputLanguage,
 		OverallScore:            analysis.OverallScore,
 		OptimizedScore:          analysis.OptimizedScore,
 		Status:                  analysis.Status,
@@ -208,6 +532,11 @@ func (s *GEOAnalysisService) ToResponse(analysis *model.GEOAnalysis) *model.GEOA
 		OptimizedArticle:        analysis.OptimizedArticle,
 		ContentGaps:             analysis.ContentGaps,
 		OptimizationSuggestions: analysis.OptimizationSuggestions,
+		CompetitorAnalysis:      analysis.CompetitorAnalysis,
+		SERPFeatures:            analysis.SERPFeatures,
+		PromptVersions:          analysis.PromptVersions,
+		Rating:                  analysis.Rating,
+		RatingComment:           analysis.RatingComment,
 		ValidationResult:        analysis.ValidationResult,
 		CreatedAt:               analysis.CreatedAt,
 		UpdatedAt:               analysis.UpdatedAt,
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+)
+
+// saveUsage 保存分析的 LLM 用量（失败只记录日志）
+func (s *GEOAnalysisService) saveUsage(ctx context.Context, analysisID int64, userID *int64, usage *models.LLMUsage) {
+	if s.usageRepo == nil || usage == nil {
+		return
+	}
+
+	records := make([]model.GEOLLMUsage, 0, len(usage.Agents))
+	for _, a := range usage.Agents {
+		records = append(records, model.GEOLLMUsage{
+			AnalysisID:       analysisID,
+			UserID:           userID,
+			Agent:            a.Agent,
+			Model:            a.Model,
+			Calls:            a.Calls,
+			PromptTokens:     a.PromptTokens,
+			CompletionTokens: a.CompletionTokens,
+			TotalTokens:      a.TotalTokens,
+			LatencyMs:        a.LatencyMs,
+			Cost:             a.Cost,
+			Currency:         usage.Currency,
+			EstimatedCalls:   a.EstimatedCalls,
+		})
+	}
+
+	if err := s.usageRepo.ReplaceByAnalysis(ctx, analysisID, records); err != nil {
+		zap.L().Error("保存 LLM 用量失败",
+			zap.Int64("analysis_id", analysisID),
+			zap.Error(err))
+	}
+}
+
+// summarizeAnalysisUsage 汇总单次分析的用量记录，没有记录时返回 nil
+func summarizeAnalysisUsage(usages []model.GEOLLMUsage) *model.GEOLLMUsageSummary {
+	if len(usages) == 0 {
+		return nil
+	}
+
+	summary := &model.GEOLLMUsageSummary{
+		Currency: usages[0].Currency,
+		Agents:   usages,
+	}
+	for _, u := range usages {
+		summary.Calls += u.Calls
+		summary.PromptTokens += u.PromptTokens
+		summary.CompletionTokens += u.CompletionTokens
+		summary.TotalTokens += u.TotalTokens
+		summary.LatencyMs += u.LatencyMs
+		summary.Cost += u.Cost
+	}
+	return summary
+}
+
+// UsageSummary 按用户和模型汇总时间范围内的 LLM 用量和估算费用，用于计费
+func (s *GEOAnalysisService) UsageSummary(ctx context.Context, req *model.GEOUsageSummaryRequest) (*model.GEOUsageSummaryResponse, error) {
+	from, to, err := parseDateRange(req.From, req.To, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.GEOUsageSummaryResponse{
+		From:  from.Format(dateLayout),
+		To:    to.Format(dateLayout),
+		Users: []model.GEOUserUsage{},
+	}
+	if s.usageRepo == nil {
+		return result, nil
+	}
+
+	usages, err := s.usageRepo.ListBetween(ctx, from, to.AddDate(0, 0, 1), req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	type userAccumulator struct {
+		usage    model.GEOUserUsage
+		analyses map[int64]bool
+		models   map[string]*model.GEOModelUsage
+	}
+	// 未登录用户（user_id 为空）使用 0 作为键
+	users := make(map[int64]*userAccumulator)
+	for _, u := range usages {
+		var key int64
+		if u.UserID != nil {
+			key = *u.UserID
+		}
+		acc, ok := users[key]
+		if !ok {
+			acc = &userAccumulator{
+				usage:    model.GEOUserUsage{UserID: u.UserID, Cost: make(map[string]float64)},
+				analyses: make(map[int64]bool),
+				models:   make(map[string]*model.GEOModelUsage),
+			}
+			users[key] = acc
+		}
+
+		acc.analyses[u.AnalysisID] = true
+		acc.usage.Calls += u.Calls
+		acc.usage.PromptTokens += u.PromptTokens
+		acc.usage.CompletionTokens += u.CompletionTokens
+		acc.usage.TotalTokens += u.TotalTokens
+		acc.usage.Cost[u.Currency] += u.Cost
+
+		modelKey := u.Model + "|" + u.Currency
+		m, ok := acc.models[modelKey]
+		if !ok {
+			m = &model.GEOModelUsage{Model: u.Model, Currency: u.Currency}
+			acc.models[modelKey] = m
+		}
+		m.Calls += u.Calls
+		m.PromptTokens += u.PromptTokens
+		m.CompletionTokens += u.CompletionTokens
+		m.TotalTokens += u.TotalTokens
+		m.Cost += u.Cost
+	}
+
+	keys := make([]int64, 0, len(users))
+	for k := range users {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, k := range keys {
+		acc := users[k]
+		acc.usage.Analyses = len(acc.analyses)
+		acc.usage.Models = make([]model.GEOModelUsage, 0, len(acc.models))
+		for _, m := range acc.models {
+			acc.usage.Models = append(acc.usage.Models, *m)
+		}
+		sort.Slice(acc.usage.Models, func(i, j int) bool {
+			return acc.usage.Models[i].TotalTokens > acc.usage.Models[j].TotalTokens
+		})
+		result.Users = append(result.Users, acc.usage)
+	}
+	return result, nil
+}
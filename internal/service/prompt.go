@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// ErrPromptNotFound prompt 不存在
+var ErrPromptNotFound = errors.New("prompt 不存在")
+
+// ErrInvalidPrompt prompt 模板无效
+var ErrInvalidPrompt = prompts.ErrInvalid
+
+// ErrPromptVersionNotFound prompt 版本不存在
+var ErrPromptVersionNotFound = repository.ErrPromptVersionNotFound
+
+// PromptService prompt 管理服务，数据库中激活的版本优先于覆盖目录和内嵌模板，修改后立即生效
+type PromptService struct {
+	repo     *repository.PromptRepository
+	registry *prompts.Registry
+}
+
+// NewPromptService 创建 prompt 管理服务
+func NewPromptService(repo *repository.PromptRepository, registry *prompts.Registry) *PromptService {
+	return &PromptService{
+		repo:     repo,
+		registry: registry,
+	}
+}
+
+// Load 将数据库中激活的版本加载到注册表
+func (s *PromptService) Load(ctx context.Context) error {
+	active, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make([]prompts.Prompt, 0, len(active))
+	for _, p := range active {
+		overrides = append(overrides, prompts.NewDBPrompt(p.Name, p.Content, p.Version))
+	}
+	s.registry.SetOverrides(overrides)
+	return nil
+}
+
+// List 列出所有 prompt 及当前生效的版本
+func (s *PromptService) List(ctx context.Context) ([]model.PromptSummary, error) {
+	counts, err := s.repo.CountByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := s.registry.Names()
+	list := make([]model.PromptSummary, 0, len(names))
+	for _, name := range names {
+		p, err := s.registry.Get(name)
+		if err != nil {
+			continue
+		}
+		list = append(list, model.PromptSummary{
+			Name:     name,
+			Source:   p.Source,
+			Version:  p.Version,
+			Versions: counts[name],
+		})
+	}
+	return list, nil
+}
+
+// Get 获取 prompt 详情及数据库中的所有版本
+func (s *PromptService) Get(ctx context.Context, name string) (*model.PromptDetail, error) {
+	p, err := s.registry.Get(name)
+	if err != nil {
+		return nil, ErrPromptNotFound
+	}
+
+	versions, err := s.repo.ListByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.PromptDetail{
+		Name:     name,
+		Source:   p.Source,
+		Version:  p.Version,
+		Content:  p.Content,
+		Path:     p.Path,
+		Versions: versions,
+	}
+	if builtin, ok := s.registry.Builtin(name); ok {
+		detail.Builtin = builtin.Content
+	}
+	return detail, nil
+}
+
+// Create 创建新版本，默认立即激活
+func (s *PromptService) Create(ctx context.Context, name string, req *model.PromptCreateRequest, userID *int64) (*model.GEOPrompt, error) {
+	if _, err := s.registry.Get(name); err != nil {
+		return nil, ErrPromptNotFound
+	}
+	if err := prompts.Validate(ctx, req.Content); err != nil {
+		return nil, err
+	}
+
+	p := &model.GEOPrompt{
+		Name:      name,
+		Content:   req.Content,
+		Comment:   req.Comment,
+		Active:    req.Activate == nil || *req.Activate,
+		CreatedBy: userID,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	if p.Active {
+		s.registry.SetOverride(prompts.NewDBPrompt(p.Name, p.Content, p.Version))
+	}
+	return p, nil
+}
+
+// Activate 激活指定版本（可用于回滚）
+func (s *PromptService) Activate(ctx context.Context, name string, version int) (*model.GEOPrompt, error) {
+	p, err := s.repo.Activate(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	s.registry.SetOverride(prompts.NewDBPrompt(p.Name, p.Content, p.Version))
+	return p, nil
+}
+
+// Reset 停用数据库中的版本，恢复使用覆盖目录或内嵌模板
+func (s *PromptService) Reset(ctx context.Context, name string) error {
+	if err := s.repo.Deactivate(ctx, name); err != nil {
+		return err
+	}
+	s.registry.RemoveOverride(name)
+	return nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/solariswu/peanut/internal/agent/geo/flow/prompts"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// ErrExperimentNotFound 实验不存在
+var ErrExperimentNotFound = repository.ErrExperimentNotFound
+
+// ErrInvalidExperiment 实验配置无效
+var ErrInvalidExperiment = errors.New("实验配置无效")
+
+// PromptExperimentService prompt A/B 实验服务：按比例为分析分配 prompt 变体，并对比各变体的结果
+type PromptExperimentService struct {
+	repo       *repository.PromptExperimentRepository
+	promptRepo *repository.PromptRepository
+	registry   *prompts.Registry
+}
+
+// NewPromptExperimentService 创建 prompt A/B 实验服务
+func NewPromptExperimentService(repo *repository.PromptExperimentRepository, promptRepo *repository.PromptRepository, registry *prompts.Registry) *PromptExperimentService {
+	return &PromptExperimentService{
+		repo:       repo,
+		promptRepo: promptRepo,
+		registry:   registry,
+	}
+}
+
+// Create 创建并开始实验：变体比例之和须为 100，同一 prompt 同时只能有一个进行中的实验
+func (s *PromptExperimentService) Create(ctx context.Context, req *model.PromptExperimentCreateRequest) (*model.GEOPromptExperiment, error) {
+	if _, err := s.registry.Get(req.Prompt); err != nil {
+		return nil, ErrPromptNotFound
+	}
+
+	total := 0
+	keys := map[string]bool{}
+	variants := make([]model.GEOPromptExperimentVariant, 0, len(req.Variants))
+	for _, v := range req.Variants {
+		if keys[v.Key] {
+			return nil, fmt.Errorf("%w: 变体 %s 重复", ErrInvalidExperiment, v.Key)
+		}
+		keys[v.Key] = true
+		total += v.Weight
+
+		if v.PromptVersion > 0 {
+			if _, err := s.promptRepo.GetVersion(ctx, req.Prompt, v.PromptVersion); err != nil {
+				if errors.Is(err, ErrPromptVersionNotFound) {
+					return nil, fmt.Errorf("%w: 变体 %s 的 prompt 版本 %d 不存在", ErrInvalidExperiment, v.Key, v.PromptVersion)
+				}
+				return nil, err
+			}
+		}
+		variants = append(variants, model.GEOPromptExperimentVariant{
+			Key:           v.Key,
+			PromptVersion: v.PromptVersion,
+			Weight:        v.Weight,
+		})
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("%w: 变体比例之和为 %d，应为 100", ErrInvalidExperiment, total)
+	}
+
+	running, err := s.repo.HasRunning(ctx, req.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, fmt.Errorf("%w: %s 已有进行中的实验", ErrInvalidExperiment, req.Prompt)
+	}
+
+	exp := &model.GEOPromptExperiment{
+		Name:        req.Name,
+		Prompt:      req.Prompt,
+		Description: req.Description,
+		Status:      model.ExperimentStatusRunning,
+		Variants:    variants,
+	}
+	if err := s.repo.Create(ctx, exp); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+// List 查询实验列表
+func (s *PromptExperimentService) List(ctx context.Context, status string) ([]model.GEOPromptExperiment, error) {
+	return s.repo.List(ctx, status)
+}
+
+// Stop 停止实验，之后的分析不再分配变体
+func (s *PromptExperimentService) Stop(ctx context.Context, id int64) (*model.GEOPromptExperiment, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Stop(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// promptAssignment 一次分析中某个实验的变体分配
+type promptAssignment struct {
+	prompt string
+	record model.GEOPromptExperimentAssignment
+}
+
+// Assign 为分析分配各进行中实验的变体，返回带变体 prompt 的上下文
+// 同一分析在同一实验中总是分到同一变体
+func (s *PromptExperimentService) Assign(ctx context.Context, analysisID int64) (context.Context, []promptAssignment, error) {
+	exps, err := s.repo.List(ctx, model.ExperimentStatusRunning)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	assignments := make([]promptAssignment, 0, len(exps))
+	for _, exp := range exps {
+		variant := pickVariant(exp.ID, analysisID, exp.Variants)
+		if variant == nil {
+			continue
+		}
+		if variant.PromptVersion > 0 {
+			p, err := s.promptRepo.GetVersion(ctx, exp.Prompt, variant.PromptVersion)
+			if err != nil {
+				return ctx, nil, err
+			}
+			ctx = prompts.WithOverride(ctx, prompts.NewDBPrompt(p.Name, p.Content, p.Version))
+		}
+		assignments = append(assignments, promptAssignment{
+			prompt: exp.Prompt,
+			record: model.GEOPromptExperimentAssignment{
+				ExperimentID: exp.ID,
+				AnalysisID:   analysisID,
+				VariantKey:   variant.Key,
+			},
+		})
+	}
+	return ctx, assignments, nil
+}
+
+// Record 保存分配结果，记录实际使用的 prompt 版本和输出解析失败次数
+func (s *PromptExperimentService) Record(ctx context.Context, assignments []promptAssignment, rec *prompts.Recorder) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	versions := rec.Versions()
+	failures := rec.ParseFailures()
+	records := make([]model.GEOPromptExperimentAssignment, 0, len(assignments))
+	for _, a := range assignments {
+		record := a.record
+		record.PromptVersion = versions[a.prompt]
+		record.ParseFailures = failures[a.prompt]
+		records = append(records, record)
+	}
+	return s.repo.CreateAssignments(ctx, records)
+}
+
+// DeleteByAnalysis 删除分析的变体分配
+func (s *PromptExperimentService) DeleteByAnalysis(ctx context.Context, analysisID int64) error {
+	return s.repo.DeleteAssignmentsByAnalysis(ctx, analysisID)
+}
+
+// Results 对比实验各变体的结果：失败率、评分、验证评分、解析失败率、该 Agent 的 token 用量和费用、用户评分
+func (s *PromptExperimentService) Results(ctx context.Context, id int64) (*model.PromptExperimentResults, error) {
+	exp, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.VariantStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	usages, err := s.repo.UsageStats(ctx, id, exp.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	statsByKey := make(map[string]repository.ExperimentVariantStats, len(stats))
+	for _, st := range stats {
+		statsByKey[st.VariantKey] = st
+	}
+	usageByKey := make(map[string]repository.ExperimentUsageStats, len(usages))
+	for _, u := range usages {
+		usageByKey[u.VariantKey] = u
+	}
+
+	result := &model.PromptExperimentResults{
+		Experiment: exp,
+		Variants:   make([]model.PromptExperimentOutcome, 0, len(exp.Variants)),
+	}
+	for _, v := range exp.Variants {
+		st := statsByKey[v.Key]
+		outcome := model.PromptExperimentOutcome{
+			Key:                v.Key,
+			PromptVersion:      v.PromptVersion,
+			Weight:             v.Weight,
+			Analyses:           st.Analyses,
+			Completed:          st.Completed,
+			AvgOverallScore:    roundTo(st.AvgOverallScore, 2),
+			Validated:          st.Validated,
+			AvgValidationScore: roundTo(st.AvgValidationScore, 2),
+			Ratings:            st.Ratings,
+			AvgRating:          roundTo(st.AvgRating, 2),
+		}
+		if finished := st.Completed + st.Failed; finished > 0 {
+			outcome.FailureRate = roundTo(float64(st.Failed)/float64(finished), 4)
+		}
+		if st.Analyses > 0 {
+			outcome.ParseFailureRate = roundTo(float64(st.ParseFailed)/float64(st.Analyses), 4)
+		}
+		if u, ok := usageByKey[v.Key]; ok && u.Analyses > 0 {
+			outcome.AvgTokens = roundTo(float64(u.Tokens)/float64(u.Analyses), 2)
+			outcome.AvgCost = roundTo(u.Cost/float64(u.Analyses), 6)
+			outcome.Currency = u.Currency
+		}
+		result.Variants = append(result.Variants, outcome)
+	}
+	return result, nil
+}
+
+// pickVariant 按分析 ID 的哈希和变体比例选择变体
+func pickVariant(experimentID, analysisID int64, variants []model.GEOPromptExperimentVariant) *model.GEOPromptExperimentVariant {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d:%d", experimentID, analysisID)
+	bucket := int(h.Sum32() % 100)
+
+	cumulative := 0
+	for i := range variants {
+		cumulative += variants[i].Weight
+		if bucket < cumulative {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// roundTo 保留 n 位小数
+func roundTo(v float64, n int) float64 {
+	p := math.Pow10(n)
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,87 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// TestPromptExperimentResults 测试按变体统计失败率、评分、验证评分、解析失败率和用户评分
+func TestPromptExperimentResults(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	analyses := repository.NewGEOAnalysisRepository(db)
+	repo := repository.NewPromptExperimentRepository(db)
+	s := NewPromptExperimentService(repo, nil, nil)
+
+	exp := &model.GEOPromptExperiment{
+		Name:   "test",
+		Prompt: "content_rewriter",
+		Status: model.ExperimentStatusRunning,
+		Variants: []model.GEOPromptExperimentVariant{
+			{Key: "A", Weight: 50},
+			{Key: "B", Weight: 50},
+		},
+	}
+	if err := repo.Create(ctx, exp); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	intPtr := func(v int) *int { return &v }
+	var assignments []model.GEOPromptExperimentAssignment
+	seed := func(variant, status string, score int, validation, rating *int, parseFailures int) {
+		analysis := &model.GEOAnalysis{
+			URL:             "https://example.com",
+			Status:          status,
+			OverallScore:    score,
+			ValidationScore: validation,
+			Rating:          rating,
+		}
+		if err := analyses.Create(analysis); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		assignments = append(assignments, model.GEOPromptExperimentAssignment{
+			ExperimentID:  exp.ID,
+			AnalysisID:    analysis.ID,
+			VariantKey:    variant,
+			ParseFailures: parseFailures,
+		})
+	}
+
+	seed("A", "completed", 40, intPtr(60), intPtr(4), 0)
+	seed("A", "completed", 50, intPtr(71), nil, 0)
+	// 验证结果无法解析时没有验证评分，不计入平均值
+	seed("A", "completed", 60, nil, intPtr(5), 1)
+	seed("A", "failed", 0, nil, nil, 0)
+	seed("B", "completed", 30, intPtr(50), nil, 0)
+	// 失败的分析即使有验证评分也不计入
+	seed("B", "failed", 0, intPtr(90), nil, 0)
+	seed("B", "processing", 0, nil, nil, 0)
+	if err := repo.CreateAssignments(ctx, assignments); err != nil {
+		t.Fatalf("CreateAssignments() error = %v", err)
+	}
+
+	result, err := s.Results(ctx, exp.ID)
+	if err != nil {
+		t.Fatalf("Results() error = %v", err)
+	}
+	if len(result.Variants) != 2 {
+		t.Fatalf("Variants = %+v, want 2", result.Variants)
+	}
+
+	want := []model.PromptExperimentOutcome{
+		{Key: "A", Weight: 50, Analyses: 4, Completed: 3, FailureRate: 0.25, AvgOverallScore: 50,
+			Validated: 2, AvgValidationScore: 65.5, ParseFailureRate: 0.25, Ratings: 2, AvgRating: 4.5},
+		{Key: "B", Weight: 50, Analyses: 3, Completed: 1, FailureRate: 0.5, AvgOverallScore: 30,
+			Validated: 1, AvgValidationScore: 50},
+	}
+	for i, w := range want {
+		if got := result.Variants[i]; got != w {
+			t.Errorf("Variants[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/export"
+	"github.com/solariswu/peanut/internal/agent/geo/i18n"
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// ErrReportTemplateNotFound 报告模板不存在
+var ErrReportTemplateNotFound = repository.ErrReportTemplateNotFound
+
+// ErrInvalidReportTemplate 报告模板配置无效
+var ErrInvalidReportTemplate = errors.New("报告模板配置无效")
+
+// workspaceRe 工作区标识格式
+var workspaceRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ReportService 报告导出和工作区白标模板服务
+type ReportService struct {
+	repo     *repository.ReportTemplateRepository
+	analyses *GEOAnalysisService
+	exporter *export.Exporter
+}
+
+// NewReportService 创建报告服务
+func NewReportService(repo *repository.ReportTemplateRepository, analyses *GEOAnalysisService, exporter *export.Exporter) *ReportService {
+	return &ReportService{
+		repo:     repo,
+		analyses: analyses,
+		exporter: exporter,
+	}
+}
+
+// Save 创建或整体更新工作区的报告模板
+func (s *ReportService) Save(ctx context.Context, workspace string, req *model.ReportTemplateRequest) (*model.GEOReportTemplate, error) {
+	if !workspaceRe.MatchString(workspace) {
+		return nil, fmt.Errorf("%w: 工作区标识只能包含小写字母、数字、- 和 _", ErrInvalidReportTemplate)
+	}
+	tmpl, err := buildTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetByWorkspace(ctx, workspace)
+	if errors.Is(err, ErrReportTemplateNotFound) {
+		record = &model.GEOReportTemplate{Workspace: workspace}
+	} else if err != nil {
+		return nil, err
+	}
+	record.BrandName = tmpl.Brand.Name
+	record.Color = tmpl.Brand.Color
+	// 地址未变时复用已保存的 Logo，否则重新下载
+	if tmpl.Brand.LogoURL != record.LogoURL || len(record.Logo) == 0 {
+		if record.Logo, err = loadLogo(ctx, tmpl.Brand.LogoURL); err != nil {
+			return nil, err
+		}
+	}
+	record.LogoURL = tmpl.Brand.LogoURL
+	record.Header = tmpl.Brand.Header
+	record.Footer = tmpl.Brand.Footer
+	record.Sections = marshalStrings(tmpl.Sections)
+	record.Language = tmpl.Language
+
+	if err := s.repo.Save(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Get 获取工作区的报告模板
+func (s *ReportService) Get(ctx context.Context, workspace string) (*model.GEOReportTemplate, error) {
+	return s.repo.GetByWorkspace(ctx, workspace)
+}
+
+// List 查询所有报告模板
+func (s *ReportService) List(ctx context.Context) ([]model.ReportTemplateResponse, error) {
+	templates, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.ReportTemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = *s.ToResponse(&templates[i])
+	}
+	return responses, nil
+}
+
+// Delete 删除工作区的报告模板
+func (s *ReportService) Delete(ctx context.Context, workspace string) error {
+	return s.repo.Delete(ctx, workspace)
+}
+
+// Export 导出分析报告，workspace 为空时使用默认品牌
+func (s *ReportService) Export(ctx context.Context, analysisID int64, workspace, format string) (*export.File, error) {
+	if export.ContentType(format) == "" {
+		return nil, export.ErrUnsupportedFormat
+	}
+
+	var tmpl *export.Template
+	if workspace != "" {
+		record, err := s.repo.GetByWorkspace(ctx, workspace)
+		if err != nil {
+			return nil, err
+		}
+		tmpl = toExportTemplate(record)
+	}
+
+	report, err := s.analyses.Report(analysisID)
+	if err != nil {
+		return nil, err
+	}
+	return s.exporter.Export(&export.Document{ID: analysisID, Report: report, Template: tmpl}, format)
+}
+
+// Preview 使用未保存的模板预览报告，未指定分析时使用示例报告
+func (s *ReportService) Preview(ctx context.Context, req *model.ReportTemplatePreviewRequest) (*export.File, error) {
+	tmpl, err := buildTemplate(&req.ReportTemplateRequest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Format == export.FormatPDF || req.Format == export.FormatDOCX {
+		if tmpl.Brand.Logo, err = loadLogo(ctx, tmpl.Brand.LogoURL); err != nil {
+			return nil, err
+		}
+	}
+	return s.preview(tmpl, req.AnalysisID, req.Format)
+}
+
+// PreviewSaved 预览已保存的工作区模板
+func (s *ReportService) PreviewSaved(ctx context.Context, workspace string, analysisID int64, format string) (*export.File, error) {
+	record, err := s.repo.GetByWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	return s.preview(toExportTemplate(record), analysisID, format)
+}
+
+// preview 按模板渲染预览
+func (s *ReportService) preview(tmpl *export.Template, analysisID int64, format string) (*export.File, error) {
+	if format == "" {
+		format = export.FormatHTML
+	}
+	if export.ContentType(format) == "" {
+		return nil, export.ErrUnsupportedFormat
+	}
+
+	report := sampleReport()
+	if analysisID > 0 {
+		var err error
+		if report, err = s.analyses.Report(analysisID); err != nil {
+			return nil, err
+		}
+	}
+	return s.exporter.Export(&export.Document{ID: analysisID, Report: report, Template: tmpl}, format)
+}
+
+// ToResponse 转换为响应格式
+func (s *ReportService) ToResponse(record *model.GEOReportTemplate) *model.ReportTemplateResponse {
+	return &model.ReportTemplateResponse{
+		ID:        record.ID,
+		Workspace: record.Workspace,
+		BrandName: record.BrandName,
+		Color:     record.Color,
+		LogoURL:   record.LogoURL,
+		HasLogo:   len(record.Logo) > 0,
+		Header:    record.Header,
+		Footer:    record.Footer,
+		Sections:  unmarshalStrings(record.Sections),
+		Language:  record.Language,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+}
+
+// buildTemplate 校验请求并补全默认值
+func buildTemplate(req *model.ReportTemplateRequest) (*export.Template, error) {
+	brand := export.DefaultBranding()
+	brand.Name = strings.TrimSpace(req.BrandName)
+	if req.Color != "" {
+		if !export.ValidColor(req.Color) {
+			return nil, fmt.Errorf("%w: 主色应为 #RRGGBB 格式", ErrInvalidReportTemplate)
+		}
+		brand.Color = req.Color
+	}
+	brand.LogoURL = strings.TrimSpace(req.LogoURL)
+	brand.Header = strings.TrimSpace(req.Header)
+	brand.Footer = strings.TrimSpace(req.Footer)
+
+	sections := normalizeQueries(req.Sections)
+	if len(sections) == 0 {
+		sections = export.DefaultSections
+	}
+	for _, section := range sections {
+		if !export.IsSection(section) {
+			return nil, fmt.Errorf("%w: 未知章节 %s，可选 %s", ErrInvalidReportTemplate, section, strings.Join(export.DefaultSections, "、"))
+		}
+	}
+
+	// 未指定语言时使用分析的输出语言
+	language := strings.TrimSpace(req.Language)
+	if language != "" && !export.IsLanguage(language) {
+		return nil, fmt.Errorf("%w: 语言可选 %s", ErrInvalidReportTemplate, strings.Join(i18n.Languages, "、"))
+	}
+
+	return &export.Template{Brand: brand, Sections: sections, Language: language}, nil
+}
+
+// loadLogo 下载模板的 Logo 用于嵌入 PDF 和 DOCX，地址为空时返回 nil
+func loadLogo(ctx context.Context, url string) ([]byte, error) {
+	if url == "" {
+		return nil, nil
+	}
+	logo, err := export.LoadLogo(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReportTemplate, err)
+	}
+	return logo, nil
+}
+
+// toExportTemplate 将数据库记录转换为导出模板
+func toExportTemplate(record *model.GEOReportTemplate) *export.Template {
+	return &export.Template{
+		Brand: export.Branding{
+			Name:    record.BrandName,
+			Color:   record.Color,
+			LogoURL: record.LogoURL,
+			Logo:    record.Logo,
+			Header:  record.Header,
+			Footer:  record.Footer,
+		},
+		Sections: unmarshalStrings(record.Sections),
+		Language: record.Language,
+	}
+}
+
+// sampleReport 预览用的示例报告
+func sampleReport() *models.OptimizationReport {
+	return &models.OptimizationReport{
+		URL:          "https://example.com/blog/geo-guide",
+		Title:        "GEO 入门指南",
+		MainQuery:    "什么是生成式引擎优化",
+		OverallScore: 68,
+		ComparisonTable: []models.ComparisonItem{
+			{Dimension: "内容结构", YourContent: "长段落为主", AIOverview: "分点列出定义和步骤", Similarity: "都覆盖了基本定义", Difference: "缺少步骤化说明"},
+			{Dimension: "数据支撑", YourContent: "无引用数据", AIOverview: "引用行业报告", Similarity: "-", Difference: "缺少可信来源"},
+		},
+		ContentGaps: []string{"缺少 GEO 与 SEO 的对比", "缺少常见问题解答"},
+		SERPFeatures: &models.SERPFeatures{
+			PeopleAlsoAsk: []string{"GEO 和 SEO 有什么区别？", "如何提高被 AI 引用的概率？"},
+		},
+		OptimizationSuggestions: []models.OptimizationSuggestion{
+			{Priority: "high", Category: "结构", Issue: "缺少小标题", Suggestion: "按问题拆分小标题，每节开头直接给出答案"},
+			{Priority: "medium", Category: "可信度", Issue: "缺少数据", Suggestion: "补充 **行业报告** 中的统计数据并标注来源"},
+			{Priority: "low", Category: "格式", Issue: "缺少 FAQ", Suggestion: "在文末增加常见问题解答"},
+		},
+		OptimizedArticle: "# 什么是 GEO\n\n生成式引擎优化（GEO）是让内容更容易被 AI 搜索引用的方法。\n\n## 与 SEO 的区别\n\n- SEO 关注排名\n- GEO 关注被引用",
+		Timestamp:        time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
@@ -0,0 +1,658 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// 引用份额相关错误
+var (
+	ErrQuerySetNotFound = repository.ErrQuerySetNotFound
+	ErrQuerySetRunning  = errors.New("查询集正在采集中")
+	ErrInvalidDateRange = errors.New("无效的日期范围")
+	// ErrUnsupportedPlatform 目前只能采集 Google AI Overview 的回答
+	ErrUnsupportedPlatform = errors.New("不支持的平台")
+)
+
+// AnswerRetriever 获取 AI 回答及其引用来源
+type AnswerRetriever interface {
+	GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error)
+}
+
+// ShareOfVoiceService 引用份额追踪服务
+type ShareOfVoiceService struct {
+	repo          *repository.QuerySetRepository
+	answers       AnswerRetriever
+	checkInterval time.Duration
+
+	mu      sync.Mutex
+	running map[int64]bool
+	jobCtx  context.Context // Start 传入的任务上下文，手动触发的采集也随其取消
+}
+
+// NewShareOfVoiceService 创建引用份额追踪服务
+func NewShareOfVoiceService(repo *repository.QuerySetRepository, answers AnswerRetriever) *ShareOfVoiceService {
+	return &ShareOfVoiceService{
+		repo:          repo,
+		answers:       answers,
+		checkInterval: time.Minute,
+		running:       make(map[int64]bool),
+		jobCtx:        context.Background(),
+	}
+}
+
+// Create 创建查询集
+func (s *ShareOfVoiceService) Create(ctx context.Context, req *model.QuerySetCreateRequest, userID *int64) (*model.QuerySet, error) {
+	platform := req.Platform
+	if platform == "" {
+		platform = string(models.PlatformGoogle)
+	}
+	if !supportedPlatform(platform) {
+		return nil, ErrUnsupportedPlatform
+	}
+	interval := req.IntervalMinutes
+	if interval == 0 {
+		interval = 24 * 60
+	}
+
+	set := &model.QuerySet{
+		Name:              req.Name,
+		Queries:           marshalStrings(normalizeQueries(req.Queries)),
+		Domains:           marshalStrings(normalizeDomains(req.Domains)),
+		CompetitorDomains: marshalStrings(normalizeDomains(req.CompetitorDomains)),
+		Platform:          platform,
+		IntervalMinutes:   interval,
+		Enabled:           true,
+		UserID:            userID,
+	}
+
+	if err := s.repo.Create(ctx, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// GetByID 获取查询集
+func (s *ShareOfVoiceService) GetByID(ctx context.Context, id int64) (*model.QuerySet, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List 查询查询集列表
+func (s *ShareOfVoiceService) List(ctx context.Context, userID *int64) ([]model.QuerySetResponse, error) {
+	sets, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.QuerySetResponse, len(sets))
+	for i := range sets {
+		responses[i] = *s.ToResponse(&sets[i])
+	}
+	return responses, nil
+}
+
+// Update 更新查询集
+func (s *ShareOfVoiceService) Update(ctx context.Context, id int64, req *model.QuerySetUpdateRequest) (*model.QuerySet, error) {
+	set, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		set.Name = req.Name
+	}
+	if len(req.Queries) > 0 {
+		set.Queries = marshalStrings(normalizeQueries(req.Queries))
+	}
+	if len(req.Domains) > 0 {
+		set.Domains = marshalStrings(normalizeDomains(req.Domains))
+	}
+	if req.CompetitorDomains != nil {
+		set.CompetitorDomains = marshalStrings(normalizeDomains(req.CompetitorDomains))
+	}
+	if req.IntervalMinutes > 0 {
+		set.IntervalMinutes = req.IntervalMinutes
+	}
+	if req.Enabled != nil {
+		set.Enabled = *req.Enabled
+	}
+
+	if err := s.repo.Update(ctx, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Delete 删除查询集
+func (s *ShareOfVoiceService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ListRuns 查询最近的采集记录
+func (s *ShareOfVoiceService) ListRuns(ctx context.Context, id int64, limit int) ([]model.QuerySetRun, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRuns(ctx, id, limit)
+}
+
+// ToResponse 转换为响应格式
+func (s *ShareOfVoiceService) ToResponse(set *model.QuerySet) *model.QuerySetResponse {
+	return &model.QuerySetResponse{
+		ID:                set.ID,
+		Name:              set.Name,
+		Queries:           unmarshalStrings(set.Queries),
+		Domains:           unmarshalStrings(set.Domains),
+		CompetitorDomains: unmarshalStrings(set.CompetitorDomains),
+		Platform:          set.Platform,
+		IntervalMinutes:   set.IntervalMinutes,
+		Enabled:           set.Enabled,
+		LastRunAt:         set.LastRunAt,
+		CreatedAt:         set.CreatedAt,
+		UpdatedAt:         set.UpdatedAt,
+	}
+}
+
+// Start 启动定时采集任务，阻塞直到 ctx 取消
+func (s *ShareOfVoiceService) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.jobCtx = ctx
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue 依次采集所有到期的查询集
+func (s *ShareOfVoiceService) runDue(ctx context.Context) {
+	sets, err := s.repo.ListDue(ctx, time.Now())
+	if err != nil {
+		zap.L().Error("获取待采集查询集失败", zap.Error(err))
+		return
+	}
+
+	for i := range sets {
+		if ctx.Err() != nil {
+			return
+		}
+		run, err := s.startRun(ctx, &sets[i])
+		if err != nil {
+			if !errors.Is(err, ErrQuerySetRunning) {
+				zap.L().Error("创建采集记录失败", zap.Int64("query_set_id", sets[i].ID), zap.Error(err))
+			}
+			continue
+		}
+		s.executeRun(ctx, &sets[i], run)
+	}
+}
+
+// RunNow 立即触发一次采集（异步执行）
+func (s *ShareOfVoiceService) RunNow(ctx context.Context, id int64) (*model.QuerySetRun, error) {
+	set, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(ctx, set)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	jobCtx := s.jobCtx
+	s.mu.Unlock()
+	go s.executeRun(jobCtx, set, run)
+
+	return run, nil
+}
+
+// startRun 标记查询集为采集中并创建采集记录
+func (s *ShareOfVoiceService) startRun(ctx context.Context, set *model.QuerySet) (*model.QuerySetRun, error) {
+	if !supportedPlatform(set.Platform) {
+		return nil, ErrUnsupportedPlatform
+	}
+
+	s.mu.Lock()
+	if s.running[set.ID] {
+		s.mu.Unlock()
+		return nil, ErrQuerySetRunning
+	}
+	s.running[set.ID] = true
+	s.mu.Unlock()
+
+	queries := unmarshalStrings(set.Queries)
+	run := &model.QuerySetRun{
+		QuerySetID:   set.ID,
+		Status:       model.QuerySetRunRunning,
+		QueriesTotal: len(queries),
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		s.finishRun(set.ID)
+		return nil, err
+	}
+	return run, nil
+}
+
+// supportedPlatform 判断平台是否可采集，AnswerRetriever 只对接了 Google AI Overview
+func supportedPlatform(platform string) bool {
+	return models.PlatformType(platform) == models.PlatformGoogle
+}
+
+// finishRun 清除采集中标记
+func (s *ShareOfVoiceService) finishRun(id int64) {
+	s.mu.Lock()
+	delete(s.running, id)
+	s.mu.Unlock()
+}
+
+// executeRun 逐个查询获取 AI 回答并记录被引用的域名及位置
+func (s *ShareOfVoiceService) executeRun(ctx context.Context, set *model.QuerySet, run *model.QuerySetRun) {
+	defer s.finishRun(set.ID)
+
+	queries := unmarshalStrings(set.Queries)
+	snapshots := make([]model.CitationSnapshot, 0)
+	var lastErr error
+
+	for _, query := range queries {
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+
+		overview, err := s.answers.GetAIOverview(ctx, query)
+		if err != nil {
+			run.QueriesFailed++
+			lastErr = err
+			zap.L().Warn("获取 AI 回答失败",
+				zap.Int64("query_set_id", set.ID),
+				zap.String("query", query),
+				zap.Error(err))
+			continue
+		}
+		if !overview.Found {
+			continue
+		}
+
+		run.QueriesAnswered++
+		citedAt := time.Now()
+		for i, source := range overview.Sources {
+			domain := tools.Domain(source)
+			if domain == "" {
+				continue
+			}
+			snapshots = append(snapshots, model.CitationSnapshot{
+				QuerySetID: set.ID,
+				RunID:      run.ID,
+				Query:      query,
+				Domain:     domain,
+				URL:        source,
+				Position:   i + 1,
+				CitedAt:    citedAt,
+			})
+		}
+	}
+
+	// 使用独立上下文保存结果，避免 ctx 取消导致已采集的数据丢失
+	saveCtx := context.Background()
+	if err := s.repo.CreateSnapshots(saveCtx, snapshots); err != nil {
+		lastErr = err
+	}
+
+	now := time.Now()
+	run.FinishedAt = &now
+	switch {
+	case ctx.Err() != nil:
+		// 中途取消的采集只覆盖部分查询，标记为已取消，不计入引用份额趋势
+		run.Status = model.QuerySetRunCancelled
+		lastErr = ctx.Err()
+	case run.QueriesTotal > 0 && run.QueriesFailed == run.QueriesTotal:
+		run.Status = model.QuerySetRunFailed
+	default:
+		run.Status = model.QuerySetRunCompleted
+	}
+	if lastErr != nil {
+		run.ErrorMessage = lastErr.Error()
+	}
+
+	if err := s.repo.UpdateRun(saveCtx, run); err != nil {
+		zap.L().Error("更新采集记录失败", zap.Int64("run_id", run.ID), zap.Error(err))
+	}
+	if run.Status == model.QuerySetRunCancelled {
+		// 不更新采集时间，服务重启后重新采集
+		return
+	}
+	if err := s.repo.UpdateFields(saveCtx, set.ID, map[string]any{"last_run_at": &now}); err != nil {
+		zap.L().Error("更新查询集采集时间失败", zap.Int64("query_set_id", set.ID), zap.Error(err))
+	}
+}
+
+// sovAccumulator 引用份额统计累加器
+type sovAccumulator struct {
+	citations   int
+	positionSum int
+	queries     map[string]bool // runID/query 组合，用于计算覆盖率
+}
+
+func (a *sovAccumulator) add(s *model.CitationSnapshot) {
+	a.citations++
+	a.positionSum += s.Position
+	a.queries[fmt.Sprintf("%d/%s", s.RunID, s.Query)] = true
+}
+
+func newSOVAccumulator() *sovAccumulator {
+	return &sovAccumulator{queries: make(map[string]bool)}
+}
+
+// ShareOfVoice 计算查询集在时间范围内各域名的引用份额趋势
+func (s *ShareOfVoiceService) ShareOfVoice(ctx context.Context, id int64, req *model.ShareOfVoiceRequest) (*model.ShareOfVoiceResponse, error) {
+	set, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := parseDateRange(req.From, req.To, 30)
+	if err != nil {
+		return nil, err
+	}
+	granularity := req.Granularity
+	if granularity != "week" {
+		granularity = "day"
+	}
+
+	runs, err := s.repo.ListCompletedRunsBetween(ctx, id, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	runIDs := make([]int64, len(runs))
+	answered := make(map[string]int)
+	periods := make([]string, 0)
+	totalAnswered := 0
+	for i, run := range runs {
+		runIDs[i] = run.ID
+		period := periodKey(run.CreatedAt, granularity)
+		if _, ok := answered[period]; !ok {
+			periods = append(periods, period)
+		}
+		answered[period] += run.QueriesAnswered
+		totalAnswered += run.QueriesAnswered
+	}
+
+	snapshots, err := s.repo.ListSnapshotsByRuns(ctx, runIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	own := unmarshalStrings(set.Domains)
+	competitors := unmarshalStrings(set.CompetitorDomains)
+	runPeriod := make(map[int64]string, len(runs))
+	for _, run := range runs {
+		runPeriod[run.ID] = periodKey(run.CreatedAt, granularity)
+	}
+
+	totals := make(map[string]*sovAccumulator)
+	byPeriod := make(map[string]map[string]*sovAccumulator)
+	periodCitations := make(map[string]int)
+	for i := range snapshots {
+		snap := &snapshots[i]
+		domain := groupDomain(snap.Domain, own, competitors)
+		period := runPeriod[snap.RunID]
+
+		if totals[domain] == nil {
+			totals[domain] = newSOVAccumulator()
+			byPeriod[domain] = make(map[string]*sovAccumulator)
+		}
+		if byPeriod[domain][period] == nil {
+			byPeriod[domain][period] = newSOVAccumulator()
+		}
+		totals[domain].add(snap)
+		byPeriod[domain][period].add(snap)
+		periodCitations[period]++
+	}
+
+	domains := selectSOVDomains(totals, own, competitors, req.Top)
+	result := make([]model.DomainShareOfVoice, 0, len(domains))
+	for _, domain := range domains {
+		acc := totals[domain]
+		if acc == nil {
+			acc = newSOVAccumulator()
+		}
+
+		item := model.DomainShareOfVoice{
+			Domain:      domain,
+			Role:        domainRole(domain, own, competitors),
+			Citations:   acc.citations,
+			Share:       percent(acc.citations, len(snapshots)),
+			Coverage:    percent(len(acc.queries), totalAnswered),
+			AvgPosition: average(acc.positionSum, acc.citations),
+			Trend:       make([]model.ShareOfVoicePoint, 0, len(periods)),
+		}
+
+		for _, period := range periods {
+			p := byPeriod[domain][period]
+			if p == nil {
+				p = newSOVAccumulator()
+			}
+			item.Trend = append(item.Trend, model.ShareOfVoicePoint{
+				Period:      period,
+				Citations:   p.citations,
+				Share:       percent(p.citations, periodCitations[period]),
+				Coverage:    percent(len(p.queries), answered[period]),
+				AvgPosition: average(p.positionSum, p.citations),
+			})
+		}
+
+		result = append(result, item)
+	}
+
+	return &model.ShareOfVoiceResponse{
+		QuerySetID:      id,
+		From:            from.Format(dateLayout),
+		To:              to.Format(dateLayout),
+		Granularity:     granularity,
+		Runs:            len(runs),
+		QueriesAnswered: totalAnswered,
+		TotalCitations:  len(snapshots),
+		Domains:         result,
+	}, nil
+}
+
+const dateLayout = "2006-01-02"
+
+// parseDateRange 解析日期范围，默认最近 defaultDays 天
+func parseDateRange(fromStr, toStr string, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if toStr != "" {
+		t, err := time.ParseInLocation(dateLayout, toStr, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, -defaultDays)
+	if fromStr != "" {
+		f, err := time.ParseInLocation(dateLayout, fromStr, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+		from = f
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// periodKey 返回时间所在的时间桶（按天、按周或按月，周以周一为起点，月以 1 日为起点）
+func periodKey(t time.Time, granularity string) string {
+	t = t.Local()
+	switch granularity {
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		t = t.AddDate(0, 0, -offset)
+	case "month":
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return t.Format(dateLayout)
+}
+
+// matchDomain 判断 domain 是否属于 target（相同或为其子域名）
+func matchDomain(domain, target string) bool {
+	return domain == target || strings.HasSuffix(domain, "."+target)
+}
+
+// groupDomain 将子域名归并到配置的我方/竞品域名，其他域名保持原样
+func groupDomain(domain string, own, competitors []string) string {
+	for _, d := range own {
+		if matchDomain(domain, d) {
+			return d
+		}
+	}
+	for _, d := range competitors {
+		if matchDomain(domain, d) {
+			return d
+		}
+	}
+	return domain
+}
+
+// domainRole 返回域名角色
+func domainRole(domain string, own, competitors []string) string {
+	for _, d := range own {
+		if d == domain {
+			return model.DomainRoleOwn
+		}
+	}
+	for _, d := range competitors {
+		if d == domain {
+			return model.DomainRoleCompetitor
+		}
+	}
+	return model.DomainRoleOther
+}
+
+// selectSOVDomains 选出返回的域名：我方和重点竞品始终返回，其他域名按引用数取前 top 个
+func selectSOVDomains(totals map[string]*sovAccumulator, own, competitors []string, top int) []string {
+	selected := make([]string, 0, len(own)+len(competitors)+top)
+	fixed := make(map[string]bool)
+	for _, d := range append(append([]string{}, own...), competitors...) {
+		if !fixed[d] {
+			fixed[d] = true
+			selected = append(selected, d)
+		}
+	}
+
+	others := make([]string, 0, len(totals))
+	for domain := range totals {
+		if !fixed[domain] {
+			others = append(others, domain)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		if totals[others[i]].citations != totals[others[j]].citations {
+			return totals[others[i]].citations > totals[others[j]].citations
+		}
+		return others[i] < others[j]
+	})
+	if top > 0 && len(others) > top {
+		others = others[:top]
+	}
+
+	return append(selected, others...)
+}
+
+// percent 计算百分比（保留两位小数）
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
+
+// average 计算平均值（保留两位小数）
+func average(sum, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(float64(sum)*100/float64(count)) / 100
+}
+
+// normalizeDomains 规范化域名列表（去协议、路径和 www. 前缀，去重）
+func normalizeDomains(domains []string) []string {
+	result := make([]string, 0, len(domains))
+	seen := make(map[string]bool)
+	for _, d := range domains {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		if !strings.Contains(d, "://") {
+			d = "https://" + d
+		}
+		if domain := tools.Domain(d); domain != "" && !seen[domain] {
+			seen[domain] = true
+			result = append(result, domain)
+		}
+	}
+	return result
+}
+
+// normalizeQueries 去除空白查询和重复查询
+func normalizeQueries(queries []string) []string {
+	result := make([]string, 0, len(queries))
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		q = strings.TrimSpace(q)
+		if q != "" && !seen[q] {
+			seen[q] = true
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// marshalStrings 将字符串数组序列化为 JSON
+func marshalStrings(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// unmarshalStrings 将 JSON 反序列化为字符串数组
+func unmarshalStrings(data string) []string {
+	items := make([]string, 0)
+	if data == "" {
+		return items
+	}
+	_ = json.Unmarshal([]byte(data), &items)
+	return items
+}
@@ -0,0 +1,210 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// TestShareOfVoice 测试引用份额、覆盖率、平均位置和趋势的汇总
+func TestShareOfVoice(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewQuerySetRepository(openTestDB(t))
+	s := NewShareOfVoiceService(repo, nil)
+
+	set, err := s.Create(ctx, &model.QuerySetCreateRequest{
+		Name:              "test",
+		Queries:           []string{"q1", "q2"},
+		Domains:           []string{"https://www.example.com/"},
+		CompetitorDomains: []string{"rival.com"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	newRun := func(createdAt time.Time, status string, answered int) int64 {
+		run := &model.QuerySetRun{QuerySetID: set.ID, Status: status, QueriesTotal: 2, QueriesAnswered: answered}
+		run.CreatedAt = createdAt
+		if err := repo.CreateRun(ctx, run); err != nil {
+			t.Fatalf("CreateRun() error = %v", err)
+		}
+		return run.ID
+	}
+	snapshot := func(runID int64, query, domain string, position int) model.CitationSnapshot {
+		return model.CitationSnapshot{QuerySetID: set.ID, RunID: runID, Query: query, Domain: domain, Position: position, CitedAt: time.Now()}
+	}
+
+	run1 := newRun(date(12, 10), model.QuerySetRunCompleted, 2)
+	run2 := newRun(date(14, 10), model.QuerySetRunCompleted, 1)
+	failed := newRun(date(14, 11), model.QuerySetRunFailed, 0)
+	outside := newRun(date(20, 10), model.QuerySetRunCompleted, 2)
+	if err := repo.CreateSnapshots(ctx, []model.CitationSnapshot{
+		snapshot(run1, "q1", "blog.example.com", 1),
+		snapshot(run1, "q1", "rival.com", 2),
+		snapshot(run1, "q2", "other.org", 1),
+		snapshot(run2, "q1", "example.com", 2),
+		snapshot(run2, "q1", "other.org", 1),
+		snapshot(failed, "q1", "rival.com", 1),
+		snapshot(outside, "q1", "rival.com", 1),
+	}); err != nil {
+		t.Fatalf("CreateSnapshots() error = %v", err)
+	}
+
+	result, err := s.ShareOfVoice(ctx, set.ID, &model.ShareOfVoiceRequest{From: "2026-10-12", To: "2026-10-14", Granularity: "day", Top: 10})
+	if err != nil {
+		t.Fatalf("ShareOfVoice() error = %v", err)
+	}
+	if result.Runs != 2 || result.QueriesAnswered != 3 || result.TotalCitations != 5 {
+		t.Errorf("runs, answered, citations = %d, %d, %d, want 2, 3, 5",
+			result.Runs, result.QueriesAnswered, result.TotalCitations)
+	}
+
+	want := []model.DomainShareOfVoice{
+		{
+			Domain: "example.com", Role: model.DomainRoleOwn, Citations: 2, Share: 40, Coverage: 66.67, AvgPosition: 1.5,
+			Trend: []model.ShareOfVoicePoint{
+				{Period: "2026-10-12", Citations: 1, Share: 33.33, Coverage: 50, AvgPosition: 1},
+				{Period: "2026-10-14", Citations: 1, Share: 50, Coverage: 100, AvgPosition: 2},
+			},
+		},
+		{
+			Domain: "rival.com", Role: model.DomainRoleCompetitor, Citations: 1, Share: 20, Coverage: 33.33, AvgPosition: 2,
+			Trend: []model.ShareOfVoicePoint{
+				{Period: "2026-10-12", Citations: 1, Share: 33.33, Coverage: 50, AvgPosition: 2},
+				{Period: "2026-10-14"},
+			},
+		},
+		{
+			Domain: "other.org", Role: model.DomainRoleOther, Citations: 2, Share: 40, Coverage: 66.67, AvgPosition: 1,
+			Trend: []model.ShareOfVoicePoint{
+				{Period: "2026-10-12", Citations: 1, Share: 33.33, Coverage: 50, AvgPosition: 1},
+				{Period: "2026-10-14", Citations: 1, Share: 50, Coverage: 100, AvgPosition: 1},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result.Domains, want) {
+		t.Errorf("Domains = %+v, want %+v", result.Domains, want)
+	}
+
+	// 按周汇总时两次采集落在同一个时间桶
+	weekly, err := s.ShareOfVoice(ctx, set.ID, &model.ShareOfVoiceRequest{From: "2026-10-12", To: "2026-10-14", Granularity: "week", Top: 10})
+	if err != nil {
+		t.Fatalf("ShareOfVoice(week) error = %v", err)
+	}
+	wantTrend := []model.ShareOfVoicePoint{{Period: "2026-10-12", Citations: 2, Share: 40, Coverage: 66.67, AvgPosition: 1.5}}
+	if !reflect.DeepEqual(weekly.Domains[0].Trend, wantTrend) {
+		t.Errorf("weekly trend = %+v, want %+v", weekly.Domains[0].Trend, wantTrend)
+	}
+}
+
+// TestRunNowUsesJobContext 测试手动触发的采集随任务上下文取消
+func TestRunNowUsesJobContext(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewQuerySetRepository(openTestDB(t))
+	retriever := &countingRetriever{}
+	s := NewShareOfVoiceService(repo, retriever)
+
+	set, err := s.Create(ctx, &model.QuerySetCreateRequest{
+		Name: "test", Queries: []string{"q1", "q2"}, Domains: []string{"example.com"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// 任务上下文已取消时 Start 立即返回，之后手动触发的采集不再请求 AI 回答
+	jobCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	s.Start(jobCtx)
+
+	run, err := s.RunNow(ctx, set.ID)
+	if err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runs, err := repo.ListRuns(ctx, set.ID, 1)
+		if err != nil {
+			t.Fatalf("ListRuns() error = %v", err)
+		}
+		if len(runs) == 1 && runs[0].ID == run.ID && runs[0].FinishedAt != nil {
+			if runs[0].Status != model.QuerySetRunCancelled {
+				t.Errorf("Status = %q, want %q", runs[0].Status, model.QuerySetRunCancelled)
+			}
+			if !strings.Contains(runs[0].ErrorMessage, context.Canceled.Error()) {
+				t.Errorf("ErrorMessage = %q, want %q", runs[0].ErrorMessage, context.Canceled)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if calls := retriever.calls.Load(); calls != 0 {
+		t.Errorf("GetAIOverview called %d times after job context canceled", calls)
+	}
+}
+
+// cancelingRetriever 返回第一个 AI 回答后取消采集上下文
+type cancelingRetriever struct {
+	cancel context.CancelFunc
+}
+
+func (r *cancelingRetriever) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	r.cancel()
+	return &models.AIOverview{Found: true, Sources: []string{"https://example.com/a"}}, nil
+}
+
+// TestExecuteRunCanceled 测试中途取消的采集标记为已取消，不计入引用份额，也不更新采集时间
+func TestExecuteRunCanceled(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewQuerySetRepository(openTestDB(t))
+	runCtx, cancel := context.WithCancel(ctx)
+	s := NewShareOfVoiceService(repo, &cancelingRetriever{cancel: cancel})
+
+	set, err := s.Create(ctx, &model.QuerySetCreateRequest{
+		Name: "test", Queries: []string{"q1", "q2", "q3"}, Domains: []string{"example.com"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	run, err := s.startRun(ctx, set)
+	if err != nil {
+		t.Fatalf("startRun() error = %v", err)
+	}
+	s.executeRun(runCtx, set, run)
+
+	runs, err := repo.ListRuns(ctx, set.ID, 1)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("ListRuns() = %v, %v", runs, err)
+	}
+	if runs[0].Status != model.QuerySetRunCancelled || runs[0].QueriesAnswered != 1 {
+		t.Errorf("run = %+v, want cancelled with 1 answered query", runs[0])
+	}
+
+	completed, err := repo.ListCompletedRunsBetween(ctx, set.ID, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListCompletedRunsBetween() error = %v", err)
+	}
+	if len(completed) != 0 {
+		t.Errorf("completed runs = %+v, want none", completed)
+	}
+
+	got, err := repo.GetByID(ctx, set.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.LastRunAt != nil {
+		t.Errorf("LastRunAt = %v, want nil after canceled run", got.LastRunAt)
+	}
+}
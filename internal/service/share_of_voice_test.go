@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// date 返回本地时区的日期时间
+func date(day, hour int) time.Time {
+	return time.Date(2026, time.October, day, hour, 0, 0, 0, time.Local)
+}
+
+// TestParseDateRange 测试日期范围解析
+func TestParseDateRange(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "默认最近 7 天", wantFrom: today.AddDate(0, 0, -7), wantTo: today},
+		{name: "只有结束日期", to: "2026-10-14", wantFrom: date(7, 0), wantTo: date(14, 0)},
+		{name: "起止日期", from: "2026-10-01", to: "2026-10-14", wantFrom: date(1, 0), wantTo: date(14, 0)},
+		{name: "同一天", from: "2026-10-14", to: "2026-10-14", wantFrom: date(14, 0), wantTo: date(14, 0)},
+		{name: "起始晚于结束", from: "2026-10-15", to: "2026-10-14", wantErr: true},
+		{name: "格式错误", from: "2026/10/01", wantErr: true},
+		{name: "日期不存在", to: "2026-02-30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := parseDateRange(tt.from, tt.to, 7)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDateRange) {
+					t.Fatalf("parseDateRange() error = %v, want ErrInvalidDateRange", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDateRange() error = %v", err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("parseDateRange() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+// TestPeriodKey 测试按天、周、月划分时间桶
+func TestPeriodKey(t *testing.T) {
+	tests := []struct {
+		t           time.Time
+		granularity string
+		want        string
+	}{
+		{date(14, 23), "day", "2026-10-14"},
+		{date(14, 10), "week", "2026-10-12"}, // 周三归到周一
+		{date(12, 0), "week", "2026-10-12"},  // 周一
+		{date(18, 10), "week", "2026-10-12"}, // 周日仍属于本周
+		{date(19, 10), "week", "2026-10-19"},
+		{date(1, 10), "week", "2026-09-28"}, // 跨月
+		{date(18, 10), "month", "2026-10-01"},
+		{date(14, 10), "", "2026-10-14"},
+	}
+
+	for _, tt := range tests {
+		if got := periodKey(tt.t, tt.granularity); got != tt.want {
+			t.Errorf("periodKey(%v, %q) = %q, want %q", tt.t, tt.granularity, got, tt.want)
+		}
+	}
+}
+
+// TestGroupDomain 测试子域名归并和域名角色
+func TestGroupDomain(t *testing.T) {
+	own := []string{"example.com"}
+	competitors := []string{"rival.com", "shop.other.com"}
+
+	tests := []struct {
+		domain    string
+		wantGroup string
+		wantRole  string
+	}{
+		{"example.com", "example.com", model.DomainRoleOwn},
+		{"blog.example.com", "example.com", model.DomainRoleOwn},
+		{"notexample.com", "notexample.com", model.DomainRoleOther},
+		{"rival.com", "rival.com", model.DomainRoleCompetitor},
+		{"a.b.rival.com", "rival.com", model.DomainRoleCompetitor},
+		{"shop.other.com", "shop.other.com", model.DomainRoleCompetitor},
+		{"other.com", "other.com", model.DomainRoleOther},
+		{"wikipedia.org", "wikipedia.org", model.DomainRoleOther},
+	}
+
+	for _, tt := range tests {
+		group := groupDomain(tt.domain, own, competitors)
+		if group != tt.wantGroup {
+			t.Errorf("groupDomain(%q) = %q, want %q", tt.domain, group, tt.wantGroup)
+		}
+		if role := domainRole(group, own, competitors); role != tt.wantRole {
+			t.Errorf("domainRole(%q) = %q, want %q", group, role, tt.wantRole)
+		}
+	}
+}
+
+// TestSelectSOVDomains 测试我方和重点竞品始终返回，其他域名按引用数取前 top 个
+func TestSelectSOVDomains(t *testing.T) {
+	acc := func(citations int) *sovAccumulator {
+		return &sovAccumulator{citations: citations}
+	}
+	totals := map[string]*sovAccumulator{
+		"example.com": acc(1),
+		"a.org":       acc(5),
+		"b.org":       acc(3),
+		"c.org":       acc(3),
+		"d.org":       acc(1),
+	}
+	own := []string{"example.com"}
+	competitors := []string{"rival.com", "example.com"}
+
+	tests := []struct {
+		name string
+		top  int
+		want []string
+	}{
+		{name: "取前 2 个，引用数相同按域名排序", top: 2, want: []string{"example.com", "rival.com", "a.org", "b.org"}},
+		{name: "top 大于其他域名数", top: 10, want: []string{"example.com", "rival.com", "a.org", "b.org", "c.org", "d.org"}},
+		{name: "top 为 0 返回全部", top: 0, want: []string{"example.com", "rival.com", "a.org", "b.org", "c.org", "d.org"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectSOVDomains(totals, own, competitors, tt.top); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectSOVDomains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingRetriever 记录调用次数的 AnswerRetriever
+type countingRetriever struct {
+	calls atomic.Int32
+}
+
+func (r *countingRetriever) GetAIOverview(ctx context.Context, query string) (*models.AIOverview, error) {
+	r.calls.Add(1)
+	return &models.AIOverview{Found: true, Sources: []string{"https://example.com/a"}}, nil
+}
+
+// TestCreateUnsupportedPlatform 测试只能创建 Google 平台的查询集
+func TestCreateUnsupportedPlatform(t *testing.T) {
+	s := NewShareOfVoiceService(repository.NewQuerySetRepository(nil), &countingRetriever{})
+
+	_, err := s.Create(context.Background(), &model.QuerySetCreateRequest{
+		Name: "test", Queries: []string{"q"}, Domains: []string{"example.com"}, Platform: "bing",
+	}, nil)
+	if !errors.Is(err, ErrUnsupportedPlatform) {
+		t.Errorf("Create(bing) error = %v, want ErrUnsupportedPlatform", err)
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/solariswu/peanut/internal/agent/geo/tools"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// ErrInvalidDomain 无效的域名
+var ErrInvalidDomain = errors.New("无效的域名")
+
+// statsDefaultDays 统计默认的时间范围（天）
+const statsDefaultDays = 90
+
+// StatisticsService 基于 GEO 分析记录的域名统计服务
+type StatisticsService struct {
+	repo    *repository.GEOAnalysisRepository
+	results *repository.AnalysisResultRepository
+}
+
+// NewStatisticsService 创建统计服务
+func NewStatisticsService(repo *repository.GEOAnalysisRepository, results *repository.AnalysisResultRepository) *StatisticsService {
+	return &StatisticsService{repo: repo, results: results}
+}
+
+// scoreAccumulator 累计分析次数和评分
+type scoreAccumulator struct {
+	analyses, completed, failed int
+	scoreSum                    int
+	last                        *time.Time
+}
+
+func (a *scoreAccumulator) add(analysis *model.GEOAnalysis) {
+	a.analyses++
+	if a.last == nil || analysis.CreatedAt.After(*a.last) {
+		createdAt := analysis.CreatedAt
+		a.last = &createdAt
+	}
+	switch analysis.Status {
+	case "completed":
+		a.completed++
+		a.scoreSum += analysis.OverallScore
+	case "failed":
+		a.failed++
+	}
+}
+
+// summary 转换为域名汇总，pages 为去重后的页面数
+func (a *scoreAccumulator) summary(domain string, pages int) model.DomainSummary {
+	return model.DomainSummary{
+		Domain:         domain,
+		Analyses:       a.analyses,
+		Completed:      a.completed,
+		Failed:         a.failed,
+		Pages:          pages,
+		AvgScore:       average(a.scoreSum, a.completed),
+		LastAnalyzedAt: a.last,
+	}
+}
+
+// Domains 按域名汇总时间范围内的分析次数和平均评分
+func (s *StatisticsService) Domains(ctx context.Context, req *model.DomainStatsRequest) (*model.DomainOverviewResponse, error) {
+	from, to, err := parseDateRange(req.From, req.To, statsDefaultDays)
+	if err != nil {
+		return nil, err
+	}
+	analyses, err := s.repo.ListForStats(ctx, from, to.AddDate(0, 0, 1), "", req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]*scoreAccumulator)
+	pages := make(map[string]map[string]bool)
+	for i := range analyses {
+		domain := tools.Domain(analyses[i].URL)
+		if domain == "" {
+			continue
+		}
+		if totals[domain] == nil {
+			totals[domain] = &scoreAccumulator{}
+			pages[domain] = make(map[string]bool)
+		}
+		totals[domain].add(&analyses[i])
+		pages[domain][analyses[i].URL] = true
+	}
+
+	domains := make([]model.DomainSummary, 0, len(totals))
+	for domain, acc := range totals {
+		domains = append(domains, acc.summary(domain, len(pages[domain])))
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Analyses != domains[j].Analyses {
+			return domains[i].Analyses > domains[j].Analyses
+		}
+		return domains[i].Domain < domains[j].Domain
+	})
+
+	return &model.DomainOverviewResponse{
+		From:    from.Format(dateLayout),
+		To:      to.Format(dateLayout),
+		Domains: domains,
+	}, nil
+}
+
+// Domain 统计单个域名的评分趋势、常见内容差距、薄弱维度和最好/最差页面
+func (s *StatisticsService) Domain(ctx context.Context, rawDomain string, req *model.DomainStatsRequest) (*model.DomainDashboardResponse, error) {
+	normalized := normalizeDomains([]string{rawDomain})
+	if len(normalized) == 0 {
+		return nil, ErrInvalidDomain
+	}
+	domain := normalized[0]
+
+	from, to, err := parseDateRange(req.From, req.To, statsDefaultDays)
+	if err != nil {
+		return nil, err
+	}
+	granularity := req.Granularity
+	if granularity != "day" && granularity != "month" {
+		granularity = "week"
+	}
+
+	records, err := s.repo.ListForStats(ctx, from, to.AddDate(0, 0, 1), domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 数据库按 URL 模式预筛选，这里只保留主机名与域名完全一致的记录（子域名单独统计）
+	total := &scoreAccumulator{}
+	byPeriod := make(map[string]*scoreAccumulator)
+	latest := make(map[string]*model.GEOAnalysis)
+	completedByPage := make(map[string]int)
+	pages := make(map[string]bool)
+	for i := range records {
+		analysis := &records[i]
+		if tools.Domain(analysis.URL) != domain {
+			continue
+		}
+		total.add(analysis)
+		pages[analysis.URL] = true
+
+		period := periodKey(analysis.CreatedAt, granularity)
+		if byPeriod[period] == nil {
+			byPeriod[period] = &scoreAccumulator{}
+		}
+		byPeriod[period].add(analysis)
+
+		// 记录按创建时间升序，后出现的为最近一次
+		if analysis.Status == "completed" {
+			latest[analysis.URL] = analysis
+			completedByPage[analysis.URL]++
+		}
+	}
+
+	pageScores := make([]model.PageScore, 0, len(latest))
+	latestIDs := make([]int64, 0, len(latest))
+	for url, analysis := range latest {
+		latestIDs = append(latestIDs, analysis.ID)
+		analyzedAt := analysis.CreatedAt
+		if analysis.CompletedAt != nil {
+			analyzedAt = *analysis.CompletedAt
+		}
+		pageScores = append(pageScores, model.PageScore{
+			URL:        url,
+			Title:      analysis.Title,
+			AnalysisID: analysis.ID,
+			Score:      analysis.OverallScore,
+			Analyses:   completedByPage[url],
+			AnalyzedAt: analyzedAt,
+		})
+	}
+	top, bottom := rankPages(pageScores, req.Top)
+
+	// 内容差距和建议类别在结构化结果子表中按分析聚合
+	gaps, err := s.results.ContentGapCounts(ctx, latestIDs, req.Top)
+	if err != nil {
+		return nil, err
+	}
+	categories, withSuggestions, err := s.results.SuggestionCategoryCounts(ctx, latestIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DomainDashboardResponse{
+		DomainSummary:     total.summary(domain, len(pages)),
+		From:              from.Format(dateLayout),
+		To:                to.Format(dateLayout),
+		Granularity:       granularity,
+		Trend:             scoreTrend(byPeriod),
+		ContentGaps:       gaps,
+		MissingDimensions: missingDimensions(categories, withSuggestions),
+		TopPages:          top,
+		BottomPages:       bottom,
+	}, nil
+}
+
+// rankPages 返回评分最高和最低的各 n 个页面，页面较少时两者可能重叠
+func rankPages(pages []model.PageScore, n int) ([]model.PageScore, []model.PageScore) {
+	sort.Slice(pages, func(i, j int) bool {
+		if pages[i].Score != pages[j].Score {
+			return pages[i].Score > pages[j].Score
+		}
+		return pages[i].URL < pages[j].URL
+	})
+
+	top := pages[:min(n, len(pages))]
+	bottom := make([]model.PageScore, 0, min(n, len(pages)))
+	for i := len(pages) - 1; i >= 0 && len(bottom) < n; i-- {
+		bottom = append(bottom, pages[i])
+	}
+	return top, bottom
+}
+
+// scoreTrend 按时间桶升序返回各时间桶的分析次数和平均评分
+func scoreTrend(byPeriod map[string]*scoreAccumulator) []model.DomainScorePoint {
+	periods := make([]string, 0, len(byPeriod))
+	for period := range byPeriod {
+		periods = append(periods, period)
+	}
+	// 时间桶为 YYYY-MM-DD，按字符串排序即按时间排序
+	sort.Strings(periods)
+
+	trend := make([]model.DomainScorePoint, 0, len(periods))
+	for _, period := range periods {
+		acc := byPeriod[period]
+		trend = append(trend, model.DomainScorePoint{
+			Period:    period,
+			Analyses:  acc.analyses,
+			Completed: acc.completed,
+			AvgScore:  average(acc.scoreSum, acc.completed),
+		})
+	}
+	return trend
+}
+
+// missingDimensions 将优化建议类别统计转换为薄弱维度：某类别有建议的页面即视为缺少该维度
+// withSuggestions 为有优化建议的页面数，用于计算占比
+func missingDimensions(counts []repository.SuggestionCategoryCount, withSuggestions int) []model.MissingDimension {
+	dimensions := make([]model.MissingDimension, 0, len(counts))
+	for _, c := range counts {
+		dimensions = append(dimensions, model.MissingDimension{
+			Dimension:    c.Category,
+			Pages:        c.Analyses,
+			Share:        percent(c.Analyses, withSuggestions),
+			HighPriority: c.HighPriority,
+		})
+	}
+	sort.Slice(dimensions, func(i, j int) bool {
+		if dimensions[i].Pages != dimensions[j].Pages {
+			return dimensions[i].Pages > dimensions[j].Pages
+		}
+		return dimensions[i].Dimension < dimensions[j].Dimension
+	})
+	return dimensions
+}
@@ -0,0 +1,98 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/agent/geo/models"
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// TestDomainDashboard 测试域名看板只统计该域名各页面最近一次完成的分析
+func TestDomainDashboard(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := repository.NewGEOAnalysisRepository(db)
+	resultRepo := repository.NewAnalysisResultRepository(db)
+	results := NewAnalysisResultService(resultRepo)
+	s := NewStatisticsService(repo, resultRepo)
+
+	seed := func(url, status string, day, score int, report *models.OptimizationReport) {
+		analysis := &model.GEOAnalysis{URL: url, Title: url, Status: status, OverallScore: score}
+		analysis.CreatedAt = date(day, 10)
+		if err := repo.Create(analysis); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if report != nil {
+			if err := results.Save(ctx, analysis.ID, report); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+	}
+
+	seed("https://example.com/a", "completed", 5, 40, &models.OptimizationReport{ContentGaps: []string{"Old gap"}})
+	seed("https://example.com/a", "completed", 13, 70, &models.OptimizationReport{
+		ContentGaps: []string{"Missing FAQ", " missing \n faq ", "No author bio"},
+		OptimizationSuggestions: []models.OptimizationSuggestion{
+			{Priority: "high", Category: "authority", Suggestion: "cite experts"},
+			{Priority: "low", Category: " Authority ", Suggestion: "add author page"},
+			{Priority: "medium", Category: "content", Suggestion: "add FAQ"},
+		},
+	})
+	seed("https://www.example.com/b", "completed", 14, 90, &models.OptimizationReport{
+		ContentGaps: []string{"MISSING FAQ"},
+		OptimizationSuggestions: []models.OptimizationSuggestion{
+			{Priority: "medium", Category: "freshness", Suggestion: "update dates"},
+			{Priority: "high", Category: "", Suggestion: "misc"},
+		},
+	})
+	seed("https://example.com/c", "failed", 14, 0, nil)
+	seed("https://blog.example.com/x", "completed", 14, 10, &models.OptimizationReport{ContentGaps: []string{"Missing FAQ"}})
+
+	dashboard, err := s.Domain(ctx, "www.example.com", &model.DomainStatsRequest{
+		From: "2026-10-01", To: "2026-10-18", Granularity: "week", Top: 10,
+	})
+	if err != nil {
+		t.Fatalf("Domain() error = %v", err)
+	}
+
+	summary := dashboard.DomainSummary
+	summary.LastAnalyzedAt = nil
+	wantSummary := model.DomainSummary{Domain: "example.com", Analyses: 4, Completed: 3, Failed: 1, Pages: 3, AvgScore: 66.67}
+	if summary != wantSummary {
+		t.Errorf("summary = %+v, want %+v", summary, wantSummary)
+	}
+
+	wantTrend := []model.DomainScorePoint{
+		{Period: "2026-10-05", Analyses: 1, Completed: 1, AvgScore: 40},
+		{Period: "2026-10-12", Analyses: 3, Completed: 2, AvgScore: 80},
+	}
+	if !reflect.DeepEqual(dashboard.Trend, wantTrend) {
+		t.Errorf("trend = %+v, want %+v", dashboard.Trend, wantTrend)
+	}
+
+	// 大小写和空白不同的差距合并，同一页面重复的差距只计一次，旧分析和子域名不计入
+	wantGaps := []model.ContentGapCount{{Gap: "MISSING FAQ", Pages: 2}, {Gap: "No author bio", Pages: 1}}
+	if !reflect.DeepEqual(dashboard.ContentGaps, wantGaps) {
+		t.Errorf("content gaps = %+v, want %+v", dashboard.ContentGaps, wantGaps)
+	}
+
+	wantDimensions := []model.MissingDimension{
+		{Dimension: "authority", Pages: 1, Share: 50, HighPriority: 1},
+		{Dimension: "content", Pages: 1, Share: 50},
+		{Dimension: "freshness", Pages: 1, Share: 50},
+		{Dimension: "general", Pages: 1, Share: 50, HighPriority: 1},
+	}
+	if !reflect.DeepEqual(dashboard.MissingDimensions, wantDimensions) {
+		t.Errorf("missing dimensions = %+v, want %+v", dashboard.MissingDimensions, wantDimensions)
+	}
+
+	if len(dashboard.TopPages) != 2 || dashboard.TopPages[0].URL != "https://www.example.com/b" ||
+		dashboard.TopPages[1].Score != 70 || dashboard.TopPages[1].Analyses != 2 {
+		t.Errorf("top pages = %+v", dashboard.TopPages)
+	}
+}
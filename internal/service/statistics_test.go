@@ -0,0 +1,89 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// TestRankPages 测试评分最高和最低的页面，评分相同时按 URL 排序
+func TestRankPages(t *testing.T) {
+	page := func(url string, score int) model.PageScore {
+		return model.PageScore{URL: url, Score: score}
+	}
+	urls := func(pages []model.PageScore) []string {
+		result := make([]string, len(pages))
+		for i, p := range pages {
+			result[i] = p.URL
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		pages      []model.PageScore
+		n          int
+		wantTop    []string
+		wantBottom []string
+	}{
+		{name: "没有页面", pages: nil, n: 3, wantTop: []string{}, wantBottom: []string{}},
+		{name: "页面少于 n，两者重叠", pages: []model.PageScore{page("/a", 60), page("/b", 80)}, n: 5,
+			wantTop: []string{"/b", "/a"}, wantBottom: []string{"/a", "/b"}},
+		{name: "评分相同", pages: []model.PageScore{page("/c", 80), page("/a", 80), page("/b", 60), page("/d", 60)}, n: 2,
+			wantTop: []string{"/a", "/c"}, wantBottom: []string{"/d", "/b"}},
+		{name: "只取一个", pages: []model.PageScore{page("/a", 50), page("/b", 90), page("/c", 70)}, n: 1,
+			wantTop: []string{"/b"}, wantBottom: []string{"/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, bottom := rankPages(tt.pages, tt.n)
+			if got := urls(top); !reflect.DeepEqual(got, tt.wantTop) {
+				t.Errorf("top = %v, want %v", got, tt.wantTop)
+			}
+			if got := urls(bottom); !reflect.DeepEqual(got, tt.wantBottom) {
+				t.Errorf("bottom = %v, want %v", got, tt.wantBottom)
+			}
+		})
+	}
+}
+
+// TestScoreTrend 测试趋势按时间桶升序
+func TestScoreTrend(t *testing.T) {
+	got := scoreTrend(map[string]*scoreAccumulator{
+		"2026-10-19": {analyses: 1, completed: 1, scoreSum: 70},
+		"2026-09-28": {analyses: 2, failed: 2},
+		"2026-10-05": {analyses: 3, completed: 2, scoreSum: 121},
+	})
+	want := []model.DomainScorePoint{
+		{Period: "2026-09-28", Analyses: 2},
+		{Period: "2026-10-05", Analyses: 3, Completed: 2, AvgScore: 60.5},
+		{Period: "2026-10-19", Analyses: 1, Completed: 1, AvgScore: 70},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreTrend() = %+v, want %+v", got, want)
+	}
+}
+
+// TestMissingDimensions 测试薄弱维度的占比、高优先级页面数和排序
+func TestMissingDimensions(t *testing.T) {
+	got := missingDimensions([]repository.SuggestionCategoryCount{
+		{Category: "freshness", Analyses: 2, HighPriority: 2},
+		{Category: "authority", Analyses: 3},
+		{Category: "content", Analyses: 2, HighPriority: 1},
+	}, 3)
+	want := []model.MissingDimension{
+		{Dimension: "authority", Pages: 3, Share: 100},
+		{Dimension: "content", Pages: 2, Share: 66.67, HighPriority: 1},
+		{Dimension: "freshness", Pages: 2, Share: 66.67, HighPriority: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("missingDimensions() = %+v, want %+v", got, want)
+	}
+
+	if got := missingDimensions(nil, 0); len(got) != 0 {
+		t.Errorf("missingDimensions(nil) = %+v, want empty", got)
+	}
+}
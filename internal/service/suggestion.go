@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/solariswu/peanut/internal/model"
+	"github.com/solariswu/peanut/internal/repository"
+)
+
+// ErrSuggestionNotFound 优化建议不存在
+var ErrSuggestionNotFound = repository.ErrSuggestionNotFound
+
+// ErrInvalidDueDate 截止日期格式错误
+var ErrInvalidDueDate = errors.New("截止日期格式应为 YYYY-MM-DD")
+
+// ErrReanalysisUnavailable GEO 分析服务不可用，无法重新分析
+var ErrReanalysisUnavailable = errors.New("GEO 分析服务不可用，无法重新分析")
+
+// SuggestionService 优化建议任务服务：状态、负责人、截止日期、评论，以及完成后重新分析验证评分
+type SuggestionService struct {
+	repo         *repository.SuggestionRepository
+	analysisRepo *repository.GEOAnalysisRepository
+	analyses     *GEOAnalysisService
+}
+
+// NewSuggestionService 创建优化建议服务
+// analyses 可为 nil，此时标记完成时不能重新分析
+func NewSuggestionService(repo *repository.SuggestionRepository, analysisRepo *repository.GEOAnalysisRepository, analyses *GEOAnalysisService) *SuggestionService {
+	return &SuggestionService{
+		repo:         repo,
+		analysisRepo: analysisRepo,
+		analyses:     analyses,
+	}
+}
+
+// List 跨分析查询优化建议
+func (s *SuggestionService) List(ctx context.Context, req *model.GEOSuggestionListRequest) ([]model.GEOSuggestionResponse, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+	req.Keyword = strings.TrimSpace(req.Keyword)
+	req.Assignee = strings.TrimSpace(req.Assignee)
+	return s.repo.List(ctx, req)
+}
+
+// Get 获取优化建议详情，包含评论和重新分析的验证结果
+func (s *SuggestionService) Get(ctx context.Context, id int64) (*model.GEOSuggestionDetailResponse, error) {
+	suggestion, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.ListComments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.GEOSuggestionDetailResponse{
+		GEOSuggestionResponse: *suggestion,
+		Comments:              comments,
+	}
+	// 重新分析的记录被删除时不返回验证结果
+	if suggestion.VerificationAnalysisID != nil {
+		if verification, err := s.analysisRepo.GetByID(*suggestion.VerificationAnalysisID); err == nil {
+			detail.Verification = verify(suggestion.OverallScore, verification)
+		}
+	}
+	return detail, nil
+}
+
+// Update 更新状态、负责人和截止日期，状态改为完成时记录完成时间
+func (s *SuggestionService) Update(ctx context.Context, id int64, req *model.GEOSuggestionUpdateRequest) (*model.GEOSuggestionDetailResponse, error) {
+	suggestion, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]any{}
+	if req.Status != nil {
+		for k, v := range statusFields(suggestion.Status, *req.Status) {
+			fields[k] = v
+		}
+	}
+	if req.Assignee != nil {
+		fields["assignee"] = strings.TrimSpace(*req.Assignee)
+	}
+	if req.DueDate != nil {
+		if strings.TrimSpace(*req.DueDate) == "" {
+			fields["due_date"] = nil
+		} else {
+			due, err := time.ParseInLocation(dateLayout, strings.TrimSpace(*req.DueDate), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidDueDate, *req.DueDate)
+			}
+			fields["due_date"] = due
+		}
+	}
+
+	if len(fields) > 0 {
+		if err := s.repo.UpdateFields(ctx, id, fields); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, id)
+}
+
+// AddComment 为优化建议添加评论
+func (s *SuggestionService) AddComment(ctx context.Context, id int64, req *model.GEOSuggestionCommentRequest) (*model.GEOSuggestionComment, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	comment := &model.GEOSuggestionComment{
+		SuggestionID: id,
+		Author:       strings.TrimSpace(req.Author),
+		Content:      strings.TrimSpace(req.Content),
+	}
+	if err := s.repo.AddComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Complete 标记优化建议完成，默认重新分析同一 URL 以验证评分是否提高
+// 重新分析无法发起时不修改建议状态
+func (s *SuggestionService) Complete(ctx context.Context, id int64, req *model.GEOSuggestionCompleteRequest, userID *int64) (*model.GEOSuggestionDetailResponse, error) {
+	suggestion, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := statusFields(suggestion.Status, model.SuggestionStatusDone)
+	if req.Reanalyze == nil || *req.Reanalyze {
+		if s.analyses == nil {
+			return nil, ErrReanalysisUnavailable
+		}
+		analysis, err := s.analyses.Reanalyze(ctx, suggestion.AnalysisID, userID)
+		if err != nil {
+			return nil, err
+		}
+		fields["verification_analysis_id"] = analysis.ID
+	}
+
+	if err := s.repo.UpdateFields(ctx, id, fields); err != nil {
+		return nil, err
+	}
+	if comment := strings.TrimSpace(req.Comment); comment != "" {
+		if _, err := s.AddComment(ctx, id, &model.GEOSuggestionCommentRequest{Author: req.Author, Content: comment}); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, id)
+}
+
+// statusFields 返回状态变更需要更新的字段：变为完成时记录完成时间，离开完成状态时清除
+func statusFields(from, to string) map[string]any {
+	fields := map[string]any{"status": to}
+	switch {
+	case to == model.SuggestionStatusDone && from != model.SuggestionStatusDone:
+		fields["completed_at"] = time.Now()
+	case to != model.SuggestionStatusDone:
+		fields["completed_at"] = nil
+	}
+	return fields
+}
+
+// verify 比较重新分析与原分析的评分
+func verify(baselineScore int, analysis *model.GEOAnalysis) *model.GEOSuggestionVerification {
+	v := &model.GEOSuggestionVerification{
+		AnalysisID:    analysis.ID,
+		Status:        analysis.Status,
+		BaselineScore: baselineScore,
+	}
+	if analysis.Status == "completed" {
+		v.Score = analysis.OverallScore
+		v.ScoreDelta = analysis.OverallScore - baselineScore
+		improved := v.ScoreDelta > 0
+		v.Improved = &improved
+	}
+	return v
+}
@@ -0,0 +1,34 @@
+//go:build sqlite_fts5
+
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/solariswu/peanut/internal/pkg/database"
+	"github.com/solariswu/peanut/internal/pkg/migrate"
+)
+
+// openTestDB 创建执行过全部迁移的临时 SQLite 数据库
+// 全文搜索迁移依赖 FTS5，依赖数据库的测试放在带 sqlite_fts5 构建标签的文件中（make test 已包含该标签）
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLite() error = %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	m, err := migrate.New(db.DB())
+	if err != nil {
+		t.Fatalf("migrate.New() error = %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return db.DB()
+}
@@ -56,6 +56,7 @@ const stepNames: Record<string, { name: string; icon: typeof Search; description
   'query_summarizer': { name: '总结查询发散', icon: FileText, description: '总结相关搜索词' },
   'content_optimizer': { name: '生成优化报告', icon: Lightbulb, description: '对比分析生成报告' },
   'content_rewriter': { name: '生成优化文章', icon: Edit3, description: '重写优化内容' },
+  'content_validator': { name: '验证优化效果', icon: Sparkles, description: '对比优化前后的评分' },
 }
 
 export function AnalysisResult({ analysis, isLoading, onRefresh }: AnalysisResultProps) {
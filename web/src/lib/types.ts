@@ -93,6 +93,7 @@ export interface GEOAnalysisResponse {
   content_gaps?: string // JSON 字符串
   optimization_suggestions?: string // JSON 字符串
   validation_result?: string // JSON 字符串
+  validation_score?: number // 优化后文章的验证评分（0-100）
   created_at: string
   updated_at: string
   completed_at?: string